@@ -10,6 +10,8 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(RunService())
+	rootCmd.AddCommand(Keys())
+	rootCmd.AddCommand(Policy())
 }
 
 func Execute() {
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"app/config"
+	"app/internal/pkg/token"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func Keys() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage JWT signing keys",
+	}
+
+	command.AddCommand(rotateKeys())
+
+	return command
+}
+
+func rotateKeys() *cobra.Command {
+	var (
+		algorithm   string
+		keyDir      string
+		retireAfter time.Duration
+		configFile  string
+	)
+
+	command := &cobra.Command{
+		Use:   "rotate",
+		Short: "Generate a new signing key, make it active and retire the current one",
+		Long: "Generate a new signing key into the key directory, make it the active key in the jwt.keys ring " +
+			"and keep the previous key verifying until every token it signed has expired. " +
+			"Restart the service to pick up the new ring.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			_ = godotenv.Load(".env")
+
+			conf := config.Config{}
+			conf.Load()
+
+			if algorithm == "" {
+				algorithm = activeAlgorithm(conf.JWT)
+			}
+			if retireAfter == 0 {
+				retireAfter = longestTokenLifetime(conf.JWT)
+			}
+
+			newKey, err := token.GenerateKey(algorithm, keyDir)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			ring, err := token.RotateKeyRing(conf.JWT, newKey, now.Add(retireAfter), now)
+			if err != nil {
+				return err
+			}
+
+			if err = writeKeyRing(configFile, ring); err != nil {
+				return err
+			}
+
+			fmt.Printf("Activated %s key %s, previous key retires at %s\n",
+				newKey.Algorithm, newKey.ID, now.Add(retireAfter).UTC().Format(time.RFC3339))
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&algorithm, "algorithm", "", "HS256, RS256, ES256 or EdDSA (defaults to the active key algorithm)")
+	command.Flags().StringVar(&keyDir, "key-dir", "keys", "directory the generated key material is written to")
+	command.Flags().DurationVar(&retireAfter, "retire-after", 0,
+		"how long the previous key keeps verifying (defaults to the longest token lifetime)")
+	command.Flags().StringVar(&configFile, "config", "config.yaml", "config file holding the jwt.keys ring")
+
+	return command
+}
+
+func activeAlgorithm(conf config.JWTConfig) string {
+	for _, key := range conf.Keys {
+		if key.Active && key.Algorithm != "" {
+			return key.Algorithm
+		}
+	}
+	if conf.Algorithm != "" {
+		return conf.Algorithm
+	}
+	return "HS256"
+}
+
+func longestTokenLifetime(conf config.JWTConfig) time.Duration {
+	longest := time.Duration(conf.RefreshExpire) * 24 * time.Hour
+	for _, lifetime := range []time.Duration{conf.Expire, conf.ResetPasswordExpire, conf.VerifyEmailExpire} {
+		longest = max(longest, lifetime)
+	}
+	return longest
+}
+
+// writeKeyRing replaces jwt.keys in the config file, keeping the rest of the document and its comments intact.
+func writeKeyRing(configFile string, ring []config.JWTKey) error {
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(content, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return errors.New("config file is not a yaml mapping")
+	}
+
+	jwtNode := mappingValue(doc.Content[0], "jwt")
+	if jwtNode == nil || jwtNode.Kind != yaml.MappingNode {
+		return errors.New("config file has no jwt section")
+	}
+
+	var ringNode yaml.Node
+	if err = ringNode.Encode(ring); err != nil {
+		return err
+	}
+
+	if keysNode := mappingValue(jwtNode, "keys"); keysNode != nil {
+		*keysNode = ringNode
+	} else {
+		jwtNode.Content = append(jwtNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "keys"}, &ringNode)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(&doc); err != nil {
+		return err
+	}
+
+	return os.WriteFile(configFile, buf.Bytes(), 0o600)
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
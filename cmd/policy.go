@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"app/internal/pkg/policy"
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func Policy() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "policy",
+		Short: "Manage attribute policies",
+	}
+
+	command.AddCommand(testPolicy())
+
+	return command
+}
+
+// policyCase is a sample input of a policy file with the effect it should have.
+type policyCase struct {
+	Name         string `yaml:"name"`
+	policy.Input `yaml:",inline"`
+	Expect       policy.Effect `yaml:"expect"`
+}
+
+func testPolicy() *cobra.Command {
+	var (
+		policyFile string
+		casesFile  string
+	)
+
+	command := &cobra.Command{
+		Use:   "test",
+		Short: "Evaluate a policy file against sample inputs",
+		Long: "Evaluate every case of the cases file against the policy file and print the decision with the policy " +
+			"that made it. A case passes when its decision is the expected allow, deny or not_applicable; " +
+			"not_applicable means the roles decide. Fails if a case does not pass.",
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			document, err := policy.Load(policyFile)
+			if err != nil {
+				return err
+			}
+
+			cases, err := loadPolicyCases(casesFile)
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, c := range cases {
+				decision := document.Evaluate(c.Input)
+
+				status := "PASS"
+				if c.Expect != "" && decision.Effect != c.Expect {
+					status = "FAIL"
+					failed++
+				}
+
+				fmt.Printf("%s  %s: %s", status, c.Name, decision.Effect)
+				if decision.Policy != "" {
+					fmt.Printf(" by %s", decision.Policy)
+				}
+				if status == "FAIL" {
+					fmt.Printf(", expected %s", c.Expect)
+				}
+				fmt.Println()
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d cases failed", failed, len(cases))
+			}
+			fmt.Printf("%d cases passed\n", len(cases))
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&policyFile, "file", "policies.yaml", "policy file to evaluate")
+	command.Flags().StringVar(&casesFile, "cases", "policies.test.yaml", "file with the sample inputs and expected effects")
+
+	return command
+}
+
+func loadPolicyCases(file string) ([]policyCase, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Cases []policyCase `yaml:"cases"`
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cases file %s: %w", file, err)
+	}
+
+	for _, c := range doc.Cases {
+		switch c.Expect {
+		case "", policy.Allow, policy.Deny, policy.NotApplicable:
+		default:
+			return nil, fmt.Errorf("case %s: expect must be allow, deny or not_applicable", c.Name)
+		}
+	}
+
+	return doc.Cases, nil
+}
@@ -9,22 +9,32 @@ import (
 )
 
 type Config struct {
-	AppName     string         `mapstructure:"app_name"`
-	AppVersion  string         `mapstructure:"app_version"`
-	Environment string         `mapstructure:"environment"`
-	Http        HttpConfig     `mapstructure:"http"`
-	Log         LogConfig      `mapstructure:"log"`
-	Database    DatabaseConfig `mapstructure:"database"`
-	JWT         JWTConfig      `mapstructure:"jwt"`
-	SMTP        SMTPConfig     `mapstructure:"smtp"`
-	OAuth2      OAuth2Config   `mapstructure:"oauth2"`
+	AppName     string           `mapstructure:"app_name"`
+	AppVersion  string           `mapstructure:"app_version"`
+	Environment string           `mapstructure:"environment"`
+	Http        HttpConfig       `mapstructure:"http"`
+	Log         LogConfig        `mapstructure:"log"`
+	Database    DatabaseConfig   `mapstructure:"database"`
+	JWT         JWTConfig        `mapstructure:"jwt"`
+	SMTP        SMTPConfig       `mapstructure:"smtp"`
+	OAuth2      OAuth2Config     `mapstructure:"oauth2"`
+	MFA         MFAConfig        `mapstructure:"mfa"`
+	WebAuthn    WebAuthnConfig   `mapstructure:"webauthn"`
+	MagicLink   MagicLinkConfig  `mapstructure:"magic_link"`
+	AuthServer  AuthServerConfig `mapstructure:"auth_server"`
+	Roles       RolesConfig      `mapstructure:"roles"`
+	Policy      PolicyConfig     `mapstructure:"policy"`
+	Lockout     LockoutConfig    `mapstructure:"lockout"`
+	Password    PasswordConfig   `mapstructure:"password"`
 }
 
 type HttpConfig struct {
-	Host         string        `mapstructure:"host"`
-	Port         int           `mapstructure:"port"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	Host           string        `mapstructure:"host"`
+	Port           int           `mapstructure:"port"`
+	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
+	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
+	ProxyHeader    string        `mapstructure:"proxy_header"`
+	TrustedProxies []string      `mapstructure:"trusted_proxies"`
 }
 
 type LogConfig struct {
@@ -49,10 +59,45 @@ type DatabaseConfig struct {
 
 type JWTConfig struct {
 	Secret              string        `mapstructure:"secret"`
+	Algorithm           string        `mapstructure:"algorithm"`
+	KeyID               string        `mapstructure:"key_id"`
+	PrivateKeyFile      string        `mapstructure:"private_key_file"`
 	Expire              time.Duration `mapstructure:"expire"`
 	RefreshExpire       int           `mapstructure:"refresh_expire"`
 	ResetPasswordExpire time.Duration `mapstructure:"reset_password_expire"`
 	VerifyEmailExpire   time.Duration `mapstructure:"verify_email_expire"`
+	MFAPendingExpire    time.Duration `mapstructure:"mfa_pending_expire"`
+	MagicLinkExpire     time.Duration `mapstructure:"magic_link_expire"`
+	InvitationExpire    time.Duration `mapstructure:"invitation_expire"`
+	UnlockAccountExpire time.Duration `mapstructure:"unlock_account_expire"`
+	TokenHashKey        string        `mapstructure:"token_hash_key"`
+	Keys                []JWTKey      `mapstructure:"keys"`
+	// ChangePasswordExpire is the time a user whose password expired has to choose a new one after login
+	ChangePasswordExpire time.Duration `mapstructure:"change_password_expire"`
+	// RevocationSyncInterval is how often revoked access tokens are reloaded from the database and pruned
+	RevocationSyncInterval time.Duration `mapstructure:"revocation_sync_interval"`
+	// AuthorizeFromToken lets JWTAuth check required rights against the permissions claim of the access token
+	// without loading the user
+	AuthorizeFromToken bool `mapstructure:"authorize_from_token"`
+}
+
+// HashKey keys the hashes of tokens and codes stored in the database, falling back to the signing secret.
+func (c JWTConfig) HashKey() string {
+	if c.TokenHashKey != "" {
+		return c.TokenHashKey
+	}
+	return c.Secret
+}
+
+// JWTKey is one entry of the signing key ring. Exactly one key is active and signs new tokens;
+// the others keep verifying tokens until their not_after date.
+type JWTKey struct {
+	ID             string `mapstructure:"id" yaml:"id"`
+	Algorithm      string `mapstructure:"algorithm" yaml:"algorithm"`
+	SecretFile     string `mapstructure:"secret_file" yaml:"secret_file,omitempty"`
+	PrivateKeyFile string `mapstructure:"private_key_file" yaml:"private_key_file,omitempty"`
+	Active         bool   `mapstructure:"active" yaml:"active"`
+	NotAfter       string `mapstructure:"not_after" yaml:"not_after,omitempty"`
 }
 
 type SMTPConfig struct {
@@ -64,9 +109,111 @@ type SMTPConfig struct {
 }
 
 type OAuth2Config struct {
-	GoogleClientID     string `mapstructure:"google_client_id"`
-	GoogleClientSecret string `mapstructure:"google_client_secret"`
-	RedirectURL        string `mapstructure:"redirect_url"`
+	// StateExpire is how long a started login or link may take to come back to the callback
+	StateExpire time.Duration `mapstructure:"state_expire"`
+	// CodeExpire is the lifetime of the one-time code handed to RedirectURIs in place of tokens
+	CodeExpire time.Duration `mapstructure:"code_expire"`
+	// RedirectURIs are the front-end pages the callback may redirect to, matched exactly
+	RedirectURIs []string `mapstructure:"redirect_uris"`
+	// Providers maps the :provider route segment to an OpenID Connect provider, entries without a client id are disabled
+	Providers map[string]OIDCProviderConfig `mapstructure:"providers"`
+}
+
+type OIDCProviderConfig struct {
+	// Issuer is the provider base URL, its /.well-known/openid-configuration is used for discovery
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+type MFAConfig struct {
+	// Issuer is the account label shown in authenticator apps, defaults to app_name
+	Issuer        string `mapstructure:"issuer"`
+	RecoveryCodes int    `mapstructure:"recovery_codes"`
+}
+
+type WebAuthnConfig struct {
+	// RPID is the relying party id, the registrable domain passkeys are bound to (e.g. example.com)
+	RPID          string `mapstructure:"rp_id"`
+	RPDisplayName string `mapstructure:"rp_display_name"`
+	// RPOrigins are the origins allowed to run the ceremonies (e.g. https://app.example.com)
+	RPOrigins []string      `mapstructure:"rp_origins"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
+
+type MagicLinkConfig struct {
+	// RateLimit is how many links can be requested for one email address within RateLimitWindow
+	RateLimit       int           `mapstructure:"rate_limit"`
+	RateLimitWindow time.Duration `mapstructure:"rate_limit_window"`
+}
+
+// AuthServerConfig configures the OAuth2 authorization server that issues tokens to registered clients.
+type AuthServerConfig struct {
+	// CodeExpire is the lifetime of an authorization code, defaults to one minute
+	CodeExpire time.Duration `mapstructure:"code_expire"`
+	// DeviceCodeExpire is the time a user has to approve a device, defaults to ten minutes
+	DeviceCodeExpire time.Duration `mapstructure:"device_code_expire"`
+	// DevicePollInterval is the minimum time between two polls of a device, defaults to five seconds
+	DevicePollInterval time.Duration `mapstructure:"device_poll_interval"`
+	// DeviceVerificationURI is the front-end page where users enter the code shown by the device
+	DeviceVerificationURI string `mapstructure:"device_verification_uri"`
+}
+
+// RolesConfig configures the in-memory copy of the roles and permissions stored in the database.
+type RolesConfig struct {
+	// CacheTTL is how long an instance trusts its copy, defaults to one minute. Changes made through
+	// an instance apply there at once and reach the other instances within this time.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// PolicyConfig configures the attribute policies evaluated on top of the role rules.
+type PolicyConfig struct {
+	// File is the YAML policy file, relative to the working directory. No attribute policies apply if empty.
+	File string `mapstructure:"file"`
+}
+
+// LockoutConfig throttles password logins to an account after failures and locks it after too many.
+type LockoutConfig struct {
+	// MaxAttempts is how many failed logins in a row lock the account, 0 disables locking
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// Duration is how long a locked account refuses password logins, defaults to fifteen minutes
+	Duration time.Duration `mapstructure:"duration"`
+	// Window is how long a failed login is remembered; the count starts again after a quiet window.
+	// Failures are never forgotten if empty.
+	Window time.Duration `mapstructure:"window"`
+	// BackoffBase is the wait after the first failed login, doubling with each further failure up to
+	// BackoffMax, which defaults to one minute. 0 disables the back-off.
+	BackoffBase time.Duration `mapstructure:"backoff_base"`
+	BackoffMax  time.Duration `mapstructure:"backoff_max"`
+}
+
+// PasswordConfig is the policy new passwords must follow when users are created, update their password
+// or reset it. Existing passwords are not checked again.
+type PasswordConfig struct {
+	// MinLength defaults to eight characters. MaxLength defaults to and cannot exceed 72 bytes,
+	// the most bcrypt hashes.
+	MinLength int `mapstructure:"min_length"`
+	MaxLength int `mapstructure:"max_length"`
+	// RequireUpper, RequireLower, RequireDigit and RequireSpecial each require one character of the class
+	RequireUpper   bool `mapstructure:"require_upper"`
+	RequireLower   bool `mapstructure:"require_lower"`
+	RequireDigit   bool `mapstructure:"require_digit"`
+	RequireSpecial bool `mapstructure:"require_special"`
+	// ForbidUserInfo refuses passwords containing the name of the user or the local part of their email
+	ForbidUserInfo bool `mapstructure:"forbid_user_info"`
+	// ForbiddenWords are refused anywhere in a password, ignoring case, such as the name of the app
+	ForbiddenWords []string `mapstructure:"forbidden_words"`
+	// CommonPasswordsFile lists refused passwords, one per line. A built-in list is used if empty.
+	CommonPasswordsFile string `mapstructure:"common_passwords_file"`
+	// History is how many of the last passwords of a user, the current one included, cannot be reused.
+	// 0 allows any password again.
+	History int `mapstructure:"history"`
+	// MaxAge is how long a password lasts before the user has to change it at login, 0 never expires it.
+	// Only users with one of ExpireRoles are concerned, or every user if empty.
+	MaxAge      time.Duration `mapstructure:"max_age"`
+	ExpireRoles []string      `mapstructure:"expire_roles"`
 }
 
 func (c *Config) Load() {
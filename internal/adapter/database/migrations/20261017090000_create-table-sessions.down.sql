@@ -0,0 +1,3 @@
+ALTER TABLE tokens DROP CONSTRAINT IF EXISTS fk_session;
+ALTER TABLE tokens DROP COLUMN IF EXISTS session_id;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,18 @@
+CREATE TABLE sessions(
+    id              UUID            PRIMARY KEY NOT NULL,
+    user_id         UUID            NOT NULL,
+    device_name     VARCHAR(255)    DEFAULT ''  NOT NULL,
+    user_agent      TEXT            DEFAULT ''  NOT NULL,
+    ip_address      VARCHAR(255)    DEFAULT ''  NOT NULL,
+    last_used_at    TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+ALTER TABLE tokens ADD COLUMN session_id UUID NULL;
+ALTER TABLE tokens ADD CONSTRAINT fk_session
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE;
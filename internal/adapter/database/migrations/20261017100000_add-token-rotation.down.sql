@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS security_events;
+ALTER TABLE tokens DROP COLUMN IF EXISTS consumed_at;
+ALTER TABLE tokens DROP COLUMN IF EXISTS parent_id;
+DELETE FROM tokens WHERE LENGTH(token) > 255;
+ALTER TABLE tokens ALTER COLUMN token TYPE VARCHAR(255);
@@ -0,0 +1,18 @@
+-- A jti claim keeps rotated tokens unique but pushes them past 255 characters
+ALTER TABLE tokens ALTER COLUMN token TYPE TEXT;
+ALTER TABLE tokens ADD COLUMN parent_id UUID NULL;
+ALTER TABLE tokens ADD COLUMN consumed_at TIMESTAMP NULL;
+
+CREATE TABLE security_events(
+    id              UUID            PRIMARY KEY NOT NULL,
+    user_id         UUID            NOT NULL,
+    type            VARCHAR(255)    NOT NULL,
+    ip_address      VARCHAR(255)    DEFAULT ''  NOT NULL,
+    user_agent      TEXT            DEFAULT ''  NOT NULL,
+    detail          TEXT            DEFAULT ''  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_security_events_user_id ON security_events(user_id);
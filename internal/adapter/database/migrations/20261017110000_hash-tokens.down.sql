@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tokens_token_hash;
+
+-- A hash cannot be turned back into its token, so the rows are kept but no longer match any token
+-- the clients hold; their users sign in again
+ALTER TABLE tokens RENAME COLUMN token_hash TO token;
+ALTER TABLE tokens ALTER COLUMN token TYPE TEXT;
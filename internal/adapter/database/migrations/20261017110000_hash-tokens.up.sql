@@ -0,0 +1,20 @@
+-- Existing tokens are hashed the way the application hashes them: a hex HMAC-SHA256 keyed with
+-- jwt.token_hash_key (or jwt.secret). The key is read from the app.token_hash_key setting of the
+-- migration connection, which make migrate-up passes from JWT_TOKEN_HASH_KEY.
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM tokens) AND COALESCE(current_setting('app.token_hash_key', true), '') = '' THEN
+        RAISE EXCEPTION 'app.token_hash_key must be set to the jwt.token_hash_key to hash the existing tokens';
+    END IF;
+END $$;
+
+ALTER TABLE tokens ADD COLUMN token_hash VARCHAR(255) NULL;
+
+UPDATE tokens SET token_hash = encode(hmac(token, current_setting('app.token_hash_key', true), 'sha256'), 'hex');
+
+ALTER TABLE tokens ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE tokens DROP COLUMN token;
+
+CREATE INDEX idx_tokens_token_hash ON tokens(token_hash);
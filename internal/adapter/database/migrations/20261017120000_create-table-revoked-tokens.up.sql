@@ -0,0 +1,11 @@
+-- No foreign keys: entries must outlive the deleted user or session they revoke
+CREATE TABLE revoked_tokens(
+    id              UUID            PRIMARY KEY NOT NULL,
+    jti             VARCHAR(255)    NULL,
+    session_id      UUID            NULL,
+    user_id         UUID            NULL,
+    revoked_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    expires_at      TIMESTAMP       NOT NULL
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(255) DEFAULT '' NOT NULL;
+ALTER TABLE users ADD COLUMN totp_enabled BOOLEAN DEFAULT FALSE NOT NULL;
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT DEFAULT 0 NOT NULL;
+
+CREATE TABLE recovery_codes(
+    id              UUID            PRIMARY KEY NOT NULL,
+    user_id         UUID            NOT NULL,
+    code_hash       VARCHAR(255)    NOT NULL,
+    used_at         TIMESTAMP       NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_recovery_codes_user_id_code_hash ON recovery_codes(user_id, code_hash);
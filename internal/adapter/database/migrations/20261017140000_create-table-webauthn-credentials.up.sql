@@ -0,0 +1,20 @@
+CREATE TABLE webauthn_credentials(
+    id                  UUID            PRIMARY KEY NOT NULL,
+    user_id             UUID            NOT NULL,
+    credential_id       BYTEA           NOT NULL,
+    public_key          BYTEA           NOT NULL,
+    attestation_type    VARCHAR(255)    NOT NULL,
+    transports          VARCHAR(255)    DEFAULT '' NOT NULL,
+    sign_count          BIGINT          DEFAULT 0 NOT NULL,
+    aaguid              BYTEA           NULL,
+    backup_eligible     BOOLEAN         DEFAULT FALSE NOT NULL,
+    backup_state        BOOLEAN         DEFAULT FALSE NOT NULL,
+    name                VARCHAR(255)    DEFAULT '' NOT NULL,
+    last_used_at        TIMESTAMP       NULL,
+    created_at          TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_webauthn_credentials_credential_id ON webauthn_credentials(credential_id);
+CREATE INDEX idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
@@ -0,0 +1,15 @@
+CREATE TABLE user_identities(
+    id              UUID            PRIMARY KEY NOT NULL,
+    user_id         UUID            NOT NULL,
+    provider        VARCHAR(255)    NOT NULL,
+    subject         VARCHAR(255)    NOT NULL,
+    email           VARCHAR(255)    DEFAULT '' NOT NULL,
+    linked_at       TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_identities_provider_subject ON user_identities(provider, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
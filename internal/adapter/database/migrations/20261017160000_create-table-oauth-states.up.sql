@@ -0,0 +1,16 @@
+CREATE TABLE oauth_states(
+    id              UUID            PRIMARY KEY NOT NULL,
+    state_hash      VARCHAR(255)    NOT NULL,
+    provider        VARCHAR(255)    NOT NULL,
+    nonce           VARCHAR(255)    NOT NULL,
+    code_verifier   VARCHAR(255)    NOT NULL,
+    redirect_uri    VARCHAR(2048)   DEFAULT '' NOT NULL,
+    user_id         UUID            NULL,
+    expires_at      TIMESTAMP       NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_oauth_states_state_hash ON oauth_states(state_hash);
+CREATE INDEX idx_oauth_states_expires_at ON oauth_states(expires_at);
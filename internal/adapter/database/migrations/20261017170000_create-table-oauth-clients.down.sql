@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sessions_client_id;
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS fk_oauth_client;
+ALTER TABLE sessions DROP COLUMN IF EXISTS scope;
+ALTER TABLE sessions DROP COLUMN IF EXISTS client_id;
+DROP TABLE IF EXISTS oauth_clients;
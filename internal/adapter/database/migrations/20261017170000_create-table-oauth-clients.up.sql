@@ -0,0 +1,18 @@
+CREATE TABLE oauth_clients(
+    id              UUID            PRIMARY KEY NOT NULL,
+    name            VARCHAR(255)    NOT NULL,
+    secret_hash     VARCHAR(255)    DEFAULT ''  NOT NULL,
+    redirect_uris   TEXT            DEFAULT ''  NOT NULL,
+    grant_types     VARCHAR(255)    DEFAULT ''  NOT NULL,
+    scopes          TEXT            DEFAULT ''  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
+
+-- Deleting a client ends the sessions it opened, and their refresh tokens with them
+ALTER TABLE sessions ADD COLUMN client_id UUID NULL;
+ALTER TABLE sessions ADD COLUMN scope TEXT DEFAULT '' NOT NULL;
+ALTER TABLE sessions ADD CONSTRAINT fk_oauth_client
+    FOREIGN KEY (client_id) REFERENCES oauth_clients(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_sessions_client_id ON sessions(client_id);
@@ -0,0 +1,20 @@
+CREATE TABLE device_authorizations(
+    id                  UUID            PRIMARY KEY NOT NULL,
+    device_code_hash    VARCHAR(255)    NOT NULL,
+    user_code_hash      VARCHAR(255)    NOT NULL,
+    device_name         VARCHAR(255)    DEFAULT '' NOT NULL,
+    user_agent          TEXT            DEFAULT '' NOT NULL,
+    ip_address          VARCHAR(255)    DEFAULT '' NOT NULL,
+    status              VARCHAR(20)     NOT NULL,
+    user_id             UUID            NULL,
+    poll_interval       INTEGER         NOT NULL,
+    last_polled_at      TIMESTAMP       NULL,
+    expires_at          TIMESTAMP       NOT NULL,
+    created_at          TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_device_authorizations_device_code_hash ON device_authorizations(device_code_hash);
+CREATE UNIQUE INDEX idx_device_authorizations_user_code_hash ON device_authorizations(user_code_hash);
+CREATE INDEX idx_device_authorizations_expires_at ON device_authorizations(expires_at);
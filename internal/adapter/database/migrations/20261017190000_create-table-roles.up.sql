@@ -0,0 +1,46 @@
+CREATE TABLE roles(
+    id              UUID            PRIMARY KEY NOT NULL,
+    name            VARCHAR(50)     NOT NULL,
+    description     VARCHAR(255)    DEFAULT ''  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_roles_name ON roles(name);
+
+CREATE TABLE permissions(
+    id              UUID            PRIMARY KEY NOT NULL,
+    name            VARCHAR(100)    NOT NULL,
+    description     VARCHAR(255)    DEFAULT ''  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_permissions_name ON permissions(name);
+
+CREATE TABLE role_permissions(
+    role_id         UUID            NOT NULL,
+    permission_id   UUID            NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role
+        FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_permission
+        FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+-- The roles and rights that were compiled into config/roles.go, plus the right to manage roles
+INSERT INTO roles (id, name, description) VALUES
+    ('01927a3c-0000-7000-8000-000000000001', 'user', 'Default role of registered users'),
+    ('01927a3c-0000-7000-8000-000000000002', 'admin', 'Manages users, roles and OAuth clients');
+
+INSERT INTO permissions (id, name, description) VALUES
+    ('01927a3c-0000-7000-8000-000000000101', 'getUsers', 'List and read any user'),
+    ('01927a3c-0000-7000-8000-000000000102', 'manageUsers', 'Create, update and delete any user'),
+    ('01927a3c-0000-7000-8000-000000000103', 'manageOAuthClients', 'Register and delete OAuth clients'),
+    ('01927a3c-0000-7000-8000-000000000104', 'manageRoles', 'Create, update and delete roles');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '01927a3c-0000-7000-8000-000000000002', id FROM permissions;
+
+-- A role cannot be deleted while users have it
+ALTER TABLE users ADD CONSTRAINT fk_role
+    FOREIGN KEY (role) REFERENCES roles(name);
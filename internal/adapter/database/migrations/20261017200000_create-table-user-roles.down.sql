@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN role VARCHAR(255) DEFAULT 'user' NOT NULL;
+
+-- Users with several roles keep admin if they had it, otherwise any one of their roles
+UPDATE users SET role = COALESCE(
+    (SELECT role FROM user_roles WHERE user_roles.user_id = users.id ORDER BY role = 'admin' DESC, role LIMIT 1),
+    'user'
+);
+
+ALTER TABLE users ADD CONSTRAINT fk_role
+    FOREIGN KEY (role) REFERENCES roles(name);
+
+DROP TABLE IF EXISTS user_roles;
@@ -0,0 +1,19 @@
+CREATE TABLE user_roles(
+    user_id         UUID            NOT NULL,
+    role            VARCHAR(50)     NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    PRIMARY KEY (user_id, role),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    -- A role cannot be deleted while users have it
+    CONSTRAINT fk_role
+        FOREIGN KEY (role) REFERENCES roles(name)
+);
+
+CREATE INDEX idx_user_roles_role ON user_roles(role);
+
+INSERT INTO user_roles (user_id, role)
+SELECT id, role FROM users;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_role;
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,2 @@
+-- Attributes compared by the attribute policies, such as the region of support staff
+ALTER TABLE users ADD COLUMN attributes JSONB DEFAULT '{}' NOT NULL;
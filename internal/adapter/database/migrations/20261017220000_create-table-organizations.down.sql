@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+
+DELETE FROM user_roles WHERE role = 'org-admin';
+DELETE FROM roles WHERE name = 'org-admin';
+DELETE FROM permissions WHERE name IN ('manageOrganizations', 'manageMembers');
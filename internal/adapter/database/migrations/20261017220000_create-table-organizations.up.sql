@@ -0,0 +1,41 @@
+CREATE TABLE organizations(
+    id              UUID            PRIMARY KEY NOT NULL,
+    name            VARCHAR(100)    NOT NULL,
+    slug            VARCHAR(50)     NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_organizations_slug ON organizations(slug);
+
+CREATE TABLE organization_members(
+    organization_id UUID            NOT NULL,
+    user_id         UUID            NOT NULL,
+    role            VARCHAR(50)     DEFAULT 'user'  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT fk_organization
+        FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    -- A role cannot be deleted while members have it
+    CONSTRAINT fk_role
+        FOREIGN KEY (role) REFERENCES roles(name)
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+INSERT INTO permissions (id, name, description) VALUES
+    ('01927a3c-0000-7000-8000-000000000105', 'manageOrganizations', 'Create organizations and manage the members of any organization'),
+    ('01927a3c-0000-7000-8000-000000000106', 'manageMembers', 'Add, change and remove the members of the current organization');
+
+-- Admins of an organization manage its users and members, but not roles or OAuth clients
+INSERT INTO roles (id, name, description) VALUES
+    ('01927a3c-0000-7000-8000-000000000003', 'org-admin', 'Manages the users and members of an organization');
+
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+    ('01927a3c-0000-7000-8000-000000000002', '01927a3c-0000-7000-8000-000000000105'),
+    ('01927a3c-0000-7000-8000-000000000002', '01927a3c-0000-7000-8000-000000000106'),
+    ('01927a3c-0000-7000-8000-000000000003', '01927a3c-0000-7000-8000-000000000101'),
+    ('01927a3c-0000-7000-8000-000000000003', '01927a3c-0000-7000-8000-000000000102'),
+    ('01927a3c-0000-7000-8000-000000000003', '01927a3c-0000-7000-8000-000000000106');
@@ -0,0 +1,22 @@
+CREATE TABLE organization_invitations(
+    id              UUID            PRIMARY KEY NOT NULL,
+    organization_id UUID            NOT NULL,
+    email           VARCHAR(255)    NOT NULL,
+    role            VARCHAR(50)     DEFAULT 'user'  NOT NULL,
+    invited_by      UUID,
+    token_hash      VARCHAR(255)    NOT NULL,
+    expires         TIMESTAMP       NOT NULL,
+    accepted_at     TIMESTAMP,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_organization
+        FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_invited_by
+        FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_role
+        FOREIGN KEY (role) REFERENCES roles(name)
+);
+
+CREATE UNIQUE INDEX idx_organization_invitations_token_hash ON organization_invitations(token_hash);
+-- An address has at most one pending invitation per organization
+CREATE UNIQUE INDEX idx_organization_invitations_pending
+    ON organization_invitations(organization_id, email) WHERE accepted_at IS NULL;
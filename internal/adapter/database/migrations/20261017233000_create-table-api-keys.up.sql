@@ -0,0 +1,26 @@
+CREATE TABLE api_keys(
+    id              UUID            PRIMARY KEY NOT NULL,
+    user_id         UUID,
+    created_by      UUID,
+    name            VARCHAR(100)    NOT NULL,
+    prefix          VARCHAR(20)     NOT NULL,
+    secret_hash     VARCHAR(255)    NOT NULL,
+    scopes          TEXT            DEFAULT ''  NOT NULL,
+    expires_at      TIMESTAMP,
+    last_used_at    TIMESTAMP,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    -- Personal keys go with their user; service keys have none
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX idx_api_keys_prefix ON api_keys(prefix);
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+INSERT INTO permissions (id, name, description) VALUES
+    ('01927a3c-0000-7000-8000-000000000107', 'manageApiKeys', 'Create service API keys and list or revoke the API keys of every user');
+
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+    ('01927a3c-0000-7000-8000-000000000002', '01927a3c-0000-7000-8000-000000000107');
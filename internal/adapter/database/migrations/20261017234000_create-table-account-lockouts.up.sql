@@ -0,0 +1,8 @@
+CREATE TABLE account_lockouts(
+    user_id             UUID            PRIMARY KEY NOT NULL,
+    failed_attempts     INTEGER         DEFAULT 0  NOT NULL,
+    last_failed_at      TIMESTAMP       NOT NULL,
+    locked_until        TIMESTAMP,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+CREATE TABLE login_events(
+    id                  UUID            PRIMARY KEY NOT NULL,
+    -- Failed logins of unknown email addresses or refresh tokens belong to no user
+    user_id             UUID,
+    email               VARCHAR(255)    DEFAULT ''  NOT NULL,
+    method              VARCHAR(50)     NOT NULL,
+    success             BOOLEAN         NOT NULL,
+    failure_reason      VARCHAR(255)    DEFAULT ''  NOT NULL,
+    ip_address          VARCHAR(255)    DEFAULT ''  NOT NULL,
+    user_agent          TEXT            DEFAULT ''  NOT NULL,
+    device_fingerprint  VARCHAR(255)    NOT NULL,
+    created_at          TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_login_events_user_id_created_at ON login_events(user_id, created_at);
+CREATE INDEX idx_login_events_user_id_device_fingerprint ON login_events(user_id, device_fingerprint);
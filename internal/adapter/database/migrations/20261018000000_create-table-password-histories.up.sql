@@ -0,0 +1,14 @@
+-- Existing passwords count as changed now, so their users get a full password.max_age before expiry
+ALTER TABLE users ADD COLUMN password_changed_at TIMESTAMP;
+UPDATE users SET password_changed_at = NOW();
+
+CREATE TABLE password_histories(
+    id                  UUID            PRIMARY KEY NOT NULL,
+    user_id             UUID            NOT NULL,
+    password            VARCHAR(255)    NOT NULL,
+    created_at          TIMESTAMP       NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_password_histories_user_id_created_at ON password_histories(user_id, created_at);
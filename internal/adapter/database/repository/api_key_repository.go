@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+// apiKeyTouchInterval limits how often the last use of a key is written, so busy keys do not write on every request
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *APIKeyRepositoryImpl) GetAll(ctx context.Context) ([]domain.APIKey, error) {
+	var apiKeys []domain.APIKey
+
+	result := r.DB.GetDB().WithContext(ctx).Order("created_at DESC").Find(&apiKeys)
+
+	if result.Error != nil {
+		golog.Error("Error getting api keys", result.Error)
+		return nil, myerrors.ErrGetAPIKeyFailed
+	}
+
+	return apiKeys, nil
+}
+
+func (r *APIKeyRepositoryImpl) GetByUserID(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	var apiKeys []domain.APIKey
+
+	result := r.DB.GetDB().WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&apiKeys)
+
+	if result.Error != nil {
+		golog.Error("Error getting api keys by user id", result.Error)
+		return nil, myerrors.ErrGetAPIKeyFailed
+	}
+
+	return apiKeys, nil
+}
+
+func (r *APIKeyRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, myerrors.ErrAPIKeyNotFound
+	}
+
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *APIKeyRepositoryImpl) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	return r.first(ctx, "prefix = ?", prefix)
+}
+
+func (r *APIKeyRepositoryImpl) first(ctx context.Context, query string, args ...any) (*domain.APIKey, error) {
+	var apiKey domain.APIKey
+
+	result := r.DB.GetDB().WithContext(ctx).Where(query, args...).First(&apiKey)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrAPIKeyNotFound
+		}
+		golog.Error("Error getting api key", result.Error)
+		return nil, myerrors.ErrGetAPIKeyFailed
+	}
+
+	return &apiKey, nil
+}
+
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, apiKey *domain.APIKey) (*domain.APIKey, error) {
+	apiKey.ID = uuid.Must(uuid.NewV7())
+
+	result := r.DB.GetDB().WithContext(ctx).Create(apiKey)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return nil, myerrors.ErrUserNotFound
+		}
+		golog.Error("Error creating api key", result.Error)
+		return nil, myerrors.ErrSaveAPIKeyFailed
+	}
+
+	return apiKey, nil
+}
+
+func (r *APIKeyRepositoryImpl) Delete(ctx context.Context, id string) error {
+	result := r.DB.GetDB().WithContext(ctx).Delete(&domain.APIKey{}, "id = ?", id)
+
+	if result.Error != nil {
+		golog.Error("Error deleting api key", result.Error)
+		return myerrors.ErrDeleteAPIKeyFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Touch records the use of a key, unless it was already recorded within apiKeyTouchInterval.
+func (r *APIKeyRepositoryImpl) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	result := r.DB.GetDB().WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-apiKeyTouchInterval)).
+		Update("last_used_at", usedAt)
+
+	if result.Error != nil {
+		golog.Error("Error touching api key", result.Error)
+		return myerrors.ErrSaveAPIKeyFailed
+	}
+
+	return nil
+}
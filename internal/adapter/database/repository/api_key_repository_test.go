@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *APIKeyRepositoryImpl
+	alice    domain.User
+}
+
+func TestAPIKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(apiKeyRepositoryTestSuite))
+}
+
+func (s *apiKeyRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.APIKey{}))
+
+	s.alice = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Alice", Email: "alice@example.com", Password: "pass1"}
+	s.Require().NoError(gormDB.Create(&s.alice).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &APIKeyRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *apiKeyRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *apiKeyRepositoryTestSuite) createAPIKey(prefix string, userID *uuid.UUID) *domain.APIKey {
+	apiKey, err := s.repo.Create(s.ctx, &domain.APIKey{
+		UserID: userID, Name: prefix, Prefix: prefix, SecretHash: "hash", Scopes: "getUsers",
+	})
+	s.Require().NoError(err)
+	return apiKey
+}
+
+// ==================== Get Tests ====================
+
+func (s *apiKeyRepositoryTestSuite) TestGetByUserID_OnlyPersonalKeysOfUser() {
+	s.createAPIKey("ak_alice", &s.alice.ID)
+	s.createAPIKey("ak_service", nil)
+
+	apiKeys, err := s.repo.GetByUserID(s.ctx, s.alice.ID.String())
+	s.Require().NoError(err)
+	s.Len(apiKeys, 1)
+	s.Equal("ak_alice", apiKeys[0].Prefix)
+
+	all, err := s.repo.GetAll(s.ctx)
+	s.Require().NoError(err)
+	s.Len(all, 2)
+}
+
+func (s *apiKeyRepositoryTestSuite) TestGetByPrefix() {
+	created := s.createAPIKey("ak_alice", &s.alice.ID)
+
+	found, err := s.repo.GetByPrefix(s.ctx, "ak_alice")
+	s.Require().NoError(err)
+	s.Equal(created.ID, found.ID)
+	s.Equal([]string{"getUsers"}, found.ScopeList())
+
+	_, err = s.repo.GetByPrefix(s.ctx, "ak_unknown")
+	s.Equal(myerrors.ErrAPIKeyNotFound, err)
+}
+
+func (s *apiKeyRepositoryTestSuite) TestGetByID_InvalidID() {
+	_, err := s.repo.GetByID(s.ctx, "not-a-uuid")
+
+	s.Equal(myerrors.ErrAPIKeyNotFound, err)
+}
+
+// ==================== Delete Tests ====================
+
+func (s *apiKeyRepositoryTestSuite) TestDelete() {
+	created := s.createAPIKey("ak_alice", &s.alice.ID)
+
+	s.Require().NoError(s.repo.Delete(s.ctx, created.ID.String()))
+
+	err := s.repo.Delete(s.ctx, created.ID.String())
+	s.Equal(myerrors.ErrAPIKeyNotFound, err)
+}
+
+// ==================== Touch Tests ====================
+
+func (s *apiKeyRepositoryTestSuite) TestTouch_ThrottlesWrites() {
+	created := s.createAPIKey("ak_alice", &s.alice.ID)
+	first := time.Now().UTC().Truncate(time.Millisecond)
+
+	s.Require().NoError(s.repo.Touch(s.ctx, created.ID.String(), first))
+	s.Require().NoError(s.repo.Touch(s.ctx, created.ID.String(), first.Add(30*time.Second)))
+
+	found, err := s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.WithinDuration(first, *found.LastUsedAt, time.Millisecond)
+
+	s.Require().NoError(s.repo.Touch(s.ctx, created.ID.String(), first.Add(2*time.Minute)))
+
+	found, err = s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.WithinDuration(first.Add(2*time.Minute), *found.LastUsedAt, time.Millisecond)
+}
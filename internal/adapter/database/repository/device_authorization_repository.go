@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+type DeviceAuthorizationRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *DeviceAuthorizationRepositoryImpl) Create(
+	ctx context.Context, authorization *domain.DeviceAuthorization,
+) (*domain.DeviceAuthorization, error) {
+	authorization.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(authorization)
+	if result.Error != nil {
+		golog.Error("Error creating device authorization", result.Error)
+		return nil, myerrors.ErrSaveDeviceAuthorizationFailed
+	}
+	return authorization, nil
+}
+
+// GetByDeviceCodeHash returns the authorization of a device code, expired or not, so the device can be told apart.
+func (r *DeviceAuthorizationRepositoryImpl) GetByDeviceCodeHash(
+	ctx context.Context, deviceCodeHash string,
+) (*domain.DeviceAuthorization, error) {
+	var authorization domain.DeviceAuthorization
+
+	err := r.DB.GetDB().WithContext(ctx).First(&authorization, "device_code_hash = ?", deviceCodeHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrInvalidGrant
+		}
+		golog.Error("Error getting device authorization", err)
+		return nil, myerrors.ErrGetDeviceAuthorizationFailed
+	}
+
+	return &authorization, nil
+}
+
+func (r *DeviceAuthorizationRepositoryImpl) RecordPoll(
+	ctx context.Context, id string, polledAt time.Time, pollInterval int,
+) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.DeviceAuthorization{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"last_polled_at": polledAt, "poll_interval": pollInterval})
+
+	if result.Error != nil {
+		golog.Error("Error recording device poll", result.Error)
+		return myerrors.ErrSaveDeviceAuthorizationFailed
+	}
+
+	return nil
+}
+
+// Decide records the answer of the user to a pending, unexpired user code. A code can be answered once.
+func (r *DeviceAuthorizationRepositoryImpl) Decide(
+	ctx context.Context, userCodeHash, userID, status string, now time.Time,
+) (*domain.DeviceAuthorization, error) {
+	var authorization domain.DeviceAuthorization
+
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&authorization, "user_code_hash = ? AND status = ? AND expires_at > ?",
+			userCodeHash, domain.DeviceAuthorizationPending, now).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&domain.DeviceAuthorization{}).
+			Where("id = ? AND status = ?", authorization.ID, domain.DeviceAuthorizationPending).
+			Updates(map[string]any{"status": status, "user_id": userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrInvalidUserCode
+		}
+		golog.Error("Error deciding device authorization", err)
+		return nil, myerrors.ErrSaveDeviceAuthorizationFailed
+	}
+
+	parsedUserID := uuid.MustParse(userID)
+	authorization.Status = status
+	authorization.UserID = &parsedUserID
+
+	return &authorization, nil
+}
+
+// Consume deletes an approved authorization. When two polls race only the one whose delete
+// removes the row gets the tokens.
+func (r *DeviceAuthorizationRepositoryImpl) Consume(ctx context.Context, id string) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Delete(&domain.DeviceAuthorization{}, "id = ? AND status = ?", id, domain.DeviceAuthorizationApproved)
+
+	if result.Error != nil {
+		golog.Error("Error consuming device authorization", result.Error)
+		return myerrors.ErrSaveDeviceAuthorizationFailed
+	}
+	if result.RowsAffected == 0 {
+		return myerrors.ErrInvalidGrant
+	}
+
+	return nil
+}
+
+func (r *DeviceAuthorizationRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.DB.GetDB().WithContext(ctx).
+		Delete(&domain.DeviceAuthorization{}, "expires_at <= ?", now)
+
+	if result.Error != nil {
+		golog.Error("Error deleting expired device authorizations", result.Error)
+		return 0, myerrors.ErrSaveDeviceAuthorizationFailed
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type deviceAuthorizationRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *DeviceAuthorizationRepositoryImpl
+	userID   string
+}
+
+func TestDeviceAuthorizationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(deviceAuthorizationRepositoryTestSuite))
+}
+
+func (s *deviceAuthorizationRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.DeviceAuthorization{}))
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &DeviceAuthorizationRepositoryImpl{DB: s.mockDB}
+
+	user := &domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Test", Email: "test@example.com"}
+	s.Require().NoError(gormDB.Create(user).Error)
+	s.userID = user.ID.String()
+}
+
+func (s *deviceAuthorizationRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *deviceAuthorizationRepositoryTestSuite) createAuthorization(
+	deviceCodeHash, userCodeHash string, expiresAt time.Time,
+) *domain.DeviceAuthorization {
+	authorization, err := s.repo.Create(s.ctx, &domain.DeviceAuthorization{
+		DeviceCodeHash: deviceCodeHash,
+		UserCodeHash:   userCodeHash,
+		DeviceName:     "cli",
+		Status:         domain.DeviceAuthorizationPending,
+		PollInterval:   5,
+		ExpiresAt:      expiresAt,
+	})
+	s.Require().NoError(err)
+	return authorization
+}
+
+// ==================== GetByDeviceCodeHash Tests ====================
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestGetByDeviceCodeHash_Success() {
+	created := s.createAuthorization("device-1", "user-1", time.Now().Add(time.Minute))
+
+	authorization, err := s.repo.GetByDeviceCodeHash(s.ctx, "device-1")
+	s.Require().NoError(err)
+	s.Equal(created.ID, authorization.ID)
+	s.Equal("cli", authorization.DeviceName)
+}
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestGetByDeviceCodeHash_NotFound() {
+	_, err := s.repo.GetByDeviceCodeHash(s.ctx, "unknown")
+	s.True(errors.Is(err, myerrors.ErrInvalidGrant))
+}
+
+// ==================== RecordPoll Tests ====================
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestRecordPoll() {
+	created := s.createAuthorization("device-1", "user-1", time.Now().Add(time.Minute))
+	polledAt := time.Now().UTC().Truncate(time.Second)
+
+	s.Require().NoError(s.repo.RecordPoll(s.ctx, created.ID.String(), polledAt, 10))
+
+	authorization, err := s.repo.GetByDeviceCodeHash(s.ctx, "device-1")
+	s.Require().NoError(err)
+	s.Equal(10, authorization.PollInterval)
+	s.Require().NotNil(authorization.LastPolledAt)
+	s.True(polledAt.Equal(*authorization.LastPolledAt))
+}
+
+// ==================== Decide Tests ====================
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestDecide_Once() {
+	s.createAuthorization("device-1", "user-1", time.Now().Add(time.Minute))
+
+	authorization, err := s.repo.Decide(s.ctx, "user-1", s.userID, domain.DeviceAuthorizationApproved, time.Now())
+	s.Require().NoError(err)
+	s.Equal(domain.DeviceAuthorizationApproved, authorization.Status)
+	s.Equal(s.userID, authorization.UserID.String())
+
+	_, err = s.repo.Decide(s.ctx, "user-1", s.userID, domain.DeviceAuthorizationDenied, time.Now())
+	s.True(errors.Is(err, myerrors.ErrInvalidUserCode))
+
+	stored, err := s.repo.GetByDeviceCodeHash(s.ctx, "device-1")
+	s.Require().NoError(err)
+	s.Equal(domain.DeviceAuthorizationApproved, stored.Status)
+}
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestDecide_Expired() {
+	s.createAuthorization("device-1", "user-1", time.Now().Add(-time.Minute))
+
+	_, err := s.repo.Decide(s.ctx, "user-1", s.userID, domain.DeviceAuthorizationApproved, time.Now())
+	s.True(errors.Is(err, myerrors.ErrInvalidUserCode))
+}
+
+// ==================== Consume Tests ====================
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestConsume_SingleUse() {
+	created := s.createAuthorization("device-1", "user-1", time.Now().Add(time.Minute))
+	_, err := s.repo.Decide(s.ctx, "user-1", s.userID, domain.DeviceAuthorizationApproved, time.Now())
+	s.Require().NoError(err)
+
+	s.NoError(s.repo.Consume(s.ctx, created.ID.String()))
+	s.True(errors.Is(s.repo.Consume(s.ctx, created.ID.String()), myerrors.ErrInvalidGrant))
+}
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestConsume_Pending() {
+	created := s.createAuthorization("device-1", "user-1", time.Now().Add(time.Minute))
+
+	s.True(errors.Is(s.repo.Consume(s.ctx, created.ID.String()), myerrors.ErrInvalidGrant))
+}
+
+// ==================== DeleteExpired Tests ====================
+
+func (s *deviceAuthorizationRepositoryTestSuite) TestDeleteExpired() {
+	s.createAuthorization("device-1", "user-1", time.Now().Add(-time.Minute))
+	s.createAuthorization("device-2", "user-2", time.Now().Add(time.Minute))
+
+	deleted, err := s.repo.DeleteExpired(s.ctx, time.Now())
+	s.Require().NoError(err)
+	s.Equal(int64(1), deleted)
+
+	_, err = s.repo.GetByDeviceCodeHash(s.ctx, "device-2")
+	s.NoError(err)
+}
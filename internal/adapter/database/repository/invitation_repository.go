@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvitationRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+// GetPending returns the invitations of the organization that were not accepted yet, newest first.
+func (r *InvitationRepositoryImpl) GetPending(
+	ctx context.Context, organizationID string,
+) ([]domain.OrganizationInvitation, error) {
+	var invitations []domain.OrganizationInvitation
+
+	result := r.DB.GetDB().WithContext(ctx).Preload("Organization").
+		Where("organization_id = ? AND accepted_at IS NULL", organizationID).
+		Order("created_at DESC").Find(&invitations)
+
+	if result.Error != nil {
+		golog.Error("Error getting invitations", result.Error)
+		return nil, myerrors.ErrGetInvitationFailed
+	}
+
+	return invitations, nil
+}
+
+func (r *InvitationRepositoryImpl) GetByTokenHash(
+	ctx context.Context, tokenHash string,
+) (*domain.OrganizationInvitation, error) {
+	var invitation domain.OrganizationInvitation
+
+	result := r.DB.GetDB().WithContext(ctx).Preload("Organization").First(&invitation, "token_hash = ?", tokenHash)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrInvitationNotFound
+		}
+		golog.Error("Error getting invitation by token hash", result.Error)
+		return nil, myerrors.ErrGetInvitationFailed
+	}
+
+	return &invitation, nil
+}
+
+// Create stores the invitation, replacing the pending invitation of the same address to the organization.
+func (r *InvitationRepositoryImpl) Create(
+	ctx context.Context, invitation *domain.OrganizationInvitation,
+) (*domain.OrganizationInvitation, error) {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("organization_id = ? AND email = ? AND accepted_at IS NULL",
+			invitation.OrganizationID, invitation.Email).Delete(&domain.OrganizationInvitation{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Omit("Organization").Create(invitation).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return nil, myerrors.ErrInvalidRole
+		}
+		golog.Error("Error creating invitation", err)
+		return nil, myerrors.ErrSaveInvitationFailed
+	}
+
+	return invitation, nil
+}
+
+// Delete revokes a pending invitation of the organization.
+func (r *InvitationRepositoryImpl) Delete(ctx context.Context, organizationID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return myerrors.ErrInvitationNotFound
+	}
+
+	result := r.DB.GetDB().WithContext(ctx).
+		Where("id = ? AND organization_id = ? AND accepted_at IS NULL", id, organizationID).
+		Delete(&domain.OrganizationInvitation{})
+
+	if result.Error != nil {
+		golog.Error("Error deleting invitation", result.Error)
+		return myerrors.ErrSaveInvitationFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// Accept marks the invitation accepted and makes the user a member with the invited role. A user without
+// an ID is created first. Members keep the role they already have. Fails with ErrInvitationNotFound when the
+// invitation was accepted or revoked in the meantime.
+func (r *InvitationRepositoryImpl) Accept(
+	ctx context.Context, invitation *domain.OrganizationInvitation, user *domain.User,
+) error {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", time.Now().UTC())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return myerrors.ErrInvitationNotFound
+		}
+
+		if user.ID == uuid.Nil {
+			user.ID = uuid.Must(uuid.NewV7())
+			if err := tx.Omit("Memberships").Create(user).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit("Organization", "User").Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.OrganizationMember{
+				OrganizationID: invitation.OrganizationID,
+				UserID:         user.ID,
+				Role:           invitation.Role,
+			}).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, myerrors.ErrInvitationNotFound) {
+			return err
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return myerrors.ErrEmailAlreadyInUse
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return myerrors.ErrInvalidRole
+		}
+		golog.Error("Error accepting invitation", err)
+		return myerrors.ErrSaveInvitationFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type invitationRepositoryTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	mockCtrl     *gomock.Controller
+	mockDB       *mocks.MockDatabaseAdapter
+	gormDB       *gorm.DB
+	repo         *InvitationRepositoryImpl
+	organization domain.Organization
+	alice        domain.User
+}
+
+func TestInvitationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(invitationRepositoryTestSuite))
+}
+
+func (s *invitationRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(
+		&domain.User{}, &domain.UserRole{}, &domain.Organization{}, &domain.OrganizationMember{},
+		&domain.OrganizationInvitation{},
+	))
+
+	s.alice = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Alice", Email: "alice@example.com", Password: "pass1"}
+	s.Require().NoError(gormDB.Create(&s.alice).Error)
+
+	s.organization = domain.Organization{ID: uuid.Must(uuid.NewV7()), Name: "Acme", Slug: "acme"}
+	s.Require().NoError(gormDB.Create(&s.organization).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &InvitationRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *invitationRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *invitationRepositoryTestSuite) createInvitation(email, tokenHash string) *domain.OrganizationInvitation {
+	invitation, err := s.repo.Create(s.ctx, &domain.OrganizationInvitation{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: s.organization.ID,
+		Email:          email,
+		Role:           domain.RoleUser,
+		InvitedBy:      &s.alice.ID,
+		TokenHash:      tokenHash,
+		Expires:        time.Now().UTC().Add(time.Hour),
+	})
+	s.Require().NoError(err)
+	return invitation
+}
+
+// ==================== Create Tests ====================
+
+func (s *invitationRepositoryTestSuite) TestCreate_ReplacesPendingInvitation() {
+	s.createInvitation("bob@example.com", "hash-1")
+	replacement := s.createInvitation("bob@example.com", "hash-2")
+	s.createInvitation("carol@example.com", "hash-3")
+
+	invitations, err := s.repo.GetPending(s.ctx, s.organization.ID.String())
+	s.Require().NoError(err)
+	s.Len(invitations, 2)
+
+	_, err = s.repo.GetByTokenHash(s.ctx, "hash-1")
+	s.Equal(myerrors.ErrInvitationNotFound, err)
+
+	found, err := s.repo.GetByTokenHash(s.ctx, "hash-2")
+	s.Require().NoError(err)
+	s.Equal(replacement.ID, found.ID)
+	s.Equal("acme", found.Organization.Slug)
+}
+
+// ==================== Delete Tests ====================
+
+func (s *invitationRepositoryTestSuite) TestDelete_Success() {
+	invitation := s.createInvitation("bob@example.com", "hash-1")
+
+	err := s.repo.Delete(s.ctx, s.organization.ID.String(), invitation.ID.String())
+	s.Require().NoError(err)
+
+	_, err = s.repo.GetByTokenHash(s.ctx, "hash-1")
+	s.Equal(myerrors.ErrInvitationNotFound, err)
+}
+
+func (s *invitationRepositoryTestSuite) TestDelete_OtherOrganization() {
+	invitation := s.createInvitation("bob@example.com", "hash-1")
+
+	err := s.repo.Delete(s.ctx, uuid.NewString(), invitation.ID.String())
+
+	s.Equal(myerrors.ErrInvitationNotFound, err)
+}
+
+func (s *invitationRepositoryTestSuite) TestDelete_InvalidID() {
+	err := s.repo.Delete(s.ctx, s.organization.ID.String(), "not-a-uuid")
+
+	s.Equal(myerrors.ErrInvitationNotFound, err)
+}
+
+// ==================== Accept Tests ====================
+
+func (s *invitationRepositoryTestSuite) TestAccept_CreatesUser() {
+	invitation := s.createInvitation("bob@example.com", "hash-1")
+	user := &domain.User{
+		Name: "Bob", Email: "bob@example.com", Password: "hashed", VerifiedEmail: true,
+		Roles: domain.NewUserRoles(domain.RoleUser),
+	}
+
+	err := s.repo.Accept(s.ctx, invitation, user)
+	s.Require().NoError(err)
+	s.NotEqual(uuid.Nil, user.ID)
+
+	var member domain.OrganizationMember
+	s.Require().NoError(s.gormDB.First(&member, "user_id = ?", user.ID).Error)
+	s.Equal(s.organization.ID, member.OrganizationID)
+	s.Equal(domain.RoleUser, member.Role)
+
+	invitations, err := s.repo.GetPending(s.ctx, s.organization.ID.String())
+	s.Require().NoError(err)
+	s.Empty(invitations)
+}
+
+func (s *invitationRepositoryTestSuite) TestAccept_KeepsRoleOfExistingMember() {
+	s.Require().NoError(s.gormDB.Create(&domain.OrganizationMember{
+		OrganizationID: s.organization.ID, UserID: s.alice.ID, Role: "org-admin",
+	}).Error)
+	invitation := s.createInvitation("alice@example.com", "hash-1")
+
+	err := s.repo.Accept(s.ctx, invitation, &s.alice)
+	s.Require().NoError(err)
+
+	var member domain.OrganizationMember
+	s.Require().NoError(s.gormDB.First(&member, "user_id = ?", s.alice.ID).Error)
+	s.Equal("org-admin", member.Role)
+}
+
+func (s *invitationRepositoryTestSuite) TestAccept_AlreadyAccepted() {
+	invitation := s.createInvitation("alice@example.com", "hash-1")
+	s.Require().NoError(s.repo.Accept(s.ctx, invitation, &s.alice))
+
+	err := s.repo.Accept(s.ctx, invitation, &s.alice)
+
+	s.Equal(myerrors.ErrInvitationNotFound, err)
+}
+
+func (s *invitationRepositoryTestSuite) TestAccept_EmailTakenRollsBack() {
+	invitation := s.createInvitation("alice@example.com", "hash-1")
+	user := &domain.User{Name: "Other Alice", Email: "alice@example.com", Password: "hashed"}
+
+	err := s.repo.Accept(s.ctx, invitation, user)
+	s.Equal(myerrors.ErrEmailAlreadyInUse, err)
+
+	invitations, err := s.repo.GetPending(s.ctx, s.organization.ID.String())
+	s.Require().NoError(err)
+	s.Len(invitations, 1)
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/tenant"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LockoutRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+// GetAll returns the lockouts of the users the context can see, most recent failure first.
+func (r *LockoutRepositoryImpl) GetAll(ctx context.Context) ([]domain.AccountLockout, error) {
+	var lockouts []domain.AccountLockout
+
+	query := r.DB.GetDB().WithContext(ctx).Preload("User")
+	if organizationID := tenant.OrganizationID(ctx); organizationID != "" {
+		query = query.Where(
+			"user_id IN (SELECT user_id FROM organization_members WHERE organization_id = ?)", organizationID,
+		)
+	}
+
+	result := query.Order("last_failed_at DESC").Find(&lockouts)
+
+	if result.Error != nil {
+		golog.Error("Error getting account lockouts", result.Error)
+		return nil, myerrors.ErrGetLockoutFailed
+	}
+
+	return lockouts, nil
+}
+
+func (r *LockoutRepositoryImpl) GetByUserID(ctx context.Context, userID string) (*domain.AccountLockout, error) {
+	var lockout domain.AccountLockout
+
+	result := r.DB.GetDB().WithContext(ctx).Where("user_id = ?", userID).First(&lockout)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrLockoutNotFound
+		}
+		golog.Error("Error getting account lockout", result.Error)
+		return nil, myerrors.ErrGetLockoutFailed
+	}
+
+	return &lockout, nil
+}
+
+// RecordFailure counts a failed login in a single statement, so concurrent attempts are all counted.
+// The count starts again when the previous failure is older than since.
+func (r *LockoutRepositoryImpl) RecordFailure(
+	ctx context.Context, userID string, failedAt, since time.Time,
+) (*domain.AccountLockout, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, myerrors.ErrUserNotFound
+	}
+
+	result := r.DB.GetDB().WithContext(ctx).Omit("User").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"failed_attempts": gorm.Expr(
+				"CASE WHEN account_lockouts.last_failed_at < ? THEN 1 ELSE account_lockouts.failed_attempts + 1 END",
+				since,
+			),
+			"last_failed_at": failedAt,
+		}),
+	}).Create(&domain.AccountLockout{UserID: id, FailedAttempts: 1, LastFailedAt: failedAt})
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return nil, myerrors.ErrUserNotFound
+		}
+		golog.Error("Error recording failed login", result.Error)
+		return nil, myerrors.ErrSaveLockoutFailed
+	}
+
+	return r.GetByUserID(ctx, userID)
+}
+
+func (r *LockoutRepositoryImpl) Lock(ctx context.Context, userID string, until time.Time) error {
+	result := r.DB.GetDB().WithContext(ctx).Model(&domain.AccountLockout{}).
+		Where("user_id = ?", userID).Update("locked_until", until)
+
+	if result.Error != nil {
+		golog.Error("Error locking account", result.Error)
+		return myerrors.ErrSaveLockoutFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrLockoutNotFound
+	}
+
+	return nil
+}
+
+// Delete forgets the failed logins of the user and lifts the lock.
+func (r *LockoutRepositoryImpl) Delete(ctx context.Context, userID string) error {
+	result := r.DB.GetDB().WithContext(ctx).Delete(&domain.AccountLockout{}, "user_id = ?", userID)
+
+	if result.Error != nil {
+		golog.Error("Error deleting account lockout", result.Error)
+		return myerrors.ErrDeleteLockoutFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrLockoutNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/tenant"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type lockoutRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *LockoutRepositoryImpl
+	alice    domain.User
+	bob      domain.User
+}
+
+func TestLockoutRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(lockoutRepositoryTestSuite))
+}
+
+func (s *lockoutRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(
+		&domain.User{}, &domain.UserRole{}, &domain.Organization{}, &domain.OrganizationMember{},
+		&domain.AccountLockout{},
+	))
+
+	s.alice = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Alice", Email: "alice@example.com", Password: "pass1"}
+	s.bob = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Bob", Email: "bob@example.com", Password: "pass2"}
+	s.Require().NoError(gormDB.Create(&s.alice).Error)
+	s.Require().NoError(gormDB.Create(&s.bob).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &LockoutRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *lockoutRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// ==================== RecordFailure Tests ====================
+
+func (s *lockoutRepositoryTestSuite) TestRecordFailure_Counts() {
+	now := time.Now().UTC()
+
+	lockout, err := s.repo.RecordFailure(s.ctx, s.alice.ID.String(), now, now.Add(-time.Hour))
+	s.Require().NoError(err)
+	s.Equal(1, lockout.FailedAttempts)
+
+	lockout, err = s.repo.RecordFailure(s.ctx, s.alice.ID.String(), now.Add(time.Second), now.Add(-time.Hour))
+	s.Require().NoError(err)
+	s.Equal(2, lockout.FailedAttempts)
+	s.WithinDuration(now.Add(time.Second), lockout.LastFailedAt, time.Millisecond)
+}
+
+func (s *lockoutRepositoryTestSuite) TestRecordFailure_RestartsAfterWindow() {
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	_, err := s.repo.RecordFailure(s.ctx, s.alice.ID.String(), old, old.Add(-time.Hour))
+	s.Require().NoError(err)
+
+	now := time.Now().UTC()
+	lockout, err := s.repo.RecordFailure(s.ctx, s.alice.ID.String(), now, now.Add(-time.Hour))
+
+	s.Require().NoError(err)
+	s.Equal(1, lockout.FailedAttempts)
+}
+
+func (s *lockoutRepositoryTestSuite) TestRecordFailure_InvalidUserID() {
+	_, err := s.repo.RecordFailure(s.ctx, "not-a-uuid", time.Now(), time.Now())
+
+	s.ErrorIs(err, myerrors.ErrUserNotFound)
+}
+
+// ==================== Lock / Delete Tests ====================
+
+func (s *lockoutRepositoryTestSuite) TestLock() {
+	now := time.Now().UTC()
+	_, err := s.repo.RecordFailure(s.ctx, s.alice.ID.String(), now, now.Add(-time.Hour))
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.Lock(s.ctx, s.alice.ID.String(), now.Add(time.Minute)))
+
+	lockout, err := s.repo.GetByUserID(s.ctx, s.alice.ID.String())
+	s.Require().NoError(err)
+	s.True(lockout.Locked(now))
+	s.False(lockout.Locked(now.Add(time.Minute)))
+}
+
+func (s *lockoutRepositoryTestSuite) TestLock_NoFailures() {
+	err := s.repo.Lock(s.ctx, s.alice.ID.String(), time.Now())
+
+	s.ErrorIs(err, myerrors.ErrLockoutNotFound)
+}
+
+func (s *lockoutRepositoryTestSuite) TestDelete() {
+	now := time.Now().UTC()
+	_, err := s.repo.RecordFailure(s.ctx, s.alice.ID.String(), now, now.Add(-time.Hour))
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.Delete(s.ctx, s.alice.ID.String()))
+
+	_, err = s.repo.GetByUserID(s.ctx, s.alice.ID.String())
+	s.ErrorIs(err, myerrors.ErrLockoutNotFound)
+	s.ErrorIs(s.repo.Delete(s.ctx, s.alice.ID.String()), myerrors.ErrLockoutNotFound)
+}
+
+// ==================== GetAll Tests ====================
+
+func (s *lockoutRepositoryTestSuite) TestGetAll_ScopedToOrganization() {
+	now := time.Now().UTC()
+	_, err := s.repo.RecordFailure(s.ctx, s.alice.ID.String(), now, now.Add(-time.Hour))
+	s.Require().NoError(err)
+	_, err = s.repo.RecordFailure(s.ctx, s.bob.ID.String(), now.Add(time.Second), now.Add(-time.Hour))
+	s.Require().NoError(err)
+
+	organization := domain.Organization{ID: uuid.Must(uuid.NewV7()), Name: "Acme", Slug: "acme"}
+	s.Require().NoError(s.gormDB.Create(&organization).Error)
+	s.Require().NoError(s.gormDB.Omit("Organization", "User").Create(&domain.OrganizationMember{
+		OrganizationID: organization.ID, UserID: s.alice.ID, Role: domain.RoleUser,
+	}).Error)
+
+	all, err := s.repo.GetAll(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(all, 2)
+	s.Equal(s.bob.ID, all[0].UserID)
+	s.Equal("Bob", all[0].User.Name)
+
+	scoped, err := s.repo.GetAll(tenant.WithOrganization(s.ctx, organization.ID.String()))
+	s.Require().NoError(err)
+	s.Require().Len(scoped, 1)
+	s.Equal(s.alice.ID, scoped[0].UserID)
+}
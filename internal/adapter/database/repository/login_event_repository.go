@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type LoginEventRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+// GetByUserID returns a page of the login attempts of the user, newest first, and their total count.
+func (r *LoginEventRepositoryImpl) GetByUserID(
+	ctx context.Context, userID string, limit, offset int,
+) ([]domain.LoginEvent, int64, error) {
+	var events []domain.LoginEvent
+	var totalResults int64
+
+	query := r.DB.GetDB().WithContext(ctx).Model(&domain.LoginEvent{}).Where("user_id = ?", userID)
+
+	if err := query.Count(&totalResults).Error; err != nil {
+		golog.Error("Error counting login events", err)
+		return nil, 0, myerrors.ErrGetLoginEventFailed
+	}
+
+	result := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&events)
+	if result.Error != nil {
+		golog.Error("Error getting login events", result.Error)
+		return nil, 0, myerrors.ErrGetLoginEventFailed
+	}
+
+	return events, totalResults, nil
+}
+
+// CountSuccessful counts the successful logins of the user, only those from the device if a fingerprint is given.
+func (r *LoginEventRepositoryImpl) CountSuccessful(
+	ctx context.Context, userID, deviceFingerprint string,
+) (int64, error) {
+	var count int64
+
+	query := r.DB.GetDB().WithContext(ctx).Model(&domain.LoginEvent{}).Where("user_id = ? AND success = ?", userID, true)
+	if deviceFingerprint != "" {
+		query = query.Where("device_fingerprint = ?", deviceFingerprint)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		golog.Error("Error counting successful logins", err)
+		return 0, myerrors.ErrGetLoginEventFailed
+	}
+
+	return count, nil
+}
+
+func (r *LoginEventRepositoryImpl) Create(ctx context.Context, event *domain.LoginEvent) (*domain.LoginEvent, error) {
+	event.ID = uuid.Must(uuid.NewV7())
+
+	result := r.DB.GetDB().WithContext(ctx).Create(event)
+	if result.Error != nil {
+		golog.Error("Error creating login event", result.Error)
+		return nil, myerrors.ErrCreateLoginEventFailed
+	}
+
+	return event, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type loginEventRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	repo     *LoginEventRepositoryImpl
+	alice    domain.User
+}
+
+func TestLoginEventRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(loginEventRepositoryTestSuite))
+}
+
+func (s *loginEventRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.UserRole{}, &domain.LoginEvent{}))
+
+	s.alice = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Alice", Email: "alice@example.com", Password: "pass1"}
+	s.Require().NoError(gormDB.Create(&s.alice).Error)
+
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &LoginEventRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *loginEventRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *loginEventRepositoryTestSuite) record(userID *uuid.UUID, success bool, fingerprint string) {
+	_, err := s.repo.Create(s.ctx, &domain.LoginEvent{
+		UserID:            userID,
+		Email:             s.alice.Email,
+		Method:            domain.LoginMethodPassword,
+		Success:           success,
+		DeviceFingerprint: fingerprint,
+	})
+	s.Require().NoError(err)
+}
+
+func (s *loginEventRepositoryTestSuite) TestGetByUserID_Paginated() {
+	s.record(&s.alice.ID, true, "laptop")
+	s.record(&s.alice.ID, false, "phone")
+	s.record(&s.alice.ID, true, "phone")
+	s.record(nil, false, "phone")
+
+	events, total, err := s.repo.GetByUserID(s.ctx, s.alice.ID.String(), 2, 0)
+
+	s.Require().NoError(err)
+	s.Equal(int64(3), total)
+	s.Require().Len(events, 2)
+	s.True(events[0].Success)
+	s.Equal("phone", events[0].DeviceFingerprint)
+
+	events, _, err = s.repo.GetByUserID(s.ctx, s.alice.ID.String(), 2, 2)
+
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal("laptop", events[0].DeviceFingerprint)
+}
+
+func (s *loginEventRepositoryTestSuite) TestCountSuccessful() {
+	s.record(&s.alice.ID, true, "laptop")
+	s.record(&s.alice.ID, false, "phone")
+
+	all, err := s.repo.CountSuccessful(s.ctx, s.alice.ID.String(), "")
+	s.Require().NoError(err)
+	s.Equal(int64(1), all)
+
+	phone, err := s.repo.CountSuccessful(s.ctx, s.alice.ID.String(), "phone")
+	s.Require().NoError(err)
+	s.Zero(phone)
+}
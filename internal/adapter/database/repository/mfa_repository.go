@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+type MFARepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *MFARepositoryImpl) SaveTOTPSecret(ctx context.Context, userID, secret string) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", userID).
+		Update("totp_secret", secret)
+
+	if result.Error != nil {
+		golog.Error("Error saving totp secret", result.Error)
+		return myerrors.ErrSaveMFAFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *MFARepositoryImpl) EnableTOTP(
+	ctx context.Context, userID string, step int64, recoveryCodes []domain.RecoveryCode,
+) error {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The totp_enabled guard keeps two concurrent confirmations from issuing two sets of recovery codes
+		result := tx.Model(&domain.User{}).
+			Where("id = ? AND totp_enabled = ?", userID, false).
+			Updates(map[string]any{"totp_enabled": true, "totp_last_step": step})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return myerrors.ErrMFAAlreadyEnabled
+		}
+
+		if err := tx.Delete(&domain.RecoveryCode{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		for i := range recoveryCodes {
+			recoveryCodes[i].ID = uuid.Must(uuid.NewV7())
+		}
+		return tx.Create(&recoveryCodes).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, myerrors.ErrMFAAlreadyEnabled) {
+			return err
+		}
+		golog.Error("Error enabling totp", err)
+		return myerrors.ErrSaveMFAFailed
+	}
+
+	return nil
+}
+
+func (r *MFARepositoryImpl) UseTOTPStep(ctx context.Context, userID string, step int64) error {
+	// Only a later time step than the last accepted one may be used, so each code works once
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+
+	if result.Error != nil {
+		golog.Error("Error using totp step", result.Error)
+		return myerrors.ErrUseMFACodeFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (r *MFARepositoryImpl) UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+
+	if result.Error != nil {
+		golog.Error("Error using recovery code", result.Error)
+		return myerrors.ErrUseMFACodeFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrInvalidMFACode
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type mfaRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *MFARepositoryImpl
+	user     *domain.User
+}
+
+func TestMFARepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(mfaRepositoryTestSuite))
+}
+
+func (s *mfaRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.RecoveryCode{}))
+
+	s.user = &domain.User{
+		ID:       uuid.Must(uuid.NewV7()),
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "hashed",
+	}
+	s.Require().NoError(gormDB.Create(s.user).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &MFARepositoryImpl{DB: s.mockDB}
+}
+
+func (s *mfaRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *mfaRepositoryTestSuite) reloadUser() *domain.User {
+	var user domain.User
+	s.Require().NoError(s.gormDB.First(&user, "id = ?", s.user.ID).Error)
+	return &user
+}
+
+// ==================== SaveTOTPSecret Tests ====================
+
+func (s *mfaRepositoryTestSuite) TestSaveTOTPSecret_Success() {
+	s.NoError(s.repo.SaveTOTPSecret(s.ctx, s.user.ID.String(), "JBSWY3DPEHPK3PXP"))
+
+	user := s.reloadUser()
+	s.Equal("JBSWY3DPEHPK3PXP", user.TOTPSecret)
+	s.False(user.TOTPEnabled)
+}
+
+func (s *mfaRepositoryTestSuite) TestSaveTOTPSecret_UserNotFound() {
+	err := s.repo.SaveTOTPSecret(s.ctx, uuid.Must(uuid.NewV7()).String(), "JBSWY3DPEHPK3PXP")
+
+	s.True(errors.Is(err, myerrors.ErrUserNotFound))
+}
+
+// ==================== EnableTOTP Tests ====================
+
+func (s *mfaRepositoryTestSuite) TestEnableTOTP_Success() {
+	err := s.repo.EnableTOTP(s.ctx, s.user.ID.String(), 42, []domain.RecoveryCode{
+		{UserID: s.user.ID, CodeHash: "hash-1"},
+		{UserID: s.user.ID, CodeHash: "hash-2"},
+	})
+	s.Require().NoError(err)
+
+	user := s.reloadUser()
+	s.True(user.TOTPEnabled)
+	s.Equal(int64(42), user.TOTPLastStep)
+
+	var count int64
+	s.Require().NoError(s.gormDB.Model(&domain.RecoveryCode{}).Where("user_id = ?", s.user.ID).Count(&count).Error)
+	s.Equal(int64(2), count)
+}
+
+func (s *mfaRepositoryTestSuite) TestEnableTOTP_AlreadyEnabled() {
+	s.Require().NoError(s.repo.EnableTOTP(s.ctx, s.user.ID.String(), 42, []domain.RecoveryCode{
+		{UserID: s.user.ID, CodeHash: "hash-1"},
+	}))
+
+	err := s.repo.EnableTOTP(s.ctx, s.user.ID.String(), 43, []domain.RecoveryCode{
+		{UserID: s.user.ID, CodeHash: "hash-2"},
+	})
+	s.True(errors.Is(err, myerrors.ErrMFAAlreadyEnabled))
+
+	var count int64
+	s.Require().NoError(s.gormDB.Model(&domain.RecoveryCode{}).Where("code_hash = ?", "hash-1").Count(&count).Error)
+	s.Equal(int64(1), count)
+}
+
+func (s *mfaRepositoryTestSuite) TestEnableTOTP_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	err = s.repo.EnableTOTP(s.ctx, s.user.ID.String(), 42, nil)
+	s.True(errors.Is(err, myerrors.ErrSaveMFAFailed))
+}
+
+// ==================== UseTOTPStep Tests ====================
+
+func (s *mfaRepositoryTestSuite) TestUseTOTPStep_RejectsReplay() {
+	s.NoError(s.repo.UseTOTPStep(s.ctx, s.user.ID.String(), 100))
+
+	err := s.repo.UseTOTPStep(s.ctx, s.user.ID.String(), 100)
+	s.True(errors.Is(err, myerrors.ErrInvalidMFACode))
+
+	err = s.repo.UseTOTPStep(s.ctx, s.user.ID.String(), 99)
+	s.True(errors.Is(err, myerrors.ErrInvalidMFACode))
+
+	s.NoError(s.repo.UseTOTPStep(s.ctx, s.user.ID.String(), 101))
+	s.Equal(int64(101), s.reloadUser().TOTPLastStep)
+}
+
+// ==================== UseRecoveryCode Tests ====================
+
+func (s *mfaRepositoryTestSuite) TestUseRecoveryCode_SingleUse() {
+	s.Require().NoError(s.gormDB.Create(&domain.RecoveryCode{
+		ID:       uuid.Must(uuid.NewV7()),
+		UserID:   s.user.ID,
+		CodeHash: "hash-1",
+	}).Error)
+
+	s.NoError(s.repo.UseRecoveryCode(s.ctx, s.user.ID.String(), "hash-1", time.Now()))
+
+	err := s.repo.UseRecoveryCode(s.ctx, s.user.ID.String(), "hash-1", time.Now())
+	s.True(errors.Is(err, myerrors.ErrInvalidMFACode))
+}
+
+func (s *mfaRepositoryTestSuite) TestUseRecoveryCode_OtherUser() {
+	s.Require().NoError(s.gormDB.Create(&domain.RecoveryCode{
+		ID:       uuid.Must(uuid.NewV7()),
+		UserID:   s.user.ID,
+		CodeHash: "hash-1",
+	}).Error)
+
+	err := s.repo.UseRecoveryCode(s.ctx, uuid.Must(uuid.NewV7()).String(), "hash-1", time.Now())
+	s.True(errors.Is(err, myerrors.ErrInvalidMFACode))
+}
+
+func (s *mfaRepositoryTestSuite) TestUseRecoveryCode_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	err = s.repo.UseRecoveryCode(s.ctx, s.user.ID.String(), "hash-1", time.Now())
+	s.True(errors.Is(err, myerrors.ErrUseMFACodeFailed))
+}
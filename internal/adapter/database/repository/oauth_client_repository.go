@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+type OAuthClientRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *OAuthClientRepositoryImpl) Create(
+	ctx context.Context, client *domain.OAuthClient,
+) (*domain.OAuthClient, error) {
+	client.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(client)
+	if result.Error != nil {
+		golog.Error("Error creating oauth client", result.Error)
+		return nil, myerrors.ErrSaveOAuthClientFailed
+	}
+	return client, nil
+}
+
+func (r *OAuthClientRepositoryImpl) GetByID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	var client domain.OAuthClient
+
+	result := r.DB.GetDB().WithContext(ctx).First(&client, "id = ?", clientID)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrOAuthClientNotFound
+		}
+		golog.Error("Error getting oauth client by id", result.Error)
+		return nil, myerrors.ErrGetOAuthClientFailed
+	}
+
+	return &client, nil
+}
+
+func (r *OAuthClientRepositoryImpl) GetAll(ctx context.Context) ([]domain.OAuthClient, error) {
+	var clients []domain.OAuthClient
+
+	result := r.DB.GetDB().WithContext(ctx).Order("created_at ASC").Find(&clients)
+
+	if result.Error != nil {
+		golog.Error("Error getting oauth clients", result.Error)
+		return nil, myerrors.ErrGetOAuthClientFailed
+	}
+
+	return clients, nil
+}
+
+func (r *OAuthClientRepositoryImpl) Delete(ctx context.Context, clientID string) error {
+	result := r.DB.GetDB().WithContext(ctx).Delete(&domain.OAuthClient{}, "id = ?", clientID)
+
+	if result.Error != nil {
+		golog.Error("Error deleting oauth client", result.Error)
+		return myerrors.ErrDeleteOAuthClientFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrOAuthClientNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type oauthClientRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *OAuthClientRepositoryImpl
+}
+
+func TestOAuthClientRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(oauthClientRepositoryTestSuite))
+}
+
+func (s *oauthClientRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.OAuthClient{}))
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &OAuthClientRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *oauthClientRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *oauthClientRepositoryTestSuite) createClient(name string) *domain.OAuthClient {
+	client, err := s.repo.Create(s.ctx, &domain.OAuthClient{
+		Name:         name,
+		SecretHash:   "secret-hash",
+		RedirectURIs: "https://app.example.com/callback",
+		GrantTypes:   "authorization_code,refresh_token",
+		Scopes:       "profile",
+	})
+	s.Require().NoError(err)
+	return client
+}
+
+// ==================== Create Tests ====================
+
+func (s *oauthClientRepositoryTestSuite) TestCreate_Success() {
+	client := s.createClient("Mobile App")
+
+	s.NotEqual(uuid.Nil, client.ID)
+	s.False(client.CreatedAt.IsZero())
+}
+
+func (s *oauthClientRepositoryTestSuite) TestCreate_DBError() {
+	sqlDB, _ := s.gormDB.DB()
+	sqlDB.Close()
+
+	client, err := s.repo.Create(s.ctx, &domain.OAuthClient{Name: "Mobile App"})
+
+	s.Nil(client)
+	s.Equal(myerrors.ErrSaveOAuthClientFailed, err)
+}
+
+// ==================== GetByID Tests ====================
+
+func (s *oauthClientRepositoryTestSuite) TestGetByID_Success() {
+	created := s.createClient("Mobile App")
+
+	client, err := s.repo.GetByID(s.ctx, created.ID.String())
+
+	s.Require().NoError(err)
+	s.Equal("Mobile App", client.Name)
+	s.Equal([]string{"authorization_code", "refresh_token"}, client.GrantTypeList())
+}
+
+func (s *oauthClientRepositoryTestSuite) TestGetByID_NotFound() {
+	client, err := s.repo.GetByID(s.ctx, uuid.NewString())
+
+	s.Nil(client)
+	s.Equal(myerrors.ErrOAuthClientNotFound, err)
+}
+
+func (s *oauthClientRepositoryTestSuite) TestGetByID_DBError() {
+	sqlDB, _ := s.gormDB.DB()
+	sqlDB.Close()
+
+	client, err := s.repo.GetByID(s.ctx, uuid.NewString())
+
+	s.Nil(client)
+	s.Equal(myerrors.ErrGetOAuthClientFailed, err)
+}
+
+// ==================== GetAll Tests ====================
+
+func (s *oauthClientRepositoryTestSuite) TestGetAll_Success() {
+	s.createClient("Mobile App")
+	s.createClient("Partner")
+
+	clients, err := s.repo.GetAll(s.ctx)
+
+	s.Require().NoError(err)
+	s.Len(clients, 2)
+	s.Equal("Mobile App", clients[0].Name)
+}
+
+func (s *oauthClientRepositoryTestSuite) TestGetAll_DBError() {
+	sqlDB, _ := s.gormDB.DB()
+	sqlDB.Close()
+
+	clients, err := s.repo.GetAll(s.ctx)
+
+	s.Nil(clients)
+	s.Equal(myerrors.ErrGetOAuthClientFailed, err)
+}
+
+// ==================== Delete Tests ====================
+
+func (s *oauthClientRepositoryTestSuite) TestDelete_Success() {
+	client := s.createClient("Mobile App")
+
+	s.Require().NoError(s.repo.Delete(s.ctx, client.ID.String()))
+
+	_, err := s.repo.GetByID(s.ctx, client.ID.String())
+	s.Equal(myerrors.ErrOAuthClientNotFound, err)
+}
+
+func (s *oauthClientRepositoryTestSuite) TestDelete_NotFound() {
+	s.Equal(myerrors.ErrOAuthClientNotFound, s.repo.Delete(s.ctx, uuid.NewString()))
+}
+
+func (s *oauthClientRepositoryTestSuite) TestDelete_DBError() {
+	sqlDB, _ := s.gormDB.DB()
+	sqlDB.Close()
+
+	s.Equal(myerrors.ErrDeleteOAuthClientFailed, s.repo.Delete(s.ctx, uuid.NewString()))
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+type OAuthStateRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *OAuthStateRepositoryImpl) Create(ctx context.Context, state *domain.OAuthState) (*domain.OAuthState, error) {
+	state.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(state)
+	if result.Error != nil {
+		golog.Error("Error creating oauth state", result.Error)
+		return nil, myerrors.ErrSaveOAuthStateFailed
+	}
+	return state, nil
+}
+
+// Consume deletes the unexpired state of the provider and returns it. When two callbacks race
+// with the same state only the one whose delete removes the row gets it.
+func (r *OAuthStateRepositoryImpl) Consume(
+	ctx context.Context, stateHash, provider string, now time.Time,
+) (*domain.OAuthState, error) {
+	var state domain.OAuthState
+
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&state, "state_hash = ? AND provider = ? AND expires_at > ?",
+			stateHash, provider, now).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&domain.OAuthState{}, "id = ?", state.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrInvalidOAuthState
+		}
+		golog.Error("Error consuming oauth state", err)
+		return nil, myerrors.ErrUseOAuthStateFailed
+	}
+
+	return &state, nil
+}
+
+func (r *OAuthStateRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.DB.GetDB().WithContext(ctx).
+		Delete(&domain.OAuthState{}, "expires_at <= ?", now)
+
+	if result.Error != nil {
+		golog.Error("Error deleting expired oauth states", result.Error)
+		return 0, myerrors.ErrUseOAuthStateFailed
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type oauthStateRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *OAuthStateRepositoryImpl
+}
+
+func TestOAuthStateRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(oauthStateRepositoryTestSuite))
+}
+
+func (s *oauthStateRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.OAuthState{}))
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &OAuthStateRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *oauthStateRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *oauthStateRepositoryTestSuite) createState(stateHash string, expiresAt time.Time) {
+	_, err := s.repo.Create(s.ctx, &domain.OAuthState{
+		StateHash:    stateHash,
+		Provider:     "google",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    expiresAt,
+	})
+	s.Require().NoError(err)
+}
+
+// ==================== Consume Tests ====================
+
+func (s *oauthStateRepositoryTestSuite) TestConsume_SingleUse() {
+	s.createState("hash-1", time.Now().Add(time.Minute))
+
+	state, err := s.repo.Consume(s.ctx, "hash-1", "google", time.Now())
+	s.Require().NoError(err)
+	s.Equal("nonce", state.Nonce)
+	s.Equal("verifier", state.CodeVerifier)
+
+	_, err = s.repo.Consume(s.ctx, "hash-1", "google", time.Now())
+	s.True(errors.Is(err, myerrors.ErrInvalidOAuthState))
+}
+
+func (s *oauthStateRepositoryTestSuite) TestConsume_OtherProvider() {
+	s.createState("hash-1", time.Now().Add(time.Minute))
+
+	_, err := s.repo.Consume(s.ctx, "hash-1", "github", time.Now())
+	s.True(errors.Is(err, myerrors.ErrInvalidOAuthState))
+}
+
+func (s *oauthStateRepositoryTestSuite) TestConsume_Expired() {
+	s.createState("hash-1", time.Now().Add(-time.Minute))
+
+	_, err := s.repo.Consume(s.ctx, "hash-1", "google", time.Now())
+	s.True(errors.Is(err, myerrors.ErrInvalidOAuthState))
+}
+
+func (s *oauthStateRepositoryTestSuite) TestConsume_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	_, err = s.repo.Consume(s.ctx, "hash-1", "google", time.Now())
+	s.True(errors.Is(err, myerrors.ErrUseOAuthStateFailed))
+}
+
+// ==================== DeleteExpired Tests ====================
+
+func (s *oauthStateRepositoryTestSuite) TestDeleteExpired() {
+	s.createState("hash-1", time.Now().Add(-time.Minute))
+	s.createState("hash-2", time.Now().Add(time.Minute))
+
+	deleted, err := s.repo.DeleteExpired(s.ctx, time.Now())
+	s.Require().NoError(err)
+	s.Equal(int64(1), deleted)
+
+	_, err = s.repo.Consume(s.ctx, "hash-2", "google", time.Now())
+	s.NoError(err)
+}
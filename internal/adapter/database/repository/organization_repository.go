@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *OrganizationRepositoryImpl) GetAll(ctx context.Context) ([]domain.Organization, error) {
+	var organizations []domain.Organization
+
+	result := r.DB.GetDB().WithContext(ctx).Order("name ASC").Find(&organizations)
+
+	if result.Error != nil {
+		golog.Error("Error getting organizations", result.Error)
+		return nil, myerrors.ErrGetOrganizationFailed
+	}
+
+	return organizations, nil
+}
+
+func (r *OrganizationRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.Organization, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, myerrors.ErrOrganizationNotFound
+	}
+
+	var organization domain.Organization
+
+	result := r.DB.GetDB().WithContext(ctx).First(&organization, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrOrganizationNotFound
+		}
+		golog.Error("Error getting organization by id", result.Error)
+		return nil, myerrors.ErrGetOrganizationFailed
+	}
+
+	return &organization, nil
+}
+
+// Create stores the organization together with its first members, if any.
+func (r *OrganizationRepositoryImpl) Create(
+	ctx context.Context, organization *domain.Organization,
+) (*domain.Organization, error) {
+	organization.ID = uuid.Must(uuid.NewV7())
+
+	result := r.DB.GetDB().WithContext(ctx).Omit("Members.Organization", "Members.User").Create(organization)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return nil, myerrors.ErrOrganizationAlreadyExists
+		}
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return nil, myerrors.ErrUserNotFound
+		}
+		golog.Error("Error creating organization", result.Error)
+		return nil, myerrors.ErrSaveOrganizationFailed
+	}
+
+	return organization, nil
+}
+
+// GetMemberships returns the organizations of the user, oldest membership first.
+func (r *OrganizationRepositoryImpl) GetMemberships(
+	ctx context.Context, userID string,
+) ([]domain.OrganizationMember, error) {
+	var members []domain.OrganizationMember
+
+	result := r.DB.GetDB().WithContext(ctx).Preload("Organization").
+		Where("user_id = ?", userID).Order("created_at ASC").Find(&members)
+
+	if result.Error != nil {
+		golog.Error("Error getting memberships", result.Error)
+		return nil, myerrors.ErrGetOrganizationFailed
+	}
+
+	return members, nil
+}
+
+func (r *OrganizationRepositoryImpl) GetMembership(
+	ctx context.Context, organizationID, userID string,
+) (*domain.OrganizationMember, error) {
+	if _, err := uuid.Parse(organizationID); err != nil {
+		return nil, myerrors.ErrMemberNotFound
+	}
+
+	var member domain.OrganizationMember
+
+	result := r.DB.GetDB().WithContext(ctx).
+		First(&member, "organization_id = ? AND user_id = ?", organizationID, userID)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrMemberNotFound
+		}
+		golog.Error("Error getting membership", result.Error)
+		return nil, myerrors.ErrGetOrganizationFailed
+	}
+
+	return &member, nil
+}
+
+// GetMembers returns the members of the organization with their users.
+func (r *OrganizationRepositoryImpl) GetMembers(
+	ctx context.Context, organizationID string,
+) ([]domain.OrganizationMember, error) {
+	var members []domain.OrganizationMember
+
+	result := r.DB.GetDB().WithContext(ctx).Preload("User").
+		Where("organization_id = ?", organizationID).Order("created_at ASC").Find(&members)
+
+	if result.Error != nil {
+		golog.Error("Error getting organization members", result.Error)
+		return nil, myerrors.ErrGetOrganizationFailed
+	}
+
+	return members, nil
+}
+
+// SetMember adds the user to the organization, or changes the role of an existing member.
+func (r *OrganizationRepositoryImpl) SetMember(ctx context.Context, member *domain.OrganizationMember) error {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&domain.User{}, "id = ?", member.UserID).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit("Organization", "User").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role"}),
+		}).Create(member).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return myerrors.ErrUserNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return myerrors.ErrInvalidRole
+		}
+		golog.Error("Error setting organization member", err)
+		return myerrors.ErrSaveOrganizationFailed
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepositoryImpl) DeleteMember(ctx context.Context, organizationID, userID string) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Delete(&domain.OrganizationMember{}, "organization_id = ? AND user_id = ?", organizationID, userID)
+
+	if result.Error != nil {
+		golog.Error("Error deleting organization member", result.Error)
+		return myerrors.ErrDeleteMemberFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrMemberNotFound
+	}
+
+	return nil
+}
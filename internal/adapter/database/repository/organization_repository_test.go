@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type organizationRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *OrganizationRepositoryImpl
+	alice    domain.User
+	bob      domain.User
+}
+
+func TestOrganizationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(organizationRepositoryTestSuite))
+}
+
+func (s *organizationRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.Organization{}, &domain.OrganizationMember{}))
+
+	s.alice = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Alice", Email: "alice@example.com", Password: "pass1"}
+	s.bob = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Bob", Email: "bob@example.com", Password: "pass2"}
+	s.Require().NoError(gormDB.Create(&[]domain.User{s.alice, s.bob}).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &OrganizationRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *organizationRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *organizationRepositoryTestSuite) createOrganization(slug string) *domain.Organization {
+	organization, err := s.repo.Create(s.ctx, &domain.Organization{Name: slug, Slug: slug})
+	s.Require().NoError(err)
+	return organization
+}
+
+// ==================== Create Tests ====================
+
+func (s *organizationRepositoryTestSuite) TestCreate_Success() {
+	created := s.createOrganization("acme")
+
+	found, err := s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.Equal("acme", found.Slug)
+}
+
+func (s *organizationRepositoryTestSuite) TestCreate_DuplicateSlug() {
+	s.createOrganization("acme")
+
+	_, err := s.repo.Create(s.ctx, &domain.Organization{Name: "Acme Corp", Slug: "acme"})
+
+	s.Equal(myerrors.ErrOrganizationAlreadyExists, err)
+}
+
+func (s *organizationRepositoryTestSuite) TestGetByID_NotFound() {
+	_, err := s.repo.GetByID(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.Equal(myerrors.ErrOrganizationNotFound, err)
+
+	_, err = s.repo.GetByID(s.ctx, "not-a-uuid")
+	s.Equal(myerrors.ErrOrganizationNotFound, err)
+}
+
+// ==================== Member Tests ====================
+
+func (s *organizationRepositoryTestSuite) TestSetMember_AddsAndChangesRole() {
+	acme := s.createOrganization("acme")
+
+	err := s.repo.SetMember(s.ctx, &domain.OrganizationMember{OrganizationID: acme.ID, UserID: s.alice.ID, Role: "user"})
+	s.Require().NoError(err)
+
+	err = s.repo.SetMember(s.ctx, &domain.OrganizationMember{OrganizationID: acme.ID, UserID: s.alice.ID, Role: "org-admin"})
+	s.Require().NoError(err)
+
+	member, err := s.repo.GetMembership(s.ctx, acme.ID.String(), s.alice.ID.String())
+	s.Require().NoError(err)
+	s.Equal("org-admin", member.Role)
+
+	members, err := s.repo.GetMembers(s.ctx, acme.ID.String())
+	s.Require().NoError(err)
+	s.Require().Len(members, 1)
+	s.Equal("Alice", members[0].User.Name)
+}
+
+func (s *organizationRepositoryTestSuite) TestSetMember_UnknownUser() {
+	acme := s.createOrganization("acme")
+
+	err := s.repo.SetMember(s.ctx, &domain.OrganizationMember{
+		OrganizationID: acme.ID, UserID: uuid.Must(uuid.NewV7()), Role: "user",
+	})
+
+	s.Equal(myerrors.ErrUserNotFound, err)
+}
+
+func (s *organizationRepositoryTestSuite) TestGetMemberships_OldestFirst() {
+	acme := s.createOrganization("acme")
+	globex := s.createOrganization("globex")
+
+	s.Require().NoError(s.repo.SetMember(s.ctx, &domain.OrganizationMember{
+		OrganizationID: globex.ID, UserID: s.alice.ID, Role: "user", CreatedAt: time.Now().Add(-time.Hour),
+	}))
+	s.Require().NoError(s.repo.SetMember(s.ctx, &domain.OrganizationMember{OrganizationID: acme.ID, UserID: s.alice.ID, Role: "user"}))
+
+	memberships, err := s.repo.GetMemberships(s.ctx, s.alice.ID.String())
+	s.Require().NoError(err)
+	s.Require().Len(memberships, 2)
+	s.Equal("globex", memberships[0].Organization.Slug)
+	s.Equal("acme", memberships[1].Organization.Slug)
+
+	memberships, err = s.repo.GetMemberships(s.ctx, s.bob.ID.String())
+	s.Require().NoError(err)
+	s.Empty(memberships)
+}
+
+func (s *organizationRepositoryTestSuite) TestDeleteMember() {
+	acme := s.createOrganization("acme")
+	s.Require().NoError(s.repo.SetMember(s.ctx, &domain.OrganizationMember{OrganizationID: acme.ID, UserID: s.alice.ID, Role: "user"}))
+
+	s.Require().NoError(s.repo.DeleteMember(s.ctx, acme.ID.String(), s.alice.ID.String()))
+
+	_, err := s.repo.GetMembership(s.ctx, acme.ID.String(), s.alice.ID.String())
+	s.Equal(myerrors.ErrMemberNotFound, err)
+
+	err = s.repo.DeleteMember(s.ctx, acme.ID.String(), s.alice.ID.String())
+	s.Equal(myerrors.ErrMemberNotFound, err)
+}
+
+func (s *organizationRepositoryTestSuite) TestCreate_WithAdmin() {
+	created, err := s.repo.Create(s.ctx, &domain.Organization{
+		Name:    "Acme",
+		Slug:    "acme",
+		Members: []domain.OrganizationMember{{UserID: s.alice.ID, Role: "org-admin"}},
+	})
+	s.Require().NoError(err)
+
+	member, err := s.repo.GetMembership(s.ctx, created.ID.String(), s.alice.ID.String())
+	s.Require().NoError(err)
+	s.Equal("org-admin", member.Role)
+}
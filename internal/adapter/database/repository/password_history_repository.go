@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type PasswordHistoryRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *PasswordHistoryRepositoryImpl) GetRecent(
+	ctx context.Context, userID string, limit int,
+) ([]domain.PasswordHistory, error) {
+	var entries []domain.PasswordHistory
+
+	result := r.DB.GetDB().WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries)
+	if result.Error != nil {
+		golog.Error("Error getting password history", result.Error)
+		return nil, myerrors.ErrGetPasswordHistoryFailed
+	}
+
+	return entries, nil
+}
+
+func (r *PasswordHistoryRepositoryImpl) Create(ctx context.Context, entry *domain.PasswordHistory) error {
+	entry.ID = uuid.Must(uuid.NewV7())
+
+	result := r.DB.GetDB().WithContext(ctx).Create(entry)
+	if result.Error != nil {
+		golog.Error("Error creating password history", result.Error)
+		return myerrors.ErrCreatePasswordHistoryFailed
+	}
+
+	return nil
+}
+
+func (r *PasswordHistoryRepositoryImpl) Prune(ctx context.Context, userID string, keep int) error {
+	db := r.DB.GetDB().WithContext(ctx)
+
+	newest := db.Model(&domain.PasswordHistory{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(keep)
+
+	result := db.Where("user_id = ? AND id NOT IN (?)", userID, newest).Delete(&domain.PasswordHistory{})
+	if result.Error != nil {
+		golog.Error("Error pruning password history", result.Error)
+		return myerrors.ErrDeletePasswordHistoryFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type passwordHistoryRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	repo     *PasswordHistoryRepositoryImpl
+	alice    domain.User
+}
+
+func TestPasswordHistoryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(passwordHistoryRepositoryTestSuite))
+}
+
+func (s *passwordHistoryRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.UserRole{}, &domain.PasswordHistory{}))
+
+	s.alice = domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Alice", Email: "alice@example.com", Password: "pass1"}
+	s.Require().NoError(gormDB.Create(&s.alice).Error)
+
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &PasswordHistoryRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *passwordHistoryRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// Helper to store the passwords of alice, oldest first
+func (s *passwordHistoryRepositoryTestSuite) store(passwords ...string) {
+	for i, password := range passwords {
+		entry := &domain.PasswordHistory{
+			UserID:    s.alice.ID,
+			Password:  password,
+			CreatedAt: time.Now().Add(time.Duration(i-len(passwords)) * time.Minute),
+		}
+		s.Require().NoError(s.repo.Create(s.ctx, entry))
+	}
+}
+
+func (s *passwordHistoryRepositoryTestSuite) TestGetRecent_NewestFirst() {
+	s.store("hash1", "hash2", "hash3")
+
+	entries, err := s.repo.GetRecent(s.ctx, s.alice.ID.String(), 2)
+
+	s.Require().NoError(err)
+	s.Require().Len(entries, 2)
+	s.Equal("hash3", entries[0].Password)
+	s.Equal("hash2", entries[1].Password)
+}
+
+func (s *passwordHistoryRepositoryTestSuite) TestPrune_KeepsNewest() {
+	s.store("hash1", "hash2", "hash3", "hash4")
+
+	s.Require().NoError(s.repo.Prune(s.ctx, s.alice.ID.String(), 2))
+
+	entries, err := s.repo.GetRecent(s.ctx, s.alice.ID.String(), 10)
+	s.Require().NoError(err)
+	s.Require().Len(entries, 2)
+	s.Equal("hash4", entries[0].Password)
+	s.Equal("hash3", entries[1].Password)
+}
+
+func (s *passwordHistoryRepositoryTestSuite) TestPrune_KeepNone() {
+	s.store("hash1", "hash2")
+
+	s.Require().NoError(s.repo.Prune(s.ctx, s.alice.ID.String(), 0))
+
+	entries, err := s.repo.GetRecent(s.ctx, s.alice.ID.String(), 10)
+	s.Require().NoError(err)
+	s.Empty(entries)
+}
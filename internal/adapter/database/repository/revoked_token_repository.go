@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type RevokedTokenRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *RevokedTokenRepositoryImpl) Create(
+	ctx context.Context, revokedToken *domain.RevokedToken,
+) (*domain.RevokedToken, error) {
+	revokedToken.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(revokedToken)
+	if result.Error != nil {
+		golog.Error("Error creating revoked token", result.Error)
+		return nil, myerrors.ErrRevokeTokenFailed
+	}
+	return revokedToken, nil
+}
+
+func (r *RevokedTokenRepositoryImpl) GetActive(ctx context.Context, now time.Time) ([]domain.RevokedToken, error) {
+	var revokedTokens []domain.RevokedToken
+
+	result := r.DB.GetDB().WithContext(ctx).
+		Find(&revokedTokens, "expires_at > ?", now)
+
+	if result.Error != nil {
+		golog.Error("Error getting active revoked tokens", result.Error)
+		return nil, myerrors.ErrGetRevokedTokensFailed
+	}
+
+	return revokedTokens, nil
+}
+
+func (r *RevokedTokenRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.DB.GetDB().WithContext(ctx).
+		Delete(&domain.RevokedToken{}, "expires_at <= ?", now)
+
+	if result.Error != nil {
+		golog.Error("Error deleting expired revoked tokens", result.Error)
+		return 0, myerrors.ErrDeleteRevokedTokensFailed
+	}
+
+	return result.RowsAffected, nil
+}
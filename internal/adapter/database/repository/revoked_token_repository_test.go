@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type revokedTokenRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *RevokedTokenRepositoryImpl
+}
+
+func TestRevokedTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(revokedTokenRepositoryTestSuite))
+}
+
+func (s *revokedTokenRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.RevokedToken{}))
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &RevokedTokenRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *revokedTokenRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *revokedTokenRepositoryTestSuite) TestCreate_Success() {
+	jti := uuid.NewString()
+	now := time.Now().UTC()
+
+	revokedToken, err := s.repo.Create(s.ctx, &domain.RevokedToken{JTI: &jti, RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	s.Require().NoError(err)
+	s.NotEqual(uuid.Nil, revokedToken.ID)
+
+	var stored domain.RevokedToken
+	s.Require().NoError(s.gormDB.First(&stored, "id = ?", revokedToken.ID).Error)
+	s.Require().NotNil(stored.JTI)
+	s.Equal(jti, *stored.JTI)
+	s.Nil(stored.SessionID)
+	s.Nil(stored.UserID)
+}
+
+func (s *revokedTokenRepositoryTestSuite) TestCreate_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	revokedToken, err := s.repo.Create(s.ctx, &domain.RevokedToken{ExpiresAt: time.Now()})
+	s.Nil(revokedToken)
+	s.True(errors.Is(err, myerrors.ErrRevokeTokenFailed))
+}
+
+func (s *revokedTokenRepositoryTestSuite) TestGetActiveAndDeleteExpired() {
+	now := time.Now().UTC()
+	sessionID := uuid.Must(uuid.NewV7())
+	userID := uuid.Must(uuid.NewV7())
+
+	_, err := s.repo.Create(s.ctx, &domain.RevokedToken{SessionID: &sessionID, RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	s.Require().NoError(err)
+	_, err = s.repo.Create(s.ctx, &domain.RevokedToken{UserID: &userID, RevokedAt: now, ExpiresAt: now.Add(-time.Minute)})
+	s.Require().NoError(err)
+
+	active, err := s.repo.GetActive(s.ctx, now)
+	s.Require().NoError(err)
+	s.Require().Len(active, 1)
+	s.Equal(sessionID, *active[0].SessionID)
+
+	deleted, err := s.repo.DeleteExpired(s.ctx, now)
+	s.NoError(err)
+	s.Equal(int64(1), deleted)
+
+	var count int64
+	s.Require().NoError(s.gormDB.Model(&domain.RevokedToken{}).Count(&count).Error)
+	s.Equal(int64(1), count)
+}
+
+func (s *revokedTokenRepositoryTestSuite) TestGetActive_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	revokedTokens, err := s.repo.GetActive(s.ctx, time.Now())
+	s.Nil(revokedTokens)
+	s.True(errors.Is(err, myerrors.ErrGetRevokedTokensFailed))
+}
+
+func (s *revokedTokenRepositoryTestSuite) TestDeleteExpired_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	_, err = s.repo.DeleteExpired(s.ctx, time.Now())
+	s.True(errors.Is(err, myerrors.ErrDeleteRevokedTokensFailed))
+}
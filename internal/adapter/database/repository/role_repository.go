@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+type RoleRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *RoleRepositoryImpl) GetAll(ctx context.Context) ([]domain.Role, error) {
+	var roles []domain.Role
+
+	result := r.DB.GetDB().WithContext(ctx).Preload("Permissions").Order("name ASC").Find(&roles)
+
+	if result.Error != nil {
+		golog.Error("Error getting roles", result.Error)
+		return nil, myerrors.ErrGetRoleFailed
+	}
+
+	return roles, nil
+}
+
+func (r *RoleRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.Role, error) {
+	var role domain.Role
+
+	result := r.DB.GetDB().WithContext(ctx).Preload("Permissions").First(&role, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrRoleNotFound
+		}
+		golog.Error("Error getting role by id", result.Error)
+		return nil, myerrors.ErrGetRoleFailed
+	}
+
+	return &role, nil
+}
+
+// Create stores the role and assigns it the given, already stored, permissions.
+func (r *RoleRepositoryImpl) Create(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	role.ID = uuid.Must(uuid.NewV7())
+
+	result := r.DB.GetDB().WithContext(ctx).Omit("Permissions.*").Create(role)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return nil, myerrors.ErrRoleAlreadyExists
+		}
+		golog.Error("Error creating role", result.Error)
+		return nil, myerrors.ErrSaveRoleFailed
+	}
+
+	return role, nil
+}
+
+// Update saves the description of the role and replaces its permissions.
+func (r *RoleRepositoryImpl) Update(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Role{}).Where("id = ?", role.ID).Update("description", role.Description)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(role).Omit("Permissions.*").Association("Permissions").Replace(role.Permissions)
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrRoleNotFound
+		}
+		golog.Error("Error updating role", err)
+		return nil, myerrors.ErrSaveRoleFailed
+	}
+
+	return role, nil
+}
+
+// Delete removes a role no user has anymore.
+func (r *RoleRepositoryImpl) Delete(ctx context.Context, id string) error {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var role domain.Role
+		if err := tx.First(&role, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		var users int64
+		if err := tx.Model(&domain.UserRole{}).Where("role = ?", role.Name).Count(&users).Error; err != nil {
+			return err
+		}
+		if users > 0 {
+			return myerrors.ErrRoleInUse
+		}
+
+		var members int64
+		if err := tx.Model(&domain.OrganizationMember{}).Where("role = ?", role.Name).Count(&members).Error; err != nil {
+			return err
+		}
+		if members > 0 {
+			return myerrors.ErrRoleInUse
+		}
+
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Role{}, "id = ?", id).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return myerrors.ErrRoleNotFound
+		}
+		if errors.Is(err, myerrors.ErrRoleInUse) {
+			return err
+		}
+		golog.Error("Error deleting role", err)
+		return myerrors.ErrDeleteRoleFailed
+	}
+
+	return nil
+}
+
+// GetUserRoles returns the names of the roles the user holds.
+func (r *RoleRepositoryImpl) GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+	roles := []string{}
+
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.UserRole{}).
+		Where("user_id = ?", userID).
+		Order("role ASC").
+		Pluck("role", &roles)
+
+	if result.Error != nil {
+		golog.Error("Error getting user roles", result.Error)
+		return nil, myerrors.ErrGetRoleFailed
+	}
+
+	return roles, nil
+}
+
+func (r *RoleRepositoryImpl) GetPermissions(ctx context.Context) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+
+	result := r.DB.GetDB().WithContext(ctx).Order("name ASC").Find(&permissions)
+
+	if result.Error != nil {
+		golog.Error("Error getting permissions", result.Error)
+		return nil, myerrors.ErrGetRoleFailed
+	}
+
+	return permissions, nil
+}
+
+func (r *RoleRepositoryImpl) GetPermissionsByNames(ctx context.Context, names []string) ([]domain.Permission, error) {
+	permissions := []domain.Permission{}
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	result := r.DB.GetDB().WithContext(ctx).Where("name IN ?", names).Order("name ASC").Find(&permissions)
+
+	if result.Error != nil {
+		golog.Error("Error getting permissions by name", result.Error)
+		return nil, myerrors.ErrGetRoleFailed
+	}
+
+	return permissions, nil
+}
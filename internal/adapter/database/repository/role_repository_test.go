@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type roleRepositoryTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	mockCtrl    *gomock.Controller
+	mockDB      *mocks.MockDatabaseAdapter
+	gormDB      *gorm.DB
+	repo        *RoleRepositoryImpl
+	getUsers    domain.Permission
+	manageUsers domain.Permission
+}
+
+func TestRoleRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(roleRepositoryTestSuite))
+}
+
+func (s *roleRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(
+		&domain.User{}, &domain.UserRole{}, &domain.Role{}, &domain.Permission{},
+		&domain.Organization{}, &domain.OrganizationMember{},
+	))
+
+	s.getUsers = domain.Permission{ID: uuid.Must(uuid.NewV7()), Name: "getUsers"}
+	s.manageUsers = domain.Permission{ID: uuid.Must(uuid.NewV7()), Name: "manageUsers"}
+	s.Require().NoError(gormDB.Create(&[]domain.Permission{s.getUsers, s.manageUsers}).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &RoleRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *roleRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *roleRepositoryTestSuite) createRole(name string, permissions ...domain.Permission) *domain.Role {
+	role, err := s.repo.Create(s.ctx, &domain.Role{Name: name, Permissions: permissions})
+	s.Require().NoError(err)
+	return role
+}
+
+// ==================== Create Tests ====================
+
+func (s *roleRepositoryTestSuite) TestCreate_WithPermissions() {
+	created := s.createRole("support", s.getUsers)
+
+	role, err := s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.Equal("support", role.Name)
+	s.Equal([]string{"getUsers"}, role.PermissionNames())
+}
+
+func (s *roleRepositoryTestSuite) TestCreate_DuplicateName() {
+	s.createRole("support")
+
+	_, err := s.repo.Create(s.ctx, &domain.Role{Name: "support"})
+	s.True(errors.Is(err, myerrors.ErrRoleAlreadyExists))
+}
+
+// ==================== GetAll Tests ====================
+
+func (s *roleRepositoryTestSuite) TestGetAll() {
+	s.createRole("support", s.getUsers)
+	s.createRole("admin", s.getUsers, s.manageUsers)
+
+	roles, err := s.repo.GetAll(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(roles, 2)
+	s.Equal("admin", roles[0].Name)
+	s.Len(roles[0].Permissions, 2)
+}
+
+// ==================== Update Tests ====================
+
+func (s *roleRepositoryTestSuite) TestUpdate_ReplacesPermissions() {
+	created := s.createRole("support", s.getUsers)
+
+	created.Description = "Answers customers"
+	created.Permissions = []domain.Permission{s.manageUsers}
+	_, err := s.repo.Update(s.ctx, created)
+	s.Require().NoError(err)
+
+	role, err := s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.Equal("Answers customers", role.Description)
+	s.Equal([]string{"manageUsers"}, role.PermissionNames())
+}
+
+func (s *roleRepositoryTestSuite) TestUpdate_NotFound() {
+	_, err := s.repo.Update(s.ctx, &domain.Role{ID: uuid.Must(uuid.NewV7())})
+	s.True(errors.Is(err, myerrors.ErrRoleNotFound))
+}
+
+// ==================== Delete Tests ====================
+
+func (s *roleRepositoryTestSuite) TestDelete_Success() {
+	created := s.createRole("support", s.getUsers)
+
+	s.Require().NoError(s.repo.Delete(s.ctx, created.ID.String()))
+
+	_, err := s.repo.GetByID(s.ctx, created.ID.String())
+	s.True(errors.Is(err, myerrors.ErrRoleNotFound))
+}
+
+func (s *roleRepositoryTestSuite) TestDelete_InUse() {
+	created := s.createRole("support")
+	s.Require().NoError(s.gormDB.Create(&domain.User{
+		ID: uuid.Must(uuid.NewV7()), Name: "Test", Email: "test@example.com", Roles: domain.NewUserRoles("support"),
+	}).Error)
+
+	err := s.repo.Delete(s.ctx, created.ID.String())
+	s.True(errors.Is(err, myerrors.ErrRoleInUse))
+}
+
+func (s *roleRepositoryTestSuite) TestDelete_NotFound() {
+	err := s.repo.Delete(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.True(errors.Is(err, myerrors.ErrRoleNotFound))
+}
+
+// ==================== GetUserRoles Tests ====================
+
+func (s *roleRepositoryTestSuite) TestGetUserRoles() {
+	user := &domain.User{
+		ID: uuid.Must(uuid.NewV7()), Name: "Test", Email: "test@example.com",
+		Roles: domain.NewUserRoles("user", "support"),
+	}
+	s.Require().NoError(s.gormDB.Create(user).Error)
+
+	roles, err := s.repo.GetUserRoles(s.ctx, user.ID.String())
+	s.Require().NoError(err)
+	s.Equal([]string{"support", "user"}, roles)
+}
+
+// ==================== Permission Tests ====================
+
+func (s *roleRepositoryTestSuite) TestGetPermissions() {
+	permissions, err := s.repo.GetPermissions(s.ctx)
+	s.Require().NoError(err)
+	s.Len(permissions, 2)
+}
+
+func (s *roleRepositoryTestSuite) TestGetPermissionsByNames() {
+	permissions, err := s.repo.GetPermissionsByNames(s.ctx, []string{"manageUsers", "unknown"})
+	s.Require().NoError(err)
+	s.Require().Len(permissions, 1)
+	s.Equal("manageUsers", permissions[0].Name)
+}
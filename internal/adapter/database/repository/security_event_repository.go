@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type SecurityEventRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *SecurityEventRepositoryImpl) Create(
+	ctx context.Context, event *domain.SecurityEvent,
+) (*domain.SecurityEvent, error) {
+	event.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(event)
+	if result.Error != nil {
+		golog.Error("Error creating security event", result.Error)
+		return nil, myerrors.ErrCreateSecurityEventFailed
+	}
+	return event, nil
+}
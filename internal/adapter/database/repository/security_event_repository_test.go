@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type securityEventRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *SecurityEventRepositoryImpl
+}
+
+func TestSecurityEventRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(securityEventRepositoryTestSuite))
+}
+
+func (s *securityEventRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.SecurityEvent{}))
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &SecurityEventRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *securityEventRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *securityEventRepositoryTestSuite) TestCreate_Success() {
+	userID := uuid.Must(uuid.NewV7())
+
+	event, err := s.repo.Create(s.ctx, &domain.SecurityEvent{
+		UserID:    userID,
+		Type:      domain.SecurityEventRefreshTokenReuse,
+		IPAddress: "198.51.100.7",
+	})
+	s.Require().NoError(err)
+	s.NotEqual(uuid.Nil, event.ID)
+
+	var stored domain.SecurityEvent
+	s.Require().NoError(s.gormDB.First(&stored, "id = ?", event.ID).Error)
+	s.Equal(userID, stored.UserID)
+	s.Equal(domain.SecurityEventRefreshTokenReuse, stored.Type)
+}
+
+func (s *securityEventRepositoryTestSuite) TestCreate_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	event, err := s.repo.Create(s.ctx, &domain.SecurityEvent{UserID: uuid.Must(uuid.NewV7())})
+	s.Nil(event)
+	s.True(errors.Is(err, myerrors.ErrCreateSecurityEventFailed))
+}
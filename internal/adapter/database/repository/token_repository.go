@@ -6,6 +6,7 @@ import (
 	"app/internal/domain/myerrors"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tommynurwantoro/golog"
@@ -38,30 +39,188 @@ func (r *TokenRepositoryImpl) Delete(ctx context.Context, tokenType domain.Token
 	return nil
 }
 
-func (r *TokenRepositoryImpl) DeleteAll(ctx context.Context, userID string) error {
-	result := r.DB.GetDB().WithContext(ctx).Delete(&domain.Token{}, "user_id = ?", userID)
+func (r *TokenRepositoryImpl) DeleteExpired(
+	ctx context.Context, tokenType domain.TokenType, userID string, now time.Time,
+) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Delete(&domain.Token{}, "type = ? AND user_id = ? AND expires < ?", tokenType.String(), userID, now)
 
 	if result.Error != nil {
-		golog.Error("Error deleting all token", result.Error)
+		golog.Error("Error deleting expired tokens", result.Error)
+		return myerrors.ErrDeleteTokenFailed
+	}
+
+	return nil
+}
+
+func (r *TokenRepositoryImpl) DeleteAll(ctx context.Context, userID string) error {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.Token{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Session{}, "user_id = ?", userID).Error
+	})
+
+	if err != nil {
+		golog.Error("Error deleting all token", err)
 		return myerrors.ErrDeleteAllTokenFailed
 	}
 
 	return nil
 }
 
-func (r *TokenRepositoryImpl) GetByTokenAndUserID(ctx context.Context, token, userID string) (*domain.Token, error) {
+func (r *TokenRepositoryImpl) GetByTokenHashAndUserID(
+	ctx context.Context, tokenHash, userID string,
+) (*domain.Token, error) {
 	var tokenDoc domain.Token
 
+	// The session tells which OAuth client, if any, a refresh token was issued to
 	result := r.DB.GetDB().WithContext(ctx).
-		First(&tokenDoc, "token = ? AND user_id = ?", token, userID)
+		Preload("Session").
+		First(&tokenDoc, "token_hash = ? AND user_id = ?", tokenHash, userID)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, myerrors.ErrTokenNotFound
 		}
-		golog.Error("Error getting token by token and user id", result.Error)
+		golog.Error("Error getting token by token hash and user id", result.Error)
 		return nil, myerrors.ErrGetTokenByUserIDFailed
 	}
 
 	return &tokenDoc, nil
 }
+
+func (r *TokenRepositoryImpl) ConsumeToken(ctx context.Context, tokenID string, consumedAt time.Time) error {
+	// The consumed_at guard makes rotation race-safe: only one caller can consume a token
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.Token{}).
+		Where("id = ? AND consumed_at IS NULL", tokenID).
+		Update("consumed_at", consumedAt)
+
+	if result.Error != nil {
+		golog.Error("Error consuming token", result.Error)
+		return myerrors.ErrConsumeTokenFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrTokenAlreadyConsumed
+	}
+
+	return nil
+}
+
+func (r *TokenRepositoryImpl) RevokeTokenFamily(ctx context.Context, sessionID string) error {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.Token{}, "session_id = ?", sessionID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Session{}, "id = ?", sessionID).Error
+	})
+
+	if err != nil {
+		golog.Error("Error revoking token family", err)
+		return myerrors.ErrRevokeTokenFamily
+	}
+
+	return nil
+}
+
+func (r *TokenRepositoryImpl) CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	session.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(session)
+	if result.Error != nil {
+		golog.Error("Error creating session", result.Error)
+		return nil, myerrors.ErrCreateSessionFailed
+	}
+	return session, nil
+}
+
+func (r *TokenRepositoryImpl) GetSessionsByUserID(ctx context.Context, userID string) ([]domain.Session, error) {
+	var sessions []domain.Session
+
+	result := r.DB.GetDB().WithContext(ctx).
+		Order("last_used_at desc").
+		Find(&sessions, "user_id = ?", userID)
+
+	if result.Error != nil {
+		golog.Error("Error getting sessions by user id", result.Error)
+		return nil, myerrors.ErrGetSessionsFailed
+	}
+
+	return sessions, nil
+}
+
+func (r *TokenRepositoryImpl) GetSessionsByClientID(ctx context.Context, clientID string) ([]domain.Session, error) {
+	var sessions []domain.Session
+
+	result := r.DB.GetDB().WithContext(ctx).Find(&sessions, "client_id = ?", clientID)
+
+	if result.Error != nil {
+		golog.Error("Error getting sessions by client id", result.Error)
+		return nil, myerrors.ErrGetSessionsFailed
+	}
+
+	return sessions, nil
+}
+
+func (r *TokenRepositoryImpl) TouchSession(ctx context.Context, sessionID string, lastUsedAt time.Time) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("id = ?", sessionID).
+		Update("last_used_at", lastUsedAt)
+
+	if result.Error != nil {
+		golog.Error("Error updating session last used", result.Error)
+		return myerrors.ErrUpdateSessionFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *TokenRepositoryImpl) DeleteSession(ctx context.Context, sessionID, userID string) error {
+	var rowsAffected int64
+
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.Token{}, "session_id = ? AND user_id = ?", sessionID, userID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&domain.Session{}, "id = ? AND user_id = ?", sessionID, userID)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+
+	if err != nil {
+		golog.Error("Error deleting session", err)
+		return myerrors.ErrDeleteSessionFailed
+	}
+
+	if rowsAffected == 0 {
+		return myerrors.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *TokenRepositoryImpl) DeleteOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(
+			&domain.Token{}, "user_id = ? AND session_id IS NOT NULL AND session_id <> ?", userID, currentSessionID,
+		).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Session{}, "user_id = ? AND id <> ?", userID, currentSessionID).Error
+	})
+
+	if err != nil {
+		golog.Error("Error deleting other sessions", err)
+		return myerrors.ErrDeleteSessionFailed
+	}
+
+	return nil
+}
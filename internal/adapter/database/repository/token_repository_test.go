@@ -40,7 +40,7 @@ func (s *tokenRepositoryTestSuite) SetupTest() {
 		TranslateError: true,
 	})
 	s.Require().NoError(err)
-	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.Token{}))
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.Session{}, &domain.Token{}))
 
 	s.gormDB = gormDB
 	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
@@ -54,10 +54,11 @@ func (s *tokenRepositoryTestSuite) TearDownTest() {
 
 func (s *tokenRepositoryTestSuite) makeToken(token string, userID uuid.UUID, tokenType domain.TokenType, expires time.Time) *domain.Token {
 	return &domain.Token{
-		Token:   token,
-		UserID:  userID,
-		Type:    tokenType,
-		Expires: expires,
+		Token:     token,
+		TokenHash: token,
+		UserID:    userID,
+		Type:      tokenType,
+		Expires:   expires,
 	}
 }
 
@@ -101,7 +102,7 @@ func (s *tokenRepositoryTestSuite) TestDelete_Success() {
 	s.NoError(err)
 
 	// Verify token is deleted
-	_, err = s.repo.GetByTokenAndUserID(s.ctx, "test-token", userID.String())
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "test-token", userID.String())
 	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
 }
 
@@ -115,6 +116,25 @@ func (s *tokenRepositoryTestSuite) TestDelete_Error() {
 	s.True(errors.Is(err, myerrors.ErrDeleteTokenFailed))
 }
 
+func (s *tokenRepositoryTestSuite) TestDeleteExpired_KeepsLiveTokens() {
+	userID := uuid.Must(uuid.NewV7())
+
+	_, err := s.repo.Create(s.ctx, s.makeToken("expired-code", userID, domain.TokenTypeAuthorizationCode,
+		time.Now().Add(-time.Minute)))
+	s.Require().NoError(err)
+	_, err = s.repo.Create(s.ctx, s.makeToken("live-code", userID, domain.TokenTypeAuthorizationCode,
+		time.Now().Add(time.Minute)))
+	s.Require().NoError(err)
+
+	err = s.repo.DeleteExpired(s.ctx, domain.TokenTypeAuthorizationCode, userID.String(), time.Now())
+	s.NoError(err)
+
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "expired-code", userID.String())
+	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "live-code", userID.String())
+	s.NoError(err)
+}
+
 func (s *tokenRepositoryTestSuite) TestDeleteAll_Success() {
 	userID := uuid.Must(uuid.NewV7())
 	expires := time.Now().Add(time.Hour)
@@ -131,9 +151,9 @@ func (s *tokenRepositoryTestSuite) TestDeleteAll_Success() {
 	s.NoError(err)
 
 	// Verify all tokens for user are deleted
-	_, err = s.repo.GetByTokenAndUserID(s.ctx, "token-1", userID.String())
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "token-1", userID.String())
 	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
-	_, err = s.repo.GetByTokenAndUserID(s.ctx, "token-2", userID.String())
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "token-2", userID.String())
 	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
 }
 
@@ -147,7 +167,7 @@ func (s *tokenRepositoryTestSuite) TestDeleteAll_Error() {
 	s.True(errors.Is(err, myerrors.ErrDeleteAllTokenFailed))
 }
 
-func (s *tokenRepositoryTestSuite) TestGetByTokenAndUserID_Success() {
+func (s *tokenRepositoryTestSuite) TestGetByTokenHashAndUserID_Success() {
 	userID := uuid.Must(uuid.NewV7())
 	expires := time.Now().Add(time.Hour)
 	token := s.makeToken("test-token", userID, domain.TokenTypeRefresh, expires)
@@ -155,31 +175,232 @@ func (s *tokenRepositoryTestSuite) TestGetByTokenAndUserID_Success() {
 	created, err := s.repo.Create(s.ctx, token)
 	s.Require().NoError(err)
 
-	found, err := s.repo.GetByTokenAndUserID(s.ctx, "test-token", userID.String())
+	found, err := s.repo.GetByTokenHashAndUserID(s.ctx, "test-token", userID.String())
 	s.NoError(err)
 	s.Require().NotNil(found)
 	s.Equal(created.ID, found.ID)
-	s.Equal("test-token", found.Token)
+	s.Equal("test-token", found.TokenHash)
+	s.Empty(found.Token)
 	s.Equal(userID, found.UserID)
 	s.Equal(domain.TokenTypeRefresh, found.Type)
 }
 
-func (s *tokenRepositoryTestSuite) TestGetByTokenAndUserID_NotFound() {
+func (s *tokenRepositoryTestSuite) TestGetByTokenHashAndUserID_NotFound() {
 	userID := uuid.Must(uuid.NewV7())
 
-	found, err := s.repo.GetByTokenAndUserID(s.ctx, "nonexistent-token", userID.String())
+	found, err := s.repo.GetByTokenHashAndUserID(s.ctx, "nonexistent-token", userID.String())
 	s.Error(err)
 	s.Nil(found)
 	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
 }
 
-func (s *tokenRepositoryTestSuite) TestGetByTokenAndUserID_Error() {
+func (s *tokenRepositoryTestSuite) TestGetByTokenHashAndUserID_Error() {
 	sqlDB, err := s.gormDB.DB()
 	s.Require().NoError(err)
 	s.Require().NoError(sqlDB.Close())
 
-	found, err := s.repo.GetByTokenAndUserID(s.ctx, "test-token", uuid.Must(uuid.NewV7()).String())
+	found, err := s.repo.GetByTokenHashAndUserID(s.ctx, "test-token", uuid.Must(uuid.NewV7()).String())
 	s.Error(err)
 	s.Nil(found)
 	s.True(errors.Is(err, myerrors.ErrGetTokenByUserIDFailed))
 }
+
+func (s *tokenRepositoryTestSuite) makeSession(userID uuid.UUID, deviceName string) *domain.Session {
+	session, err := s.repo.CreateSession(s.ctx, &domain.Session{
+		UserID:     userID,
+		DeviceName: deviceName,
+		LastUsedAt: time.Now(),
+	})
+	s.Require().NoError(err)
+	return session
+}
+
+func (s *tokenRepositoryTestSuite) TestGetByTokenHashAndUserID_LoadsSession() {
+	userID := uuid.Must(uuid.NewV7())
+	clientID := uuid.Must(uuid.NewV7())
+	session, err := s.repo.CreateSession(s.ctx, &domain.Session{
+		UserID:     userID,
+		DeviceName: "Partner",
+		ClientID:   &clientID,
+		Scope:      "profile",
+		LastUsedAt: time.Now(),
+	})
+	s.Require().NoError(err)
+
+	token := s.makeToken("refresh-token", userID, domain.TokenTypeRefresh, time.Now().Add(time.Hour))
+	token.SessionID = &session.ID
+	_, err = s.repo.Create(s.ctx, token)
+	s.Require().NoError(err)
+
+	found, err := s.repo.GetByTokenHashAndUserID(s.ctx, "refresh-token", userID.String())
+	s.Require().NoError(err)
+	s.Require().NotNil(found.Session)
+	s.Equal(&clientID, found.Session.ClientID)
+	s.Equal("profile", found.Session.Scope)
+}
+
+func (s *tokenRepositoryTestSuite) TestConsumeToken_OnlyOnce() {
+	userID := uuid.Must(uuid.NewV7())
+	token, err := s.repo.Create(s.ctx, s.makeToken("refresh-token", userID, domain.TokenTypeRefresh, time.Now().Add(time.Hour)))
+	s.Require().NoError(err)
+
+	err = s.repo.ConsumeToken(s.ctx, token.ID.String(), time.Now())
+	s.NoError(err)
+
+	err = s.repo.ConsumeToken(s.ctx, token.ID.String(), time.Now())
+	s.True(errors.Is(err, myerrors.ErrTokenAlreadyConsumed))
+
+	consumed, err := s.repo.GetByTokenHashAndUserID(s.ctx, "refresh-token", userID.String())
+	s.Require().NoError(err)
+	s.NotNil(consumed.ConsumedAt)
+}
+
+func (s *tokenRepositoryTestSuite) TestRevokeTokenFamily_RemovesSessionAndTokens() {
+	userID := uuid.Must(uuid.NewV7())
+	family := s.makeSession(userID, "laptop")
+	other := s.makeSession(userID, "phone")
+
+	parent := s.makeToken("parent-token", userID, domain.TokenTypeRefresh, time.Now().Add(time.Hour))
+	parent.SessionID = &family.ID
+	_, err := s.repo.Create(s.ctx, parent)
+	s.Require().NoError(err)
+
+	child := s.makeToken("child-token", userID, domain.TokenTypeRefresh, time.Now().Add(time.Hour))
+	child.SessionID = &family.ID
+	child.ParentID = &parent.ID
+	_, err = s.repo.Create(s.ctx, child)
+	s.Require().NoError(err)
+
+	otherToken := s.makeToken("other-token", userID, domain.TokenTypeRefresh, time.Now().Add(time.Hour))
+	otherToken.SessionID = &other.ID
+	_, err = s.repo.Create(s.ctx, otherToken)
+	s.Require().NoError(err)
+
+	err = s.repo.RevokeTokenFamily(s.ctx, family.ID.String())
+	s.NoError(err)
+
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "parent-token", userID.String())
+	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "child-token", userID.String())
+	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "other-token", userID.String())
+	s.NoError(err)
+
+	sessions, err := s.repo.GetSessionsByUserID(s.ctx, userID.String())
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal(other.ID, sessions[0].ID)
+}
+
+func (s *tokenRepositoryTestSuite) TestCreateSession_Success() {
+	userID := uuid.Must(uuid.NewV7())
+
+	session := s.makeSession(userID, "laptop")
+
+	s.NotEqual(uuid.Nil, session.ID)
+	s.Equal("laptop", session.DeviceName)
+}
+
+func (s *tokenRepositoryTestSuite) TestCreateSession_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	created, err := s.repo.CreateSession(s.ctx, &domain.Session{UserID: uuid.Must(uuid.NewV7())})
+	s.Nil(created)
+	s.True(errors.Is(err, myerrors.ErrCreateSessionFailed))
+}
+
+func (s *tokenRepositoryTestSuite) TestGetSessionsByUserID_Success() {
+	userID := uuid.Must(uuid.NewV7())
+	s.makeSession(userID, "laptop")
+	s.makeSession(userID, "phone")
+	s.makeSession(uuid.Must(uuid.NewV7()), "other user")
+
+	sessions, err := s.repo.GetSessionsByUserID(s.ctx, userID.String())
+	s.NoError(err)
+	s.Len(sessions, 2)
+}
+
+func (s *tokenRepositoryTestSuite) TestGetSessionsByClientID_Success() {
+	userID := uuid.Must(uuid.NewV7())
+	clientID := uuid.Must(uuid.NewV7())
+	_, err := s.repo.CreateSession(s.ctx, &domain.Session{
+		UserID:     userID,
+		DeviceName: "Partner",
+		ClientID:   &clientID,
+		LastUsedAt: time.Now(),
+	})
+	s.Require().NoError(err)
+	s.makeSession(userID, "laptop")
+
+	sessions, err := s.repo.GetSessionsByClientID(s.ctx, clientID.String())
+	s.NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal(&clientID, sessions[0].ClientID)
+}
+
+func (s *tokenRepositoryTestSuite) TestTouchSession_Success() {
+	userID := uuid.Must(uuid.NewV7())
+	session := s.makeSession(userID, "laptop")
+	lastUsedAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	err := s.repo.TouchSession(s.ctx, session.ID.String(), lastUsedAt)
+	s.NoError(err)
+
+	sessions, err := s.repo.GetSessionsByUserID(s.ctx, userID.String())
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.True(lastUsedAt.Equal(sessions[0].LastUsedAt))
+}
+
+func (s *tokenRepositoryTestSuite) TestTouchSession_NotFound() {
+	err := s.repo.TouchSession(s.ctx, uuid.Must(uuid.NewV7()).String(), time.Now())
+	s.True(errors.Is(err, myerrors.ErrSessionNotFound))
+}
+
+func (s *tokenRepositoryTestSuite) TestDeleteSession_RemovesItsTokens() {
+	userID := uuid.Must(uuid.NewV7())
+	laptop := s.makeSession(userID, "laptop")
+	phone := s.makeSession(userID, "phone")
+
+	laptopToken := s.makeToken("laptop-token", userID, domain.TokenTypeRefresh, time.Now().Add(time.Hour))
+	laptopToken.SessionID = &laptop.ID
+	_, err := s.repo.Create(s.ctx, laptopToken)
+	s.Require().NoError(err)
+
+	phoneToken := s.makeToken("phone-token", userID, domain.TokenTypeRefresh, time.Now().Add(time.Hour))
+	phoneToken.SessionID = &phone.ID
+	_, err = s.repo.Create(s.ctx, phoneToken)
+	s.Require().NoError(err)
+
+	err = s.repo.DeleteSession(s.ctx, laptop.ID.String(), userID.String())
+	s.NoError(err)
+
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "laptop-token", userID.String())
+	s.True(errors.Is(err, myerrors.ErrTokenNotFound))
+	_, err = s.repo.GetByTokenHashAndUserID(s.ctx, "phone-token", userID.String())
+	s.NoError(err)
+}
+
+func (s *tokenRepositoryTestSuite) TestDeleteSession_OtherUsersSession() {
+	session := s.makeSession(uuid.Must(uuid.NewV7()), "laptop")
+
+	err := s.repo.DeleteSession(s.ctx, session.ID.String(), uuid.Must(uuid.NewV7()).String())
+	s.True(errors.Is(err, myerrors.ErrSessionNotFound))
+}
+
+func (s *tokenRepositoryTestSuite) TestDeleteOtherSessions_KeepsCurrent() {
+	userID := uuid.Must(uuid.NewV7())
+	current := s.makeSession(userID, "laptop")
+	s.makeSession(userID, "phone")
+	s.makeSession(userID, "tablet")
+
+	err := s.repo.DeleteOtherSessions(s.ctx, userID.String(), current.ID.String())
+	s.NoError(err)
+
+	sessions, err := s.repo.GetSessionsByUserID(s.ctx, userID.String())
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal(current.ID, sessions[0].ID)
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *UserIdentityRepositoryImpl) Create(
+	ctx context.Context, identity *domain.UserIdentity,
+) (*domain.UserIdentity, error) {
+	identity.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(identity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return nil, myerrors.ErrIdentityAlreadyLinked
+		}
+
+		golog.Error("Error creating user identity", result.Error)
+		return nil, myerrors.ErrSaveIdentityFailed
+	}
+	return identity, nil
+}
+
+// CreateWithUser creates the user and its first identity together, so a failed link leaves no account without a way to log in.
+func (r *UserIdentityRepositoryImpl) CreateWithUser(
+	ctx context.Context, user *domain.User, identity *domain.UserIdentity,
+) (*domain.User, error) {
+	err := r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user.ID = uuid.Must(uuid.NewV7())
+		if err := tx.Create(user).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return myerrors.ErrEmailAlreadyInUse
+			}
+			return err
+		}
+
+		identity.ID = uuid.Must(uuid.NewV7())
+		identity.UserID = user.ID
+		if err := tx.Create(identity).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return myerrors.ErrIdentityAlreadyLinked
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, myerrors.ErrEmailAlreadyInUse) || errors.Is(err, myerrors.ErrIdentityAlreadyLinked) {
+			return nil, err
+		}
+		golog.Error("Error creating user with identity", err)
+		return nil, myerrors.ErrCreateUserFailed
+	}
+
+	return user, nil
+}
+
+func (r *UserIdentityRepositoryImpl) GetByProviderSubject(
+	ctx context.Context, provider, subject string,
+) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+
+	result := r.DB.GetDB().WithContext(ctx).
+		First(&identity, "provider = ? AND subject = ?", provider, subject)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, myerrors.ErrIdentityNotFound
+		}
+		golog.Error("Error getting user identity by provider subject", result.Error)
+		return nil, myerrors.ErrGetIdentityFailed
+	}
+
+	return &identity, nil
+}
+
+func (r *UserIdentityRepositoryImpl) GetByUserID(ctx context.Context, userID string) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+
+	result := r.DB.GetDB().WithContext(ctx).
+		Order("linked_at ASC").
+		Find(&identities, "user_id = ?", userID)
+
+	if result.Error != nil {
+		golog.Error("Error getting user identities by user id", result.Error)
+		return nil, myerrors.ErrGetIdentityFailed
+	}
+
+	return identities, nil
+}
+
+func (r *UserIdentityRepositoryImpl) Delete(ctx context.Context, userID, identityID string) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Delete(&domain.UserIdentity{}, "id = ? AND user_id = ?", identityID, userID)
+
+	if result.Error != nil {
+		golog.Error("Error deleting user identity", result.Error)
+		return myerrors.ErrDeleteIdentityFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrIdentityNotFound
+	}
+
+	return nil
+}
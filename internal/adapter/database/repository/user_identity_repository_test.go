@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type userIdentityRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *UserIdentityRepositoryImpl
+	user     *domain.User
+}
+
+func TestUserIdentityRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(userIdentityRepositoryTestSuite))
+}
+
+func (s *userIdentityRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.UserIdentity{}))
+
+	s.user = &domain.User{
+		ID:       uuid.Must(uuid.NewV7()),
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "hashed",
+	}
+	s.Require().NoError(gormDB.Create(s.user).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &UserIdentityRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *userIdentityRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *userIdentityRepositoryTestSuite) newIdentity(provider, subject string) *domain.UserIdentity {
+	return &domain.UserIdentity{
+		UserID:   s.user.ID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    "test@example.com",
+		LinkedAt: time.Now(),
+	}
+}
+
+// ==================== Create Tests ====================
+
+func (s *userIdentityRepositoryTestSuite) TestCreate_Success() {
+	identity, err := s.repo.Create(s.ctx, s.newIdentity("google", "subject-1"))
+
+	s.Require().NoError(err)
+	s.NotEqual(uuid.Nil, identity.ID)
+}
+
+func (s *userIdentityRepositoryTestSuite) TestCreate_AlreadyLinked() {
+	_, err := s.repo.Create(s.ctx, s.newIdentity("google", "subject-1"))
+	s.Require().NoError(err)
+
+	_, err = s.repo.Create(s.ctx, s.newIdentity("google", "subject-1"))
+	s.True(errors.Is(err, myerrors.ErrIdentityAlreadyLinked))
+
+	// The same subject at another provider is another account
+	_, err = s.repo.Create(s.ctx, s.newIdentity("github", "subject-1"))
+	s.NoError(err)
+}
+
+// ==================== CreateWithUser Tests ====================
+
+func (s *userIdentityRepositoryTestSuite) TestCreateWithUser_Success() {
+	user, err := s.repo.CreateWithUser(s.ctx,
+		&domain.User{Name: "New User", Email: "new@example.com", VerifiedEmail: true},
+		&domain.UserIdentity{Provider: "google", Subject: "subject-2", Email: "new@example.com", LinkedAt: time.Now()},
+	)
+	s.Require().NoError(err)
+
+	identity, err := s.repo.GetByProviderSubject(s.ctx, "google", "subject-2")
+	s.Require().NoError(err)
+	s.Equal(user.ID, identity.UserID)
+}
+
+func (s *userIdentityRepositoryTestSuite) TestCreateWithUser_EmailAlreadyInUse() {
+	_, err := s.repo.CreateWithUser(s.ctx,
+		&domain.User{Name: "New User", Email: s.user.Email},
+		&domain.UserIdentity{Provider: "google", Subject: "subject-2", LinkedAt: time.Now()},
+	)
+	s.True(errors.Is(err, myerrors.ErrEmailAlreadyInUse))
+}
+
+func (s *userIdentityRepositoryTestSuite) TestCreateWithUser_RollsBackUser() {
+	_, err := s.repo.Create(s.ctx, s.newIdentity("google", "subject-1"))
+	s.Require().NoError(err)
+
+	_, err = s.repo.CreateWithUser(s.ctx,
+		&domain.User{Name: "New User", Email: "new@example.com"},
+		&domain.UserIdentity{Provider: "google", Subject: "subject-1", LinkedAt: time.Now()},
+	)
+	s.True(errors.Is(err, myerrors.ErrIdentityAlreadyLinked))
+
+	var count int64
+	s.Require().NoError(s.gormDB.Model(&domain.User{}).Where("email = ?", "new@example.com").Count(&count).Error)
+	s.Zero(count)
+}
+
+// ==================== GetByProviderSubject Tests ====================
+
+func (s *userIdentityRepositoryTestSuite) TestGetByProviderSubject_NotFound() {
+	_, err := s.repo.GetByProviderSubject(s.ctx, "google", "unknown")
+	s.True(errors.Is(err, myerrors.ErrIdentityNotFound))
+}
+
+func (s *userIdentityRepositoryTestSuite) TestGetByProviderSubject_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	_, err = s.repo.GetByProviderSubject(s.ctx, "google", "subject-1")
+	s.True(errors.Is(err, myerrors.ErrGetIdentityFailed))
+}
+
+// ==================== GetByUserID Tests ====================
+
+func (s *userIdentityRepositoryTestSuite) TestGetByUserID_Success() {
+	_, err := s.repo.Create(s.ctx, s.newIdentity("google", "subject-1"))
+	s.Require().NoError(err)
+	_, err = s.repo.Create(s.ctx, s.newIdentity("github", "subject-2"))
+	s.Require().NoError(err)
+
+	identities, err := s.repo.GetByUserID(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	s.Len(identities, 2)
+	s.Equal("google", identities[0].Provider)
+}
+
+// ==================== Delete Tests ====================
+
+func (s *userIdentityRepositoryTestSuite) TestDelete_Success() {
+	identity, err := s.repo.Create(s.ctx, s.newIdentity("google", "subject-1"))
+	s.Require().NoError(err)
+
+	s.NoError(s.repo.Delete(s.ctx, s.user.ID.String(), identity.ID.String()))
+
+	_, err = s.repo.GetByProviderSubject(s.ctx, "google", "subject-1")
+	s.True(errors.Is(err, myerrors.ErrIdentityNotFound))
+}
+
+func (s *userIdentityRepositoryTestSuite) TestDelete_OtherUser() {
+	identity, err := s.repo.Create(s.ctx, s.newIdentity("google", "subject-1"))
+	s.Require().NoError(err)
+
+	err = s.repo.Delete(s.ctx, uuid.Must(uuid.NewV7()).String(), identity.ID.String())
+	s.True(errors.Is(err, myerrors.ErrIdentityNotFound))
+}
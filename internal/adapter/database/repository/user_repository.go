@@ -4,6 +4,7 @@ import (
 	"app/internal/adapter/database"
 	"app/internal/domain"
 	"app/internal/domain/myerrors"
+	"app/internal/pkg/tenant"
 	"context"
 	"errors"
 
@@ -16,6 +17,18 @@ type UserRepositoryImpl struct {
 	DB database.DatabaseAdapter `inject:"database"`
 }
 
+// inOrganization limits the query to the members of the organization the context acts in. Contexts without
+// an organization, such as logins and platform admins acting outside any organization, see every user.
+func inOrganization(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		organizationID := tenant.OrganizationID(ctx)
+		if organizationID == "" {
+			return db
+		}
+		return db.Where("id IN (SELECT user_id FROM organization_members WHERE organization_id = ?)", organizationID)
+	}
+}
+
 func (r *UserRepositoryImpl) GetAll(
 	ctx context.Context,
 	limit, offset int,
@@ -24,10 +37,13 @@ func (r *UserRepositoryImpl) GetAll(
 	var users []domain.User
 	var totalResults int64
 
-	query := r.DB.GetDB().WithContext(ctx).Order("created_at asc")
+	query := r.DB.GetDB().WithContext(ctx).Scopes(inOrganization(ctx)).Order("created_at asc")
 
 	if search != "" {
-		query = query.Where("name LIKE ? OR email LIKE ? OR role LIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+		query = query.Where(
+			"name LIKE ? OR email LIKE ? OR id IN (SELECT user_id FROM user_roles WHERE role LIKE ?)",
+			"%"+search+"%", "%"+search+"%", "%"+search+"%",
+		)
 	}
 
 	resultCount := query.Find(&users).Count(&totalResults)
@@ -36,7 +52,7 @@ func (r *UserRepositoryImpl) GetAll(
 		return nil, 0, myerrors.ErrGetUserFailed
 	}
 
-	result := query.Limit(limit).Offset(offset).Find(&users)
+	result := query.Preload("Roles").Limit(limit).Offset(offset).Find(&users)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, 0, myerrors.ErrUserNotFound
@@ -51,7 +67,7 @@ func (r *UserRepositoryImpl) GetAll(
 func (r *UserRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	var user domain.User
 
-	result := r.DB.GetDB().WithContext(ctx).First(&user, "id = ?", id)
+	result := r.DB.GetDB().WithContext(ctx).Scopes(inOrganization(ctx)).Preload("Roles").First(&user, "id = ?", id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, myerrors.ErrUserNotFound
@@ -66,7 +82,7 @@ func (r *UserRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.Us
 func (r *UserRepositoryImpl) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
 
-	result := r.DB.GetDB().WithContext(ctx).First(&user, "email = ?", email)
+	result := r.DB.GetDB().WithContext(ctx).Preload("Roles").First(&user, "email = ?", email)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, myerrors.ErrUserNotFound
@@ -92,7 +108,7 @@ func (r *UserRepositoryImpl) Create(ctx context.Context, user *domain.User) (*do
 }
 
 func (r *UserRepositoryImpl) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
-	result := r.DB.GetDB().WithContext(ctx).Where("id = ?", user.ID).Updates(user)
+	result := r.DB.GetDB().WithContext(ctx).Scopes(inOrganization(ctx)).Where("id = ?", user.ID).Updates(user)
 
 	if result.RowsAffected == 0 {
 		return nil, myerrors.ErrUserNotFound
@@ -109,8 +125,66 @@ func (r *UserRepositoryImpl) Update(ctx context.Context, user *domain.User) (*do
 	return user, nil
 }
 
+// SetRoles replaces the roles of the user.
+func (r *UserRepositoryImpl) SetRoles(ctx context.Context, id string, roles []domain.UserRole) error {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return myerrors.ErrUserNotFound
+	}
+
+	for i := range roles {
+		roles[i].UserID = userID
+	}
+
+	err = r.DB.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(inOrganization(ctx)).First(&domain.User{}, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&domain.UserRole{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&roles).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return myerrors.ErrUserNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return myerrors.ErrInvalidRole
+		}
+		golog.Error("Error setting user roles", err)
+		return myerrors.ErrUpdateUserFailed
+	}
+
+	return nil
+}
+
+// SetAttributes replaces the attributes of the user.
+func (r *UserRepositoryImpl) SetAttributes(ctx context.Context, id string, attributes map[string]string) error {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+
+	result := r.DB.GetDB().WithContext(ctx).Model(&domain.User{}).Scopes(inOrganization(ctx)).Where("id = ?", id).
+		Select("attributes").Updates(&domain.User{Attributes: attributes})
+
+	if result.Error != nil {
+		golog.Error("Error setting user attributes", result.Error)
+		return myerrors.ErrUpdateUserFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepositoryImpl) UpdatePassOrVerify(ctx context.Context, user *domain.User, id string) error {
-	result := r.DB.GetDB().WithContext(ctx).Where("id = ?", id).Updates(user)
+	result := r.DB.GetDB().WithContext(ctx).Scopes(inOrganization(ctx)).Where("id = ?", id).Updates(user)
 
 	if result.RowsAffected == 0 {
 		return myerrors.ErrUserNotFound
@@ -125,7 +199,7 @@ func (r *UserRepositoryImpl) UpdatePassOrVerify(ctx context.Context, user *domai
 }
 
 func (r *UserRepositoryImpl) Delete(ctx context.Context, id string) error {
-	result := r.DB.GetDB().WithContext(ctx).Delete(&domain.User{}, "id = ?", id)
+	result := r.DB.GetDB().WithContext(ctx).Scopes(inOrganization(ctx)).Delete(&domain.User{}, "id = ?", id)
 
 	if result.RowsAffected == 0 {
 		return myerrors.ErrUserNotFound
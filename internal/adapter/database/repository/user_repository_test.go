@@ -4,6 +4,7 @@ import (
 	"app/internal/adapter/database/mocks"
 	"app/internal/domain"
 	"app/internal/domain/myerrors"
+	"app/internal/pkg/tenant"
 	"context"
 	"errors"
 	"fmt"
@@ -39,7 +40,9 @@ func (s *userRepositoryTestSuite) SetupTest() {
 		TranslateError: true,
 	})
 	s.Require().NoError(err)
-	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.Token{}))
+	s.Require().NoError(gormDB.AutoMigrate(
+		&domain.User{}, &domain.UserRole{}, &domain.Token{}, &domain.Organization{}, &domain.OrganizationMember{},
+	))
 
 	s.gormDB = gormDB
 	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
@@ -56,7 +59,7 @@ func (s *userRepositoryTestSuite) makeUser(name, email, password, role string) *
 		Name:          name,
 		Email:         email,
 		Password:      password,
-		Role:          role,
+		Roles:         domain.NewUserRoles(role),
 		VerifiedEmail: false,
 	}
 }
@@ -100,6 +103,22 @@ func (s *userRepositoryTestSuite) TestGetAll_SuccessWithSearch() {
 	s.Equal("alice@example.com", users[0].Email)
 }
 
+func (s *userRepositoryTestSuite) TestGetAll_SearchByRole() {
+	user1 := s.makeUser("Alice", "alice@example.com", "pass1", "user")
+	user2 := s.makeUser("Bob", "bob@example.com", "pass2", "admin")
+
+	_, err := s.repo.Create(s.ctx, user1)
+	s.Require().NoError(err)
+	_, err = s.repo.Create(s.ctx, user2)
+	s.Require().NoError(err)
+
+	users, total, err := s.repo.GetAll(s.ctx, 10, 0, "admin")
+	s.NoError(err)
+	s.Require().Len(users, 1)
+	s.Equal(int64(1), total)
+	s.Equal([]string{"admin"}, users[0].RoleNames())
+}
+
 func (s *userRepositoryTestSuite) TestGetAll_ErrorOnCount() {
 	sqlDB, err := s.gormDB.DB()
 	s.Require().NoError(err)
@@ -255,3 +274,119 @@ func (s *userRepositoryTestSuite) TestDelete_NotFound() {
 	s.Error(err)
 	s.True(errors.Is(err, myerrors.ErrUserNotFound))
 }
+
+func (s *userRepositoryTestSuite) TestSetRoles_Success() {
+	user := s.makeUser("Alice", "alice@example.com", "pass1", "user")
+	created, err := s.repo.Create(s.ctx, user)
+	s.Require().NoError(err)
+
+	err = s.repo.SetRoles(s.ctx, created.ID.String(), domain.NewUserRoles("admin", "support"))
+	s.Require().NoError(err)
+
+	found, err := s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.ElementsMatch([]string{"admin", "support"}, found.RoleNames())
+}
+
+func (s *userRepositoryTestSuite) TestSetRoles_NotFound() {
+	err := s.repo.SetRoles(s.ctx, uuid.Must(uuid.NewV7()).String(), domain.NewUserRoles("admin"))
+	s.True(errors.Is(err, myerrors.ErrUserNotFound))
+}
+
+func (s *userRepositoryTestSuite) TestSetAttributes_Success() {
+	user := s.makeUser("Alice", "alice@example.com", "pass1", "user")
+	created, err := s.repo.Create(s.ctx, user)
+	s.Require().NoError(err)
+
+	found, err := s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.Empty(found.Attributes)
+
+	err = s.repo.SetAttributes(s.ctx, created.ID.String(), map[string]string{"region": "eu"})
+	s.Require().NoError(err)
+
+	found, err = s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"region": "eu"}, found.Attributes)
+
+	err = s.repo.SetAttributes(s.ctx, created.ID.String(), nil)
+	s.Require().NoError(err)
+
+	found, err = s.repo.GetByID(s.ctx, created.ID.String())
+	s.Require().NoError(err)
+	s.Empty(found.Attributes)
+}
+
+func (s *userRepositoryTestSuite) TestSetAttributes_NotFound() {
+	err := s.repo.SetAttributes(s.ctx, uuid.Must(uuid.NewV7()).String(), map[string]string{"region": "eu"})
+	s.True(errors.Is(err, myerrors.ErrUserNotFound))
+}
+
+// ==================== Organization Scope Tests ====================
+
+// addToOrganization creates an organization with the users as members and returns its id.
+func (s *userRepositoryTestSuite) addToOrganization(slug string, users ...*domain.User) string {
+	organization := domain.Organization{ID: uuid.Must(uuid.NewV7()), Name: slug, Slug: slug}
+	s.Require().NoError(s.gormDB.Create(&organization).Error)
+
+	for _, user := range users {
+		member := domain.OrganizationMember{OrganizationID: organization.ID, UserID: user.ID, Role: "user"}
+		s.Require().NoError(s.gormDB.Omit("Organization", "User").Create(&member).Error)
+	}
+
+	return organization.ID.String()
+}
+
+func (s *userRepositoryTestSuite) TestOrganizationScope_GetAll() {
+	alice, err := s.repo.Create(s.ctx, s.makeUser("Alice", "alice@example.com", "pass1", "user"))
+	s.Require().NoError(err)
+	bob, err := s.repo.Create(s.ctx, s.makeUser("Bob", "bob@example.com", "pass2", "user"))
+	s.Require().NoError(err)
+	_, err = s.repo.Create(s.ctx, s.makeUser("Carol", "carol@example.com", "pass3", "user"))
+	s.Require().NoError(err)
+
+	acme := tenant.WithOrganization(s.ctx, s.addToOrganization("acme", alice, bob))
+
+	users, total, err := s.repo.GetAll(acme, 10, 0, "")
+	s.Require().NoError(err)
+	s.Equal(int64(2), total)
+	s.Len(users, 2)
+
+	users, total, err = s.repo.GetAll(acme, 10, 0, "carol")
+	s.Require().NoError(err)
+	s.Equal(int64(0), total)
+	s.Empty(users)
+
+	_, total, err = s.repo.GetAll(s.ctx, 10, 0, "")
+	s.Require().NoError(err)
+	s.Equal(int64(3), total)
+}
+
+func (s *userRepositoryTestSuite) TestOrganizationScope_OtherOrganizationNotFound() {
+	alice, err := s.repo.Create(s.ctx, s.makeUser("Alice", "alice@example.com", "pass1", "user"))
+	s.Require().NoError(err)
+	bob, err := s.repo.Create(s.ctx, s.makeUser("Bob", "bob@example.com", "pass2", "user"))
+	s.Require().NoError(err)
+
+	acme := tenant.WithOrganization(s.ctx, s.addToOrganization("acme", alice))
+	s.addToOrganization("globex", bob)
+
+	_, err = s.repo.GetByID(acme, bob.ID.String())
+	s.True(errors.Is(err, myerrors.ErrUserNotFound))
+
+	err = s.repo.SetAttributes(acme, bob.ID.String(), map[string]string{"region": "eu"})
+	s.True(errors.Is(err, myerrors.ErrUserNotFound))
+
+	err = s.repo.SetRoles(acme, bob.ID.String(), domain.NewUserRoles("admin"))
+	s.True(errors.Is(err, myerrors.ErrUserNotFound))
+
+	err = s.repo.Delete(acme, bob.ID.String())
+	s.True(errors.Is(err, myerrors.ErrUserNotFound))
+
+	found, err := s.repo.GetByID(acme, alice.ID.String())
+	s.Require().NoError(err)
+	s.Equal(alice.ID, found.ID)
+
+	_, err = s.repo.GetByID(s.ctx, bob.ID.String())
+	s.NoError(err)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"app/internal/adapter/database"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+	"gorm.io/gorm"
+)
+
+type WebAuthnCredentialRepositoryImpl struct {
+	DB database.DatabaseAdapter `inject:"database"`
+}
+
+func (r *WebAuthnCredentialRepositoryImpl) Create(
+	ctx context.Context, credential *domain.WebAuthnCredential,
+) (*domain.WebAuthnCredential, error) {
+	credential.ID = uuid.Must(uuid.NewV7())
+	result := r.DB.GetDB().WithContext(ctx).Create(credential)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return nil, myerrors.ErrWebAuthnCredentialExists
+		}
+
+		golog.Error("Error creating webauthn credential", result.Error)
+		return nil, myerrors.ErrSaveWebAuthnCredentialFailed
+	}
+	return credential, nil
+}
+
+func (r *WebAuthnCredentialRepositoryImpl) GetByUserID(
+	ctx context.Context, userID string,
+) ([]domain.WebAuthnCredential, error) {
+	var credentials []domain.WebAuthnCredential
+
+	result := r.DB.GetDB().WithContext(ctx).
+		Order("created_at ASC").
+		Find(&credentials, "user_id = ?", userID)
+
+	if result.Error != nil {
+		golog.Error("Error getting webauthn credentials by user id", result.Error)
+		return nil, myerrors.ErrGetWebAuthnCredentialsFailed
+	}
+
+	return credentials, nil
+}
+
+func (r *WebAuthnCredentialRepositoryImpl) UpdateAfterLogin(
+	ctx context.Context, credential *domain.WebAuthnCredential,
+) error {
+	result := r.DB.GetDB().WithContext(ctx).
+		Model(&domain.WebAuthnCredential{}).
+		Where("id = ? AND user_id = ?", credential.ID, credential.UserID).
+		Updates(map[string]any{
+			"sign_count":   credential.SignCount,
+			"backup_state": credential.BackupState,
+			"last_used_at": credential.LastUsedAt,
+		})
+
+	if result.Error != nil {
+		golog.Error("Error updating webauthn credential", result.Error)
+		return myerrors.ErrSaveWebAuthnCredentialFailed
+	}
+
+	if result.RowsAffected == 0 {
+		return myerrors.ErrWebAuthnNoCredentials
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"app/internal/adapter/database/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type webAuthnCredentialRepositoryTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockCtrl *gomock.Controller
+	mockDB   *mocks.MockDatabaseAdapter
+	gormDB   *gorm.DB
+	repo     *WebAuthnCredentialRepositoryImpl
+	user     *domain.User
+}
+
+func TestWebAuthnCredentialRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(webAuthnCredentialRepositoryTestSuite))
+}
+
+func (s *webAuthnCredentialRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockCtrl = gomock.NewController(s.T())
+
+	dbPath := fmt.Sprintf("file:test-%s.db?mode=memory", uuid.New().String())
+	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		TranslateError: true,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(gormDB.AutoMigrate(&domain.User{}, &domain.WebAuthnCredential{}))
+
+	s.user = &domain.User{
+		ID:       uuid.Must(uuid.NewV7()),
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "hashed",
+	}
+	s.Require().NoError(gormDB.Create(s.user).Error)
+
+	s.gormDB = gormDB
+	s.mockDB = mocks.NewMockDatabaseAdapter(s.mockCtrl)
+	s.mockDB.EXPECT().GetDB().Return(gormDB).AnyTimes()
+	s.repo = &WebAuthnCredentialRepositoryImpl{DB: s.mockDB}
+}
+
+func (s *webAuthnCredentialRepositoryTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *webAuthnCredentialRepositoryTestSuite) newCredential(credentialID string) *domain.WebAuthnCredential {
+	return &domain.WebAuthnCredential{
+		UserID:          s.user.ID,
+		CredentialID:    []byte(credentialID),
+		PublicKey:       []byte("public-key"),
+		AttestationType: "none",
+		Transports:      "internal,hybrid",
+		SignCount:       1,
+		Name:            "Laptop",
+	}
+}
+
+// ==================== Create Tests ====================
+
+func (s *webAuthnCredentialRepositoryTestSuite) TestCreate_Success() {
+	credential, err := s.repo.Create(s.ctx, s.newCredential("credential-1"))
+
+	s.Require().NoError(err)
+	s.NotEqual(uuid.Nil, credential.ID)
+
+	credentials, err := s.repo.GetByUserID(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	s.Require().Len(credentials, 1)
+	s.Equal([]byte("credential-1"), credentials[0].CredentialID)
+	s.Equal("internal,hybrid", credentials[0].Transports)
+}
+
+func (s *webAuthnCredentialRepositoryTestSuite) TestCreate_DuplicateCredentialID() {
+	_, err := s.repo.Create(s.ctx, s.newCredential("credential-1"))
+	s.Require().NoError(err)
+
+	_, err = s.repo.Create(s.ctx, s.newCredential("credential-1"))
+	s.True(errors.Is(err, myerrors.ErrWebAuthnCredentialExists))
+}
+
+func (s *webAuthnCredentialRepositoryTestSuite) TestCreate_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	_, err = s.repo.Create(s.ctx, s.newCredential("credential-1"))
+	s.True(errors.Is(err, myerrors.ErrSaveWebAuthnCredentialFailed))
+}
+
+// ==================== GetByUserID Tests ====================
+
+func (s *webAuthnCredentialRepositoryTestSuite) TestGetByUserID_OnlyOwnCredentials() {
+	_, err := s.repo.Create(s.ctx, s.newCredential("credential-1"))
+	s.Require().NoError(err)
+
+	credentials, err := s.repo.GetByUserID(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.NoError(err)
+	s.Empty(credentials)
+}
+
+func (s *webAuthnCredentialRepositoryTestSuite) TestGetByUserID_Error() {
+	sqlDB, err := s.gormDB.DB()
+	s.Require().NoError(err)
+	s.Require().NoError(sqlDB.Close())
+
+	_, err = s.repo.GetByUserID(s.ctx, s.user.ID.String())
+	s.True(errors.Is(err, myerrors.ErrGetWebAuthnCredentialsFailed))
+}
+
+// ==================== UpdateAfterLogin Tests ====================
+
+func (s *webAuthnCredentialRepositoryTestSuite) TestUpdateAfterLogin_Success() {
+	credential, err := s.repo.Create(s.ctx, s.newCredential("credential-1"))
+	s.Require().NoError(err)
+
+	lastUsedAt := time.Now().UTC()
+	credential.SignCount = 5
+	credential.BackupState = true
+	credential.LastUsedAt = &lastUsedAt
+	s.Require().NoError(s.repo.UpdateAfterLogin(s.ctx, credential))
+
+	credentials, err := s.repo.GetByUserID(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	s.Equal(uint32(5), credentials[0].SignCount)
+	s.True(credentials[0].BackupState)
+	s.NotNil(credentials[0].LastUsedAt)
+}
+
+func (s *webAuthnCredentialRepositoryTestSuite) TestUpdateAfterLogin_OtherUser() {
+	credential, err := s.repo.Create(s.ctx, s.newCredential("credential-1"))
+	s.Require().NoError(err)
+
+	credential.UserID = uuid.Must(uuid.NewV7())
+	err = s.repo.UpdateAfterLogin(s.ctx, credential)
+	s.True(errors.Is(err, myerrors.ErrWebAuthnNoCredentials))
+}
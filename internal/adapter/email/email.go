@@ -3,14 +3,20 @@ package email
 import (
 	"app/config"
 	"fmt"
+	"time"
 
 	"gopkg.in/gomail.v2"
 )
 
+//go:generate mockgen -source=email.go -destination=mocks/email.go -package=mocks
 type EmailAdapter interface {
 	SendEmail(to, subject, body string) error
 	SendResetPasswordEmail(to, token string) error
 	SendVerificationEmail(to, token string) error
+	SendMagicLinkEmail(to, token string) error
+	SendInvitationEmail(to, organization, token string) error
+	SendUnlockAccountEmail(to, token string) error
+	SendNewDeviceLoginEmail(to, deviceName, ipAddress string, loggedInAt time.Time) error
 }
 
 type EmailAdapterImpl struct {
@@ -71,3 +77,60 @@ To verify your email, click on this link: %s
 If you did not create an account, then ignore this email.`, verificationEmailURL)
 	return a.SendEmail(to, subject, body)
 }
+
+func (a *EmailAdapterImpl) SendMagicLinkEmail(to, token string) error {
+	subject := "Your login link"
+
+	// TODO: replace this url with the link to the magic link login page of your front-end app
+	magicLinkURL := fmt.Sprintf("http://link-to-app/magic-link?token=%s", token)
+	body := fmt.Sprintf(`Dear user,
+
+To log in, click on this link: %s
+
+The link can only be used once. If you did not request it, then ignore this email.`, magicLinkURL)
+	return a.SendEmail(to, subject, body)
+}
+
+func (a *EmailAdapterImpl) SendInvitationEmail(to, organization, token string) error {
+	subject := fmt.Sprintf("You are invited to join %s", organization)
+
+	// TODO: replace this url with the link to the invitation page of your front-end app
+	invitationURL := fmt.Sprintf("http://link-to-app/accept-invitation?token=%s", token)
+	body := fmt.Sprintf(`Dear user,
+
+You have been invited to join %s. To accept the invitation, click on this link: %s
+
+If you do not have an account yet, you can create one from the same page. If you did not expect this
+invitation, then ignore this email.`, organization, invitationURL)
+	return a.SendEmail(to, subject, body)
+}
+
+func (a *EmailAdapterImpl) SendUnlockAccountEmail(to, token string) error {
+	subject := "Your account has been locked"
+
+	// TODO: replace this url with the link to the unlock account page of your front-end app
+	unlockURL := fmt.Sprintf("http://link-to-app/unlock-account?token=%s", token)
+	body := fmt.Sprintf(`Dear user,
+
+Your account has been locked after too many failed login attempts. To unlock it now, click on this link: %s
+
+Otherwise it unlocks by itself after a while. If the failed attempts were not yours, then change your password
+once you are logged in again.`, unlockURL)
+	return a.SendEmail(to, subject, body)
+}
+
+func (a *EmailAdapterImpl) SendNewDeviceLoginEmail(to, deviceName, ipAddress string, loggedInAt time.Time) error {
+	subject := "New login to your account"
+
+	body := fmt.Sprintf(`Dear user,
+
+Your account was logged into from a new device.
+
+Device: %s
+IP address: %s
+Time: %s
+
+If this was you, you can ignore this email. Otherwise change your password and log out your other sessions.`,
+		deviceName, ipAddress, loggedInAt.UTC().Format(time.RFC1123))
+	return a.SendEmail(to, subject, body)
+}
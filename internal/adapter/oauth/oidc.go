@@ -0,0 +1,208 @@
+package oauth
+
+import (
+	"app/config"
+	"app/internal/domain/myerrors"
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/tommynurwantoro/golog"
+	"golang.org/x/oauth2"
+)
+
+const httpTimeout = 10 * time.Second
+
+var (
+	DefaultScopes = []string{oidc.ScopeOpenID, "profile", "email"}
+)
+
+// Identity is the account a provider vouched for in a validated ID token.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+//go:generate mockgen -source=oidc.go -destination=mocks/oidc.go -package=mocks
+type OIDCAdapter interface {
+	Providers() []string
+	AuthCodeURL(ctx context.Context, provider, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, provider, code, nonce, codeVerifier string) (*Identity, error)
+}
+
+// OIDCAdapterImpl is the registry of the providers configured under oauth2.providers.
+// Providers are discovered on first use and cached, so an unreachable provider does not block startup.
+type OIDCAdapterImpl struct {
+	Conf       *config.Config `inject:"config"`
+	HTTPClient *http.Client
+
+	mu        sync.Mutex
+	providers map[string]*provider
+}
+
+type provider struct {
+	name     string
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+	oidc     *oidc.Provider
+}
+
+func (o *OIDCAdapterImpl) Startup() error {
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{Timeout: httpTimeout}
+	}
+	o.providers = make(map[string]*provider)
+	return nil
+}
+
+func (o *OIDCAdapterImpl) Shutdown() error {
+	return nil
+}
+
+// Providers lists the names of the enabled providers.
+func (o *OIDCAdapterImpl) Providers() []string {
+	names := make([]string, 0, len(o.Conf.OAuth2.Providers))
+	for name, providerConf := range o.Conf.OAuth2.Providers {
+		if providerConf.ClientID != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AuthCodeURL builds the consent page URL. The code verifier is only sent as its S256 challenge (PKCE).
+func (o *OIDCAdapterImpl) AuthCodeURL(ctx context.Context, name, state, nonce, codeVerifier string) (string, error) {
+	p, err := o.provider(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange redeems the authorization code with the PKCE code verifier and validates the returned ID token:
+// signature against the provider's JWKS, issuer, audience, expiry and the nonce of this login.
+func (o *OIDCAdapterImpl) Exchange(ctx context.Context, name, code, nonce, codeVerifier string) (*Identity, error) {
+	p, err := o.provider(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = oidc.ClientContext(ctx, o.HTTPClient)
+	oauthToken, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		golog.Error("Error exchanging oauth authorization code", err)
+		return nil, myerrors.ErrOAuthExchangeFailed
+	}
+
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok {
+		return nil, myerrors.ErrInvalidIDToken
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		golog.Error("Error verifying id token", err)
+		return nil, myerrors.ErrInvalidIDToken
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, myerrors.ErrInvalidIDToken
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err = idToken.Claims(&claims); err != nil {
+		golog.Error("Error decoding id token claims", err)
+		return nil, myerrors.ErrInvalidIDToken
+	}
+
+	identity := &Identity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+	}
+
+	// Some providers keep profile claims out of the ID token and only serve them from the userinfo endpoint
+	if identity.Email == "" && p.oidc.UserInfoEndpoint() != "" {
+		userInfo, errUserInfo := p.oidc.UserInfo(ctx, oauth2.StaticTokenSource(oauthToken))
+		if errUserInfo != nil {
+			golog.Error("Error getting oidc userinfo", errUserInfo)
+			return nil, myerrors.ErrOAuthExchangeFailed
+		}
+
+		if userInfo.Subject == identity.Subject {
+			identity.Email = userInfo.Email
+			identity.EmailVerified = userInfo.EmailVerified
+		}
+	}
+
+	return identity, nil
+}
+
+func (o *OIDCAdapterImpl) provider(ctx context.Context, name string) (*provider, error) {
+	providerConf, ok := o.Conf.OAuth2.Providers[name]
+	if !ok || providerConf.ClientID == "" {
+		return nil, myerrors.ErrOAuthProviderNotFound
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if p, found := o.providers[name]; found {
+		return p, nil
+	}
+
+	// The provider keeps this context for later JWKS refreshes, so it must outlive the current request
+	discoveryCtx := oidc.ClientContext(context.WithoutCancel(ctx), o.HTTPClient)
+	oidcProvider, err := oidc.NewProvider(discoveryCtx, providerConf.Issuer)
+	if err != nil {
+		golog.Error("Error discovering oidc provider "+name, err)
+		return nil, myerrors.ErrOAuthDiscoveryFailed
+	}
+
+	scopes := providerConf.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	p := &provider{
+		name: name,
+		oauth2: &oauth2.Config{
+			ClientID:     providerConf.ClientID,
+			ClientSecret: providerConf.ClientSecret,
+			RedirectURL:  providerConf.RedirectURL,
+			Endpoint:     oidcProvider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier: oidcProvider.Verifier(&oidc.Config{ClientID: providerConf.ClientID}),
+		oidc:     oidcProvider,
+	}
+	o.providers[name] = p
+
+	return p, nil
+}
+
+// isTrue reads email_verified, which some providers send as the string "true".
+func isTrue(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
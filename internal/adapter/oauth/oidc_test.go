@@ -0,0 +1,278 @@
+package oauth
+
+import (
+	"app/config"
+	"app/internal/domain/myerrors"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testClientID = "test-client"
+	testNonce    = "test-nonce"
+	testKeyID    = "test-key"
+	// testVerifier is the code verifier from RFC 7636 appendix B
+	testVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+// oidcTestSuite runs the adapter against a local provider serving discovery, JWKS, token and userinfo endpoints.
+type oidcTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	claims   jwt.MapClaims
+	signKey  *rsa.PrivateKey
+	userInfo map[string]any
+	adapter  *OIDCAdapterImpl
+}
+
+func TestOIDCAdapter(t *testing.T) {
+	suite.Run(t, new(oidcTestSuite))
+}
+
+func (s *oidcTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.key = key
+	s.signKey = key
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		s.writeJSON(w, map[string]any{
+			"issuer":                                s.server.URL,
+			"authorization_endpoint":                s.server.URL + "/authorize",
+			"token_endpoint":                        s.server.URL + "/token",
+			"jwks_uri":                              s.server.URL + "/jwks",
+			"userinfo_endpoint":                     s.server.URL + "/userinfo",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		s.writeJSON(w, map[string]any{
+			"keys": []map[string]any{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": testKeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "valid-code" || r.FormValue("code_verifier") != testVerifier {
+			w.WriteHeader(http.StatusBadRequest)
+			s.writeJSON(w, map[string]any{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, s.claims)
+		token.Header["kid"] = testKeyID
+		idToken, errSign := token.SignedString(s.signKey)
+		s.Require().NoError(errSign)
+
+		s.writeJSON(w, map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, _ *http.Request) {
+		s.writeJSON(w, s.userInfo)
+	})
+	s.server = httptest.NewServer(mux)
+
+	s.claims = jwt.MapClaims{
+		"iss":            s.server.URL,
+		"sub":            "subject-1",
+		"aud":            testClientID,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          testNonce,
+		"email":          "oidc@example.com",
+		"email_verified": true,
+		"name":           "OIDC User",
+	}
+	s.userInfo = map[string]any{
+		"sub":            "subject-1",
+		"email":          "userinfo@example.com",
+		"email_verified": true,
+	}
+
+	s.adapter = &OIDCAdapterImpl{
+		Conf: &config.Config{
+			OAuth2: config.OAuth2Config{
+				Providers: map[string]config.OIDCProviderConfig{
+					"local": {
+						Issuer:       s.server.URL,
+						ClientID:     testClientID,
+						ClientSecret: "test-secret",
+						RedirectURL:  "http://localhost:8888/auth/oauth/local/callback",
+					},
+					"disabled": {
+						Issuer: s.server.URL,
+					},
+				},
+			},
+		},
+	}
+	s.Require().NoError(s.adapter.Startup())
+}
+
+func (s *oidcTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *oidcTestSuite) writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	s.Require().NoError(json.NewEncoder(w).Encode(body))
+}
+
+// ==================== Providers Tests ====================
+
+func (s *oidcTestSuite) TestProviders_OnlyEnabled() {
+	s.Equal([]string{"local"}, s.adapter.Providers())
+}
+
+// ==================== AuthCodeURL Tests ====================
+
+func (s *oidcTestSuite) TestAuthCodeURL_Success() {
+	authURL, err := s.adapter.AuthCodeURL(s.ctx, "local", "state-1", testNonce, testVerifier)
+	s.Require().NoError(err)
+
+	parsed, err := url.Parse(authURL)
+	s.Require().NoError(err)
+	s.Equal(s.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+
+	query := parsed.Query()
+	s.Equal(testClientID, query.Get("client_id"))
+	s.Equal("state-1", query.Get("state"))
+	s.Equal(testNonce, query.Get("nonce"))
+	s.Equal("openid profile email", query.Get("scope"))
+	s.Equal(testChallenge, query.Get("code_challenge"))
+	s.Equal("S256", query.Get("code_challenge_method"))
+}
+
+func (s *oidcTestSuite) TestAuthCodeURL_UnknownProvider() {
+	_, err := s.adapter.AuthCodeURL(s.ctx, "unknown", "state-1", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrOAuthProviderNotFound)
+
+	_, err = s.adapter.AuthCodeURL(s.ctx, "disabled", "state-1", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrOAuthProviderNotFound)
+}
+
+func (s *oidcTestSuite) TestAuthCodeURL_DiscoveryFailed() {
+	s.server.Close()
+
+	_, err := s.adapter.AuthCodeURL(s.ctx, "local", "state-1", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrOAuthDiscoveryFailed)
+}
+
+// ==================== Exchange Tests ====================
+
+func (s *oidcTestSuite) TestExchange_Success() {
+	identity, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.Require().NoError(err)
+
+	s.Equal(&Identity{
+		Provider:      "local",
+		Subject:       "subject-1",
+		Email:         "oidc@example.com",
+		EmailVerified: true,
+		Name:          "OIDC User",
+	}, identity)
+}
+
+func (s *oidcTestSuite) TestExchange_EmailVerifiedAsString() {
+	s.claims["email_verified"] = "true"
+
+	identity, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.Require().NoError(err)
+	s.True(identity.EmailVerified)
+}
+
+func (s *oidcTestSuite) TestExchange_EmailFromUserInfo() {
+	delete(s.claims, "email")
+	delete(s.claims, "email_verified")
+
+	identity, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.Require().NoError(err)
+	s.Equal("userinfo@example.com", identity.Email)
+	s.True(identity.EmailVerified)
+}
+
+func (s *oidcTestSuite) TestExchange_UserInfoForOtherSubject() {
+	delete(s.claims, "email")
+	s.userInfo["sub"] = "subject-2"
+
+	identity, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.Require().NoError(err)
+	s.Empty(identity.Email)
+}
+
+func (s *oidcTestSuite) TestExchange_InvalidCode() {
+	_, err := s.adapter.Exchange(s.ctx, "local", "invalid-code", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrOAuthExchangeFailed)
+}
+
+func (s *oidcTestSuite) TestExchange_WrongCodeVerifier() {
+	_, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, "other-verifier")
+	s.ErrorIs(err, myerrors.ErrOAuthExchangeFailed)
+}
+
+func (s *oidcTestSuite) TestExchange_WrongNonce() {
+	_, err := s.adapter.Exchange(s.ctx, "local", "valid-code", "other-nonce", testVerifier)
+	s.ErrorIs(err, myerrors.ErrInvalidIDToken)
+}
+
+func (s *oidcTestSuite) TestExchange_WrongAudience() {
+	s.claims["aud"] = "other-client"
+
+	_, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrInvalidIDToken)
+}
+
+func (s *oidcTestSuite) TestExchange_WrongIssuer() {
+	s.claims["iss"] = "https://evil.example.com"
+
+	_, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrInvalidIDToken)
+}
+
+func (s *oidcTestSuite) TestExchange_Expired() {
+	s.claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	_, err := s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrInvalidIDToken)
+}
+
+func (s *oidcTestSuite) TestExchange_BadSignature() {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.signKey = otherKey
+
+	_, err = s.adapter.Exchange(s.ctx, "local", "valid-code", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrInvalidIDToken)
+}
+
+func (s *oidcTestSuite) TestExchange_UnknownProvider() {
+	_, err := s.adapter.Exchange(s.ctx, "unknown", "valid-code", testNonce, testVerifier)
+	s.ErrorIs(err, myerrors.ErrOAuthProviderNotFound)
+}
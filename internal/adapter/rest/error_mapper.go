@@ -10,6 +10,7 @@ import (
 var CodeMap = map[error]formatter.Status{
 	// Fiber errors
 	myerrors.ErrInvalidRequest: formatter.InvalidRequest,
+	myerrors.ErrForbidden:      formatter.Unauthorized,
 
 	// Token errors
 	myerrors.ErrInvalidToken:       formatter.Unauthorized,
@@ -17,6 +18,79 @@ var CodeMap = map[error]formatter.Status{
 	myerrors.ErrInvalidTokenType:   formatter.Unauthorized,
 	myerrors.ErrInvalidTokenUserID: formatter.Unauthorized,
 	myerrors.ErrTokenNotFound:      formatter.DataNotFound,
+	myerrors.ErrRefreshTokenReused: formatter.Unauthorized,
+
+	// Session errors
+	myerrors.ErrSessionNotFound:     formatter.DataNotFound,
+	myerrors.ErrInvalidTokenSession: formatter.Unauthorized,
+
+	// MFA errors
+	myerrors.ErrMFAAlreadyEnabled: formatter.DataConflict,
+	myerrors.ErrMFANotEnrolled:    formatter.InvalidRequest,
+	myerrors.ErrInvalidMFACode:    formatter.Unauthorized,
+
+	// WebAuthn errors
+	myerrors.ErrWebAuthnNotConfigured:    formatter.InternalServerError,
+	myerrors.ErrWebAuthnNoCredentials:    formatter.DataNotFound,
+	myerrors.ErrWebAuthnCredentialExists: formatter.DataConflict,
+	myerrors.ErrInvalidWebAuthnResponse:  formatter.Unauthorized,
+
+	// OAuth errors
+	myerrors.ErrOAuthProviderNotFound: formatter.DataNotFound,
+	myerrors.ErrOAuthDiscoveryFailed:  formatter.ExternalServiceError,
+	myerrors.ErrInvalidOAuthState:     formatter.Unauthorized,
+	myerrors.ErrInvalidRedirectURI:    formatter.InvalidRequest,
+	myerrors.ErrOAuthExchangeFailed:   formatter.Unauthorized,
+	myerrors.ErrInvalidIDToken:        formatter.Unauthorized,
+	myerrors.ErrOAuthEmailMissing:     formatter.InvalidRequest,
+	myerrors.ErrOAuthAccountNotLinked: formatter.DataConflict,
+	myerrors.ErrIdentityNotFound:      formatter.DataNotFound,
+	myerrors.ErrIdentityAlreadyLinked: formatter.DataConflict,
+	myerrors.ErrLastLoginMethod:       formatter.DataConflict,
+
+	// Authorization server errors
+	myerrors.ErrInvalidClient:           formatter.Unauthorized,
+	myerrors.ErrInvalidGrant:            formatter.InvalidRequest,
+	myerrors.ErrUnauthorizedClient:      formatter.InvalidRequest,
+	myerrors.ErrUnsupportedGrantType:    formatter.InvalidRequest,
+	myerrors.ErrUnsupportedResponseType: formatter.InvalidRequest,
+	myerrors.ErrInvalidScope:            formatter.InvalidRequest,
+	myerrors.ErrOAuthClientNotFound:     formatter.DataNotFound,
+	myerrors.ErrInvalidUserCode:         formatter.InvalidRequest,
+
+	// Role errors
+	myerrors.ErrRoleNotFound:       formatter.DataNotFound,
+	myerrors.ErrInvalidRole:        formatter.InvalidRequest,
+	myerrors.ErrRoleAlreadyExists:  formatter.DataConflict,
+	myerrors.ErrRoleInUse:          formatter.DataConflict,
+	myerrors.ErrDefaultRole:        formatter.DataConflict,
+	myerrors.ErrPermissionNotFound: formatter.InvalidRequest,
+
+	// Organization errors
+	myerrors.ErrOrganizationNotFound:      formatter.DataNotFound,
+	myerrors.ErrOrganizationAlreadyExists: formatter.DataConflict,
+	myerrors.ErrNoOrganization:            formatter.InvalidRequest,
+	myerrors.ErrNotOrganizationMember:     formatter.Unauthorized,
+	myerrors.ErrMemberNotFound:            formatter.DataNotFound,
+	myerrors.ErrRoleNotGrantable:          formatter.Unauthorized,
+	myerrors.ErrAlreadyMember:             formatter.DataConflict,
+	myerrors.ErrInvitationNotFound:        formatter.DataNotFound,
+	myerrors.ErrAccountDetailsRequired:    formatter.InvalidRequest,
+
+	// API key errors
+	myerrors.ErrAPIKeyNotFound:    formatter.DataNotFound,
+	myerrors.ErrUnknownScope:      formatter.InvalidRequest,
+	myerrors.ErrScopeNotGrantable: formatter.Unauthorized,
+	myerrors.ErrInvalidExpiry:     formatter.InvalidRequest,
+
+	// Lockout errors
+	myerrors.ErrAccountLocked:   formatter.Unauthorized,
+	myerrors.ErrLoginThrottled:  formatter.TooManyRequest,
+	myerrors.ErrLockoutNotFound: formatter.DataNotFound,
+
+	// Password history errors
+	myerrors.ErrPasswordReused:  formatter.InvalidRequest,
+	myerrors.ErrPasswordExpired: formatter.Unauthorized,
 
 	// User errors
 	myerrors.ErrUserNotFound:           formatter.DataNotFound,
@@ -28,6 +102,7 @@ var CodeMap = map[error]formatter.Status{
 var StatusMap = map[error]int{
 	// Fiber errors
 	myerrors.ErrInvalidRequest: fiber.StatusBadRequest,
+	myerrors.ErrForbidden:      fiber.StatusForbidden,
 
 	// Token errors
 	myerrors.ErrInvalidToken:       fiber.StatusUnauthorized,
@@ -35,6 +110,79 @@ var StatusMap = map[error]int{
 	myerrors.ErrInvalidTokenType:   fiber.StatusUnauthorized,
 	myerrors.ErrInvalidTokenUserID: fiber.StatusUnauthorized,
 	myerrors.ErrTokenNotFound:      fiber.StatusNotFound,
+	myerrors.ErrRefreshTokenReused: fiber.StatusUnauthorized,
+
+	// Session errors
+	myerrors.ErrSessionNotFound:     fiber.StatusNotFound,
+	myerrors.ErrInvalidTokenSession: fiber.StatusUnauthorized,
+
+	// MFA errors
+	myerrors.ErrMFAAlreadyEnabled: fiber.StatusConflict,
+	myerrors.ErrMFANotEnrolled:    fiber.StatusBadRequest,
+	myerrors.ErrInvalidMFACode:    fiber.StatusUnauthorized,
+
+	// WebAuthn errors
+	myerrors.ErrWebAuthnNotConfigured:    fiber.StatusInternalServerError,
+	myerrors.ErrWebAuthnNoCredentials:    fiber.StatusNotFound,
+	myerrors.ErrWebAuthnCredentialExists: fiber.StatusConflict,
+	myerrors.ErrInvalidWebAuthnResponse:  fiber.StatusUnauthorized,
+
+	// OAuth errors
+	myerrors.ErrOAuthProviderNotFound: fiber.StatusNotFound,
+	myerrors.ErrOAuthDiscoveryFailed:  fiber.StatusBadGateway,
+	myerrors.ErrInvalidOAuthState:     fiber.StatusUnauthorized,
+	myerrors.ErrInvalidRedirectURI:    fiber.StatusBadRequest,
+	myerrors.ErrOAuthExchangeFailed:   fiber.StatusUnauthorized,
+	myerrors.ErrInvalidIDToken:        fiber.StatusUnauthorized,
+	myerrors.ErrOAuthEmailMissing:     fiber.StatusBadRequest,
+	myerrors.ErrOAuthAccountNotLinked: fiber.StatusConflict,
+	myerrors.ErrIdentityNotFound:      fiber.StatusNotFound,
+	myerrors.ErrIdentityAlreadyLinked: fiber.StatusConflict,
+	myerrors.ErrLastLoginMethod:       fiber.StatusConflict,
+
+	// Authorization server errors
+	myerrors.ErrInvalidClient:           fiber.StatusUnauthorized,
+	myerrors.ErrInvalidGrant:            fiber.StatusBadRequest,
+	myerrors.ErrUnauthorizedClient:      fiber.StatusBadRequest,
+	myerrors.ErrUnsupportedGrantType:    fiber.StatusBadRequest,
+	myerrors.ErrUnsupportedResponseType: fiber.StatusBadRequest,
+	myerrors.ErrInvalidScope:            fiber.StatusBadRequest,
+	myerrors.ErrOAuthClientNotFound:     fiber.StatusNotFound,
+	myerrors.ErrInvalidUserCode:         fiber.StatusBadRequest,
+
+	// Role errors
+	myerrors.ErrRoleNotFound:       fiber.StatusNotFound,
+	myerrors.ErrInvalidRole:        fiber.StatusBadRequest,
+	myerrors.ErrRoleAlreadyExists:  fiber.StatusConflict,
+	myerrors.ErrRoleInUse:          fiber.StatusConflict,
+	myerrors.ErrDefaultRole:        fiber.StatusConflict,
+	myerrors.ErrPermissionNotFound: fiber.StatusBadRequest,
+
+	// Organization errors
+	myerrors.ErrOrganizationNotFound:      fiber.StatusNotFound,
+	myerrors.ErrOrganizationAlreadyExists: fiber.StatusConflict,
+	myerrors.ErrNoOrganization:            fiber.StatusBadRequest,
+	myerrors.ErrNotOrganizationMember:     fiber.StatusForbidden,
+	myerrors.ErrMemberNotFound:            fiber.StatusNotFound,
+	myerrors.ErrRoleNotGrantable:          fiber.StatusForbidden,
+	myerrors.ErrAlreadyMember:             fiber.StatusConflict,
+	myerrors.ErrInvitationNotFound:        fiber.StatusNotFound,
+	myerrors.ErrAccountDetailsRequired:    fiber.StatusBadRequest,
+
+	// API key errors
+	myerrors.ErrAPIKeyNotFound:    fiber.StatusNotFound,
+	myerrors.ErrUnknownScope:      fiber.StatusBadRequest,
+	myerrors.ErrScopeNotGrantable: fiber.StatusForbidden,
+	myerrors.ErrInvalidExpiry:     fiber.StatusBadRequest,
+
+	// Lockout errors
+	myerrors.ErrAccountLocked:   fiber.StatusLocked,
+	myerrors.ErrLoginThrottled:  fiber.StatusTooManyRequests,
+	myerrors.ErrLockoutNotFound: fiber.StatusNotFound,
+
+	// Password history errors
+	myerrors.ErrPasswordReused:  fiber.StatusBadRequest,
+	myerrors.ErrPasswordExpired: fiber.StatusForbidden,
 
 	// User errors
 	myerrors.ErrUserNotFound:           fiber.StatusNotFound,
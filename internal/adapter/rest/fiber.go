@@ -28,13 +28,17 @@ func (f *Fiber) Startup() error {
 		JSONDecoder:   json.Unmarshal,
 		ReadTimeout:   f.Conf.Http.ReadTimeout,
 		WriteTimeout:  f.Conf.Http.WriteTimeout,
+		// The client IP is only read from the proxy header of requests sent by a trusted proxy
+		ProxyHeader:             f.Conf.Http.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          f.Conf.Http.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
 	// Middleware setup
 	f.Use(requestid.New(requestid.Config{
 		ContextKey: "traceId",
 	}))
-	f.Use("/v1/auth", middleware.LimiterConfig())
 	f.Use(helmet.New())
 	f.Use(compress.New())
 	f.Use(cors.New())
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/formatter"
+	"app/internal/pkg/policy"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type APIKeyHandler interface {
+	GetAPIKeys(c *fiber.Ctx) error
+	GetAllAPIKeys(c *fiber.Ctx) error
+	CreateAPIKey(c *fiber.Ctx) error
+	RevokeAPIKey(c *fiber.Ctx) error
+}
+
+type APIKeyHandlerImpl struct {
+	APIKeyService service.APIKeyService `inject:"apiKeyService"`
+}
+
+// @Tags         API Keys
+// @Summary      Get my API keys
+// @Description  List the personal API keys of the logged-in user. Secrets are never returned.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/api-keys [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.APIKeyResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (a *APIKeyHandlerImpl) GetAPIKeys(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	apiKeys, err := a.APIKeyService.GetAPIKeys(c.Context(), user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get API keys successfully", apiKeys))
+}
+
+// @Tags         API Keys
+// @Summary      Get all API keys
+// @Description  List the API keys of every user and the service keys. Only admins (manageApiKeys permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/api-keys/all [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.APIKeyResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (a *APIKeyHandlerImpl) GetAllAPIKeys(c *fiber.Ctx) error {
+	apiKeys, err := a.APIKeyService.GetAllAPIKeys(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get API keys successfully", apiKeys))
+}
+
+// @Tags         API Keys
+// @Summary      Create an API key
+// @Description  Create a personal API key, or a service key that belongs to no user (manageApiKeys permission). Scopes must be permissions the caller has. The key is only returned in this response; send it as "Authorization: ApiKey <key>". API keys cannot create other keys.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.CreateAPIKeyRequest  true  "Request body"
+// @Router       /v1/api-keys [post]
+// @Success      201  {object}  formatter.SuccessResponse{data=model.APIKeyResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body, unknown scope or expiry in the past"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions or scope not grantable"
+func (a *APIKeyHandlerImpl) CreateAPIKey(c *fiber.Ctx) error {
+	if c.Locals("apiKey") != nil {
+		return myerrors.ErrForbidden
+	}
+
+	if _, ok := c.Locals("user").(*domain.User); !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	subject, ok := c.Locals("subject").(policy.Subject)
+	if !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	req := new(model.CreateAPIKeyRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	apiKey, err := a.APIKeyService.CreateAPIKey(c.Context(), subject, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Create API key successfully", apiKey))
+}
+
+// @Tags         API Keys
+// @Summary      Revoke an API key
+// @Description  Delete an API key of the logged-in user. Admins (manageApiKeys permission) can revoke any key.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        apiKeyId  path  string  true  "API key ID"
+// @Router       /v1/api-keys/{apiKeyId} [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid API key ID"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      404  {object}  model.ErrorNotFound  "API key not found"
+func (a *APIKeyHandlerImpl) RevokeAPIKey(c *fiber.Ctx) error {
+	apiKeyID := c.Params("apiKeyId")
+
+	if _, err := uuid.Parse(apiKeyID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid API key ID")
+	}
+
+	subject, ok := c.Locals("subject").(policy.Subject)
+	if !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	if err := a.APIKeyService.RevokeAPIKey(c.Context(), subject, apiKeyID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Revoke API key successfully", nil))
+}
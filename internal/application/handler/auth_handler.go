@@ -6,13 +6,14 @@ import (
 	"app/internal/application/model"
 	"app/internal/application/service"
 	"app/internal/domain"
+	"app/internal/domain/myerrors"
 	"app/internal/pkg/formatter"
-	"encoding/json"
-	"io"
-	"net/http"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 	"github.com/tommynurwantoro/golog"
 )
 
@@ -25,16 +26,22 @@ type AuthHandler interface {
 	ResetPassword(c *fiber.Ctx) error
 	SendVerificationEmail(c *fiber.Ctx) error
 	VerifyEmail(c *fiber.Ctx) error
-	GoogleLogin(c *fiber.Ctx) error
-	GoogleCallback(c *fiber.Ctx) error
+	SendMagicLink(c *fiber.Ctx) error
+	VerifyMagicLink(c *fiber.Ctx) error
+	ChangeExpiredPassword(c *fiber.Ctx) error
+	OAuthLogin(c *fiber.Ctx) error
+	OAuthCallback(c *fiber.Ctx) error
+	ExchangeOAuthCode(c *fiber.Ctx) error
 }
 
 type AuthHandlerImpl struct {
-	AuthService   service.AuthService  `inject:"authService"`
-	EmailAdapter  email.EmailAdapter   `inject:"email"`
-	GoogleAdapter oauth.GoogleAdapter  `inject:"oauth"`
-	TokenService  service.TokenService `inject:"tokenService"`
-	UserService   service.UserService  `inject:"userService"`
+	AuthService         service.AuthService         `inject:"authService"`
+	EmailAdapter        email.EmailAdapter          `inject:"email"`
+	OIDCAdapter         oauth.OIDCAdapter           `inject:"oauth"`
+	TokenService        service.TokenService        `inject:"tokenService"`
+	OAuthService        service.OAuthService        `inject:"oauthService"`
+	IdentityService     service.IdentityService     `inject:"identityService"`
+	LoginHistoryService service.LoginHistoryService `inject:"loginHistoryService"`
 }
 
 // @Tags         Auth
@@ -42,7 +49,8 @@ type AuthHandlerImpl struct {
 // @Description  Create a new user account. Returns user data and auth tokens on success.
 // @Accept       json
 // @Produce      json
-// @Param        request  body  model.RegisterRequest  true  "Request body (name, email, password with strong-password validation)"
+// @Param        request  body  model.RegisterRequest  true  "Request body (name, email, password following the password policy)"
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
 // @Router       /auth/register [post]
 // @Success      201  {object}  model.RegisterResponse
 // @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
@@ -60,7 +68,7 @@ func (a *AuthHandlerImpl) Register(c *fiber.Ctx) error {
 		return err
 	}
 
-	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String())
+	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String(), deviceInfo(c))
 	if err != nil {
 		return err
 	}
@@ -80,15 +88,20 @@ func (a *AuthHandlerImpl) Register(c *fiber.Ctx) error {
 
 // @Tags         Auth
 // @Summary      Login
-// @Description  Authenticate with email and password. Returns access and refresh tokens on success.
+// @Description  Authenticate with email and password. Returns access and refresh tokens on success. Users with two-factor authentication enabled get a short-lived mfa_token instead, to be exchanged at POST /auth/mfa/verify. Users whose password is older than the configured maximum age get password_expired and a short-lived password_change_token instead, to be used at POST /auth/change-password. Each failed login makes the account wait before the next attempt, and too many lock it and email an unlock link.
 // @Accept       json
 // @Produce      json
-// @Param        request  body  model.LoginRequest  true  "Request body (email, password with strong-password validation)"
+// @Param        request  body  model.LoginRequest  true  "Request body (email, password)"
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
 // @Router       /auth/login [post]
 // @Success      200  {object}  model.LoginResponse
+// @Success      200  {object}  model.PasswordExpiredResponse  "Password accepted but expired, it has to be changed"
+// @Success      202  {object}  model.MFAChallengeResponse  "Password accepted, second factor required"
 // @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
 // @Failure      401  {object}  model.ErrorFailedLogin  "Invalid email or password"
 // @Failure      404  {object}  model.ErrorNotFound  "User not found"
+// @Failure      423  {object}  model.ErrorAccountLocked  "Account locked after too many failed logins"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too soon after a failed login"
 func (a *AuthHandlerImpl) Login(c *fiber.Ctx) error {
 	req := new(model.LoginRequest)
 
@@ -98,11 +111,20 @@ func (a *AuthHandlerImpl) Login(c *fiber.Ctx) error {
 	}
 
 	user, err := a.AuthService.Login(c.Context(), req)
+	a.recordLogin(c, domain.LoginMethodPassword, user, req.Email, err)
 	if err != nil {
 		return err
 	}
 
-	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String())
+	if a.AuthService.PasswordExpired(user) {
+		return a.passwordExpired(c, user)
+	}
+
+	if user.TOTPEnabled {
+		return a.mfaChallenge(c, user)
+	}
+
+	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String(), deviceInfo(c))
 	if err != nil {
 		return err
 	}
@@ -120,10 +142,11 @@ func (a *AuthHandlerImpl) Login(c *fiber.Ctx) error {
 
 // @Tags         Auth
 // @Summary      Logout
-// @Description  Invalidate the refresh token. Requires valid refresh token in request body.
+// @Description  End the session the refresh token belongs to. Other sessions of the user stay logged in. An access token sent as bearer token is revoked as well.
 // @Accept       json
 // @Produce      json
 // @Param        request  body  model.LogoutRequest  true  "Request body (refresh_token)"
+// @Param        Authorization  header  string  false  "Bearer access token to revoke"
 // @Router       /auth/logout [post]
 // @Success      200  {object}  model.SuccessMessageAPIResponse
 // @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
@@ -141,20 +164,27 @@ func (a *AuthHandlerImpl) Logout(c *fiber.Ctx) error {
 		return err
 	}
 
+	if accessToken, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+		if err := a.TokenService.RevokeAccessToken(c.Context(), accessToken); err != nil {
+			return err
+		}
+	}
+
 	return c.Status(fiber.StatusOK).
 		JSON(formatter.NewSuccessResponse(formatter.Success, "Logout successfully", nil))
 }
 
 // @Tags         Auth
 // @Summary      Refresh auth tokens
-// @Description  Exchange a valid refresh token for a new access token.
+// @Description  Exchange a valid refresh token for a new access token and refresh token. The presented refresh token is consumed; presenting it again revokes the whole session.
 // @Accept       json
 // @Produce      json
 // @Param        request  body  model.RefreshTokenRequest  true  "Request body (refresh_token)"
 // @Router       /auth/refresh-tokens [post]
 // @Success      200  {object}  model.RefreshTokenResponse
 // @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
-// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or expired refresh token"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid, expired or reused refresh token"
+// @Failure      403  {object}  model.ErrorForbidden  "Password expired, log in with the password to change it"
 // @Failure      404  {object}  model.ErrorNotFound  "Token not found"
 func (a *AuthHandlerImpl) RefreshTokens(c *fiber.Ctx) error {
 	req := new(model.RefreshTokenRequest)
@@ -164,14 +194,19 @@ func (a *AuthHandlerImpl) RefreshTokens(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	accessToken, err := a.AuthService.RefreshAuth(c.Context(), req)
+	user, accessToken, refreshToken, err := a.AuthService.RefreshAuth(c.Context(), req, deviceInfo(c))
 	if err != nil {
 		return err
 	}
 
+	// A refresh token that fails does not tell whose it was, so only refreshed sessions are recorded
+	a.recordLogin(c, domain.LoginMethodRefreshToken, user, "", nil)
+
 	resp := &model.RefreshTokenResponse{
-		AccessToken: accessToken.Token,
-		ExpiresAt:   accessToken.Expires,
+		AccessToken:           accessToken.Token,
+		ExpiresAt:             accessToken.Expires,
+		RefreshToken:          refreshToken.Token,
+		RefreshTokenExpiresAt: refreshToken.Expires,
 	}
 
 	return c.Status(fiber.StatusOK).
@@ -189,6 +224,8 @@ func (a *AuthHandlerImpl) RefreshTokens(c *fiber.Ctx) error {
 // @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
 // @Failure      401  {object}  model.ErrorFailedLogin  "Invalid email or password"
 // @Failure      404  {object}  model.ErrorNotFound  "User not found"
+// @Failure      423  {object}  model.ErrorAccountLocked  "Account locked after too many failed logins"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too soon after a failed login, or too many failed requests"
 func (a *AuthHandlerImpl) ForgotPassword(c *fiber.Ctx) error {
 	req := new(model.ForgotPasswordRequest)
 
@@ -197,7 +234,7 @@ func (a *AuthHandlerImpl) ForgotPassword(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	resetPasswordToken, err := a.TokenService.GenerateResetPasswordToken(c.Context(), req)
+	resetPasswordToken, err := a.AuthService.ForgotPassword(c.Context(), req)
 	if err != nil {
 		return err
 	}
@@ -221,6 +258,7 @@ func (a *AuthHandlerImpl) ForgotPassword(c *fiber.Ctx) error {
 // @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
 // @Failure      401  {object}  model.ErrorFailedResetPassword  "Invalid or expired reset token"
 // @Failure      404  {object}  model.ErrorNotFound  "User not found"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
 func (a *AuthHandlerImpl) ResetPassword(c *fiber.Ctx) error {
 	req := new(model.ResetPasswordRequest)
 
@@ -277,6 +315,7 @@ func (a *AuthHandlerImpl) SendVerificationEmail(c *fiber.Ctx) error {
 // @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
 // @Failure      401  {object}  model.ErrorFailedVerifyEmail  "Invalid or expired verification token"
 // @Failure      404  {object}  model.ErrorNotFound  "User not found"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
 func (a *AuthHandlerImpl) VerifyEmail(c *fiber.Ctx) error {
 	req := new(model.VerifyEmailRequest)
 
@@ -294,85 +333,283 @@ func (a *AuthHandlerImpl) VerifyEmail(c *fiber.Ctx) error {
 }
 
 // @Tags         Auth
-// @Summary      Login with google
-// @Description  Initiates the Google OAuth2 login flow. Redirects to Google consent page. Use in browser.
+// @Summary      Login with an OpenID Connect provider
+// @Description  Initiates the login flow of a provider configured under oauth2.providers. Redirects to the provider consent page. Use in browser. With redirect_uri the callback redirects there with a one-time code instead of answering with tokens.
 // @Produce      json
-// @Router       /auth/google [get]
-// @Success      303  "Redirects to Google OAuth consent page"
-func (a *AuthHandlerImpl) GoogleLogin(c *fiber.Ctx) error {
-	// Generate a random state
-	state := uuid.New().String()
-
-	c.Cookie(&fiber.Cookie{
-		Name:   "oauth_state",
-		Value:  state,
-		MaxAge: 30,
+// @Param        provider      path   string  true   "Provider name"  example(google)
+// @Param        redirect_uri  query  string  false  "Front-end page listed in oauth2.redirect_uris"
+// @Router       /auth/oauth/{provider} [get]
+// @Success      303  "Redirects to the provider consent page"
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Redirect URI not allowed"
+// @Failure      404  {object}  model.ErrorNotFound  "Provider not configured"
+func (a *AuthHandlerImpl) OAuthLogin(c *fiber.Ctx) error {
+	authURL, err := beginOAuth(c, a.OAuthService, a.OIDCAdapter, &model.OAuthStateRequest{
+		Provider:    c.Params("provider"),
+		RedirectURI: c.Query("redirect_uri"),
 	})
+	if err != nil {
+		return err
+	}
 
-	url := a.GoogleAdapter.AuthCodeURL(state)
-
-	return c.Status(fiber.StatusSeeOther).Redirect(url)
+	return c.Redirect(authURL, fiber.StatusSeeOther)
 }
 
 // @Tags         Auth
-// @Summary      Google OAuth2 callback
-// @Description  OAuth2 callback. Exchanges code for tokens and creates/updates user. Returns auth tokens.
+// @Summary      OpenID Connect callback
+// @Description  Exchanges the code with the PKCE verifier and validates the ID token. Logs in the user linked to the provider account, or links it to the logged in user when the flow was started from POST /v1/identities/{provider}. An unknown provider account is linked to the user with the same email only if the provider verified the email. Returns auth tokens, or an mfa_token when the user has two-factor authentication enabled. When the flow was started with a redirect_uri, redirects there with a one-time code (or linked, or error) query parameter instead.
 // @Produce      json
-// @Param        code   query  string  true   "Authorization code from Google"
-// @Param        state  query  string  true   "State parameter for CSRF protection"
-// @Router       /auth/google-callback [get]
+// @Param        provider  path   string  true  "Provider name"  example(google)
+// @Param        code      query  string  true  "Authorization code from the provider"
+// @Param        state     query  string  true  "State parameter for CSRF protection"
+// @Router       /auth/oauth/{provider}/callback [get]
 // @Success      200  {object}  model.LoginResponse
-// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid state or authorization code"
-func (a *AuthHandlerImpl) GoogleCallback(c *fiber.Ctx) error {
+// @Success      202  {object}  model.MFAChallengeResponse  "Login accepted, second factor required"
+// @Success      303  "Redirects to the redirect_uri of the authorization"
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Provider did not return an email address"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid state, authorization code or ID token"
+// @Failure      403  {object}  model.ErrorForbidden  "Password expired, log in with the password to change it"
+// @Failure      404  {object}  model.ErrorNotFound  "Provider not configured"
+// @Failure      409  {object}  model.ErrorIdentityConflict  "Email belongs to an account the provider account is not linked to, or the provider account is linked to another user"
+func (a *AuthHandlerImpl) OAuthCallback(c *fiber.Ctx) error {
 	state := c.Query("state")
-	storedState := c.Cookies("oauth_state")
+	storedState := c.Cookies(oauthStateCookie)
+	setOAuthStateCookie(c, "", -1)
+
+	// The cookie ties the callback to the browser that started the authorization
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(storedState)) != 1 {
+		return myerrors.ErrInvalidOAuthState
+	}
+
+	authorization, err := a.OAuthService.ConsumeState(c.Context(), c.Params("provider"), state)
+	if err != nil {
+		return err
+	}
 
-	if state != storedState {
-		return fiber.NewError(fiber.StatusUnauthorized, "States is not match!")
+	err = a.completeOAuth(c, authorization)
+	if err != nil && authorization.RedirectURI != "" {
+		return redirectWithQuery(c, authorization.RedirectURI, "error", err.Error())
 	}
 
-	code := c.Query("code")
-	oauthToken, err := a.GoogleAdapter.Exchange(c.Context(), code)
+	return err
+}
+
+// @Tags         Auth
+// @Summary      Exchange a one-time OAuth code
+// @Description  Exchange the code the OpenID Connect callback sent to the redirect_uri for auth tokens, or an mfa_token when the user has two-factor authentication enabled. A code works once.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.OAuthCodeRequest  true  "Request body (code)"
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
+// @Router       /auth/oauth/code [post]
+// @Success      200  {object}  model.LoginResponse
+// @Success      202  {object}  model.MFAChallengeResponse  "Login accepted, second factor required"
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid, expired or used code"
+// @Failure      403  {object}  model.ErrorForbidden  "Password expired, log in with the password to change it"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
+func (a *AuthHandlerImpl) ExchangeOAuthCode(c *fiber.Ctx) error {
+	req := new(model.OAuthCodeRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := a.OAuthService.RedeemLoginCode(c.Context(), req)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(
-		c.Context(), http.MethodGet,
-		"https://www.googleapis.com/oauth2/v2/userinfo?access_token="+oauthToken.AccessToken,
-		nil,
+	return a.oauthLoginResponse(c, user)
+}
+
+func (a *AuthHandlerImpl) completeOAuth(c *fiber.Ctx, authorization *domain.OAuthState) error {
+	identity, err := a.OIDCAdapter.Exchange(
+		c.Context(), authorization.Provider, c.Query("code"), authorization.Nonce, authorization.CodeVerifier,
 	)
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	if identity.Email == "" {
+		return myerrors.ErrOAuthEmailMissing
+	}
+
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	req := &model.OAuthIdentityRequest{
+		Provider:      identity.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Name:          truncate(name, 50),
+	}
+
+	if authorization.UserID != nil {
+		userIdentity, errLink := a.IdentityService.Link(c.Context(), authorization.UserID.String(), req)
+		if errLink != nil {
+			return errLink
+		}
+
+		if authorization.RedirectURI != "" {
+			return redirectWithQuery(c, authorization.RedirectURI, "linked", userIdentity.Provider)
+		}
+
+		return c.Status(fiber.StatusOK).
+			JSON(formatter.NewSuccessResponse(formatter.Success, "Identity linked successfully", userIdentity))
+	}
+
+	user, err := a.IdentityService.Login(c.Context(), req)
+	a.recordLogin(c, domain.LoginMethodOAuth, user, req.Email, err)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	userData, err := io.ReadAll(resp.Body)
+	// Tokens never travel in the URL, the front-end exchanges the code at POST /auth/oauth/code
+	if authorization.RedirectURI != "" {
+		code, errCode := a.OAuthService.IssueLoginCode(c.Context(), user.ID.String())
+		if errCode != nil {
+			return errCode
+		}
+
+		return redirectWithQuery(c, authorization.RedirectURI, "code", code.Token)
+	}
+
+	return a.oauthLoginResponse(c, user)
+}
+
+// recordLogin adds the attempt to the login history and emails the user when it comes from a new device.
+// It never fails the login itself.
+func (a *AuthHandlerImpl) recordLogin(
+	c *fiber.Ctx, method domain.LoginMethod, user *domain.User, email string, loginErr error,
+) {
+	attempt := &model.LoginAttempt{
+		Email:   email,
+		Method:  method.String(),
+		Device:  deviceInfo(c),
+		Success: loginErr == nil,
+	}
+	if user != nil {
+		attempt.UserID = user.ID.String()
+		if user.Email != "" {
+			attempt.Email = user.Email
+		}
+	}
+	if loginErr != nil {
+		attempt.FailureReason = loginErr.Error()
+	}
+
+	newDevice, err := a.LoginHistoryService.RecordLogin(c.Context(), attempt)
+	if err != nil {
+		golog.Error("Error recording login", err)
+		return
+	}
+
+	if newDevice && attempt.Email != "" {
+		sendErr := a.EmailAdapter.SendNewDeviceLoginEmail(
+			attempt.Email, attempt.Device.DeviceName, attempt.Device.IPAddress, time.Now(),
+		)
+		if sendErr != nil {
+			golog.Error("Error sending new device login email", sendErr)
+		}
+	}
+}
+
+func (a *AuthHandlerImpl) oauthLoginResponse(c *fiber.Ctx, user *domain.User) error {
+	if user.TOTPEnabled {
+		return a.mfaChallenge(c, user)
+	}
+
+	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String(), deviceInfo(c))
 	if err != nil {
 		return err
 	}
 
-	googleUser := new(model.CreateGoogleUserRequest)
-	if errJSON := json.Unmarshal(userData, googleUser); errJSON != nil {
-		return errJSON
+	resp := &model.LoginResponse{
+		AccessToken:           accessToken.Token,
+		AccessTokenExpiresAt:  accessToken.Expires,
+		RefreshToken:          refreshToken.Token,
+		RefreshTokenExpiresAt: refreshToken.Expires,
 	}
 
-	user, err := a.UserService.CreateGoogleUser(c.Context(), googleUser)
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Login successfully", resp))
+}
+
+// @Tags         Auth
+// @Summary      Send magic link
+// @Description  Email a single-use login link to the address. The response is the same whether or not an account exists. Limited per email address.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.MagicLinkRequest  true  "Request body (email)"
+// @Router       /auth/magic-link [post]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many links requested for this email address"
+func (a *AuthHandlerImpl) SendMagicLink(c *fiber.Ctx) error {
+	req := new(model.MagicLinkRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	magicLinkToken, err := a.TokenService.GenerateMagicLinkToken(c.Context(), req)
+	if err != nil && !errors.Is(err, myerrors.ErrUserNotFound) {
+		return err
+	}
+
+	// Unknown addresses get the same answer so the endpoint cannot be used to probe for accounts
+	if magicLinkToken != nil {
+		if sendErr := a.EmailAdapter.SendMagicLinkEmail(req.Email, magicLinkToken.Token); sendErr != nil {
+			return sendErr
+		}
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Magic link sent if the email is registered", nil))
+}
+
+// @Tags         Auth
+// @Summary      Verify magic link
+// @Description  Exchange the token from the magic link email for access and refresh tokens. The token works once. Users with two-factor authentication enabled get a short-lived mfa_token instead, to be exchanged at POST /auth/mfa/verify.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.VerifyMagicLinkRequest  true  "Request body (token from magic link email)"
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
+// @Router       /auth/magic-link/verify [post]
+// @Success      200  {object}  model.LoginResponse
+// @Success      202  {object}  model.MFAChallengeResponse  "Link accepted, second factor required"
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid, expired or already used token"
+// @Failure      403  {object}  model.ErrorForbidden  "Password expired, log in with the password to change it"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
+func (a *AuthHandlerImpl) VerifyMagicLink(c *fiber.Ctx) error {
+	req := new(model.VerifyMagicLinkRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := a.AuthService.VerifyMagicLink(c.Context(), req)
 	if err != nil {
 		return err
 	}
 
-	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String())
+	if user.TOTPEnabled {
+		return a.mfaChallenge(c, user)
+	}
+
+	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String(), deviceInfo(c))
 	if err != nil {
 		return err
 	}
 
-	callbackResp := &model.LoginResponse{
+	resp := &model.LoginResponse{
 		AccessToken:           accessToken.Token,
 		AccessTokenExpiresAt:  accessToken.Expires,
 		RefreshToken:          refreshToken.Token,
@@ -380,11 +617,85 @@ func (a *AuthHandlerImpl) GoogleCallback(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).
-		JSON(formatter.NewSuccessResponse(formatter.Success, "Login successfully", callbackResp))
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Login successfully", resp))
+}
 
-	// TODO: replace this url with the link to the oauth google success page of your front-end app
-	// googleLoginURL := fmt.Sprintf("http://link-to-app/google/success?access_token=%s&refresh_token=%s",
-	// 	tokens.Access.Token, tokens.Refresh.Token)
+// @Tags         Auth
+// @Summary      Change expired password
+// @Description  Set a new password with the password_change_token returned by a login with an expired password. The token works once. The new password must follow the password policy and differ from the recent ones. Returns access and refresh tokens on success, or a short-lived mfa_token for users with two-factor authentication enabled.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.ChangeExpiredPasswordRequest  true  "Request body (token, new password)"
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
+// @Router       /auth/change-password [post]
+// @Success      200  {object}  model.LoginResponse
+// @Success      202  {object}  model.MFAChallengeResponse  "Password changed, second factor required"
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body, validation failed or password reused"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid, expired or already used token"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
+func (a *AuthHandlerImpl) ChangeExpiredPassword(c *fiber.Ctx) error {
+	req := new(model.ChangeExpiredPasswordRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := a.AuthService.ChangeExpiredPassword(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	if user.TOTPEnabled {
+		return a.mfaChallenge(c, user)
+	}
+
+	accessToken, refreshToken, err := a.TokenService.GenerateAuthTokens(c.Context(), user.ID.String(), deviceInfo(c))
+	if err != nil {
+		return err
+	}
+
+	resp := &model.LoginResponse{
+		AccessToken:           accessToken.Token,
+		AccessTokenExpiresAt:  accessToken.Expires,
+		RefreshToken:          refreshToken.Token,
+		RefreshTokenExpiresAt: refreshToken.Expires,
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Password changed successfully", resp))
+}
+
+// passwordExpired answers a login with an expired password with a change token instead of auth tokens.
+func (a *AuthHandlerImpl) passwordExpired(c *fiber.Ctx, user *domain.User) error {
+	changeToken, err := a.AuthService.GeneratePasswordChangeToken(c.Context(), user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	resp := &model.PasswordExpiredResponse{
+		PasswordExpired:              true,
+		PasswordChangeToken:          changeToken.Token,
+		PasswordChangeTokenExpiresAt: changeToken.Expires,
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Password expired, choose a new one", resp))
+}
+
+// mfaChallenge answers a first login step with a pending token instead of auth tokens.
+func (a *AuthHandlerImpl) mfaChallenge(c *fiber.Ctx, user *domain.User) error {
+	mfaToken, err := a.TokenService.GenerateMFAPendingToken(c.Context(), user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	resp := &model.MFAChallengeResponse{
+		MFARequired:       true,
+		MFAToken:          mfaToken.Token,
+		MFATokenExpiresAt: mfaToken.Expires,
+	}
 
-	// return c.Status(fiber.StatusSeeOther).Redirect(googleLoginURL)
+	return c.Status(fiber.StatusAccepted).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Two-factor authentication required", resp))
 }
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tommynurwantoro/golog"
+)
+
+type DeviceHandler interface {
+	RequestCode(c *fiber.Ctx) error
+	Token(c *fiber.Ctx) error
+	Approve(c *fiber.Ctx) error
+}
+
+type DeviceHandlerImpl struct {
+	DeviceAuthorizationService service.DeviceAuthorizationService `inject:"deviceAuthorizationService"`
+}
+
+// @Tags         Device Authorization
+// @Summary      Start a device login
+// @Description  Start the device authorization grant (RFC 8628) for a tool without a browser, such as a CLI. Show the user_code and verification_uri to the user, then poll /auth/device/token every interval seconds with the device_code.
+// @Produce      json
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
+// @Router       /auth/device/code [post]
+// @Success      200  {object}  model.DeviceCodeResponse
+func (d *DeviceHandlerImpl) RequestCode(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	resp, err := d.DeviceAuthorizationService.CreateDeviceCode(c.Context(), deviceInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// @Tags         Device Authorization
+// @Summary      Poll for device tokens
+// @Description  Poll with the device_code until the user answers. Errors follow RFC 8628: authorization_pending while waiting, slow_down when polling faster than the interval (which grows by 5 seconds), access_denied, expired_token or invalid_grant. Once approved the access and refresh tokens are returned once.
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        request  formData  model.DeviceTokenRequest  true  "grant_type urn:ietf:params:oauth:grant-type:device_code and the device_code"
+// @Router       /auth/device/token [post]
+// @Success      200  {object}  model.OAuthTokenResponse
+// @Failure      400  {object}  model.OAuthErrorResponse  "authorization_pending, slow_down, access_denied, expired_token, invalid_grant or invalid_request"
+func (d *DeviceHandlerImpl) Token(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	req := new(model.DeviceTokenRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return oauthError(c, myerrors.ErrInvalidRequest)
+	}
+
+	resp, err := d.DeviceAuthorizationService.PollToken(c.Context(), req)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// @Tags         Device Authorization
+// @Summary      Approve a device
+// @Description  Approve or deny the device showing the user code, as the signed-in user. Each code can be answered once before it expires.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.DeviceApproveRequest  true  "Request body"
+// @Router       /auth/device/approve [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.DeviceApproveResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid or expired user code"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (d *DeviceHandlerImpl) Approve(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	req := new(model.DeviceApproveRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	resp, err := d.DeviceAuthorizationService.Approve(c.Context(), user.ID.String(), req)
+	if err != nil {
+		return err
+	}
+
+	message := "Approve device successfully"
+	if !resp.Approved {
+		message = "Deny device successfully"
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, message, resp))
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"app/internal/adapter/oauth"
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// deviceInfo describes the client a new session is opened from.
+func deviceInfo(c *fiber.Ctx) *model.DeviceInfo {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+
+	deviceName := c.Get("X-Device-Name")
+	if deviceName == "" {
+		deviceName = userAgent
+	}
+
+	return &model.DeviceInfo{
+		DeviceName: truncate(deviceName, 255),
+		UserAgent:  userAgent,
+		IPAddress:  c.IP(),
+	}
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+
+	return value[:limit]
+}
+
+const oauthStateCookie = "oauth_state"
+
+// setOAuthStateCookie binds a started authorization to the browser. The cookie lives for the browser
+// session and is only sent back to the callback; the stored state expires on its own.
+func setOAuthStateCookie(c *fiber.Ctx, value string, maxAge int) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthStateCookie,
+		Value:    value,
+		Path:     "/auth/oauth",
+		MaxAge:   maxAge,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+// beginOAuth stores a new authorization and returns the consent page URL of the provider.
+func beginOAuth(
+	c *fiber.Ctx, oauthService service.OAuthService, oidcAdapter oauth.OIDCAdapter, req *model.OAuthStateRequest,
+) (string, error) {
+	authorization, err := oauthService.CreateState(c.Context(), req)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := oidcAdapter.AuthCodeURL(
+		c.Context(), req.Provider, authorization.State, authorization.Nonce, authorization.CodeVerifier,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	setOAuthStateCookie(c, authorization.State, 0)
+
+	return authURL, nil
+}
+
+// redirectWithQuery sends the browser to an allow-listed front-end page with one query parameter added.
+func redirectWithQuery(c *fiber.Ctx, redirectURI, key, value string) error {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		return err
+	}
+
+	query := target.Query()
+	query.Set(key, value)
+	target.RawQuery = query.Encode()
+
+	return c.Redirect(target.String(), fiber.StatusSeeOther)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"app/internal/adapter/oauth"
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IdentityHandler interface {
+	GetIdentities(c *fiber.Ctx) error
+	LinkIdentity(c *fiber.Ctx) error
+	UnlinkIdentity(c *fiber.Ctx) error
+}
+
+type IdentityHandlerImpl struct {
+	IdentityService service.IdentityService `inject:"identityService"`
+	OAuthService    service.OAuthService    `inject:"oauthService"`
+	OIDCAdapter     oauth.OIDCAdapter       `inject:"oauth"`
+}
+
+// @Tags         Identities
+// @Summary      Get my linked identities
+// @Description  List the OpenID Connect provider accounts linked to the authenticated user.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/identities [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]domain.UserIdentity}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (i *IdentityHandlerImpl) GetIdentities(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	identities, err := i.IdentityService.GetIdentities(c.Context(), user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get identities successfully", identities))
+}
+
+// @Tags         Identities
+// @Summary      Link a provider account
+// @Description  Start linking an OpenID Connect provider account to the authenticated user. Open authorization_url in the same browser; the provider redirects to /auth/oauth/{provider}/callback, which links the account instead of logging in. With redirect_uri the callback then redirects there with a linked (or error) query parameter.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        provider      path   string  true   "Provider name"  example(google)
+// @Param        redirect_uri  query  string  false  "Front-end page listed in oauth2.redirect_uris"
+// @Router       /v1/identities/{provider} [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.OAuthLinkResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Redirect URI not allowed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      404  {object}  model.ErrorNotFound  "Provider not configured"
+func (i *IdentityHandlerImpl) LinkIdentity(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	authURL, err := beginOAuth(c, i.OAuthService, i.OIDCAdapter, &model.OAuthStateRequest{
+		Provider:    c.Params("provider"),
+		RedirectURI: c.Query("redirect_uri"),
+		UserID:      user.ID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Begin identity link successfully",
+			&model.OAuthLinkResponse{AuthorizationURL: authURL}))
+}
+
+// @Tags         Identities
+// @Summary      Unlink a provider account
+// @Description  Remove a linked provider account. The last identity of a user without a password cannot be removed.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        identityId  path  string  true  "Identity UUID"
+// @Router       /v1/identities/{identityId} [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid identity ID format"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      404  {object}  model.ErrorNotFound  "Identity not found"
+// @Failure      409  {object}  model.ErrorIdentityConflict  "Last way to log in"
+func (i *IdentityHandlerImpl) UnlinkIdentity(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	identityID := c.Params("identityId")
+	if _, err := uuid.Parse(identityID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid identity ID")
+	}
+
+	if err := i.IdentityService.Unlink(c.Context(), user.ID.String(), identityID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Unlink identity successfully", nil))
+}
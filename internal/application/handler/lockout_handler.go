@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type LockoutHandler interface {
+	GetLockouts(c *fiber.Ctx) error
+	GetLockout(c *fiber.Ctx) error
+	ClearLockout(c *fiber.Ctx) error
+	UnlockAccount(c *fiber.Ctx) error
+}
+
+type LockoutHandlerImpl struct {
+	LockoutService service.LockoutService `inject:"lockoutService"`
+}
+
+// @Tags         Users
+// @Summary      Get account lockouts
+// @Description  List the users with failed logins since their last successful one, and whether they are locked. Only admins (manageUsers permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/users/lockouts [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.LockoutResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (l *LockoutHandlerImpl) GetLockouts(c *fiber.Ctx) error {
+	lockouts, err := l.LockoutService.GetLockouts(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get lockouts successfully", lockouts))
+}
+
+// @Tags         Users
+// @Summary      Get the lockout of a user
+// @Description  Get the failed logins of a user and whether the account is locked. Only admins (manageUsers permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        userId  path  string  true  "User ID"
+// @Router       /v1/users/{userId}/lockout [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.LockoutResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid user ID"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "User not found or no failed logins"
+func (l *LockoutHandlerImpl) GetLockout(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	lockout, err := l.LockoutService.GetLockout(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get lockout successfully", lockout))
+}
+
+// @Tags         Users
+// @Summary      Clear the lockout of a user
+// @Description  Unlock the account of a user and forget the failed logins. Only admins (manageUsers permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        userId  path  string  true  "User ID"
+// @Router       /v1/users/{userId}/lockout [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid user ID"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "User not found or no failed logins"
+func (l *LockoutHandlerImpl) ClearLockout(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := l.LockoutService.ClearLockout(c.Context(), userID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Clear lockout successfully", nil))
+}
+
+// @Tags         Auth
+// @Summary      Unlock account
+// @Description  Unlock an account locked after too many failed logins, using the token from the unlock email. A token works once.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.UnlockAccountRequest  true  "Request body (token from the unlock email)"
+// @Router       /auth/unlock-account [post]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid, expired or used unlock token"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
+func (l *LockoutHandlerImpl) UnlockAccount(c *fiber.Ctx) error {
+	req := new(model.UnlockAccountRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := l.LockoutService.UnlockAccount(c.Context(), req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Unlock account successfully", nil))
+}
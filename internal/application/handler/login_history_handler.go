@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/pkg/formatter"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type LoginHistoryHandler interface {
+	GetLoginHistory(c *fiber.Ctx) error
+}
+
+type LoginHistoryHandlerImpl struct {
+	LoginHistoryService service.LoginHistoryService `inject:"loginHistoryService"`
+}
+
+// @Tags         Users
+// @Summary      Get the login history of a user
+// @Description  List the login attempts of a user, newest first: password and OAuth logins, failed or not, and refreshed sessions. Users can fetch only their own history; admins (getUsers) can fetch any user's.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        userId  path   string  true   "User ID"
+// @Param        page    query  int     false  "Page number"  default(1)
+// @Param        limit   query  int     false  "Items per page"  default(10)
+// @Router       /v1/users/{userId}/login-history [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.LoginEventResponse,metadata=formatter.Metadata}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid user ID or query parameters"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "User not found"
+func (l *LoginHistoryHandlerImpl) GetLoginHistory(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	query := &model.GetLoginHistoryRequest{
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", 10),
+	}
+
+	events, totalResults, err := l.LoginHistoryService.GetLoginHistory(c.Context(), userID, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponseWithMetadata(formatter.Success, "Get login history successfully", events, formatter.Metadata{
+			Page:         query.Page,
+			Limit:        query.Limit,
+			TotalPages:   int64(math.Ceil(float64(totalResults) / float64(query.Limit))),
+			TotalResults: totalResults,
+		}))
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tommynurwantoro/golog"
+)
+
+type MFAHandler interface {
+	EnrollTOTP(c *fiber.Ctx) error
+	ConfirmTOTP(c *fiber.Ctx) error
+	Verify(c *fiber.Ctx) error
+}
+
+type MFAHandlerImpl struct {
+	MFAService   service.MFAService   `inject:"mfaService"`
+	TokenService service.TokenService `inject:"tokenService"`
+}
+
+// @Tags         MFA
+// @Summary      Enroll TOTP
+// @Description  Generate a new TOTP secret for the authenticated user. Add it to an authenticator app (the otpauth_url can be rendered as a QR code), then confirm it with a code. Enrolling again before confirming replaces the secret.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/mfa/totp [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.EnrollTOTPResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      409  {object}  model.ErrorMFAAlreadyEnabled  "Two-factor authentication is already enabled"
+func (m *MFAHandlerImpl) EnrollTOTP(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	secret, otpAuthURL, err := m.MFAService.EnrollTOTP(c.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	resp := &model.EnrollTOTPResponse{
+		Secret:     secret,
+		OTPAuthURL: otpAuthURL,
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Enroll TOTP successfully", resp))
+}
+
+// @Tags         MFA
+// @Summary      Confirm TOTP
+// @Description  Enable two-factor authentication with a code from the enrolled authenticator. Returns single-use recovery codes; they are stored hashed and shown only once.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.ConfirmTOTPRequest  true  "Request body (6 digit code)"
+// @Router       /v1/mfa/totp/confirm [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.ConfirmTOTPResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body, validation failed or TOTP not enrolled"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid access token or code"
+// @Failure      409  {object}  model.ErrorMFAAlreadyEnabled  "Two-factor authentication is already enabled"
+func (m *MFAHandlerImpl) ConfirmTOTP(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	req := new(model.ConfirmTOTPRequest)
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	recoveryCodes, err := m.MFAService.ConfirmTOTP(c.Context(), user, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Two-factor authentication enabled",
+			&model.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}))
+}
+
+// @Tags         Auth
+// @Summary      Verify second factor
+// @Description  Finish a two-step login with the mfa_token returned by login and a TOTP code or an unused recovery code. Returns access and refresh tokens on success. The mfa_token works once, so a wrong code means logging in again, and wrong codes count towards the account lockout like wrong passwords.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.VerifyMFARequest  true  "Request body (mfa_token, code)"
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
+// @Router       /auth/mfa/verify [post]
+// @Success      200  {object}  model.LoginResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid, expired or already used mfa_token, or invalid code"
+// @Failure      403  {object}  model.ErrorForbidden  "Password expired, log in with the password to change it"
+// @Failure      423  {object}  model.ErrorAccountLocked  "Account locked after too many failed logins"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too soon after a failed login"
+func (m *MFAHandlerImpl) Verify(c *fiber.Ctx) error {
+	req := new(model.VerifyMFARequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := m.MFAService.VerifyLogin(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	accessToken, refreshToken, err := m.TokenService.GenerateAuthTokens(c.Context(), user.ID.String(), deviceInfo(c))
+	if err != nil {
+		return err
+	}
+
+	resp := &model.LoginResponse{
+		AccessToken:           accessToken.Token,
+		AccessTokenExpiresAt:  accessToken.Expires,
+		RefreshToken:          refreshToken.Token,
+		RefreshTokenExpiresAt: refreshToken.Expires,
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Login successfully", resp))
+}
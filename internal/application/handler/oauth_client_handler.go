@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type OAuthClientHandler interface {
+	GetClients(c *fiber.Ctx) error
+	CreateClient(c *fiber.Ctx) error
+	DeleteClient(c *fiber.Ctx) error
+}
+
+type OAuthClientHandlerImpl struct {
+	OAuthClientService service.OAuthClientService `inject:"oauthClientService"`
+}
+
+// @Tags         OAuth Clients
+// @Summary      Get OAuth clients
+// @Description  List the clients registered with the authorization server. Only admins (manageOAuthClients permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/oauth-clients [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.OAuthClientResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (o *OAuthClientHandlerImpl) GetClients(c *fiber.Ctx) error {
+	clients, err := o.OAuthClientService.GetClients(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get oauth clients successfully", clients))
+}
+
+// @Tags         OAuth Clients
+// @Summary      Register an OAuth client
+// @Description  Register a client with the authorization server. The secret of a confidential client is returned only in this response. Only admins (manageOAuthClients permission) can access.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.CreateOAuthClientRequest  true  "Request body"
+// @Router       /v1/oauth-clients [post]
+// @Success      201  {object}  formatter.SuccessResponse{data=model.OAuthClientResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (o *OAuthClientHandlerImpl) CreateClient(c *fiber.Ctx) error {
+	req := new(model.CreateOAuthClientRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	client, err := o.OAuthClientService.CreateClient(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Create oauth client successfully", client))
+}
+
+// @Tags         OAuth Clients
+// @Summary      Delete an OAuth client
+// @Description  Delete a client together with every session and refresh token issued to it. Only admins (manageOAuthClients permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        clientId  path  string  true  "Client ID"
+// @Router       /v1/oauth-clients/{clientId} [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid client ID format"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "OAuth client not found"
+func (o *OAuthClientHandlerImpl) DeleteClient(c *fiber.Ctx) error {
+	clientID := c.Params("clientId")
+
+	if _, err := uuid.Parse(clientID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid client ID")
+	}
+
+	if err := o.OAuthClientService.DeleteClient(c.Context(), clientID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Delete oauth client successfully", nil))
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/formatter"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tommynurwantoro/golog"
+)
+
+type OAuthServerHandler interface {
+	GetAuthorization(c *fiber.Ctx) error
+	Authorize(c *fiber.Ctx) error
+	Token(c *fiber.Ctx) error
+	Introspect(c *fiber.Ctx) error
+	Revoke(c *fiber.Ctx) error
+}
+
+type OAuthServerHandlerImpl struct {
+	OAuthServerService service.OAuthServerService `inject:"oauthServerService"`
+	OAuthClientService service.OAuthClientService `inject:"oauthClientService"`
+}
+
+// @Tags         OAuth Server
+// @Summary      Check an authorization request
+// @Description  Validate the authorization request of a client and describe it, so the consent page of the front-end can ask the signed-in user. Only response_type=code with an S256 code_challenge is supported.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        request  query  model.OAuthAuthorizeRequest  true  "Authorization request of the client"
+// @Router       /oauth/authorize [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.OAuthConsentResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request, client, redirect URI or scope"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (o *OAuthServerHandlerImpl) GetAuthorization(c *fiber.Ctx) error {
+	req := new(model.OAuthAuthorizeRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		golog.Error("Error parsing query", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query")
+	}
+
+	consent, err := o.OAuthServerService.ValidateAuthorization(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get authorization successfully", consent))
+}
+
+// @Tags         OAuth Server
+// @Summary      Approve an authorization request
+// @Description  Record the consent of the signed-in user and issue a single-use authorization code. The front-end sends the browser to redirect_to, the redirect URI of the client with the code and state.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.OAuthAuthorizeRequest  true  "Authorization request of the client"
+// @Router       /oauth/authorize [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.OAuthAuthorizeResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request, client, redirect URI or scope"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (o *OAuthServerHandlerImpl) Authorize(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	req := new(model.OAuthAuthorizeRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	resp, err := o.OAuthServerService.Authorize(c.Context(), user.ID.String(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Authorize successfully", resp))
+}
+
+// @Tags         OAuth Server
+// @Summary      Token endpoint
+// @Description  Exchange an authorization code (with its PKCE code_verifier), a refresh token or the client credentials for tokens (RFC 6749 section 4). Clients authenticate with HTTP Basic or the client_id and client_secret form fields; public clients send only client_id. Errors follow RFC 6749 section 5.2.
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        request  formData  model.OAuthTokenRequest  true  "Token request"
+// @Param        client_id  formData  string  false  "Client ID when HTTP Basic is not used"
+// @Param        client_secret  formData  string  false  "Client secret when HTTP Basic is not used"
+// @Router       /oauth/token [post]
+// @Success      200  {object}  model.OAuthTokenResponse
+// @Failure      400  {object}  model.OAuthErrorResponse  "invalid_request, invalid_grant, unauthorized_client, unsupported_grant_type or invalid_scope"
+// @Failure      401  {object}  model.OAuthErrorResponse  "invalid_client"
+func (o *OAuthServerHandlerImpl) Token(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	client, err := o.authenticateClient(c)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	req := new(model.OAuthTokenRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return oauthError(c, myerrors.ErrInvalidRequest)
+	}
+
+	resp, err := o.OAuthServerService.Token(c.Context(), client, req, deviceInfo(c))
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// @Tags         OAuth Server
+// @Summary      Introspect a token
+// @Description  Report whether an access or refresh token is active (RFC 7662). Only confidential clients may introspect.
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        request  formData  model.OAuthTokenHintRequest  true  "Token to introspect"
+// @Router       /oauth/introspect [post]
+// @Success      200  {object}  model.OAuthIntrospectionResponse
+// @Failure      400  {object}  model.OAuthErrorResponse  "invalid_request or unauthorized_client"
+// @Failure      401  {object}  model.OAuthErrorResponse  "invalid_client"
+func (o *OAuthServerHandlerImpl) Introspect(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	client, err := o.authenticateClient(c)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	req := new(model.OAuthTokenHintRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return oauthError(c, myerrors.ErrInvalidRequest)
+	}
+
+	resp, err := o.OAuthServerService.Introspect(c.Context(), client, req)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// @Tags         OAuth Server
+// @Summary      Revoke a token
+// @Description  Revoke an access or refresh token issued to the calling client (RFC 7009). Revoking a refresh token ends its session. Unknown tokens are ignored, so the response is always empty.
+// @Accept       x-www-form-urlencoded
+// @Param        request  formData  model.OAuthTokenHintRequest  true  "Token to revoke"
+// @Router       /oauth/revoke [post]
+// @Success      200
+// @Failure      400  {object}  model.OAuthErrorResponse  "invalid_request"
+// @Failure      401  {object}  model.OAuthErrorResponse  "invalid_client"
+func (o *OAuthServerHandlerImpl) Revoke(c *fiber.Ctx) error {
+	client, err := o.authenticateClient(c)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	req := new(model.OAuthTokenHintRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return oauthError(c, myerrors.ErrInvalidRequest)
+	}
+
+	if err := o.OAuthServerService.Revoke(c.Context(), client, req); err != nil {
+		return oauthError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// authenticateClient reads the client credentials from HTTP Basic, whose parts are form-encoded
+// (RFC 6749 section 2.3.1), or from the request body.
+func (o *OAuthServerHandlerImpl) authenticateClient(c *fiber.Ctx) (*domain.OAuthClient, error) {
+	credentials := new(model.OAuthClientCredentials)
+
+	if authorization := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(authorization, "Basic ") {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authorization, "Basic "))
+		if err != nil {
+			return nil, myerrors.ErrInvalidClient
+		}
+
+		clientID, clientSecret, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return nil, myerrors.ErrInvalidClient
+		}
+
+		if credentials.ClientID, err = url.QueryUnescape(clientID); err != nil {
+			return nil, myerrors.ErrInvalidClient
+		}
+		if credentials.ClientSecret, err = url.QueryUnescape(clientSecret); err != nil {
+			return nil, myerrors.ErrInvalidClient
+		}
+	} else if err := c.BodyParser(credentials); err != nil {
+		golog.Error("Error parsing client credentials", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	return o.OAuthClientService.Authenticate(c.Context(), credentials)
+}
+
+// oauthErrorCodes maps errors to the error codes of RFC 6749 section 5.2 and RFC 8628 section 3.5.
+// Other errors go to the global handler.
+var oauthErrorCodes = map[error]string{
+	myerrors.ErrInvalidRequest:       "invalid_request",
+	myerrors.ErrInvalidClient:        "invalid_client",
+	myerrors.ErrInvalidGrant:         "invalid_grant",
+	myerrors.ErrPasswordExpired:      "invalid_grant",
+	myerrors.ErrUnauthorizedClient:   "unauthorized_client",
+	myerrors.ErrUnsupportedGrantType: "unsupported_grant_type",
+	myerrors.ErrInvalidScope:         "invalid_scope",
+	myerrors.ErrAuthorizationPending: "authorization_pending",
+	myerrors.ErrSlowDown:             "slow_down",
+	myerrors.ErrAccessDenied:         "access_denied",
+	myerrors.ErrExpiredToken:         "expired_token",
+}
+
+func oauthError(c *fiber.Ctx, err error) error {
+	for target, code := range oauthErrorCodes {
+		if !errors.Is(err, target) {
+			continue
+		}
+
+		status := fiber.StatusBadRequest
+		if target == myerrors.ErrInvalidClient {
+			status = fiber.StatusUnauthorized
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		}
+
+		return c.Status(status).JSON(model.OAuthErrorResponse{Error: code, ErrorDescription: err.Error()})
+	}
+
+	return err
+}
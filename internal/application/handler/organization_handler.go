@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"app/internal/adapter/email"
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/formatter"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type OrganizationHandler interface {
+	GetOrganizations(c *fiber.Ctx) error
+	CreateOrganization(c *fiber.Ctx) error
+	GetMemberships(c *fiber.Ctx) error
+	GetCurrentOrganization(c *fiber.Ctx) error
+	GetMembers(c *fiber.Ctx) error
+	SetMember(c *fiber.Ctx) error
+	RemoveMember(c *fiber.Ctx) error
+	GetInvitations(c *fiber.Ctx) error
+	CreateInvitation(c *fiber.Ctx) error
+	RevokeInvitation(c *fiber.Ctx) error
+	AcceptInvitation(c *fiber.Ctx) error
+}
+
+type OrganizationHandlerImpl struct {
+	OrganizationService service.OrganizationService `inject:"organizationService"`
+	InvitationService   service.InvitationService   `inject:"invitationService"`
+	EmailAdapter        email.EmailAdapter          `inject:"email"`
+}
+
+// @Tags         Organizations
+// @Summary      Get organizations
+// @Description  List every organization. Only platform admins (manageOrganizations permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/organizations [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.OrganizationResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (o *OrganizationHandlerImpl) GetOrganizations(c *fiber.Ctx) error {
+	organizations, err := o.OrganizationService.GetOrganizations(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get organizations successfully", organizations))
+}
+
+// @Tags         Organizations
+// @Summary      Create an organization
+// @Description  Create an organization. Slugs may contain lowercase letters, digits and single "-". The user given as admin_id becomes its first org-admin. Only platform admins (manageOrganizations permission) can access.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.CreateOrganizationRequest  true  "Request body"
+// @Router       /v1/organizations [post]
+// @Success      201  {object}  formatter.SuccessResponse{data=model.OrganizationResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "Admin user not found"
+// @Failure      409  {object}  model.ErrorOrganizationConflict  "Slug already taken"
+func (o *OrganizationHandlerImpl) CreateOrganization(c *fiber.Ctx) error {
+	req := new(model.CreateOrganizationRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	organization, err := o.OrganizationService.CreateOrganization(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Create organization successfully", organization))
+}
+
+// @Tags         Organizations
+// @Summary      Get my organizations
+// @Description  List the organizations the logged-in user is a member of, with the role in each. The first one is used when no X-Organization-ID header is sent.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/organizations/memberships [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.MembershipResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (o *OrganizationHandlerImpl) GetMemberships(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	memberships, err := o.OrganizationService.GetMemberships(c.Context(), user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get memberships successfully", memberships))
+}
+
+// @Tags         Organizations
+// @Summary      Get the current organization
+// @Description  Get the organization the request acts in, from the X-Organization-ID header or the access token.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        X-Organization-ID  header  string  false  "Organization ID, defaults to the organization of the access token"
+// @Router       /v1/organizations/current [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.OrganizationResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "No organization selected"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Not a member of the organization"
+// @Failure      404  {object}  model.ErrorNotFound  "Organization not found"
+func (o *OrganizationHandlerImpl) GetCurrentOrganization(c *fiber.Ctx) error {
+	organizationID := tenant.OrganizationID(c.Context())
+	if organizationID == "" {
+		return myerrors.ErrNoOrganization
+	}
+
+	organization, err := o.OrganizationService.GetOrganization(c.Context(), organizationID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get organization successfully", organization))
+}
+
+// @Tags         Organizations
+// @Summary      Get members
+// @Description  List the members of the current organization with their roles. Only org-admins (manageMembers permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        X-Organization-ID  header  string  false  "Organization ID, defaults to the organization of the access token"
+// @Router       /v1/organizations/current/members [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.MemberResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "No organization selected"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (o *OrganizationHandlerImpl) GetMembers(c *fiber.Ctx) error {
+	members, err := o.OrganizationService.GetMembers(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get members successfully", members))
+}
+
+// @Tags         Organizations
+// @Summary      Add or update a member
+// @Description  Add a user to the current organization or change the role of a member. Only roles whose permissions the caller has can be granted or taken away. Adding users who are not members yet requires the manageOrganizations permission. Only org-admins (manageMembers permission) can access.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        X-Organization-ID  header  string                  false  "Organization ID, defaults to the organization of the access token"
+// @Param        userId             path    string                  true   "User ID"
+// @Param        request            body    model.SetMemberRequest  true   "Request body"
+// @Router       /v1/organizations/current/members/{userId} [put]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid user ID, request body or role"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions or role not grantable"
+// @Failure      404  {object}  model.ErrorNotFound  "User or member not found"
+func (o *OrganizationHandlerImpl) SetMember(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	subject, ok := c.Locals("subject").(policy.Subject)
+	if !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	req := new(model.SetMemberRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := o.OrganizationService.SetMember(c.Context(), subject, userID, req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Set member successfully", nil))
+}
+
+// @Tags         Organizations
+// @Summary      Remove a member
+// @Description  Remove a user from the current organization. Only members whose role the caller could grant can be removed. Only org-admins (manageMembers permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        X-Organization-ID  header  string  false  "Organization ID, defaults to the organization of the access token"
+// @Param        userId             path    string  true   "User ID"
+// @Router       /v1/organizations/current/members/{userId} [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid user ID"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions or role not grantable"
+// @Failure      404  {object}  model.ErrorNotFound  "Member not found"
+func (o *OrganizationHandlerImpl) RemoveMember(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	subject, ok := c.Locals("subject").(policy.Subject)
+	if !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	if err := o.OrganizationService.RemoveMember(c.Context(), subject, userID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Remove member successfully", nil))
+}
+
+// @Tags         Organizations
+// @Summary      Get invitations
+// @Description  List the pending invitations of the current organization, including expired ones. Only org-admins (manageMembers permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        X-Organization-ID  header  string  false  "Organization ID, defaults to the organization of the access token"
+// @Router       /v1/organizations/current/invitations [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.InvitationResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "No organization selected"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (o *OrganizationHandlerImpl) GetInvitations(c *fiber.Ctx) error {
+	invitations, err := o.InvitationService.GetInvitations(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get invitations successfully", invitations))
+}
+
+// @Tags         Organizations
+// @Summary      Invite a user
+// @Description  Email an invitation to join the current organization with a role, "user" by default. Only roles whose permissions the caller has can be given. Inviting an address again replaces its pending invitation. Only org-admins (manageMembers permission) can access.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        X-Organization-ID  header  string                         false  "Organization ID, defaults to the organization of the access token"
+// @Param        request            body    model.CreateInvitationRequest  true   "Request body"
+// @Router       /v1/organizations/current/invitations [post]
+// @Success      201  {object}  formatter.SuccessResponse{data=model.InvitationResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body, validation failed or no organization selected"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions or role not grantable"
+// @Failure      409  {object}  model.ErrorOrganizationConflict  "User is already a member"
+func (o *OrganizationHandlerImpl) CreateInvitation(c *fiber.Ctx) error {
+	subject, ok := c.Locals("subject").(policy.Subject)
+	if !ok {
+		return myerrors.ErrInvalidToken
+	}
+
+	req := new(model.CreateInvitationRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	invitation, invitationToken, err := o.InvitationService.CreateInvitation(c.Context(), subject, req)
+	if err != nil {
+		return err
+	}
+
+	sendErr := o.EmailAdapter.SendInvitationEmail(invitation.Email, invitation.OrganizationName, invitationToken.Token)
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Invitation sent successfully", invitation))
+}
+
+// @Tags         Organizations
+// @Summary      Revoke an invitation
+// @Description  Delete a pending invitation of the current organization, so its link stops working. Only org-admins (manageMembers permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        X-Organization-ID  header  string  false  "Organization ID, defaults to the organization of the access token"
+// @Param        invitationId       path    string  true   "Invitation ID"
+// @Router       /v1/organizations/current/invitations/{invitationId} [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid invitation ID or no organization selected"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "Invitation not found"
+func (o *OrganizationHandlerImpl) RevokeInvitation(c *fiber.Ctx) error {
+	invitationID := c.Params("invitationId")
+
+	if _, err := uuid.Parse(invitationID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid invitation ID")
+	}
+
+	if err := o.InvitationService.RevokeInvitation(c.Context(), invitationID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Revoke invitation successfully", nil))
+}
+
+// @Tags         Organizations
+// @Summary      Accept an invitation
+// @Description  Join the organization of an invitation with the token from the invitation email. If no account has the invited email, name and password are required and the account is created with the email verified; otherwise the existing account joins as is. The token works once.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.AcceptInvitationRequest  true  "Request body"
+// @Router       /v1/organizations/invitations/accept [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.AcceptInvitationResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body, validation failed or missing account details"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid, expired, revoked or used invitation token"
+func (o *OrganizationHandlerImpl) AcceptInvitation(c *fiber.Ctx) error {
+	req := new(model.AcceptInvitationRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	resp, err := o.InvitationService.AcceptInvitation(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Accept invitation successfully", resp))
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+type RoleHandler interface {
+	GetRoles(c *fiber.Ctx) error
+	GetRole(c *fiber.Ctx) error
+	CreateRole(c *fiber.Ctx) error
+	UpdateRole(c *fiber.Ctx) error
+	DeleteRole(c *fiber.Ctx) error
+	GetPermissions(c *fiber.Ctx) error
+}
+
+type RoleHandlerImpl struct {
+	RoleService service.RoleService `inject:"roleService"`
+}
+
+// @Tags         Roles
+// @Summary      Get roles
+// @Description  List every role with its permissions. Only admins (manageRoles permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/roles [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.RoleResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (r *RoleHandlerImpl) GetRoles(c *fiber.Ctx) error {
+	roles, err := r.RoleService.GetRoles(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get roles successfully", roles))
+}
+
+// @Tags         Roles
+// @Summary      Get a role
+// @Description  Get a role with its permissions. Only admins (manageRoles permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        roleId  path  string  true  "Role ID"
+// @Router       /v1/roles/{roleId} [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.RoleResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid role ID format"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "Role not found"
+func (r *RoleHandlerImpl) GetRole(c *fiber.Ctx) error {
+	roleID := c.Params("roleId")
+
+	if _, err := uuid.Parse(roleID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid role ID")
+	}
+
+	role, err := r.RoleService.GetRole(c.Context(), roleID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get role successfully", role))
+}
+
+// @Tags         Roles
+// @Summary      Create a role
+// @Description  Create a role with a set of existing permissions. Names may contain letters, digits, "-" and "_". Only admins (manageRoles permission) can access.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.CreateRoleRequest  true  "Request body"
+// @Router       /v1/roles [post]
+// @Success      201  {object}  formatter.SuccessResponse{data=model.RoleResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body, validation failed or unknown permission"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      409  {object}  model.ErrorRoleConflict  "Role already exists"
+func (r *RoleHandlerImpl) CreateRole(c *fiber.Ctx) error {
+	req := new(model.CreateRoleRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	role, err := r.RoleService.CreateRole(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Create role successfully", role))
+}
+
+// @Tags         Roles
+// @Summary      Update a role
+// @Description  Change the description or replace the permissions of a role. Users with the role get the new permissions on their next request. Only admins (manageRoles permission) can access.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        roleId   path  string                    true  "Role ID"
+// @Param        request  body  model.UpdateRoleRequest  true  "Request body"
+// @Router       /v1/roles/{roleId} [patch]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.RoleResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body, validation failed or unknown permission"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "Role not found"
+func (r *RoleHandlerImpl) UpdateRole(c *fiber.Ctx) error {
+	roleID := c.Params("roleId")
+
+	if _, err := uuid.Parse(roleID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid role ID")
+	}
+
+	req := new(model.UpdateRoleRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	role, err := r.RoleService.UpdateRole(c.Context(), roleID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Update role successfully", role))
+}
+
+// @Tags         Roles
+// @Summary      Delete a role
+// @Description  Delete a role that no user has. The default "user" role cannot be deleted. Only admins (manageRoles permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        roleId  path  string  true  "Role ID"
+// @Router       /v1/roles/{roleId} [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid role ID format"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "Role not found"
+// @Failure      409  {object}  model.ErrorRoleConflict  "Role is still assigned or is the default role"
+func (r *RoleHandlerImpl) DeleteRole(c *fiber.Ctx) error {
+	roleID := c.Params("roleId")
+
+	if _, err := uuid.Parse(roleID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid role ID")
+	}
+
+	if err := r.RoleService.DeleteRole(c.Context(), roleID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Delete role successfully", nil))
+}
+
+// @Tags         Roles
+// @Summary      Get permissions
+// @Description  List the permissions that can be assigned to roles. Only admins (manageRoles permission) can access.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/roles/permissions [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.PermissionResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+func (r *RoleHandlerImpl) GetPermissions(c *fiber.Ctx) error {
+	permissions, err := r.RoleService.GetPermissions(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get permissions successfully", permissions))
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type SessionHandler interface {
+	GetSessions(c *fiber.Ctx) error
+	DeleteSession(c *fiber.Ctx) error
+	DeleteOtherSessions(c *fiber.Ctx) error
+}
+
+type SessionHandlerImpl struct {
+	TokenService service.TokenService `inject:"tokenService"`
+}
+
+// @Tags         Sessions
+// @Summary      Get my sessions
+// @Description  List the active sessions (one per login) of the authenticated user. The session of the current access token is flagged as current.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/sessions [get]
+// @Success      200  {object}  formatter.SuccessResponse{data=[]model.SessionResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (s *SessionHandlerImpl) GetSessions(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+	currentSessionID, _ := c.Locals("sessionId").(string)
+
+	sessions, err := s.TokenService.GetSessions(c.Context(), user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]model.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, model.SessionResponse{
+			ID:         session.ID.String(),
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    session.ID.String() == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Get sessions successfully", resp))
+}
+
+// @Tags         Sessions
+// @Summary      Revoke a session
+// @Description  Log out one session of the authenticated user. Its refresh token stops working immediately.
+// @Security     BearerAuth
+// @Produce      json
+// @Param        sessionId  path  string  true  "Session UUID"
+// @Router       /v1/sessions/{sessionId} [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid session ID format"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      404  {object}  model.ErrorNotFound  "Session not found"
+func (s *SessionHandlerImpl) DeleteSession(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	sessionID := c.Params("sessionId")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid session ID")
+	}
+
+	if err := s.TokenService.DeleteSession(c.Context(), user.ID.String(), sessionID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Delete session successfully", nil))
+}
+
+// @Tags         Sessions
+// @Summary      Log out everywhere else
+// @Description  Revoke every session of the authenticated user except the one the current access token belongs to.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /v1/sessions/others [delete]
+// @Success      200  {object}  model.SuccessMessageAPIResponse
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (s *SessionHandlerImpl) DeleteOtherSessions(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+	currentSessionID, _ := c.Locals("sessionId").(string)
+
+	if err := s.TokenService.DeleteOtherSessions(c.Context(), user.ID.String(), currentSessionID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Delete other sessions successfully", nil))
+}
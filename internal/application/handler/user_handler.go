@@ -16,6 +16,8 @@ type UserHandler interface {
 	GetUserByID(c *fiber.Ctx) error
 	CreateUser(c *fiber.Ctx) error
 	UpdateUser(c *fiber.Ctx) error
+	UpdateUserRoles(c *fiber.Ctx) error
+	UpdateUserAttributes(c *fiber.Ctx) error
 	DeleteUser(c *fiber.Ctx) error
 }
 
@@ -88,7 +90,8 @@ func (u *UserHandlerImpl) GetUserByID(c *fiber.Ctx) error {
 		ID:              user.ID.String(),
 		Name:            user.Name,
 		Email:           user.Email,
-		Role:            user.Role,
+		Roles:           user.RoleNames(),
+		Attributes:      user.Attributes,
 		IsEmailVerified: user.VerifiedEmail,
 	}
 
@@ -126,7 +129,7 @@ func (u *UserHandlerImpl) CreateUser(c *fiber.Ctx) error {
 		ID:              user.ID.String(),
 		Name:            user.Name,
 		Email:           user.Email,
-		Role:            user.Role,
+		Roles:           user.RoleNames(),
 		IsEmailVerified: user.VerifiedEmail,
 	}
 
@@ -157,13 +160,13 @@ func (u *UserHandlerImpl) UpdateUser(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	req.UserID = userID
-
 	if err := c.BodyParser(&req); err != nil {
 		golog.Error("Error parsing request body", err)
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
+	req.UserID = userID
+
 	user, err := u.UserService.UpdateUser(c.Context(), req)
 	if err != nil {
 		return err
@@ -173,7 +176,8 @@ func (u *UserHandlerImpl) UpdateUser(c *fiber.Ctx) error {
 		ID:              user.ID.String(),
 		Name:            user.Name,
 		Email:           user.Email,
-		Role:            user.Role,
+		Roles:           user.RoleNames(),
+		Attributes:      user.Attributes,
 		IsEmailVerified: user.VerifiedEmail,
 	}
 
@@ -181,6 +185,98 @@ func (u *UserHandlerImpl) UpdateUser(c *fiber.Ctx) error {
 		JSON(formatter.NewSuccessResponse(formatter.Success, "Update user successfully", resp))
 }
 
+// @Tags         Users
+// @Summary      Update the roles of a user
+// @Description  Replace the roles of a user. The user gets the permissions of all roles; access tokens issued before are revoked, so the user has to refresh them. Only admins (manageRoles permission) can access, including for themselves.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        userId   path  string                        true  "User UUID"
+// @Param        request  body  model.UpdateUserRolesRequest  true  "Request body"
+// @Router       /v1/users/{userId}/roles [put]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.UpdateUserResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid user ID, request body or unknown role"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "User not found"
+func (u *UserHandlerImpl) UpdateUserRoles(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	req := new(model.UpdateUserRolesRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := u.UserService.UpdateUserRoles(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	resp := &model.UpdateUserResponse{
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		Email:           user.Email,
+		Roles:           user.RoleNames(),
+		Attributes:      user.Attributes,
+		IsEmailVerified: user.VerifiedEmail,
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Update user roles successfully", resp))
+}
+
+// @Tags         Users
+// @Summary      Update the attributes of a user
+// @Description  Replace the attributes of a user, such as its region, that attribute policies compare. Only admins (manageUsers permission) can access, including for themselves.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        userId   path  string                             true  "User UUID"
+// @Param        request  body  model.UpdateUserAttributesRequest  true  "Request body"
+// @Router       /v1/users/{userId}/attributes [put]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.UpdateUserResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid user ID or request body"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+// @Failure      403  {object}  model.ErrorForbidden  "Insufficient permissions"
+// @Failure      404  {object}  model.ErrorNotFound  "User not found"
+func (u *UserHandlerImpl) UpdateUserAttributes(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	req := new(model.UpdateUserAttributesRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := u.UserService.UpdateUserAttributes(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	resp := &model.UpdateUserResponse{
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		Email:           user.Email,
+		Roles:           user.RoleNames(),
+		Attributes:      user.Attributes,
+		IsEmailVerified: user.VerifiedEmail,
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Update user attributes successfully", resp))
+}
+
 // @Tags         Users
 // @Summary      Delete a user
 // @Description  Delete user by ID. Users can delete only themselves; admins (manageUsers) can delete any user. All tokens are revoked.
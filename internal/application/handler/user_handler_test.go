@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type userHandlerTestSuite struct {
+	suite.Suite
+	mockCtrl    *gomock.Controller
+	mockUserSvc *mocks.MockUserService
+	app         *fiber.App
+}
+
+func TestUserHandler(t *testing.T) {
+	suite.Run(t, new(userHandlerTestSuite))
+}
+
+func (s *userHandlerTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+
+	userHandler := &UserHandlerImpl{UserService: s.mockUserSvc}
+
+	s.app = fiber.New()
+	s.app.Patch("/v1/users/:userId", userHandler.UpdateUser)
+}
+
+func (s *userHandlerTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *userHandlerTestSuite) TestUpdateUser_IgnoresBodyUserID() {
+	// Access is checked on the path, so a user_id in the body must not pick another user
+	userID := uuid.Must(uuid.NewV7())
+	victimID := uuid.Must(uuid.NewV7())
+
+	s.mockUserSvc.EXPECT().
+		UpdateUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *model.UpdateUserRequest) (*domain.User, error) {
+			s.Equal(userID.String(), req.UserID)
+			s.Equal("taken@example.com", req.Email)
+			return &domain.User{ID: userID, Email: req.Email}, nil
+		})
+
+	body := `{"user_id":"` + victimID.String() + `","email":"taken@example.com"}`
+	req := httptest.NewRequest(fiber.MethodPatch, "/v1/users/"+userID.String(), strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusOK, resp.StatusCode)
+}
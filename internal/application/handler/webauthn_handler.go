@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"app/internal/application/model"
+	"app/internal/application/service"
+	"app/internal/domain"
+	"app/internal/pkg/formatter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tommynurwantoro/golog"
+)
+
+type WebAuthnHandler interface {
+	BeginRegistration(c *fiber.Ctx) error
+	FinishRegistration(c *fiber.Ctx) error
+	BeginLogin(c *fiber.Ctx) error
+	FinishLogin(c *fiber.Ctx) error
+}
+
+type WebAuthnHandlerImpl struct {
+	WebAuthnService service.WebAuthnService `inject:"webAuthnService"`
+	TokenService    service.TokenService    `inject:"tokenService"`
+}
+
+// @Tags         WebAuthn
+// @Summary      Begin passkey registration
+// @Description  Start registering a passkey for the authenticated user. Pass options to navigator.credentials.create() and send the result with the challenge_token to the finish endpoint before the token expires.
+// @Security     BearerAuth
+// @Produce      json
+// @Router       /auth/webauthn/register/begin [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.WebAuthnRegistrationBeginResponse}
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or missing access token"
+func (w *WebAuthnHandlerImpl) BeginRegistration(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	resp, err := w.WebAuthnService.BeginRegistration(c.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Begin passkey registration successfully", resp))
+}
+
+// @Tags         WebAuthn
+// @Summary      Finish passkey registration
+// @Description  Verify the attestation returned by the authenticator and store the passkey. The challenge_token can only be used once.
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.WebAuthnRegistrationFinishRequest  true  "Request body (challenge_token, credential, optional name)"
+// @Router       /auth/webauthn/register/finish [post]
+// @Success      201  {object}  formatter.SuccessResponse{data=domain.WebAuthnCredential}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid access token, challenge token or attestation"
+// @Failure      409  {object}  model.ErrorPasskeyAlreadyRegistered  "Passkey already registered"
+func (w *WebAuthnHandlerImpl) FinishRegistration(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*domain.User)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid user context")
+	}
+
+	req := new(model.WebAuthnRegistrationFinishRequest)
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	credential, err := w.WebAuthnService.FinishRegistration(c.Context(), user, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Passkey registered successfully", credential))
+}
+
+// @Tags         WebAuthn
+// @Summary      Begin passkey login
+// @Description  Start a passkey login for the account with the given email. Pass options to navigator.credentials.get() and send the result with the challenge_token to the finish endpoint before the token expires.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.WebAuthnLoginBeginRequest  true  "Request body (email)"
+// @Router       /auth/webauthn/login/begin [post]
+// @Success      200  {object}  formatter.SuccessResponse{data=model.WebAuthnLoginBeginResponse}
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      404  {object}  model.ErrorNotFound  "User not found or no passkey registered"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
+func (w *WebAuthnHandlerImpl) BeginLogin(c *fiber.Ctx) error {
+	req := new(model.WebAuthnLoginBeginRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	resp, err := w.WebAuthnService.BeginLogin(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Begin passkey login successfully", resp))
+}
+
+// @Tags         WebAuthn
+// @Summary      Finish passkey login
+// @Description  Verify the assertion returned by the authenticator. Returns access and refresh tokens on success. Passkeys require user verification, so no second factor is asked for.
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.WebAuthnLoginFinishRequest  true  "Request body (challenge_token, credential)"
+// @Param        X-Device-Name  header  string  false  "Name of the device the session is opened from"
+// @Router       /auth/webauthn/login/finish [post]
+// @Success      200  {object}  model.LoginResponse
+// @Failure      400  {object}  model.ErrorInvalidRequest  "Invalid request body or validation failed"
+// @Failure      401  {object}  model.ErrorUnauthorized  "Invalid or expired challenge token, or invalid assertion"
+// @Failure      403  {object}  model.ErrorForbidden  "Password expired, log in with the password to change it"
+// @Failure      429  {object}  model.ErrorTooManyRequests  "Too many failed requests"
+func (w *WebAuthnHandlerImpl) FinishLogin(c *fiber.Ctx) error {
+	req := new(model.WebAuthnLoginFinishRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		golog.Error("Error parsing request body", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := w.WebAuthnService.FinishLogin(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	accessToken, refreshToken, err := w.TokenService.GenerateAuthTokens(c.Context(), user.ID.String(), deviceInfo(c))
+	if err != nil {
+		return err
+	}
+
+	resp := &model.LoginResponse{
+		AccessToken:           accessToken.Token,
+		AccessTokenExpiresAt:  accessToken.Expires,
+		RefreshToken:          refreshToken.Token,
+		RefreshTokenExpiresAt: refreshToken.Expires,
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(formatter.NewSuccessResponse(formatter.Success, "Login successfully", resp))
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"app/internal/pkg/token"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WellKnownHandler interface {
+	JWKS(c *fiber.Ctx) error
+}
+
+type WellKnownHandlerImpl struct {
+	Keys token.KeyManager `inject:"keyManager"`
+}
+
+// @Tags         Well-Known
+// @Summary      JSON Web Key Set
+// @Description  Public keys for verifying issued tokens, matched by the kid header. Empty when tokens are signed with a shared HS256 secret.
+// @Produce      json
+// @Router       /.well-known/jwks.json [get]
+// @Success      200  {object}  token.JWKS
+func (h *WellKnownHandlerImpl) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.Keys.JWKS())
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=100" example:"nightly export"`
+	// Scopes are permissions the caller has; a personal key never gets more rights than its user
+	Scopes    []string   `json:"scopes" validate:"required,min=1,max=20,dive,required,max=50" example:"getUsers"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2025-10-07T00:00:00Z"`
+	// Service keys belong to no user; only admins (manageApiKeys permission) create them
+	Service bool `json:"service" example:"false"`
+}
+
+type APIKeyResponse struct {
+	ID string `json:"id" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	// Key is only returned when the key is created
+	Key        string     `json:"key,omitempty" example:"ak_3q2-7wYb1Lk0.Zm9vYmFyYmF6cXV4..."`
+	Prefix     string     `json:"prefix" example:"ak_3q2-7wYb1Lk0"`
+	Name       string     `json:"name" example:"nightly export"`
+	UserID     string     `json:"user_id,omitempty" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	Service    bool       `json:"service" example:"false"`
+	Scopes     []string   `json:"scopes" example:"getUsers"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
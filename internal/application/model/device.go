@@ -0,0 +1,28 @@
+package model
+
+// DeviceCodeResponse is the device authorization response (RFC 8628 section 3.2), not wrapped like other responses.
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code" example:"GmRhmhcxhwAzkoEqiMEg_DnyEysNkuNhszIySk9eS"`
+	UserCode                string `json:"user_code" example:"WDJB-MJHT"`
+	VerificationURI         string `json:"verification_uri" example:"https://app.example.com/device"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty" example:"https://app.example.com/device?user_code=WDJB-MJHT"`
+	ExpiresIn               int64  `json:"expires_in" example:"600"`
+	Interval                int    `json:"interval" example:"5"`
+}
+
+// DeviceTokenRequest is the form the device polls the token endpoint with (RFC 8628 section 3.4).
+type DeviceTokenRequest struct {
+	GrantType  string `form:"grant_type" validate:"required"`
+	DeviceCode string `form:"device_code" validate:"required,max=255"`
+}
+
+type DeviceApproveRequest struct {
+	UserCode string `json:"user_code" validate:"required,max=32" example:"WDJB-MJHT"`
+	// Deny refuses the device instead of approving it
+	Deny bool `json:"deny" example:"false"`
+}
+
+type DeviceApproveResponse struct {
+	DeviceName string `json:"device_name" example:"deploy-cli"`
+	Approved   bool   `json:"approved" example:"true"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type UnlockAccountRequest struct {
+	Token string `json:"token" validate:"required,max=1024" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+type LockoutResponse struct {
+	UserID         string     `json:"user_id" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	Name           string     `json:"name,omitempty" example:"fake name"`
+	Email          string     `json:"email,omitempty" example:"fake@example.com"`
+	FailedAttempts int        `json:"failed_attempts" example:"5"`
+	LastFailedAt   time.Time  `json:"last_failed_at"`
+	Locked         bool       `json:"locked" example:"true"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// LoginAttempt is a login recorded in the login history. UserID may be empty for an unknown email.
+type LoginAttempt struct {
+	UserID        string
+	Email         string
+	Method        string
+	Device        *DeviceInfo
+	Success       bool
+	FailureReason string
+}
+
+type GetLoginHistoryRequest struct {
+	Page  int `json:"page" validate:"omitempty,number,max=50" example:"1"`
+	Limit int `json:"limit" validate:"omitempty,number,max=50" example:"10"`
+}
+
+type LoginEventResponse struct {
+	ID            string    `json:"id" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	Method        string    `json:"method" example:"password"`
+	Success       bool      `json:"success" example:"false"`
+	FailureReason string    `json:"failure_reason,omitempty" example:"Invalid email or password"`
+	IPAddress     string    `json:"ip_address" example:"203.0.113.7"`
+	UserAgent     string    `json:"user_agent" example:"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0)"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,97 @@
+package model
+
+import "time"
+
+// OAuthAuthorizeRequest is the authorization request of a client, forwarded by the consent page of the front-end.
+type OAuthAuthorizeRequest struct {
+	ResponseType        string `json:"response_type" query:"response_type" validate:"required" example:"code"`
+	ClientID            string `json:"client_id" query:"client_id" validate:"required,uuid" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri" validate:"required,url,max=2048" example:"https://partner.example.com/callback"`
+	Scope               string `json:"scope" query:"scope" validate:"max=1024" example:"profile"`
+	State               string `json:"state" query:"state" validate:"max=1024" example:"af0ifjsldkj"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge" validate:"required,min=43,max=128" example:"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method" validate:"required,eq=S256" example:"S256"`
+}
+
+// OAuthConsentResponse describes a valid authorization request so the front-end can ask the user for consent.
+type OAuthConsentResponse struct {
+	ClientID   string   `json:"client_id" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	ClientName string   `json:"client_name" example:"Partner App"`
+	Scopes     []string `json:"scopes" example:"profile"`
+}
+
+type OAuthAuthorizeResponse struct {
+	// RedirectTo is the redirect_uri of the client with the code and state, where the front-end sends the browser
+	RedirectTo string `json:"redirect_to" example:"https://partner.example.com/callback?code=eyJhbGciOi...&state=af0ifjsldkj"`
+}
+
+// OAuthTokenRequest is the form posted to the token endpoint (RFC 6749 section 4).
+type OAuthTokenRequest struct {
+	GrantType    string `form:"grant_type" validate:"required"`
+	Code         string `form:"code" validate:"max=2048"`
+	RedirectURI  string `form:"redirect_uri" validate:"max=2048"`
+	CodeVerifier string `form:"code_verifier" validate:"max=128"`
+	RefreshToken string `form:"refresh_token" validate:"max=2048"`
+	Scope        string `form:"scope" validate:"max=1024"`
+}
+
+// OAuthClientCredentials authenticate a client with HTTP Basic or the client_id and client_secret form fields.
+// Public clients send only their client_id.
+type OAuthClientCredentials struct {
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// OAuthTokenResponse is the standard token response (RFC 6749 section 5.1), not wrapped like other responses.
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"1800"`
+	RefreshToken string `json:"refresh_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Scope        string `json:"scope,omitempty" example:"profile"`
+}
+
+// OAuthTokenHintRequest is the form of the introspection (RFC 7662) and revocation (RFC 7009) endpoints.
+type OAuthTokenHintRequest struct {
+	Token         string `form:"token" validate:"required,max=2048"`
+	TokenTypeHint string `form:"token_type_hint" validate:"omitempty,oneof=access_token refresh_token"`
+}
+
+// OAuthIntrospectionResponse follows RFC 7662 section 2.2. Inactive tokens only report active false.
+type OAuthIntrospectionResponse struct {
+	Active    bool   `json:"active" example:"true"`
+	Scope     string `json:"scope,omitempty" example:"profile"`
+	ClientID  string `json:"client_id,omitempty" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	Subject   string `json:"sub,omitempty" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	TokenType string `json:"token_type,omitempty" example:"access_token"`
+	ExpiresAt int64  `json:"exp,omitempty" example:"1735689600"`
+	IssuedAt  int64  `json:"iat,omitempty" example:"1735687800"`
+	JTI       string `json:"jti,omitempty" example:"0d3c6a4e-2f7b-4c1a-9e8d-5b6a7c8d9e0f"`
+}
+
+// OAuthErrorResponse is the error format of the token, introspection and revocation endpoints (RFC 6749 section 5.2).
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_grant"`
+	ErrorDescription string `json:"error_description,omitempty" example:"invalid, expired or revoked grant"`
+}
+
+type CreateOAuthClientRequest struct {
+	Name         string   `json:"name" validate:"required,max=255" example:"Partner App"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,dive,url,max=2048,excludesall=0x2C" example:"https://partner.example.com/callback"`
+	GrantTypes   []string `json:"grant_types" validate:"required,min=1,dive,oneof=authorization_code client_credentials refresh_token" example:"authorization_code,refresh_token"`
+	Scopes       []string `json:"scopes" validate:"omitempty,dive,required,max=255" example:"profile"`
+	// Public clients such as mobile apps get no secret and must use PKCE
+	Public bool `json:"public" example:"false"`
+}
+
+type OAuthClientResponse struct {
+	ClientID string `json:"client_id" example:"01927a3c-5b8e-7d2f-9a4b-3c2d1e0f9a8b"`
+	// ClientSecret is only returned when the client is created
+	ClientSecret string    `json:"client_secret,omitempty" example:"Zm9vYmFyYmF6cXV4..."`
+	Name         string    `json:"name" example:"Partner App"`
+	Public       bool      `json:"public" example:"false"`
+	RedirectURIs []string  `json:"redirect_uris" example:"https://partner.example.com/callback"`
+	GrantTypes   []string  `json:"grant_types" example:"authorization_code,refresh_token"`
+	Scopes       []string  `json:"scopes" example:"profile"`
+	CreatedAt    time.Time `json:"created_at"`
+}
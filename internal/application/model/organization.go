@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// CreateOrganizationRequest creates an organization. AdminID, if set, makes that user its first org-admin.
+type CreateOrganizationRequest struct {
+	Name    string `json:"name" validate:"required,max=100" example:"Acme Corp"`
+	Slug    string `json:"slug" validate:"required,max=50" example:"acme"`
+	AdminID string `json:"admin_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+type OrganizationResponse struct {
+	ID        string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string    `json:"name" example:"Acme Corp"`
+	Slug      string    `json:"slug" example:"acme"`
+	CreatedAt time.Time `json:"created_at" example:"2024-10-07T11:56:46.618180553Z"`
+}
+
+// MembershipResponse is an organization of the user with the role the user has in it.
+type MembershipResponse struct {
+	OrganizationID string    `json:"organization_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name           string    `json:"name" example:"Acme Corp"`
+	Slug           string    `json:"slug" example:"acme"`
+	Role           string    `json:"role" example:"user"`
+	JoinedAt       time.Time `json:"joined_at" example:"2024-10-07T11:56:46.618180553Z"`
+}
+
+// SetMemberRequest adds a user to the current organization or changes the role of a member.
+type SetMemberRequest struct {
+	Role string `json:"role" validate:"required,max=50" example:"org-admin"`
+}
+
+type MemberResponse struct {
+	UserID   string    `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name     string    `json:"name" example:"fake name"`
+	Email    string    `json:"email" example:"fake@example.com"`
+	Role     string    `json:"role" example:"user"`
+	JoinedAt time.Time `json:"joined_at" example:"2024-10-07T11:56:46.618180553Z"`
+}
+
+// CreateInvitationRequest invites an email address to the current organization. Role defaults to "user".
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email,max=50" example:"fake@example.com"`
+	Role  string `json:"role" validate:"omitempty,max=50" example:"user"`
+}
+
+type InvitationResponse struct {
+	ID               string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OrganizationID   string    `json:"organization_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OrganizationName string    `json:"organization_name" example:"Acme Corp"`
+	Email            string    `json:"email" example:"fake@example.com"`
+	Role             string    `json:"role" example:"user"`
+	InvitedBy        string    `json:"invited_by,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ExpiresAt        time.Time `json:"expires_at" example:"2024-10-14T11:56:46.618180553Z"`
+	CreatedAt        time.Time `json:"created_at" example:"2024-10-07T11:56:46.618180553Z"`
+}
+
+// AcceptInvitationRequest accepts the invitation of the token. Name and password create the account when
+// no user has the invited email yet, and are ignored otherwise.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9"`
+	Name     string `json:"name" validate:"omitempty,max=50" example:"fake name"`
+	Password string `json:"password" validate:"omitempty,max=72" example:"Tr0ub4dor&3"`
+}
+
+type AcceptInvitationResponse struct {
+	OrganizationID   string `json:"organization_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OrganizationName string `json:"organization_name" example:"Acme Corp"`
+	UserID           string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// AccountCreated is false when the invitation was linked to an existing account
+	AccountCreated bool `json:"account_created" example:"true"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,max=50" example:"support"`
+	Description string   `json:"description" validate:"max=255" example:"Answers customer requests"`
+	Permissions []string `json:"permissions" validate:"omitempty,dive,required,max=100" example:"getUsers"`
+}
+
+// UpdateRoleRequest changes the fields that are set. Permissions replace the current ones, an empty list removes all.
+type UpdateRoleRequest struct {
+	Description *string   `json:"description" validate:"omitempty,max=255" example:"Answers customer requests"`
+	Permissions *[]string `json:"permissions" validate:"omitempty,dive,required,max=100" example:"getUsers,manageUsers"`
+}
+
+type RoleResponse struct {
+	ID          string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string    `json:"name" example:"support"`
+	Description string    `json:"description" example:"Answers customer requests"`
+	Permissions []string  `json:"permissions" example:"getUsers"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-10-07T11:56:46.618180553Z"`
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name" example:"getUsers"`
+	Description string `json:"description" example:"List and read any user"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type DeviceInfo struct {
+	DeviceName string `json:"device_name" example:"Pixel 9"`
+	UserAgent  string `json:"user_agent" example:"Mozilla/5.0 (Linux; Android 15)"`
+	IPAddress  string `json:"ip_address" example:"203.0.113.10"`
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	DeviceName string    `json:"device_name" example:"Pixel 9"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (Linux; Android 15)"`
+	IPAddress  string    `json:"ip_address" example:"203.0.113.10"`
+	Current    bool      `json:"current" example:"true"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-10-07T11:56:46.618180553Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2024-10-07T11:56:46.618180553Z"`
+}
@@ -74,3 +74,52 @@ type ErrorFailedVerifyEmail struct {
 	Message string `json:"message" example:"Verify email failed"`
 	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
+
+// ErrorMFAAlreadyEnabled represents 409 error when two-factor authentication is already enabled
+type ErrorMFAAlreadyEnabled struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"two-factor authentication is already enabled"`
+	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ErrorPasskeyAlreadyRegistered represents 409 error when the passkey is already registered
+type ErrorPasskeyAlreadyRegistered struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"passkey already registered"`
+	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ErrorIdentityConflict represents 409 error when a provider account cannot be linked
+type ErrorIdentityConflict struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"identity already linked to an account"`
+	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ErrorTooManyRequests represents 429 error response when a rate limit is reached
+type ErrorTooManyRequests struct {
+	Status  string `json:"status" example:"APP10"`
+	Message string `json:"message" example:"Too many requests, please try again later"`
+	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ErrorRoleConflict represents 409 error when a role already exists or cannot be deleted
+type ErrorRoleConflict struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"role is assigned to users"`
+	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ErrorOrganizationConflict represents 409 error when an organization slug is already taken
+type ErrorOrganizationConflict struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"organization already exists"`
+	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ErrorAccountLocked represents 423 error when an account is locked after too many failed logins
+type ErrorAccountLocked struct {
+	Status  string `json:"status" example:"APP07"`
+	Message string `json:"message" example:"account is locked after too many failed logins, check your email to unlock it"`
+	TraceID string `json:"traceId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -7,50 +7,57 @@ type GetUserRequest struct {
 }
 
 type CreateUserRequest struct {
-	Name     string `json:"name" validate:"required,max=50" example:"fake name"`
-	Email    string `json:"email" validate:"required,email,max=50" example:"fake@example.com"`
-	Password string `json:"password" validate:"required,min=8,max=20,password" example:"password1"`
-	Role     string `json:"role" validate:"required,oneof=user admin,max=50" example:"user"`
+	Name     string   `json:"name" validate:"required,max=50" example:"fake name"`
+	Email    string   `json:"email" validate:"required,email,max=50" example:"fake@example.com"`
+	Password string   `json:"password" validate:"required,max=72" example:"Tr0ub4dor&3"`
+	Roles    []string `json:"roles" validate:"required,min=1,max=10,dive,required,max=50" example:"user"`
 }
 
 type CreateUserResponse struct {
-	ID              string `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Name            string `json:"name" example:"fake name"`
-	Email           string `json:"email" example:"fake@example.com"`
-	Role            string `json:"role" example:"user"`
-	IsEmailVerified bool   `json:"is_email_verified" example:"false"`
+	ID              string   `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name            string   `json:"name" example:"fake name"`
+	Email           string   `json:"email" example:"fake@example.com"`
+	Roles           []string `json:"roles" example:"user"`
+	IsEmailVerified bool     `json:"is_email_verified" example:"false"`
 }
 
 type UpdatePassOrVerifyRequest struct {
-	Password      string `json:"password" validate:"omitempty,min=8,max=20,password" example:"password1"`
+	Password      string `json:"password" validate:"omitempty,max=72" example:"Tr0ub4dor&3"`
 	VerifiedEmail bool   `json:"verified_email" validate:"omitempty,boolean" example:"false"`
 }
 
 type UpdateUserRequest struct {
-	UserID   string `json:"user_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// UserID comes from the path, never from the body, since access is checked on the path
+	UserID   string `json:"-" validate:"required"`
 	Name     string `json:"name" validate:"omitempty,max=50" example:"fake name"`
 	Email    string `json:"email" validate:"omitempty,email,max=50" example:"fake@example.com"`
-	Password string `json:"password" validate:"omitempty,min=8,max=20,password" example:"password1"`
+	Password string `json:"password" validate:"omitempty,max=72" example:"Tr0ub4dor&3"`
 }
 
-type UpdateUserResponse struct {
-	ID              string `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Name            string `json:"name" example:"fake name"`
-	Email           string `json:"email" example:"fake@example.com"`
-	Role            string `json:"role" example:"user"`
-	IsEmailVerified bool   `json:"is_email_verified" example:"false"`
+// UpdateUserRolesRequest replaces the roles of a user.
+type UpdateUserRolesRequest struct {
+	Roles []string `json:"roles" validate:"required,min=1,max=10,dive,required,max=50" example:"user,admin"`
 }
 
-type CreateGoogleUserRequest struct {
-	Name          string `json:"name" validate:"required,max=50" example:"fake name"`
-	Email         string `json:"email" validate:"required,email,max=50" example:"fake@example.com"`
-	VerifiedEmail bool   `json:"verified_email" validate:"omitempty,boolean" example:"false"`
+// UpdateUserAttributesRequest replaces the attributes of a user that attribute policies compare.
+type UpdateUserAttributesRequest struct {
+	Attributes map[string]string `json:"attributes" validate:"max=20,dive,keys,required,max=50,endkeys,max=255"`
+}
+
+type UpdateUserResponse struct {
+	ID              string            `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name            string            `json:"name" example:"fake name"`
+	Email           string            `json:"email" example:"fake@example.com"`
+	Roles           []string          `json:"roles" example:"user"`
+	Attributes      map[string]string `json:"attributes"`
+	IsEmailVerified bool              `json:"is_email_verified" example:"false"`
 }
 
 type GetUserResponse struct {
-	ID              string `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Name            string `json:"name" example:"fake name"`
-	Email           string `json:"email" example:"fake@example.com"`
-	Role            string `json:"role" example:"user"`
-	IsEmailVerified bool   `json:"is_email_verified" example:"false"`
+	ID              string            `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name            string            `json:"name" example:"fake name"`
+	Email           string            `json:"email" example:"fake@example.com"`
+	Roles           []string          `json:"roles" example:"user"`
+	Attributes      map[string]string `json:"attributes"`
+	IsEmailVerified bool              `json:"is_email_verified" example:"false"`
 }
@@ -7,34 +7,82 @@ import (
 	"app/internal/adapter/rest"
 	"app/internal/application/handler"
 	"app/internal/pkg/middleware"
+	"app/internal/pkg/policy"
+	"time"
 
+	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
 )
 
+const (
+	defaultMagicLinkRateLimit       = 3
+	defaultMagicLinkRateLimitWindow = 15 * time.Minute
+)
+
 type Router struct {
-	App                *rest.Fiber                `inject:"rest"`
-	Conf               *config.Config             `inject:"config"`
-	HealthCheckHandler handler.HealthCheckHandler `inject:"healthCheckHandler"`
-	AuthHandler        handler.AuthHandler        `inject:"authHandler"`
-	UserHandler        handler.UserHandler        `inject:"userHandler"`
-	AuthMiddleware     middleware.Auth            `inject:"authMiddleware"`
+	App                 *rest.Fiber                 `inject:"rest"`
+	Conf                *config.Config              `inject:"config"`
+	HealthCheckHandler  handler.HealthCheckHandler  `inject:"healthCheckHandler"`
+	AuthHandler         handler.AuthHandler         `inject:"authHandler"`
+	UserHandler         handler.UserHandler         `inject:"userHandler"`
+	RoleHandler         handler.RoleHandler         `inject:"roleHandler"`
+	OrganizationHandler handler.OrganizationHandler `inject:"organizationHandler"`
+	SessionHandler      handler.SessionHandler      `inject:"sessionHandler"`
+	MFAHandler          handler.MFAHandler          `inject:"mfaHandler"`
+	WebAuthnHandler     handler.WebAuthnHandler     `inject:"webAuthnHandler"`
+	IdentityHandler     handler.IdentityHandler     `inject:"identityHandler"`
+	WellKnownHandler    handler.WellKnownHandler    `inject:"wellKnownHandler"`
+	OAuthServerHandler  handler.OAuthServerHandler  `inject:"oauthServerHandler"`
+	OAuthClientHandler  handler.OAuthClientHandler  `inject:"oauthClientHandler"`
+	DeviceHandler       handler.DeviceHandler       `inject:"deviceHandler"`
+	APIKeyHandler       handler.APIKeyHandler       `inject:"apiKeyHandler"`
+	LockoutHandler      handler.LockoutHandler      `inject:"lockoutHandler"`
+	LoginHistoryHandler handler.LoginHistoryHandler `inject:"loginHistoryHandler"`
+	AuthMiddleware      middleware.Auth             `inject:"authMiddleware"`
 }
 
 func (r *Router) Startup() error {
 	healthCheck := r.App.Group("/health-check")
 	healthCheck.Get("/", r.HealthCheckHandler.Check)
 
+	wellKnown := r.App.Group("/.well-known")
+	wellKnown.Get("/jwks.json", r.WellKnownHandler.JWKS)
+
 	auth := r.App.Group("/auth")
 	auth.Post("/register", r.AuthHandler.Register)
-	auth.Post("/login", r.AuthHandler.Login)
+	auth.Post("/login", middleware.LimiterConfig(), r.AuthHandler.Login)
 	auth.Post("/logout", r.AuthHandler.Logout)
 	auth.Post("/refresh-tokens", r.AuthHandler.RefreshTokens)
-	auth.Post("/forgot-password", r.AuthHandler.ForgotPassword)
-	auth.Post("/reset-password", r.AuthHandler.ResetPassword)
+	auth.Post("/mfa/verify", middleware.LimiterConfig(), r.MFAHandler.Verify)
+	auth.Post("/magic-link", r.magicLinkLimiter(), r.AuthHandler.SendMagicLink)
+	auth.Post("/magic-link/verify", middleware.LimiterConfig(), r.AuthHandler.VerifyMagicLink)
+	auth.Post("/forgot-password", middleware.LimiterConfig(), r.AuthHandler.ForgotPassword)
+	auth.Post("/reset-password", middleware.LimiterConfig(), r.AuthHandler.ResetPassword)
+	auth.Post("/change-password", middleware.LimiterConfig(), r.AuthHandler.ChangeExpiredPassword)
 	auth.Post("/send-verification-email", r.AuthMiddleware.JWTAuth(), r.AuthHandler.SendVerificationEmail)
-	auth.Post("/verify-email", r.AuthHandler.VerifyEmail)
-	auth.Get("/google", r.AuthHandler.GoogleLogin)
-	auth.Get("/google-callback", r.AuthHandler.GoogleCallback)
+	auth.Post("/verify-email", middleware.LimiterConfig(), r.AuthHandler.VerifyEmail)
+	auth.Post("/unlock-account", middleware.LimiterConfig(), r.LockoutHandler.UnlockAccount)
+	auth.Post("/oauth/code", middleware.LimiterConfig(), r.AuthHandler.ExchangeOAuthCode)
+	auth.Get("/oauth/:provider", r.AuthHandler.OAuthLogin)
+	auth.Get("/oauth/:provider/callback", r.AuthHandler.OAuthCallback)
+
+	device := auth.Group("/device")
+	device.Post("/code", r.DeviceHandler.RequestCode)
+	device.Post("/token", r.DeviceHandler.Token)
+	device.Post("/approve", r.AuthMiddleware.SessionAuth(), r.DeviceHandler.Approve)
+
+	webAuthn := auth.Group("/webauthn")
+	webAuthn.Post("/register/begin", r.AuthMiddleware.SessionAuth(), r.WebAuthnHandler.BeginRegistration)
+	webAuthn.Post("/register/finish", r.AuthMiddleware.SessionAuth(), r.WebAuthnHandler.FinishRegistration)
+	webAuthn.Post("/login/begin", middleware.LimiterConfig(), r.WebAuthnHandler.BeginLogin)
+	webAuthn.Post("/login/finish", middleware.LimiterConfig(), r.WebAuthnHandler.FinishLogin)
+
+	oauthServer := r.App.Group("/oauth")
+	oauthServer.Get("/authorize", r.AuthMiddleware.SessionAuth(), r.OAuthServerHandler.GetAuthorization)
+	oauthServer.Post("/authorize", r.AuthMiddleware.SessionAuth(), r.OAuthServerHandler.Authorize)
+	oauthServer.Post("/token", r.OAuthServerHandler.Token)
+	oauthServer.Post("/introspect", r.OAuthServerHandler.Introspect)
+	oauthServer.Post("/revoke", r.OAuthServerHandler.Revoke)
 
 	v1 := r.App.Group("/v1")
 
@@ -46,13 +94,91 @@ func (r *Router) Startup() error {
 	user := v1.Group("/users")
 	user.Get("/", r.AuthMiddleware.JWTAuth("getUsers"), r.UserHandler.GetUsers)
 	user.Post("/", r.AuthMiddleware.JWTAuth("manageUsers"), r.UserHandler.CreateUser)
-	user.Get("/:userId", r.AuthMiddleware.JWTAuth("getUsers"), r.UserHandler.GetUserByID)
-	user.Patch("/:userId", r.AuthMiddleware.JWTAuth("manageUsers"), r.UserHandler.UpdateUser)
-	user.Delete("/:userId", r.AuthMiddleware.JWTAuth("manageUsers"), r.UserHandler.DeleteUser)
+	user.Get("/lockouts", r.AuthMiddleware.JWTAuth("manageUsers"), r.LockoutHandler.GetLockouts)
+	user.Get("/:userId", r.AuthMiddleware.JWTAuth(), r.authorizeUser(policy.ReadUser), r.UserHandler.GetUserByID)
+	user.Patch("/:userId", r.AuthMiddleware.SessionAuth(), r.authorizeUser(policy.UpdateUser), r.UserHandler.UpdateUser)
+	user.Put("/:userId/roles", r.AuthMiddleware.JWTAuth("manageRoles"), r.UserHandler.UpdateUserRoles)
+	user.Put("/:userId/attributes", r.AuthMiddleware.JWTAuth("manageUsers"), r.authorizeUser(policy.UpdateUser),
+		r.UserHandler.UpdateUserAttributes)
+	user.Delete("/:userId", r.AuthMiddleware.SessionAuth(), r.authorizeUser(policy.DeleteUser), r.UserHandler.DeleteUser)
+	user.Get("/:userId/lockout", r.AuthMiddleware.JWTAuth("manageUsers"), r.LockoutHandler.GetLockout)
+	user.Delete("/:userId/lockout", r.AuthMiddleware.JWTAuth("manageUsers"), r.LockoutHandler.ClearLockout)
+	user.Get("/:userId/login-history", r.AuthMiddleware.JWTAuth(), r.authorizeUser(policy.ReadUser),
+		r.LoginHistoryHandler.GetLoginHistory)
+
+	role := v1.Group("/roles")
+	role.Get("/", r.AuthMiddleware.JWTAuth("manageRoles"), r.RoleHandler.GetRoles)
+	role.Post("/", r.AuthMiddleware.JWTAuth("manageRoles"), r.RoleHandler.CreateRole)
+	role.Get("/permissions", r.AuthMiddleware.JWTAuth("manageRoles"), r.RoleHandler.GetPermissions)
+	role.Get("/:roleId", r.AuthMiddleware.JWTAuth("manageRoles"), r.RoleHandler.GetRole)
+	role.Patch("/:roleId", r.AuthMiddleware.JWTAuth("manageRoles"), r.RoleHandler.UpdateRole)
+	role.Delete("/:roleId", r.AuthMiddleware.JWTAuth("manageRoles"), r.RoleHandler.DeleteRole)
+
+	organization := v1.Group("/organizations")
+	organization.Get("/", r.AuthMiddleware.JWTAuth("manageOrganizations"), r.OrganizationHandler.GetOrganizations)
+	organization.Post("/", r.AuthMiddleware.JWTAuth("manageOrganizations"), r.OrganizationHandler.CreateOrganization)
+	organization.Get("/memberships", r.AuthMiddleware.JWTAuth(), r.OrganizationHandler.GetMemberships)
+	organization.Get("/current", r.AuthMiddleware.JWTAuth(), r.OrganizationHandler.GetCurrentOrganization)
+	organization.Get("/current/members", r.AuthMiddleware.JWTAuth("manageMembers"), r.OrganizationHandler.GetMembers)
+	organization.Put("/current/members/:userId", r.AuthMiddleware.JWTAuth("manageMembers"),
+		r.OrganizationHandler.SetMember)
+	organization.Delete("/current/members/:userId", r.AuthMiddleware.JWTAuth("manageMembers"),
+		r.OrganizationHandler.RemoveMember)
+	organization.Get("/current/invitations", r.AuthMiddleware.JWTAuth("manageMembers"),
+		r.OrganizationHandler.GetInvitations)
+	organization.Post("/current/invitations", r.AuthMiddleware.JWTAuth("manageMembers"),
+		r.OrganizationHandler.CreateInvitation)
+	organization.Delete("/current/invitations/:invitationId", r.AuthMiddleware.JWTAuth("manageMembers"),
+		r.OrganizationHandler.RevokeInvitation)
+	organization.Post("/invitations/accept", r.OrganizationHandler.AcceptInvitation)
+
+	apiKey := v1.Group("/api-keys")
+	apiKey.Get("/", r.AuthMiddleware.SessionAuth(), r.APIKeyHandler.GetAPIKeys)
+	apiKey.Post("/", r.AuthMiddleware.SessionAuth(), r.APIKeyHandler.CreateAPIKey)
+	apiKey.Get("/all", r.AuthMiddleware.JWTAuth("manageApiKeys"), r.APIKeyHandler.GetAllAPIKeys)
+	apiKey.Delete("/:apiKeyId", r.AuthMiddleware.SessionAuth(), r.APIKeyHandler.RevokeAPIKey)
+
+	session := v1.Group("/sessions")
+	session.Get("/", r.AuthMiddleware.JWTAuth(), r.SessionHandler.GetSessions)
+	session.Delete("/others", r.AuthMiddleware.SessionAuth(), r.SessionHandler.DeleteOtherSessions)
+	session.Delete("/:sessionId", r.AuthMiddleware.SessionAuth(), r.SessionHandler.DeleteSession)
+
+	mfa := v1.Group("/mfa")
+	mfa.Post("/totp", r.AuthMiddleware.SessionAuth(), r.MFAHandler.EnrollTOTP)
+	mfa.Post("/totp/confirm", r.AuthMiddleware.SessionAuth(), r.MFAHandler.ConfirmTOTP)
+
+	identity := v1.Group("/identities")
+	identity.Get("/", r.AuthMiddleware.JWTAuth(), r.IdentityHandler.GetIdentities)
+	identity.Post("/:provider", r.AuthMiddleware.SessionAuth(), r.IdentityHandler.LinkIdentity)
+	identity.Delete("/:identityId", r.AuthMiddleware.SessionAuth(), r.IdentityHandler.UnlinkIdentity)
+
+	oauthClient := v1.Group("/oauth-clients")
+	oauthClient.Get("/", r.AuthMiddleware.JWTAuth("manageOAuthClients"), r.OAuthClientHandler.GetClients)
+	oauthClient.Post("/", r.AuthMiddleware.JWTAuth("manageOAuthClients"), r.OAuthClientHandler.CreateClient)
+	oauthClient.Delete("/:clientId", r.AuthMiddleware.JWTAuth("manageOAuthClients"), r.OAuthClientHandler.DeleteClient)
 
 	return nil
 }
 
+// authorizeUser applies the attribute policies and the rules of the action to the user in the URL.
+func (r *Router) authorizeUser(action policy.Action) fiber.Handler {
+	return r.AuthMiddleware.AuthorizeUser(action, "userId")
+}
+
+func (r *Router) magicLinkLimiter() fiber.Handler {
+	limit := r.Conf.MagicLink.RateLimit
+	if limit <= 0 {
+		limit = defaultMagicLinkRateLimit
+	}
+
+	window := r.Conf.MagicLink.RateLimitWindow
+	if window <= 0 {
+		window = defaultMagicLinkRateLimitWindow
+	}
+
+	return middleware.EmailLimiter(limit, window)
+}
+
 func (r *Router) Shutdown() error {
 	return nil
 }
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/policy"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tommynurwantoro/golog"
+)
+
+//go:generate mockgen -source=access_policy_service.go -destination=mocks/access_policy_service.go -package=mocks
+type AccessPolicyService interface {
+	Evaluate(ctx context.Context, req policy.Request) policy.Decision
+	Authorize(ctx context.Context, req policy.Request) error
+}
+
+// AccessPolicyServiceImpl evaluates the attribute policies of the policy file. They are read once at startup,
+// so the service has to be restarted for a changed file to apply.
+type AccessPolicyServiceImpl struct {
+	Conf *config.Config `inject:"config"`
+
+	document *policy.Document
+}
+
+func (s *AccessPolicyServiceImpl) Startup() error {
+	s.document = &policy.Document{}
+	if s.Conf.Policy.File == "" {
+		return nil
+	}
+
+	document, err := policy.Load(s.Conf.Policy.File)
+	if err != nil {
+		return err
+	}
+	s.document = document
+
+	return nil
+}
+
+// Evaluate applies the attribute policies to the request. The time of the environment is filled in
+// when the caller did not set it.
+func (s *AccessPolicyServiceImpl) Evaluate(_ context.Context, req policy.Request) policy.Decision {
+	if s.document == nil {
+		return policy.Decision{Effect: policy.NotApplicable}
+	}
+
+	environment := policy.Environment(time.Now())
+	for name, value := range req.Environment {
+		environment[name] = value
+	}
+	req.Environment = environment
+
+	return s.document.Evaluate(req.Input())
+}
+
+// Authorize returns ErrForbidden when an attribute policy denies the request. Requests no attribute policy
+// allows or denies fall back to the role rules of policy.Rules.
+func (s *AccessPolicyServiceImpl) Authorize(ctx context.Context, req policy.Request) error {
+	decision := s.Evaluate(ctx, req)
+
+	switch decision.Effect {
+	case policy.Deny:
+		golog.Info(fmt.Sprintf("Policy %s denied %s on %s %s to user %s",
+			decision.Policy, req.Action, req.Resource.Type, req.Resource.ID, req.Subject.UserID))
+		return myerrors.ErrForbidden
+	case policy.Allow:
+		return nil
+	}
+
+	return policy.Rules.Authorize(req.Subject, req.Action, req.Resource)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/policy"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testPolicies = `
+policies:
+  - name: support-reads-own-region
+    effect: allow
+    actions: ["user:read"]
+    resource: user
+    conditions:
+      - {attribute: subject.roles, operator: contains, value: support}
+      - {attribute: resource.region, operator: equals, ref: subject.region}
+  - name: admins-cannot-delete-admins
+    effect: deny
+    actions: ["user:delete"]
+    resource: user
+    conditions:
+      - {attribute: resource.roles, operator: contains, value: admin}
+      - {attribute: resource.id, operator: not_equals, ref: subject.id}
+  - name: internal-network-only
+    effect: deny
+    actions: ["user:update"]
+    conditions:
+      - {attribute: environment.ip, operator: not_in, value: ["10.0.0.1"]}
+      - {attribute: subject.roles, operator: contains, value: contractor}
+`
+
+type accessPolicyServiceTestSuite struct {
+	suite.Suite
+	service *AccessPolicyServiceImpl
+	ctx     context.Context
+}
+
+func TestAccessPolicyService(t *testing.T) {
+	suite.Run(t, new(accessPolicyServiceTestSuite))
+}
+
+func (s *accessPolicyServiceTestSuite) SetupTest() {
+	file := filepath.Join(s.T().TempDir(), "policies.yaml")
+	s.Require().NoError(os.WriteFile(file, []byte(testPolicies), 0o600))
+
+	s.service = &AccessPolicyServiceImpl{Conf: &config.Config{Policy: config.PolicyConfig{File: file}}}
+	s.Require().NoError(s.service.Startup())
+
+	s.ctx = context.Background()
+}
+
+func userRequest(subject policy.Subject, action policy.Action, id string, attributes map[string]any) policy.Request {
+	return policy.Request{
+		Subject:  subject,
+		Action:   action,
+		Resource: policy.Resource{Type: policy.ResourceUser, ID: id, OwnerID: id, Attributes: attributes},
+	}
+}
+
+func (s *accessPolicyServiceTestSuite) TestAuthorize_AttributePolicyAllows() {
+	support := policy.Subject{UserID: "support", Roles: []string{"support"}, Attributes: map[string]string{"region": "eu"}}
+
+	s.NoError(s.service.Authorize(s.ctx,
+		userRequest(support, policy.ReadUser, "alice", map[string]any{"region": "eu"})))
+	s.Equal(myerrors.ErrForbidden, s.service.Authorize(s.ctx,
+		userRequest(support, policy.ReadUser, "alice", map[string]any{"region": "us"})))
+}
+
+func (s *accessPolicyServiceTestSuite) TestAuthorize_DenyOverridesRules() {
+	admin := policy.Subject{UserID: "root", Roles: []string{"admin"}, Rights: []string{"manageUsers"}}
+	otherAdmin := map[string]any{"roles": []string{"admin"}}
+	regularUser := map[string]any{"roles": []string{"user"}}
+
+	s.Equal(myerrors.ErrForbidden, s.service.Authorize(s.ctx, userRequest(admin, policy.DeleteUser, "bob", otherAdmin)))
+	s.NoError(s.service.Authorize(s.ctx, userRequest(admin, policy.DeleteUser, "alice", regularUser)))
+	s.NoError(s.service.Authorize(s.ctx, userRequest(admin, policy.DeleteUser, "root", otherAdmin)))
+}
+
+func (s *accessPolicyServiceTestSuite) TestAuthorize_Environment() {
+	contractor := policy.Subject{UserID: "alice", Roles: []string{"contractor"}}
+
+	req := userRequest(contractor, policy.UpdateUser, "alice", nil)
+	req.Environment = map[string]any{"ip": "192.168.1.1"}
+	s.Equal(myerrors.ErrForbidden, s.service.Authorize(s.ctx, req))
+
+	req.Environment = map[string]any{"ip": "10.0.0.1"}
+	s.NoError(s.service.Authorize(s.ctx, req))
+}
+
+func (s *accessPolicyServiceTestSuite) TestAuthorize_WithoutPolicyFile() {
+	s.service = &AccessPolicyServiceImpl{Conf: &config.Config{}}
+	s.Require().NoError(s.service.Startup())
+
+	user := policy.Subject{UserID: "alice"}
+
+	s.NoError(s.service.Authorize(s.ctx, userRequest(user, policy.ReadUser, "alice", nil)))
+	s.Equal(myerrors.ErrForbidden, s.service.Authorize(s.ctx, userRequest(user, policy.ReadUser, "bob", nil)))
+}
+
+func (s *accessPolicyServiceTestSuite) TestStartup_InvalidPolicyFile() {
+	file := filepath.Join(s.T().TempDir(), "policies.yaml")
+	s.Require().NoError(os.WriteFile(file, []byte("policies:\n  - {name: a, effect: permit, actions: [\"*\"]}\n"), 0o600))
+
+	s.service = &AccessPolicyServiceImpl{Conf: &config.Config{Policy: config.PolicyConfig{File: file}}}
+
+	s.Error(s.service.Startup())
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/validator"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+const (
+	// apiKeyPrefixSize and apiKeySecretSize are the number of random bytes in the two parts of a key
+	apiKeyPrefixSize = 9
+	apiKeySecretSize = 32
+)
+
+//go:generate mockgen -source=api_key_service.go -destination=mocks/api_key_service.go -package=mocks
+type APIKeyService interface {
+	GetAPIKeys(ctx context.Context, userID string) ([]model.APIKeyResponse, error)
+	GetAllAPIKeys(ctx context.Context) ([]model.APIKeyResponse, error)
+	CreateAPIKey(ctx context.Context, subject policy.Subject, req *model.CreateAPIKeyRequest) (*model.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, subject policy.Subject, id string) error
+	Authenticate(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+type APIKeyServiceImpl struct {
+	Conf             *config.Config              `inject:"config"`
+	APIKeyRepository repository.APIKeyRepository `inject:"apiKeyRepository"`
+	RoleService      RoleService                 `inject:"roleService"`
+	Validator        validator.Validator         `inject:"validator"`
+}
+
+// GetAPIKeys returns the personal keys of the user, newest first.
+func (s *APIKeyServiceImpl) GetAPIKeys(ctx context.Context, userID string) ([]model.APIKeyResponse, error) {
+	apiKeys, err := s.APIKeyRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAPIKeyResponses(apiKeys), nil
+}
+
+// GetAllAPIKeys returns the keys of every user and the service keys, newest first.
+func (s *APIKeyServiceImpl) GetAllAPIKeys(ctx context.Context) ([]model.APIKeyResponse, error) {
+	apiKeys, err := s.APIKeyRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAPIKeyResponses(apiKeys), nil
+}
+
+// CreateAPIKey issues a personal key of the subject, or a service key. Keys can only get scopes the subject has.
+// The key is returned once and only the hash of its secret is stored.
+func (s *APIKeyServiceImpl) CreateAPIKey(
+	ctx context.Context, subject policy.Subject, req *model.CreateAPIKeyRequest,
+) (*model.APIKeyResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if req.Service && !subject.HasRight("manageApiKeys") {
+		return nil, myerrors.ErrForbidden
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, myerrors.ErrInvalidExpiry
+	}
+
+	scopes := slices.Compact(slices.Sorted(slices.Values(req.Scopes)))
+	if err := s.checkScopes(ctx, subject, scopes); err != nil {
+		return nil, err
+	}
+
+	prefix := domain.APIKeyPrefix + crypto.RandomString(apiKeyPrefixSize)
+	secret := crypto.RandomString(apiKeySecretSize)
+
+	apiKey := &domain.APIKey{
+		Name:       req.Name,
+		Prefix:     prefix,
+		SecretHash: s.hashSecret(secret),
+		Scopes:     strings.Join(scopes, ","),
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if creator, err := uuid.Parse(subject.UserID); err == nil {
+		apiKey.CreatedBy = &creator
+		if !req.Service {
+			apiKey.UserID = &creator
+		}
+	} else if !req.Service {
+		return nil, myerrors.ErrInvalidToken
+	}
+
+	apiKey, err := s.APIKeyRepository.Create(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := newAPIKeyResponse(apiKey)
+	resp.Key = prefix + "." + secret
+
+	return resp, nil
+}
+
+// RevokeAPIKey deletes a key of the subject. Subjects who manage API keys can revoke any key; to everyone else
+// the keys of others do not exist.
+func (s *APIKeyServiceImpl) RevokeAPIKey(ctx context.Context, subject policy.Subject, id string) error {
+	apiKey, err := s.APIKeyRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	owned := apiKey.UserID != nil && apiKey.UserID.String() == subject.UserID
+	if !owned && !subject.HasRight("manageApiKeys") {
+		return myerrors.ErrAPIKeyNotFound
+	}
+
+	return s.APIKeyRepository.Delete(ctx, id)
+}
+
+// Authenticate finds the key presented by a request and records its use. Unknown, wrong and expired keys
+// fail with ErrInvalidToken, like a bad access token.
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, key string) (*domain.APIKey, error) {
+	prefix, secret, ok := strings.Cut(key, ".")
+	if !ok || !strings.HasPrefix(prefix, domain.APIKeyPrefix) || secret == "" {
+		return nil, myerrors.ErrInvalidToken
+	}
+
+	apiKey, err := s.APIKeyRepository.GetByPrefix(ctx, prefix)
+	if errors.Is(err, myerrors.ErrAPIKeyNotFound) {
+		return nil, myerrors.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(s.hashSecret(secret)), []byte(apiKey.SecretHash)) != 1 {
+		golog.Info("Invalid secret for api key " + apiKey.Prefix)
+		return nil, myerrors.ErrInvalidToken
+	}
+
+	now := time.Now().UTC()
+	if apiKey.Expired(now) {
+		return nil, myerrors.ErrInvalidToken
+	}
+
+	// A failed write must not fail the request
+	if err = s.APIKeyRepository.Touch(ctx, apiKey.ID.String(), now); err != nil {
+		golog.Error("Error recording api key use", err)
+	}
+
+	return apiKey, nil
+}
+
+// checkScopes fails unless every scope is a permission the subject has.
+func (s *APIKeyServiceImpl) checkScopes(ctx context.Context, subject policy.Subject, scopes []string) error {
+	permissions, err := s.RoleService.GetPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range scopes {
+		known := slices.ContainsFunc(permissions, func(permission model.PermissionResponse) bool {
+			return permission.Name == scope
+		})
+		if !known {
+			return myerrors.ErrUnknownScope
+		}
+		if !subject.HasRight(scope) {
+			return myerrors.ErrScopeNotGrantable
+		}
+	}
+
+	return nil
+}
+
+func (s *APIKeyServiceImpl) hashSecret(secret string) string {
+	return crypto.HashToken(secret, s.Conf.JWT.HashKey())
+}
+
+func newAPIKeyResponses(apiKeys []domain.APIKey) []model.APIKeyResponse {
+	resp := make([]model.APIKeyResponse, 0, len(apiKeys))
+	for i := range apiKeys {
+		resp = append(resp, *newAPIKeyResponse(&apiKeys[i]))
+	}
+
+	return resp
+}
+
+func newAPIKeyResponse(apiKey *domain.APIKey) *model.APIKeyResponse {
+	resp := &model.APIKeyResponse{
+		ID:         apiKey.ID.String(),
+		Prefix:     apiKey.Prefix,
+		Name:       apiKey.Name,
+		Service:    apiKey.Service(),
+		Scopes:     apiKey.ScopeList(),
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+	if apiKey.UserID != nil {
+		resp.UserID = apiKey.UserID.String()
+	}
+
+	return resp
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/policy"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type apiKeyServiceTestSuite struct {
+	suite.Suite
+	mockCtrl      *gomock.Controller
+	mockRepo      *mockRepository.MockAPIKeyRepository
+	mockRoleSvc   *mocks.MockRoleService
+	mockValidator *mockValidator.MockValidator
+	service       *APIKeyServiceImpl
+	ctx           context.Context
+	user          policy.Subject
+	admin         policy.Subject
+}
+
+func TestAPIKeyService(t *testing.T) {
+	suite.Run(t, new(apiKeyServiceTestSuite))
+}
+
+func (s *apiKeyServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockRepo = mockRepository.NewMockAPIKeyRepository(s.mockCtrl)
+	s.mockRoleSvc = mocks.NewMockRoleService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.service = &APIKeyServiceImpl{
+		Conf:             &config.Config{JWT: config.JWTConfig{TokenHashKey: "test-hash-key"}},
+		APIKeyRepository: s.mockRepo,
+		RoleService:      s.mockRoleSvc,
+		Validator:        s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+	s.user = policy.Subject{UserID: uuid.Must(uuid.NewV7()).String(), Rights: []string{"getUsers"}}
+	s.admin = policy.Subject{
+		UserID: uuid.Must(uuid.NewV7()).String(),
+		Rights: []string{"getUsers", "manageUsers", "manageApiKeys"},
+	}
+
+	s.mockValidator.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.mockRoleSvc.EXPECT().GetPermissions(gomock.Any()).Return([]model.PermissionResponse{
+		{Name: "getUsers"}, {Name: "manageUsers"}, {Name: "manageApiKeys"},
+	}, nil).AnyTimes()
+}
+
+func (s *apiKeyServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *apiKeyServiceTestSuite) storedKey(prefix, secret string) *domain.APIKey {
+	userID := uuid.MustParse(s.user.UserID)
+	return &domain.APIKey{
+		ID:         uuid.Must(uuid.NewV7()),
+		UserID:     &userID,
+		Prefix:     prefix,
+		SecretHash: crypto.HashToken(secret, "test-hash-key"),
+		Scopes:     "getUsers",
+	}
+}
+
+// ==================== CreateAPIKey Tests ====================
+
+func (s *apiKeyServiceTestSuite) TestCreateAPIKey_Personal() {
+	req := &model.CreateAPIKeyRequest{Name: "export", Scopes: []string{"getUsers", "getUsers"}}
+
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, apiKey *domain.APIKey) (*domain.APIKey, error) {
+			s.Equal(s.user.UserID, apiKey.UserID.String())
+			s.Equal("getUsers", apiKey.Scopes)
+			s.True(strings.HasPrefix(apiKey.Prefix, domain.APIKeyPrefix))
+			return apiKey, nil
+		})
+
+	resp, err := s.service.CreateAPIKey(s.ctx, s.user, req)
+
+	s.Require().NoError(err)
+	s.False(resp.Service)
+	prefix, secret, ok := strings.Cut(resp.Key, ".")
+	s.Require().True(ok)
+	s.Equal(resp.Prefix, prefix)
+	s.NotEmpty(secret)
+}
+
+func (s *apiKeyServiceTestSuite) TestCreateAPIKey_Service() {
+	req := &model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"manageUsers"}, Service: true}
+
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, apiKey *domain.APIKey) (*domain.APIKey, error) {
+			s.Nil(apiKey.UserID)
+			s.Equal(s.admin.UserID, apiKey.CreatedBy.String())
+			return apiKey, nil
+		})
+
+	resp, err := s.service.CreateAPIKey(s.ctx, s.admin, req)
+
+	s.Require().NoError(err)
+	s.True(resp.Service)
+}
+
+func (s *apiKeyServiceTestSuite) TestCreateAPIKey_ServiceForbidden() {
+	req := &model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"getUsers"}, Service: true}
+
+	_, err := s.service.CreateAPIKey(s.ctx, s.user, req)
+
+	s.ErrorIs(err, myerrors.ErrForbidden)
+}
+
+func (s *apiKeyServiceTestSuite) TestCreateAPIKey_ScopeNotGrantable() {
+	req := &model.CreateAPIKeyRequest{Name: "export", Scopes: []string{"manageUsers"}}
+
+	_, err := s.service.CreateAPIKey(s.ctx, s.user, req)
+
+	s.ErrorIs(err, myerrors.ErrScopeNotGrantable)
+}
+
+func (s *apiKeyServiceTestSuite) TestCreateAPIKey_UnknownScope() {
+	req := &model.CreateAPIKeyRequest{Name: "export", Scopes: []string{"doEverything"}}
+
+	_, err := s.service.CreateAPIKey(s.ctx, s.admin, req)
+
+	s.ErrorIs(err, myerrors.ErrUnknownScope)
+}
+
+func (s *apiKeyServiceTestSuite) TestCreateAPIKey_ExpiryInThePast() {
+	past := time.Now().Add(-time.Minute)
+	req := &model.CreateAPIKeyRequest{Name: "export", Scopes: []string{"getUsers"}, ExpiresAt: &past}
+
+	_, err := s.service.CreateAPIKey(s.ctx, s.user, req)
+
+	s.ErrorIs(err, myerrors.ErrInvalidExpiry)
+}
+
+// ==================== RevokeAPIKey Tests ====================
+
+func (s *apiKeyServiceTestSuite) TestRevokeAPIKey_Owner() {
+	apiKey := s.storedKey("ak_owned", "secret")
+
+	s.mockRepo.EXPECT().GetByID(s.ctx, apiKey.ID.String()).Return(apiKey, nil)
+	s.mockRepo.EXPECT().Delete(s.ctx, apiKey.ID.String()).Return(nil)
+
+	s.NoError(s.service.RevokeAPIKey(s.ctx, s.user, apiKey.ID.String()))
+}
+
+func (s *apiKeyServiceTestSuite) TestRevokeAPIKey_Admin() {
+	apiKey := s.storedKey("ak_owned", "secret")
+
+	s.mockRepo.EXPECT().GetByID(s.ctx, apiKey.ID.String()).Return(apiKey, nil)
+	s.mockRepo.EXPECT().Delete(s.ctx, apiKey.ID.String()).Return(nil)
+
+	s.NoError(s.service.RevokeAPIKey(s.ctx, s.admin, apiKey.ID.String()))
+}
+
+func (s *apiKeyServiceTestSuite) TestRevokeAPIKey_OtherUser() {
+	apiKey := s.storedKey("ak_owned", "secret")
+	other := policy.Subject{UserID: uuid.Must(uuid.NewV7()).String(), Rights: []string{"getUsers"}}
+
+	s.mockRepo.EXPECT().GetByID(s.ctx, apiKey.ID.String()).Return(apiKey, nil)
+
+	err := s.service.RevokeAPIKey(s.ctx, other, apiKey.ID.String())
+
+	s.ErrorIs(err, myerrors.ErrAPIKeyNotFound)
+}
+
+// ==================== Authenticate Tests ====================
+
+func (s *apiKeyServiceTestSuite) TestAuthenticate_Success() {
+	apiKey := s.storedKey("ak_valid", "secret")
+
+	s.mockRepo.EXPECT().GetByPrefix(s.ctx, "ak_valid").Return(apiKey, nil)
+	s.mockRepo.EXPECT().Touch(s.ctx, apiKey.ID.String(), gomock.Any()).Return(nil)
+
+	got, err := s.service.Authenticate(s.ctx, "ak_valid.secret")
+
+	s.Require().NoError(err)
+	s.Equal(apiKey.ID, got.ID)
+}
+
+func (s *apiKeyServiceTestSuite) TestAuthenticate_TouchFailureIgnored() {
+	apiKey := s.storedKey("ak_valid", "secret")
+
+	s.mockRepo.EXPECT().GetByPrefix(s.ctx, "ak_valid").Return(apiKey, nil)
+	s.mockRepo.EXPECT().Touch(s.ctx, apiKey.ID.String(), gomock.Any()).Return(errors.New("db down"))
+
+	_, err := s.service.Authenticate(s.ctx, "ak_valid.secret")
+
+	s.NoError(err)
+}
+
+func (s *apiKeyServiceTestSuite) TestAuthenticate_WrongSecret() {
+	s.mockRepo.EXPECT().GetByPrefix(s.ctx, "ak_valid").Return(s.storedKey("ak_valid", "secret"), nil)
+
+	_, err := s.service.Authenticate(s.ctx, "ak_valid.guess")
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *apiKeyServiceTestSuite) TestAuthenticate_Expired() {
+	apiKey := s.storedKey("ak_valid", "secret")
+	expired := time.Now().Add(-time.Minute)
+	apiKey.ExpiresAt = &expired
+
+	s.mockRepo.EXPECT().GetByPrefix(s.ctx, "ak_valid").Return(apiKey, nil)
+
+	_, err := s.service.Authenticate(s.ctx, "ak_valid.secret")
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *apiKeyServiceTestSuite) TestAuthenticate_UnknownKey() {
+	s.mockRepo.EXPECT().GetByPrefix(s.ctx, "ak_unknown").Return(nil, myerrors.ErrAPIKeyNotFound)
+
+	_, err := s.service.Authenticate(s.ctx, "ak_unknown.secret")
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *apiKeyServiceTestSuite) TestAuthenticate_Malformed() {
+	for _, key := range []string{"", "ak_nosecret", "ak_empty.", "xx_wrong.secret"} {
+		_, err := s.service.Authenticate(s.ctx, key)
+
+		s.ErrorIs(err, myerrors.ErrInvalidToken, key)
+	}
+}
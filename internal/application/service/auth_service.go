@@ -9,23 +9,35 @@ import (
 	"app/internal/pkg/token"
 	"app/internal/pkg/validator"
 	"context"
+	"time"
 )
 
+const defaultChangePasswordExpire = 10 * time.Minute
+
 //go:generate mockgen -source=auth_service.go -destination=mocks/auth_service.go -package=mocks
 type AuthService interface {
 	Register(ctx context.Context, req *model.RegisterRequest) (*domain.User, error)
 	Login(ctx context.Context, req *model.LoginRequest) (*domain.User, error)
+	ForgotPassword(ctx context.Context, req *model.ForgotPasswordRequest) (*domain.Token, error)
 	Logout(ctx context.Context, req *model.LogoutRequest) error
-	RefreshAuth(ctx context.Context, req *model.RefreshTokenRequest) (*domain.Token, error)
+	RefreshAuth(
+		ctx context.Context, req *model.RefreshTokenRequest, device *model.DeviceInfo,
+	) (*domain.User, *domain.Token, *domain.Token, error)
 	ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error
 	VerifyEmail(ctx context.Context, query *model.VerifyEmailRequest) error
+	VerifyMagicLink(ctx context.Context, req *model.VerifyMagicLinkRequest) (*domain.User, error)
+	PasswordExpired(user *domain.User) bool
+	GeneratePasswordChangeToken(ctx context.Context, userID string) (*domain.Token, error)
+	ChangeExpiredPassword(ctx context.Context, req *model.ChangeExpiredPasswordRequest) (*domain.User, error)
 }
 
 type AuthServiceImpl struct {
-	Conf         *config.Config      `inject:"config"`
-	TokenService TokenService        `inject:"tokenService"`
-	UserService  UserService         `inject:"userService"`
-	Validate     validator.Validator `inject:"validator"`
+	Conf           *config.Config      `inject:"config"`
+	TokenService   TokenService        `inject:"tokenService"`
+	UserService    UserService         `inject:"userService"`
+	LockoutService LockoutService      `inject:"lockoutService"`
+	Validate       validator.Validator `inject:"validator"`
+	Keys           token.KeyManager    `inject:"keyManager"`
 }
 
 func (s *AuthServiceImpl) Register(ctx context.Context, req *model.RegisterRequest) (*domain.User, error) {
@@ -37,7 +49,7 @@ func (s *AuthServiceImpl) Register(ctx context.Context, req *model.RegisterReque
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
-		Role:     "user",
+		Roles:    []string{domain.RoleUser},
 	})
 	if err != nil {
 		return nil, err
@@ -46,6 +58,9 @@ func (s *AuthServiceImpl) Register(ctx context.Context, req *model.RegisterReque
 	return newUser, nil
 }
 
+// Login checks the password of the user. Locked or throttled accounts are refused before the password is
+// checked, and wrong passwords count towards the lockout of the account. With two-factor authentication the
+// failures are only forgotten once the second factor is verified, so wrong codes keep adding up.
 func (s *AuthServiceImpl) Login(ctx context.Context, req *model.LoginRequest) (*domain.User, error) {
 	if err := s.Validate.Validate(ctx, req); err != nil {
 		return nil, err
@@ -56,13 +71,51 @@ func (s *AuthServiceImpl) Login(ctx context.Context, req *model.LoginRequest) (*
 		return nil, err
 	}
 
-	if !crypto.CheckPasswordHash(req.Password, user.Password) {
-		return nil, myerrors.ErrInvalidEmailOrPassword
+	if err = s.checkPassword(ctx, user, req.Password); err != nil {
+		return nil, err
 	}
 
 	return user, nil
 }
 
+// ForgotPassword issues a reset password token once the password of the user is confirmed, with the same
+// lockout checks as Login, so the endpoint cannot be used to guess passwords.
+func (s *AuthServiceImpl) ForgotPassword(ctx context.Context, req *model.ForgotPasswordRequest) (*domain.Token, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkPassword(ctx, user, req.Password); err != nil {
+		return nil, err
+	}
+
+	return s.TokenService.GenerateResetPasswordToken(ctx, user.ID.String())
+}
+
+func (s *AuthServiceImpl) checkPassword(ctx context.Context, user *domain.User, password string) error {
+	if err := s.LockoutService.CheckLogin(ctx, user.ID.String()); err != nil {
+		return err
+	}
+
+	if !crypto.CheckPasswordHash(password, user.Password) {
+		if err := s.LockoutService.RecordFailedLogin(ctx, user); err != nil {
+			return err
+		}
+		return myerrors.ErrInvalidEmailOrPassword
+	}
+
+	if user.TOTPEnabled {
+		return nil
+	}
+
+	return s.LockoutService.RecordSuccessfulLogin(ctx, user.ID.String())
+}
+
 func (s *AuthServiceImpl) Logout(ctx context.Context, req *model.LogoutRequest) error {
 	if err := s.Validate.Validate(ctx, req); err != nil {
 		return err
@@ -73,30 +126,43 @@ func (s *AuthServiceImpl) Logout(ctx context.Context, req *model.LogoutRequest)
 		return err
 	}
 
-	return s.TokenService.DeleteToken(ctx, domain.TokenTypeRefresh, token.UserID.String())
+	// Refresh tokens issued before sessions existed are not tied to one
+	if token.SessionID == nil {
+		return s.TokenService.DeleteToken(ctx, domain.TokenTypeRefresh, token.UserID.String())
+	}
+
+	return s.TokenService.DeleteSession(ctx, token.UserID.String(), token.SessionID.String())
 }
 
-func (s *AuthServiceImpl) RefreshAuth(ctx context.Context, req *model.RefreshTokenRequest) (*domain.Token, error) {
+// RefreshAuth rotates the refresh token of a login and returns its user with the new tokens.
+func (s *AuthServiceImpl) RefreshAuth(
+	ctx context.Context, req *model.RefreshTokenRequest, device *model.DeviceInfo,
+) (*domain.User, *domain.Token, *domain.Token, error) {
 	if err := s.Validate.Validate(ctx, req); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	token, err := s.TokenService.GetTokenByRefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
+	}
+
+	// Refresh tokens issued to an OAuth client are bound to it and only refreshed at /oauth/token
+	if token.Session != nil && token.Session.ClientID != nil {
+		return nil, nil, nil, myerrors.ErrInvalidToken
 	}
 
 	user, err := s.UserService.GetUserByID(ctx, token.UserID.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	accessToken, err := s.TokenService.GenerateAccessToken(ctx, user.ID.String())
+	accessToken, refreshToken, err := s.TokenService.RotateRefreshToken(ctx, token, device)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	return accessToken, nil
+	return user, accessToken, refreshToken, nil
 }
 
 func (s *AuthServiceImpl) ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error {
@@ -104,7 +170,7 @@ func (s *AuthServiceImpl) ResetPassword(ctx context.Context, req *model.ResetPas
 		return err
 	}
 
-	userID, err := token.VerifyToken(req.Token, s.Conf.JWT.Secret, domain.TokenTypeResetPassword.String())
+	userID, err := token.VerifyToken(req.Token, s.Keys, domain.TokenTypeResetPassword.String())
 	if err != nil {
 		return err
 	}
@@ -133,7 +199,7 @@ func (s *AuthServiceImpl) VerifyEmail(ctx context.Context, req *model.VerifyEmai
 		return err
 	}
 
-	userID, err := token.VerifyToken(req.Token, s.Conf.JWT.Secret, domain.TokenTypeVerifyEmail.String())
+	userID, err := token.VerifyToken(req.Token, s.Keys, domain.TokenTypeVerifyEmail.String())
 	if err != nil {
 		return err
 	}
@@ -149,3 +215,75 @@ func (s *AuthServiceImpl) VerifyEmail(ctx context.Context, req *model.VerifyEmai
 
 	return nil
 }
+
+// VerifyMagicLink consumes the emailed login token and returns the user it was issued to.
+func (s *AuthServiceImpl) VerifyMagicLink(ctx context.Context, req *model.VerifyMagicLinkRequest) (*domain.User, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.TokenService.ConsumeChallengeToken(ctx, req.Token, domain.TokenTypeMagicLink)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, myerrors.ErrInvalidTokenUserID
+	}
+
+	return s.UserService.GetUserByID(ctx, userID)
+}
+
+// PasswordExpired reports whether the password of the user is older than password.max_age, for users with
+// one of password.expire_roles.
+func (s *AuthServiceImpl) PasswordExpired(user *domain.User) bool {
+	return user.PasswordExpired(s.Conf.Password.MaxAge, s.Conf.Password.ExpireRoles, time.Now())
+}
+
+// GeneratePasswordChangeToken issues the single-use token a user with an expired password gets at login
+// instead of the auth tokens.
+func (s *AuthServiceImpl) GeneratePasswordChangeToken(ctx context.Context, userID string) (*domain.Token, error) {
+	expire := s.Conf.JWT.ChangePasswordExpire
+	if expire <= 0 {
+		expire = defaultChangePasswordExpire
+	}
+
+	return s.TokenService.GenerateChallengeToken(
+		ctx, userID, domain.TokenTypeChangePassword, time.Now().UTC().Add(expire), nil,
+	)
+}
+
+// ChangeExpiredPassword consumes the password change token and sets the new password, which follows the
+// password policy and history like any other.
+func (s *AuthServiceImpl) ChangeExpiredPassword(
+	ctx context.Context, req *model.ChangeExpiredPasswordRequest,
+) (*domain.User, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.TokenService.ConsumeChallengeToken(ctx, req.Token, domain.TokenTypeChangePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, myerrors.ErrInvalidTokenUserID
+	}
+
+	user, err := s.UserService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.UserService.UpdatePassOrVerify(ctx, &model.UpdatePassOrVerifyRequest{
+		Password:      req.Password,
+		VerifiedEmail: user.VerifiedEmail,
+	}, userID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
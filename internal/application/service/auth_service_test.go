@@ -7,6 +7,7 @@ import (
 	"app/internal/domain"
 	"app/internal/domain/myerrors"
 	"app/internal/pkg/crypto"
+	pkgToken "app/internal/pkg/token"
 	mockValidator "app/internal/pkg/validator/mocks"
 	"context"
 	"errors"
@@ -24,6 +25,7 @@ type authServiceTestSuite struct {
 	mockCtrl      *gomock.Controller
 	mockTokenSvc  *mocks.MockTokenService
 	mockUserSvc   *mocks.MockUserService
+	mockLockout   *mocks.MockLockoutService
 	mockValidator *mockValidator.MockValidator
 	authService   *AuthServiceImpl
 	ctx           context.Context
@@ -39,17 +41,24 @@ func (s *authServiceTestSuite) SetupTest() {
 	s.mockCtrl = gomock.NewController(s.T())
 	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
 	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockLockout = mocks.NewMockLockoutService(s.mockCtrl)
 	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
 
-	s.authService = &AuthServiceImpl{
-		Conf: &config.Config{
-			JWT: config.JWTConfig{
-				Secret: "test-secret-key-for-unit-testing",
-			},
+	conf := &config.Config{
+		JWT: config.JWTConfig{
+			Secret: "test-secret-key-for-unit-testing",
 		},
-		TokenService: s.mockTokenSvc,
-		UserService:  s.mockUserSvc,
-		Validate:     s.mockValidator,
+	}
+	keys := &pkgToken.KeyManagerImpl{Conf: conf}
+	s.Require().NoError(keys.Startup())
+
+	s.authService = &AuthServiceImpl{
+		Conf:           conf,
+		TokenService:   s.mockTokenSvc,
+		UserService:    s.mockUserSvc,
+		LockoutService: s.mockLockout,
+		Validate:       s.mockValidator,
+		Keys:           keys,
 	}
 
 	s.ctx = context.Background()
@@ -71,7 +80,7 @@ func (s *authServiceTestSuite) createTestUser() *domain.User {
 		Name:          "Test User",
 		Email:         "test@example.com",
 		Password:      s.hashedPass,
-		Role:          "user",
+		Roles:         domain.NewUserRoles("user"),
 		VerifiedEmail: false,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
@@ -111,7 +120,7 @@ func (s *authServiceTestSuite) TestRegister_Success() {
 			Name:     req.Name,
 			Email:    req.Email,
 			Password: req.Password,
-			Role:     "user",
+			Roles:    []string{"user"},
 		}).
 		Return(expectedUser, nil)
 
@@ -185,6 +194,9 @@ func (s *authServiceTestSuite) TestLogin_Success() {
 		GetUserByEmail(s.ctx, req.Email).
 		Return(testUser, nil)
 
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(nil)
+	s.mockLockout.EXPECT().RecordSuccessfulLogin(s.ctx, testUser.ID.String()).Return(nil)
+
 	result, err := s.authService.Login(s.ctx, req)
 
 	s.NoError(err)
@@ -192,6 +204,32 @@ func (s *authServiceTestSuite) TestLogin_Success() {
 	s.Equal("test@example.com", result.Email)
 }
 
+func (s *authServiceTestSuite) TestLogin_TOTPKeepsFailures() {
+	req := &model.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	testUser := s.createTestUser()
+	testUser.TOTPEnabled = true
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+
+	s.mockUserSvc.EXPECT().
+		GetUserByEmail(s.ctx, req.Email).
+		Return(testUser, nil)
+
+	// The failures are forgotten by the second factor, not by the password
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(nil)
+
+	result, err := s.authService.Login(s.ctx, req)
+
+	s.NoError(err)
+	s.Equal(testUser, result)
+}
+
 func (s *authServiceTestSuite) TestLogin_ValidationError() {
 	req := &model.LoginRequest{
 		Email:    "",
@@ -248,6 +286,9 @@ func (s *authServiceTestSuite) TestLogin_InvalidPassword() {
 		GetUserByEmail(s.ctx, req.Email).
 		Return(testUser, nil)
 
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(nil)
+	s.mockLockout.EXPECT().RecordFailedLogin(s.ctx, testUser).Return(nil)
+
 	result, err := s.authService.Login(s.ctx, req)
 
 	s.Error(err)
@@ -255,6 +296,107 @@ func (s *authServiceTestSuite) TestLogin_InvalidPassword() {
 	s.Nil(result)
 }
 
+func (s *authServiceTestSuite) TestLogin_InvalidPasswordLocksAccount() {
+	req := &model.LoginRequest{Email: "test@example.com", Password: "wrongpassword"}
+	testUser := s.createTestUser()
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(testUser, nil)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(nil)
+	s.mockLockout.EXPECT().RecordFailedLogin(s.ctx, testUser).Return(myerrors.ErrAccountLocked)
+
+	result, err := s.authService.Login(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrAccountLocked)
+	s.Nil(result)
+}
+
+func (s *authServiceTestSuite) TestLogin_Locked() {
+	req := &model.LoginRequest{Email: "test@example.com", Password: "password123"}
+	testUser := s.createTestUser()
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(testUser, nil)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(myerrors.ErrAccountLocked)
+
+	result, err := s.authService.Login(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrAccountLocked)
+	s.Nil(result)
+}
+
+// ==================== ForgotPassword Tests ====================
+
+func (s *authServiceTestSuite) TestForgotPassword_Success() {
+	req := &model.ForgotPasswordRequest{Email: "test@example.com", Password: "password123"}
+	testUser := s.createTestUser()
+	resetToken := &domain.Token{Token: "reset-token", Type: domain.TokenTypeResetPassword}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(testUser, nil)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(nil)
+	s.mockLockout.EXPECT().RecordSuccessfulLogin(s.ctx, testUser.ID.String()).Return(nil)
+	s.mockTokenSvc.EXPECT().GenerateResetPasswordToken(s.ctx, testUser.ID.String()).Return(resetToken, nil)
+
+	result, err := s.authService.ForgotPassword(s.ctx, req)
+
+	s.NoError(err)
+	s.Equal(resetToken, result)
+}
+
+func (s *authServiceTestSuite) TestForgotPassword_ValidationError() {
+	req := &model.ForgotPasswordRequest{Email: "invalid-email"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(myerrors.ErrInvalidRequest)
+
+	result, err := s.authService.ForgotPassword(s.ctx, req)
+
+	s.Equal(myerrors.ErrInvalidRequest, err)
+	s.Nil(result)
+}
+
+func (s *authServiceTestSuite) TestForgotPassword_UserNotFound() {
+	req := &model.ForgotPasswordRequest{Email: "nonexistent@example.com", Password: "password123"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(nil, myerrors.ErrUserNotFound)
+
+	result, err := s.authService.ForgotPassword(s.ctx, req)
+
+	s.Equal(myerrors.ErrUserNotFound, err)
+	s.Nil(result)
+}
+
+func (s *authServiceTestSuite) TestForgotPassword_InvalidPasswordCounted() {
+	req := &model.ForgotPasswordRequest{Email: "test@example.com", Password: "wrongpassword"}
+	testUser := s.createTestUser()
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(testUser, nil)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(nil)
+	s.mockLockout.EXPECT().RecordFailedLogin(s.ctx, testUser).Return(nil)
+
+	result, err := s.authService.ForgotPassword(s.ctx, req)
+
+	s.Equal(myerrors.ErrInvalidEmailOrPassword, err)
+	s.Nil(result)
+}
+
+func (s *authServiceTestSuite) TestForgotPassword_Locked() {
+	// The right password does not get a reset token while the account is locked
+	req := &model.ForgotPasswordRequest{Email: "test@example.com", Password: "password123"}
+	testUser := s.createTestUser()
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(testUser, nil)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, testUser.ID.String()).Return(myerrors.ErrAccountLocked)
+
+	result, err := s.authService.ForgotPassword(s.ctx, req)
+
+	s.Equal(myerrors.ErrAccountLocked, err)
+	s.Nil(result)
+}
+
 // ==================== Logout Tests ====================
 
 func (s *authServiceTestSuite) TestLogout_Success() {
@@ -281,6 +423,32 @@ func (s *authServiceTestSuite) TestLogout_Success() {
 	s.NoError(err)
 }
 
+func (s *authServiceTestSuite) TestLogout_Success_DeletesSession() {
+	req := &model.LogoutRequest{
+		RefreshToken: "valid-refresh-token",
+	}
+
+	sessionID := uuid.Must(uuid.NewV7())
+	testToken := s.createTestToken(domain.TokenTypeRefresh)
+	testToken.SessionID = &sessionID
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+
+	s.mockTokenSvc.EXPECT().
+		GetTokenByRefreshToken(s.ctx, req.RefreshToken).
+		Return(testToken, nil)
+
+	s.mockTokenSvc.EXPECT().
+		DeleteSession(s.ctx, testToken.UserID.String(), sessionID.String()).
+		Return(nil)
+
+	err := s.authService.Logout(s.ctx, req)
+
+	s.NoError(err)
+}
+
 func (s *authServiceTestSuite) TestLogout_ValidationError() {
 	req := &model.LogoutRequest{
 		RefreshToken: "",
@@ -349,10 +517,12 @@ func (s *authServiceTestSuite) TestRefreshAuth_Success() {
 	req := &model.RefreshTokenRequest{
 		RefreshToken: "valid-refresh-token",
 	}
+	device := &model.DeviceInfo{UserAgent: "test-agent", IPAddress: "127.0.0.1"}
 
 	testToken := s.createTestToken(domain.TokenTypeRefresh)
 	testUser := s.createTestUser()
 	newAccessToken := s.createTestToken(domain.TokenTypeAccess)
+	newRefreshToken := s.createTestToken(domain.TokenTypeRefresh)
 
 	s.mockValidator.EXPECT().
 		Validate(s.ctx, req).
@@ -367,13 +537,56 @@ func (s *authServiceTestSuite) TestRefreshAuth_Success() {
 		Return(testUser, nil)
 
 	s.mockTokenSvc.EXPECT().
-		GenerateAccessToken(s.ctx, testUser.ID.String()).
-		Return(newAccessToken, nil)
+		RotateRefreshToken(s.ctx, testToken, device).
+		Return(newAccessToken, newRefreshToken, nil)
 
-	result, err := s.authService.RefreshAuth(s.ctx, req)
+	user, accessToken, refreshToken, err := s.authService.RefreshAuth(s.ctx, req, device)
 
 	s.NoError(err)
-	s.Equal(newAccessToken, result)
+	s.Equal(testUser, user)
+	s.Equal(newAccessToken, accessToken)
+	s.Equal(newRefreshToken, refreshToken)
+}
+
+func (s *authServiceTestSuite) TestRefreshAuth_PasswordExpired() {
+	req := &model.RefreshTokenRequest{RefreshToken: "valid-refresh-token"}
+	testToken := s.createTestToken(domain.TokenTypeRefresh)
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().GetTokenByRefreshToken(s.ctx, req.RefreshToken).Return(testToken, nil)
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, testToken.UserID.String()).Return(s.createTestUser(), nil)
+	s.mockTokenSvc.EXPECT().RotateRefreshToken(s.ctx, testToken, nil).Return(nil, nil, myerrors.ErrPasswordExpired)
+
+	user, accessToken, refreshToken, err := s.authService.RefreshAuth(s.ctx, req, nil)
+
+	s.ErrorIs(err, myerrors.ErrPasswordExpired)
+	s.Nil(user)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
+}
+
+func (s *authServiceTestSuite) TestRefreshAuth_ClientToken() {
+	req := &model.RefreshTokenRequest{
+		RefreshToken: "client-refresh-token",
+	}
+
+	clientID := uuid.Must(uuid.NewV7())
+	testToken := s.createTestToken(domain.TokenTypeRefresh)
+	testToken.Session = &domain.Session{ClientID: &clientID}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+
+	s.mockTokenSvc.EXPECT().
+		GetTokenByRefreshToken(s.ctx, req.RefreshToken).
+		Return(testToken, nil)
+
+	_, accessToken, refreshToken, err := s.authService.RefreshAuth(s.ctx, req, nil)
+
+	s.Equal(myerrors.ErrInvalidToken, err)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
 }
 
 func (s *authServiceTestSuite) TestRefreshAuth_ValidationError() {
@@ -387,11 +600,12 @@ func (s *authServiceTestSuite) TestRefreshAuth_ValidationError() {
 		Validate(s.ctx, req).
 		Return(validationErr)
 
-	result, err := s.authService.RefreshAuth(s.ctx, req)
+	_, accessToken, refreshToken, err := s.authService.RefreshAuth(s.ctx, req, nil)
 
 	s.Error(err)
 	s.Equal(validationErr, err)
-	s.Nil(result)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
 }
 
 func (s *authServiceTestSuite) TestRefreshAuth_TokenNotFound() {
@@ -407,11 +621,12 @@ func (s *authServiceTestSuite) TestRefreshAuth_TokenNotFound() {
 		GetTokenByRefreshToken(s.ctx, req.RefreshToken).
 		Return(nil, myerrors.ErrTokenNotFound)
 
-	result, err := s.authService.RefreshAuth(s.ctx, req)
+	_, accessToken, refreshToken, err := s.authService.RefreshAuth(s.ctx, req, nil)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrTokenNotFound, err)
-	s.Nil(result)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
 }
 
 func (s *authServiceTestSuite) TestRefreshAuth_UserNotFound() {
@@ -433,16 +648,17 @@ func (s *authServiceTestSuite) TestRefreshAuth_UserNotFound() {
 		GetUserByID(s.ctx, testToken.UserID.String()).
 		Return(nil, myerrors.ErrUserNotFound)
 
-	result, err := s.authService.RefreshAuth(s.ctx, req)
+	_, accessToken, refreshToken, err := s.authService.RefreshAuth(s.ctx, req, nil)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrUserNotFound, err)
-	s.Nil(result)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
 }
 
-func (s *authServiceTestSuite) TestRefreshAuth_GenerateAccessTokenError() {
+func (s *authServiceTestSuite) TestRefreshAuth_TokenReused() {
 	req := &model.RefreshTokenRequest{
-		RefreshToken: "valid-refresh-token",
+		RefreshToken: "consumed-refresh-token",
 	}
 
 	testToken := s.createTestToken(domain.TokenTypeRefresh)
@@ -461,14 +677,15 @@ func (s *authServiceTestSuite) TestRefreshAuth_GenerateAccessTokenError() {
 		Return(testUser, nil)
 
 	s.mockTokenSvc.EXPECT().
-		GenerateAccessToken(s.ctx, testUser.ID.String()).
-		Return(nil, myerrors.ErrGenerateTokenFailed)
+		RotateRefreshToken(s.ctx, testToken, nil).
+		Return(nil, nil, myerrors.ErrRefreshTokenReused)
 
-	result, err := s.authService.RefreshAuth(s.ctx, req)
+	_, accessToken, refreshToken, err := s.authService.RefreshAuth(s.ctx, req, nil)
 
 	s.Error(err)
-	s.Equal(myerrors.ErrGenerateTokenFailed, err)
-	s.Nil(result)
+	s.Equal(myerrors.ErrRefreshTokenReused, err)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
 }
 
 // ==================== ResetPassword Tests ====================
@@ -773,3 +990,171 @@ func createTestJWTToken(userID, tokenType, secret string) string {
 	tokenString, _ := token.SignedString([]byte(secret))
 	return tokenString
 }
+
+// ==================== VerifyMagicLink Tests ====================
+
+func (s *authServiceTestSuite) TestVerifyMagicLink_Success() {
+	req := &model.VerifyMagicLinkRequest{Token: "magic-link-token"}
+	testUser := s.createTestUser()
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, req.Token, domain.TokenTypeMagicLink).
+		Return(jwt.MapClaims{"user_id": testUser.ID.String()}, nil)
+	s.mockUserSvc.EXPECT().
+		GetUserByID(s.ctx, testUser.ID.String()).
+		Return(testUser, nil)
+
+	user, err := s.authService.VerifyMagicLink(s.ctx, req)
+
+	s.NoError(err)
+	s.Equal(testUser.ID, user.ID)
+}
+
+func (s *authServiceTestSuite) TestVerifyMagicLink_AlreadyUsed() {
+	req := &model.VerifyMagicLinkRequest{Token: "magic-link-token"}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, req.Token, domain.TokenTypeMagicLink).
+		Return(nil, myerrors.ErrInvalidToken)
+
+	user, err := s.authService.VerifyMagicLink(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+	s.Nil(user)
+}
+
+func (s *authServiceTestSuite) TestVerifyMagicLink_ValidationError() {
+	req := &model.VerifyMagicLinkRequest{}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(myerrors.ErrInvalidRequest)
+
+	user, err := s.authService.VerifyMagicLink(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrInvalidRequest)
+	s.Nil(user)
+}
+
+// ==================== Password Expiry Tests ====================
+
+func (s *authServiceTestSuite) TestPasswordExpired() {
+	s.authService.Conf.Password = config.PasswordConfig{MaxAge: 24 * time.Hour, ExpireRoles: []string{"admin"}}
+
+	longAgo := time.Now().Add(-48 * time.Hour)
+	recently := time.Now().Add(-time.Hour)
+
+	admin := s.createTestUser()
+	admin.Roles = domain.NewUserRoles("admin")
+
+	admin.PasswordChangedAt = &longAgo
+	s.True(s.authService.PasswordExpired(admin))
+
+	admin.PasswordChangedAt = &recently
+	s.False(s.authService.PasswordExpired(admin))
+
+	// Users without a change date are measured from their creation
+	admin.PasswordChangedAt = nil
+	admin.CreatedAt = longAgo
+	s.True(s.authService.PasswordExpired(admin))
+
+	// Only the configured roles expire
+	user := s.createTestUser()
+	user.PasswordChangedAt = &longAgo
+	s.False(s.authService.PasswordExpired(user))
+
+	s.authService.Conf.Password.ExpireRoles = nil
+	s.True(s.authService.PasswordExpired(user))
+
+	// Accounts without a password have nothing to expire
+	user.Password = ""
+	s.False(s.authService.PasswordExpired(user))
+
+	s.authService.Conf.Password.MaxAge = 0
+	admin.PasswordChangedAt = &longAgo
+	s.False(s.authService.PasswordExpired(admin))
+}
+
+func (s *authServiceTestSuite) TestGeneratePasswordChangeToken() {
+	changeToken := s.createTestToken(domain.TokenTypeChangePassword)
+
+	s.mockTokenSvc.EXPECT().
+		GenerateChallengeToken(s.ctx, s.testUUID.String(), domain.TokenTypeChangePassword, gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, _ string, _ domain.TokenType, expires time.Time, _ map[string]any) (*domain.Token, error) {
+			s.WithinDuration(time.Now().Add(defaultChangePasswordExpire), expires, time.Minute)
+			return changeToken, nil
+		})
+
+	token, err := s.authService.GeneratePasswordChangeToken(s.ctx, s.testUUID.String())
+
+	s.NoError(err)
+	s.Equal(changeToken, token)
+}
+
+func (s *authServiceTestSuite) TestChangeExpiredPassword_Success() {
+	req := &model.ChangeExpiredPasswordRequest{Token: "change-password-token", Password: "Tr0ub4dor&3"}
+	testUser := s.createTestUser()
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, req.Token, domain.TokenTypeChangePassword).
+		Return(jwt.MapClaims{"user_id": testUser.ID.String()}, nil)
+	s.mockUserSvc.EXPECT().
+		GetUserByID(s.ctx, testUser.ID.String()).
+		Return(testUser, nil)
+	s.mockUserSvc.EXPECT().
+		UpdatePassOrVerify(s.ctx, &model.UpdatePassOrVerifyRequest{Password: req.Password}, testUser.ID.String()).
+		Return(nil)
+
+	user, err := s.authService.ChangeExpiredPassword(s.ctx, req)
+
+	s.NoError(err)
+	s.Equal(testUser.ID, user.ID)
+}
+
+func (s *authServiceTestSuite) TestChangeExpiredPassword_InvalidToken() {
+	req := &model.ChangeExpiredPasswordRequest{Token: "change-password-token", Password: "Tr0ub4dor&3"}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, req.Token, domain.TokenTypeChangePassword).
+		Return(nil, myerrors.ErrInvalidToken)
+
+	user, err := s.authService.ChangeExpiredPassword(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+	s.Nil(user)
+}
+
+func (s *authServiceTestSuite) TestChangeExpiredPassword_PasswordReused() {
+	req := &model.ChangeExpiredPasswordRequest{Token: "change-password-token", Password: "password123"}
+	testUser := s.createTestUser()
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, req.Token, domain.TokenTypeChangePassword).
+		Return(jwt.MapClaims{"user_id": testUser.ID.String()}, nil)
+	s.mockUserSvc.EXPECT().
+		GetUserByID(s.ctx, testUser.ID.String()).
+		Return(testUser, nil)
+	s.mockUserSvc.EXPECT().
+		UpdatePassOrVerify(s.ctx, gomock.Any(), testUser.ID.String()).
+		Return(myerrors.ErrPasswordReused)
+
+	user, err := s.authService.ChangeExpiredPassword(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrPasswordReused)
+	s.Nil(user)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/validator"
+	"context"
+	cryptoRand "crypto/rand"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tommynurwantoro/golog"
+)
+
+const (
+	defaultDeviceCodeExpire   = 10 * time.Minute
+	defaultDevicePollInterval = 5 * time.Second
+	// slowDownStep is added to the poll interval of a device that polls too often (RFC 8628 section 3.5)
+	slowDownStep = 5
+	// deviceCodeSize is the number of random bytes in a device code
+	deviceCodeSize = 32
+	// User codes are typed by hand, so they only use consonants that are hard to confuse (RFC 8628 section 6.1)
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+//go:generate mockgen -source=device_authorization_service.go -destination=mocks/device_authorization_service.go -package=mocks
+type DeviceAuthorizationService interface {
+	CreateDeviceCode(ctx context.Context, device *model.DeviceInfo) (*model.DeviceCodeResponse, error)
+	Approve(ctx context.Context, userID string, req *model.DeviceApproveRequest) (*model.DeviceApproveResponse, error)
+	PollToken(ctx context.Context, req *model.DeviceTokenRequest) (*model.OAuthTokenResponse, error)
+}
+
+// DeviceAuthorizationServiceImpl implements the device authorization grant (RFC 8628) for first-party
+// tools without a browser. An approved device gets the same access and refresh tokens as a password login.
+type DeviceAuthorizationServiceImpl struct {
+	Conf                          *config.Config                           `inject:"config"`
+	DeviceAuthorizationRepository repository.DeviceAuthorizationRepository `inject:"deviceAuthorizationRepository"`
+	TokenService                  TokenService                             `inject:"tokenService"`
+	Validator                     validator.Validator                      `inject:"validator"`
+}
+
+// CreateDeviceCode starts a device login. The device shows the user code and polls with the device code.
+func (s *DeviceAuthorizationServiceImpl) CreateDeviceCode(
+	ctx context.Context, device *model.DeviceInfo,
+) (*model.DeviceCodeResponse, error) {
+	now := time.Now().UTC()
+	if _, err := s.DeviceAuthorizationRepository.DeleteExpired(ctx, now); err != nil {
+		return nil, err
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		golog.Error("Error generating user code", err)
+		return nil, myerrors.ErrSaveDeviceAuthorizationFailed
+	}
+	deviceCode := crypto.RandomString(deviceCodeSize)
+
+	expire := s.deviceCodeExpire()
+	interval := s.pollInterval()
+
+	_, err = s.DeviceAuthorizationRepository.Create(ctx, &domain.DeviceAuthorization{
+		DeviceCodeHash: crypto.HashToken(deviceCode, s.Conf.JWT.HashKey()),
+		UserCodeHash:   s.hashUserCode(userCode),
+		DeviceName:     device.DeviceName,
+		UserAgent:      device.UserAgent,
+		IPAddress:      device.IPAddress,
+		Status:         domain.DeviceAuthorizationPending,
+		PollInterval:   interval,
+		ExpiresAt:      now.Add(expire),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.DeviceCodeResponse{
+		DeviceCode:      deviceCode,
+		UserCode:        userCode,
+		VerificationURI: s.Conf.AuthServer.DeviceVerificationURI,
+		ExpiresIn:       int64(expire.Seconds()),
+		Interval:        interval,
+	}
+
+	if verificationURI, err := url.Parse(resp.VerificationURI); err == nil && resp.VerificationURI != "" {
+		query := verificationURI.Query()
+		query.Set("user_code", userCode)
+		verificationURI.RawQuery = query.Encode()
+		resp.VerificationURIComplete = verificationURI.String()
+	}
+
+	return resp, nil
+}
+
+// Approve records the answer of the signed-in user to the code shown by a device.
+func (s *DeviceAuthorizationServiceImpl) Approve(
+	ctx context.Context, userID string, req *model.DeviceApproveRequest,
+) (*model.DeviceApproveResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	status := domain.DeviceAuthorizationApproved
+	if req.Deny {
+		status = domain.DeviceAuthorizationDenied
+	}
+
+	authorization, err := s.DeviceAuthorizationRepository.Decide(
+		ctx, s.hashUserCode(req.UserCode), userID, status, time.Now().UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.DeviceApproveResponse{
+		DeviceName: authorization.DeviceName,
+		Approved:   authorization.Status == domain.DeviceAuthorizationApproved,
+	}, nil
+}
+
+// PollToken answers a poll of the device. Until the user answers it reports authorization_pending, and a
+// device polling faster than its interval is told to slow down and gets a longer interval.
+// The tokens of an approved device are issued once.
+func (s *DeviceAuthorizationServiceImpl) PollToken(
+	ctx context.Context, req *model.DeviceTokenRequest,
+) (*model.OAuthTokenResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating device token request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	if req.GrantType != domain.GrantTypeDeviceCode {
+		return nil, myerrors.ErrUnsupportedGrantType
+	}
+
+	authorization, err := s.DeviceAuthorizationRepository.GetByDeviceCodeHash(
+		ctx, crypto.HashToken(req.DeviceCode, s.Conf.JWT.HashKey()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if !now.Before(authorization.ExpiresAt) {
+		return nil, myerrors.ErrExpiredToken
+	}
+
+	id := authorization.ID.String()
+	interval := time.Duration(authorization.PollInterval) * time.Second
+	if authorization.LastPolledAt != nil && now.Before(authorization.LastPolledAt.Add(interval)) {
+		if err = s.DeviceAuthorizationRepository.RecordPoll(
+			ctx, id, now, authorization.PollInterval+slowDownStep,
+		); err != nil {
+			return nil, err
+		}
+		return nil, myerrors.ErrSlowDown
+	}
+
+	switch authorization.Status {
+	case domain.DeviceAuthorizationApproved:
+	case domain.DeviceAuthorizationDenied:
+		return nil, myerrors.ErrAccessDenied
+	default:
+		if err = s.DeviceAuthorizationRepository.RecordPoll(ctx, id, now, authorization.PollInterval); err != nil {
+			return nil, err
+		}
+		return nil, myerrors.ErrAuthorizationPending
+	}
+
+	if err = s.DeviceAuthorizationRepository.Consume(ctx, id); err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := s.TokenService.GenerateAuthTokens(ctx, authorization.UserID.String(),
+		&model.DeviceInfo{
+			DeviceName: authorization.DeviceName,
+			UserAgent:  authorization.UserAgent,
+			IPAddress:  authorization.IPAddress,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OAuthTokenResponse{
+		AccessToken:  accessToken.Token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.Conf.JWT.Expire.Seconds()),
+		RefreshToken: refreshToken.Token,
+	}, nil
+}
+
+func (s *DeviceAuthorizationServiceImpl) deviceCodeExpire() time.Duration {
+	if s.Conf.AuthServer.DeviceCodeExpire > 0 {
+		return s.Conf.AuthServer.DeviceCodeExpire
+	}
+	return defaultDeviceCodeExpire
+}
+
+// pollInterval returns the poll interval in whole seconds, as it is reported to the device.
+func (s *DeviceAuthorizationServiceImpl) pollInterval() int {
+	interval := s.Conf.AuthServer.DevicePollInterval
+	if interval < time.Second {
+		interval = defaultDevicePollInterval
+	}
+	return int(interval / time.Second)
+}
+
+func (s *DeviceAuthorizationServiceImpl) hashUserCode(userCode string) string {
+	return crypto.HashToken(normalizeUserCode(userCode), s.Conf.JWT.HashKey())
+}
+
+// normalizeUserCode accepts the code as typed: in any case, with or without the dash and spaces.
+func normalizeUserCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// generateUserCode returns a random code formatted as XXXX-XXXX.
+func generateUserCode() (string, error) {
+	var builder strings.Builder
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+
+	for i := range userCodeLength {
+		if i == userCodeLength/2 {
+			builder.WriteByte('-')
+		}
+		n, err := cryptoRand.Int(cryptoRand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+
+	return builder.String(), nil
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/crypto"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type deviceAuthorizationServiceTestSuite struct {
+	suite.Suite
+	mockCtrl      *gomock.Controller
+	mockRepo      *mockRepository.MockDeviceAuthorizationRepository
+	mockTokenSvc  *mocks.MockTokenService
+	mockValidator *mockValidator.MockValidator
+	deviceService *DeviceAuthorizationServiceImpl
+	ctx           context.Context
+	testSecret    string
+}
+
+func TestDeviceAuthorizationService(t *testing.T) {
+	suite.Run(t, new(deviceAuthorizationServiceTestSuite))
+}
+
+func (s *deviceAuthorizationServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockRepo = mockRepository.NewMockDeviceAuthorizationRepository(s.mockCtrl)
+	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.testSecret = "test-secret-key-for-unit-testing"
+	s.deviceService = &DeviceAuthorizationServiceImpl{
+		Conf: &config.Config{
+			JWT: config.JWTConfig{Secret: s.testSecret, Expire: 30 * time.Minute},
+			AuthServer: config.AuthServerConfig{
+				DeviceCodeExpire:      10 * time.Minute,
+				DevicePollInterval:    5 * time.Second,
+				DeviceVerificationURI: "https://app.example.com/device",
+			},
+		},
+		DeviceAuthorizationRepository: s.mockRepo,
+		TokenService:                  s.mockTokenSvc,
+		Validator:                     s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *deviceAuthorizationServiceTestSuite) tokenRequest() *model.DeviceTokenRequest {
+	return &model.DeviceTokenRequest{GrantType: domain.GrantTypeDeviceCode, DeviceCode: "device-code"}
+}
+
+func (s *deviceAuthorizationServiceTestSuite) expectAuthorization(authorization *domain.DeviceAuthorization) {
+	s.mockValidator.EXPECT().Validate(s.ctx, gomock.Any()).Return(nil)
+	s.mockRepo.EXPECT().GetByDeviceCodeHash(s.ctx, crypto.HashToken("device-code", s.testSecret)).
+		Return(authorization, nil)
+}
+
+// ==================== CreateDeviceCode Tests ====================
+
+func (s *deviceAuthorizationServiceTestSuite) TestCreateDeviceCode_Success() {
+	device := &model.DeviceInfo{DeviceName: "deploy-cli", UserAgent: "cli/1.0", IPAddress: "203.0.113.10"}
+	var stored *domain.DeviceAuthorization
+
+	s.mockRepo.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(int64(0), nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, authorization *domain.DeviceAuthorization) (*domain.DeviceAuthorization, error) {
+			stored = authorization
+			return authorization, nil
+		})
+
+	resp, err := s.deviceService.CreateDeviceCode(s.ctx, device)
+
+	s.Require().NoError(err)
+	s.Regexp(regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`), resp.UserCode)
+	s.Equal("https://app.example.com/device", resp.VerificationURI)
+	s.Equal("https://app.example.com/device?user_code="+resp.UserCode, resp.VerificationURIComplete)
+	s.Equal(int64(600), resp.ExpiresIn)
+	s.Equal(5, resp.Interval)
+
+	s.Equal(crypto.HashToken(resp.DeviceCode, s.testSecret), stored.DeviceCodeHash)
+	s.Equal(s.deviceService.hashUserCode(resp.UserCode), stored.UserCodeHash)
+	s.Equal(domain.DeviceAuthorizationPending, stored.Status)
+	s.Equal("deploy-cli", stored.DeviceName)
+	s.WithinDuration(time.Now().Add(10*time.Minute), stored.ExpiresAt, 5*time.Second)
+}
+
+// ==================== Approve Tests ====================
+
+func (s *deviceAuthorizationServiceTestSuite) TestApprove_Success() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	req := &model.DeviceApproveRequest{UserCode: "wdjb mjht"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRepo.EXPECT().
+		Decide(s.ctx, crypto.HashToken("WDJBMJHT", s.testSecret), userID, domain.DeviceAuthorizationApproved, gomock.Any()).
+		Return(&domain.DeviceAuthorization{DeviceName: "deploy-cli", Status: domain.DeviceAuthorizationApproved}, nil)
+
+	resp, err := s.deviceService.Approve(s.ctx, userID, req)
+
+	s.Require().NoError(err)
+	s.Equal("deploy-cli", resp.DeviceName)
+	s.True(resp.Approved)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestApprove_Deny() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	req := &model.DeviceApproveRequest{UserCode: "WDJB-MJHT", Deny: true}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRepo.EXPECT().Decide(s.ctx, gomock.Any(), userID, domain.DeviceAuthorizationDenied, gomock.Any()).
+		Return(&domain.DeviceAuthorization{Status: domain.DeviceAuthorizationDenied}, nil)
+
+	resp, err := s.deviceService.Approve(s.ctx, userID, req)
+
+	s.Require().NoError(err)
+	s.False(resp.Approved)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestApprove_InvalidCode() {
+	req := &model.DeviceApproveRequest{UserCode: "WDJB-MJHT"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRepo.EXPECT().Decide(s.ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, myerrors.ErrInvalidUserCode)
+
+	resp, err := s.deviceService.Approve(s.ctx, uuid.NewString(), req)
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidUserCode, err)
+}
+
+// ==================== PollToken Tests ====================
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_Pending() {
+	authorization := &domain.DeviceAuthorization{
+		ID:           uuid.Must(uuid.NewV7()),
+		Status:       domain.DeviceAuthorizationPending,
+		PollInterval: 5,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+
+	s.expectAuthorization(authorization)
+	s.mockRepo.EXPECT().RecordPoll(s.ctx, authorization.ID.String(), gomock.Any(), 5).Return(nil)
+
+	resp, err := s.deviceService.PollToken(s.ctx, s.tokenRequest())
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrAuthorizationPending, err)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_SlowDown() {
+	lastPolledAt := time.Now().Add(-2 * time.Second)
+	authorization := &domain.DeviceAuthorization{
+		ID:           uuid.Must(uuid.NewV7()),
+		Status:       domain.DeviceAuthorizationPending,
+		PollInterval: 5,
+		LastPolledAt: &lastPolledAt,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+
+	s.expectAuthorization(authorization)
+	s.mockRepo.EXPECT().RecordPoll(s.ctx, authorization.ID.String(), gomock.Any(), 10).Return(nil)
+
+	resp, err := s.deviceService.PollToken(s.ctx, s.tokenRequest())
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrSlowDown, err)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_Expired() {
+	s.expectAuthorization(&domain.DeviceAuthorization{
+		Status:    domain.DeviceAuthorizationApproved,
+		ExpiresAt: time.Now().Add(-time.Second),
+	})
+
+	resp, err := s.deviceService.PollToken(s.ctx, s.tokenRequest())
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrExpiredToken, err)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_Denied() {
+	s.expectAuthorization(&domain.DeviceAuthorization{
+		Status:       domain.DeviceAuthorizationDenied,
+		PollInterval: 5,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	})
+
+	resp, err := s.deviceService.PollToken(s.ctx, s.tokenRequest())
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrAccessDenied, err)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_Approved() {
+	userID := uuid.Must(uuid.NewV7())
+	lastPolledAt := time.Now().Add(-6 * time.Second)
+	authorization := &domain.DeviceAuthorization{
+		ID:           uuid.Must(uuid.NewV7()),
+		DeviceName:   "deploy-cli",
+		UserAgent:    "cli/1.0",
+		IPAddress:    "203.0.113.10",
+		Status:       domain.DeviceAuthorizationApproved,
+		UserID:       &userID,
+		PollInterval: 5,
+		LastPolledAt: &lastPolledAt,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+
+	s.expectAuthorization(authorization)
+	s.mockRepo.EXPECT().Consume(s.ctx, authorization.ID.String()).Return(nil)
+	s.mockTokenSvc.EXPECT().GenerateAuthTokens(s.ctx, userID.String(), &model.DeviceInfo{
+		DeviceName: "deploy-cli",
+		UserAgent:  "cli/1.0",
+		IPAddress:  "203.0.113.10",
+	}).Return(&domain.Token{Token: "access-token"}, &domain.Token{Token: "refresh-token"}, nil)
+
+	resp, err := s.deviceService.PollToken(s.ctx, s.tokenRequest())
+
+	s.Require().NoError(err)
+	s.Equal("access-token", resp.AccessToken)
+	s.Equal("refresh-token", resp.RefreshToken)
+	s.Equal("Bearer", resp.TokenType)
+	s.Equal(int64(1800), resp.ExpiresIn)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_AlreadyConsumed() {
+	userID := uuid.Must(uuid.NewV7())
+	authorization := &domain.DeviceAuthorization{
+		ID:           uuid.Must(uuid.NewV7()),
+		Status:       domain.DeviceAuthorizationApproved,
+		UserID:       &userID,
+		PollInterval: 5,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+
+	s.expectAuthorization(authorization)
+	s.mockRepo.EXPECT().Consume(s.ctx, authorization.ID.String()).Return(myerrors.ErrInvalidGrant)
+
+	resp, err := s.deviceService.PollToken(s.ctx, s.tokenRequest())
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidGrant, err)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_UnsupportedGrantType() {
+	req := &model.DeviceTokenRequest{GrantType: domain.GrantTypeRefreshToken, DeviceCode: "device-code"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	resp, err := s.deviceService.PollToken(s.ctx, req)
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrUnsupportedGrantType, err)
+}
+
+func (s *deviceAuthorizationServiceTestSuite) TestPollToken_ValidationError() {
+	req := &model.DeviceTokenRequest{}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(errors.New("validation error"))
+
+	resp, err := s.deviceService.PollToken(s.ctx, req)
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/validator"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+//go:generate mockgen -source=identity_service.go -destination=mocks/identity_service.go -package=mocks
+type IdentityService interface {
+	Login(ctx context.Context, req *model.OAuthIdentityRequest) (*domain.User, error)
+	Link(ctx context.Context, userID string, req *model.OAuthIdentityRequest) (*domain.UserIdentity, error)
+	GetIdentities(ctx context.Context, userID string) ([]domain.UserIdentity, error)
+	Unlink(ctx context.Context, userID, identityID string) error
+}
+
+type IdentityServiceImpl struct {
+	UserIdentityRepository       repository.UserIdentityRepository       `inject:"userIdentityRepository"`
+	UserRepository               repository.UserRepository               `inject:"userRepository"`
+	WebAuthnCredentialRepository repository.WebAuthnCredentialRepository `inject:"webAuthnCredentialRepository"`
+	Validator                    validator.Validator                     `inject:"validator"`
+}
+
+// Login finds the user linked to the provider subject. An unknown subject is linked to the account
+// with the same email only when the provider has verified that email, otherwise anyone able to
+// register the address at the provider could take the account over.
+func (s *IdentityServiceImpl) Login(ctx context.Context, req *model.OAuthIdentityRequest) (*domain.User, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating oauth identity", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	identity, err := s.UserIdentityRepository.GetByProviderSubject(ctx, req.Provider, req.Subject)
+	if err == nil {
+		return s.UserRepository.GetByID(ctx, identity.UserID.String())
+	}
+	if !errors.Is(err, myerrors.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	user, err := s.UserRepository.GetByEmail(ctx, req.Email)
+	if errors.Is(err, myerrors.ErrUserNotFound) {
+		return s.UserIdentityRepository.CreateWithUser(ctx, &domain.User{
+			Name:          req.Name,
+			Email:         req.Email,
+			VerifiedEmail: req.EmailVerified,
+			Roles:         domain.NewUserRoles(domain.RoleUser),
+		}, newIdentity(req, uuid.Nil))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.EmailVerified {
+		return nil, myerrors.ErrOAuthAccountNotLinked
+	}
+
+	if _, err = s.UserIdentityRepository.Create(ctx, newIdentity(req, user.ID)); err != nil {
+		return nil, err
+	}
+
+	if !user.VerifiedEmail {
+		user.VerifiedEmail = true
+		if err = s.UserRepository.UpdatePassOrVerify(ctx, &domain.User{VerifiedEmail: true}, user.ID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// Link attaches the identity to the user who started the authorization. The provider email does not
+// need to be verified or match, since the user proved access to both accounts.
+func (s *IdentityServiceImpl) Link(
+	ctx context.Context, userID string, req *model.OAuthIdentityRequest,
+) (*domain.UserIdentity, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating oauth identity", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	linkUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	identity, err := s.UserIdentityRepository.GetByProviderSubject(ctx, req.Provider, req.Subject)
+	if err == nil {
+		if identity.UserID != linkUserID {
+			return nil, myerrors.ErrIdentityAlreadyLinked
+		}
+		return identity, nil
+	}
+	if !errors.Is(err, myerrors.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	return s.UserIdentityRepository.Create(ctx, newIdentity(req, linkUserID))
+}
+
+func (s *IdentityServiceImpl) GetIdentities(ctx context.Context, userID string) ([]domain.UserIdentity, error) {
+	return s.UserIdentityRepository.GetByUserID(ctx, userID)
+}
+
+// Unlink removes an identity unless it is the only way left to log in to the account. A password,
+// another identity or a passkey keeps the account reachable. A verified email does not count, since
+// a magic link only proves access to the mailbox and is not a login method the user set up.
+func (s *IdentityServiceImpl) Unlink(ctx context.Context, userID, identityID string) error {
+	user, err := s.UserRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == "" {
+		hasOther, errOther := s.hasOtherLoginMethod(ctx, userID)
+		if errOther != nil {
+			return errOther
+		}
+
+		if !hasOther {
+			return myerrors.ErrLastLoginMethod
+		}
+	}
+
+	return s.UserIdentityRepository.Delete(ctx, userID, identityID)
+}
+
+// hasOtherLoginMethod reports whether a user without a password can still log in after losing one identity
+func (s *IdentityServiceImpl) hasOtherLoginMethod(ctx context.Context, userID string) (bool, error) {
+	identities, err := s.UserIdentityRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(identities) > 1 {
+		return true, nil
+	}
+
+	credentials, err := s.WebAuthnCredentialRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(credentials) > 0, nil
+}
+
+func newIdentity(req *model.OAuthIdentityRequest, userID uuid.UUID) *domain.UserIdentity {
+	return &domain.UserIdentity{
+		UserID:   userID,
+		Provider: req.Provider,
+		Subject:  req.Subject,
+		Email:    req.Email,
+		LinkedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type identityServiceTestSuite struct {
+	suite.Suite
+	mockCtrl         *gomock.Controller
+	mockIdentityRepo *mockRepository.MockUserIdentityRepository
+	mockUserRepo     *mockRepository.MockUserRepository
+	mockWebAuthnRepo *mockRepository.MockWebAuthnCredentialRepository
+	mockValidator    *mockValidator.MockValidator
+	identityService  *IdentityServiceImpl
+	ctx              context.Context
+}
+
+func TestIdentityService(t *testing.T) {
+	suite.Run(t, new(identityServiceTestSuite))
+}
+
+func (s *identityServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockIdentityRepo = mockRepository.NewMockUserIdentityRepository(s.mockCtrl)
+	s.mockUserRepo = mockRepository.NewMockUserRepository(s.mockCtrl)
+	s.mockWebAuthnRepo = mockRepository.NewMockWebAuthnCredentialRepository(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.identityService = &IdentityServiceImpl{
+		UserIdentityRepository:       s.mockIdentityRepo,
+		UserRepository:               s.mockUserRepo,
+		WebAuthnCredentialRepository: s.mockWebAuthnRepo,
+		Validator:                    s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+}
+
+func (s *identityServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *identityServiceTestSuite) identityRequest(emailVerified bool) *model.OAuthIdentityRequest {
+	return &model.OAuthIdentityRequest{
+		Provider:      "google",
+		Subject:       "subject-1",
+		Email:         "oauth@example.com",
+		EmailVerified: emailVerified,
+		Name:          "OAuth User",
+	}
+}
+
+// ==================== Login Tests ====================
+
+func (s *identityServiceTestSuite) TestLogin_LinkedIdentity() {
+	req := s.identityRequest(false)
+	user := &domain.User{ID: uuid.Must(uuid.NewV7()), Email: "other@example.com"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").
+		Return(&domain.UserIdentity{UserID: user.ID}, nil)
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, user.ID.String()).Return(user, nil)
+
+	result, err := s.identityService.Login(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal(user, result)
+}
+
+func (s *identityServiceTestSuite) TestLogin_NewUser() {
+	req := s.identityRequest(true)
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").
+		Return(nil, myerrors.ErrIdentityNotFound)
+	s.mockUserRepo.EXPECT().GetByEmail(s.ctx, "oauth@example.com").Return(nil, myerrors.ErrUserNotFound)
+	s.mockIdentityRepo.EXPECT().CreateWithUser(s.ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, user *domain.User, identity *domain.UserIdentity) (*domain.User, error) {
+			s.Equal("OAuth User", user.Name)
+			s.Empty(user.Password)
+			s.True(user.VerifiedEmail)
+			s.Equal("google", identity.Provider)
+			s.Equal("subject-1", identity.Subject)
+			return user, nil
+		})
+
+	result, err := s.identityService.Login(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal("oauth@example.com", result.Email)
+}
+
+func (s *identityServiceTestSuite) TestLogin_VerifiedEmailLinksExistingUser() {
+	req := s.identityRequest(true)
+	user := &domain.User{ID: uuid.Must(uuid.NewV7()), Email: "oauth@example.com", Password: "hashed"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").
+		Return(nil, myerrors.ErrIdentityNotFound)
+	s.mockUserRepo.EXPECT().GetByEmail(s.ctx, "oauth@example.com").Return(user, nil)
+	s.mockIdentityRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+			s.Equal(user.ID, identity.UserID)
+			return identity, nil
+		})
+	s.mockUserRepo.EXPECT().UpdatePassOrVerify(s.ctx, &domain.User{VerifiedEmail: true}, user.ID.String()).Return(nil)
+
+	result, err := s.identityService.Login(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal(user.ID, result.ID)
+	s.True(result.VerifiedEmail)
+}
+
+func (s *identityServiceTestSuite) TestLogin_UnverifiedEmailDoesNotLink() {
+	req := s.identityRequest(false)
+	user := &domain.User{ID: uuid.Must(uuid.NewV7()), Email: "oauth@example.com", Password: "hashed"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").
+		Return(nil, myerrors.ErrIdentityNotFound)
+	s.mockUserRepo.EXPECT().GetByEmail(s.ctx, "oauth@example.com").Return(user, nil)
+
+	result, err := s.identityService.Login(s.ctx, req)
+
+	s.Nil(result)
+	s.True(errors.Is(err, myerrors.ErrOAuthAccountNotLinked))
+}
+
+func (s *identityServiceTestSuite) TestLogin_ValidationError() {
+	req := &model.OAuthIdentityRequest{Provider: "google"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(errors.New("validation error"))
+
+	result, err := s.identityService.Login(s.ctx, req)
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
+
+func (s *identityServiceTestSuite) TestLogin_GetIdentityError() {
+	req := s.identityRequest(true)
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").
+		Return(nil, myerrors.ErrGetIdentityFailed)
+
+	result, err := s.identityService.Login(s.ctx, req)
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrGetIdentityFailed, err)
+}
+
+// ==================== Link Tests ====================
+
+func (s *identityServiceTestSuite) TestLink_Success() {
+	req := s.identityRequest(false)
+	userID := uuid.Must(uuid.NewV7())
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").
+		Return(nil, myerrors.ErrIdentityNotFound)
+	s.mockIdentityRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+			return identity, nil
+		})
+
+	result, err := s.identityService.Link(s.ctx, userID.String(), req)
+
+	s.Require().NoError(err)
+	s.Equal(userID, result.UserID)
+	s.Equal("subject-1", result.Subject)
+}
+
+func (s *identityServiceTestSuite) TestLink_AlreadyLinkedToSameUser() {
+	req := s.identityRequest(true)
+	userID := uuid.Must(uuid.NewV7())
+	identity := &domain.UserIdentity{ID: uuid.Must(uuid.NewV7()), UserID: userID}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").Return(identity, nil)
+
+	result, err := s.identityService.Link(s.ctx, userID.String(), req)
+
+	s.Require().NoError(err)
+	s.Equal(identity, result)
+}
+
+func (s *identityServiceTestSuite) TestLink_LinkedToOtherUser() {
+	req := s.identityRequest(true)
+	userID := uuid.Must(uuid.NewV7())
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockIdentityRepo.EXPECT().GetByProviderSubject(s.ctx, "google", "subject-1").
+		Return(&domain.UserIdentity{UserID: uuid.Must(uuid.NewV7())}, nil)
+
+	result, err := s.identityService.Link(s.ctx, userID.String(), req)
+
+	s.Nil(result)
+	s.True(errors.Is(err, myerrors.ErrIdentityAlreadyLinked))
+}
+
+func (s *identityServiceTestSuite) TestLink_InvalidUserID() {
+	req := s.identityRequest(true)
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	result, err := s.identityService.Link(s.ctx, "not-a-uuid", req)
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
+
+// ==================== Unlink Tests ====================
+
+func (s *identityServiceTestSuite) TestUnlink_WithPassword() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	identityID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, userID).Return(&domain.User{Password: "hashed"}, nil)
+	s.mockIdentityRepo.EXPECT().Delete(s.ctx, userID, identityID).Return(nil)
+
+	s.NoError(s.identityService.Unlink(s.ctx, userID, identityID))
+}
+
+func (s *identityServiceTestSuite) TestUnlink_OtherIdentityLeft() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	identityID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, userID).Return(&domain.User{}, nil)
+	s.mockIdentityRepo.EXPECT().GetByUserID(s.ctx, userID).
+		Return([]domain.UserIdentity{{Provider: "google"}, {Provider: "github"}}, nil)
+	s.mockIdentityRepo.EXPECT().Delete(s.ctx, userID, identityID).Return(nil)
+
+	s.NoError(s.identityService.Unlink(s.ctx, userID, identityID))
+}
+
+func (s *identityServiceTestSuite) TestUnlink_LastLoginMethod() {
+	userID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, userID).Return(&domain.User{}, nil)
+	s.mockIdentityRepo.EXPECT().GetByUserID(s.ctx, userID).Return([]domain.UserIdentity{{Provider: "google"}}, nil)
+	s.mockWebAuthnRepo.EXPECT().GetByUserID(s.ctx, userID).Return([]domain.WebAuthnCredential{}, nil)
+
+	err := s.identityService.Unlink(s.ctx, userID, uuid.Must(uuid.NewV7()).String())
+
+	s.True(errors.Is(err, myerrors.ErrLastLoginMethod))
+}
+
+func (s *identityServiceTestSuite) TestUnlink_PasskeyLeft() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	identityID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, userID).Return(&domain.User{}, nil)
+	s.mockIdentityRepo.EXPECT().GetByUserID(s.ctx, userID).Return([]domain.UserIdentity{{Provider: "google"}}, nil)
+	s.mockWebAuthnRepo.EXPECT().GetByUserID(s.ctx, userID).Return([]domain.WebAuthnCredential{{Name: "laptop"}}, nil)
+	s.mockIdentityRepo.EXPECT().Delete(s.ctx, userID, identityID).Return(nil)
+
+	s.NoError(s.identityService.Unlink(s.ctx, userID, identityID))
+}
+
+func (s *identityServiceTestSuite) TestUnlink_VerifiedEmailDoesNotCount() {
+	// A magic link only proves access to the mailbox, so it does not keep the last identity
+	userID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, userID).Return(&domain.User{VerifiedEmail: true}, nil)
+	s.mockIdentityRepo.EXPECT().GetByUserID(s.ctx, userID).Return([]domain.UserIdentity{{Provider: "google"}}, nil)
+	s.mockWebAuthnRepo.EXPECT().GetByUserID(s.ctx, userID).Return(nil, nil)
+
+	err := s.identityService.Unlink(s.ctx, userID, uuid.Must(uuid.NewV7()).String())
+
+	s.True(errors.Is(err, myerrors.ErrLastLoginMethod))
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/password"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
+	"app/internal/pkg/validator"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+//go:generate mockgen -source=invitation_service.go -destination=mocks/invitation_service.go -package=mocks
+type InvitationService interface {
+	GetInvitations(ctx context.Context) ([]model.InvitationResponse, error)
+	CreateInvitation(
+		ctx context.Context, subject policy.Subject, req *model.CreateInvitationRequest,
+	) (*model.InvitationResponse, *domain.Token, error)
+	RevokeInvitation(ctx context.Context, id string) error
+	AcceptInvitation(ctx context.Context, req *model.AcceptInvitationRequest) (*model.AcceptInvitationResponse, error)
+}
+
+// InvitationServiceImpl invites email addresses to the organization of the request. The invitation token
+// is sent by email and accepting it creates the account or links the existing one.
+type InvitationServiceImpl struct {
+	Conf                 *config.Config                  `inject:"config"`
+	InvitationRepository repository.InvitationRepository `inject:"invitationRepository"`
+	OrganizationService  OrganizationService             `inject:"organizationService"`
+	UserService          UserService                     `inject:"userService"`
+	TokenService         TokenService                    `inject:"tokenService"`
+	PasswordPolicy       password.Policy                 `inject:"passwordPolicy"`
+	Validator            validator.Validator             `inject:"validator"`
+}
+
+// GetInvitations returns the pending invitations of the current organization, including expired ones.
+func (s *InvitationServiceImpl) GetInvitations(ctx context.Context) ([]model.InvitationResponse, error) {
+	organizationID := tenant.OrganizationID(ctx)
+	if organizationID == "" {
+		return nil, myerrors.ErrNoOrganization
+	}
+
+	invitations, err := s.InvitationRepository.GetPending(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]model.InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		resp = append(resp, *newInvitationResponse(&invitations[i]))
+	}
+
+	return resp, nil
+}
+
+// CreateInvitation invites the address to the current organization and returns the token to email to it.
+// Inviting an address again replaces its pending invitation. The role must be one the subject could grant.
+func (s *InvitationServiceImpl) CreateInvitation(
+	ctx context.Context, subject policy.Subject, req *model.CreateInvitationRequest,
+) (*model.InvitationResponse, *domain.Token, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating create invitation request", err)
+		return nil, nil, myerrors.ErrInvalidRequest
+	}
+
+	organizationID := tenant.OrganizationID(ctx)
+	if organizationID == "" {
+		return nil, nil, myerrors.ErrNoOrganization
+	}
+
+	role := req.Role
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	if err := s.OrganizationService.CheckGrantable(ctx, subject, role); err != nil {
+		return nil, nil, err
+	}
+
+	organization, err := s.OrganizationService.GetOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = s.checkNotMember(ctx, organizationID, req.Email); err != nil {
+		return nil, nil, err
+	}
+
+	invitation := &domain.OrganizationInvitation{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: uuid.MustParse(organizationID),
+		Email:          req.Email,
+		Role:           role,
+	}
+	if inviter, parseErr := uuid.Parse(subject.UserID); parseErr == nil {
+		invitation.InvitedBy = &inviter
+	}
+
+	invitationToken, err := s.TokenService.GenerateInvitationToken(ctx, invitation)
+	if err != nil {
+		return nil, nil, err
+	}
+	invitation.TokenHash = invitationToken.TokenHash
+	invitation.Expires = invitationToken.Expires
+
+	invitation, err = s.InvitationRepository.Create(ctx, invitation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := newInvitationResponse(invitation)
+	resp.OrganizationName = organization.Name
+
+	return resp, invitationToken, nil
+}
+
+// RevokeInvitation deletes a pending invitation of the current organization, so its token stops working.
+func (s *InvitationServiceImpl) RevokeInvitation(ctx context.Context, id string) error {
+	organizationID := tenant.OrganizationID(ctx)
+	if organizationID == "" {
+		return myerrors.ErrNoOrganization
+	}
+
+	return s.InvitationRepository.Delete(ctx, organizationID, id)
+}
+
+// AcceptInvitation makes the invited address a member of the organization. The token proves the address, so
+// a user who already has it is linked as is, and a new account is created with the email verified otherwise.
+// Revoked, replaced and accepted invitations fail like an invalid token.
+func (s *InvitationServiceImpl) AcceptInvitation(
+	ctx context.Context, req *model.AcceptInvitationRequest,
+) (*model.AcceptInvitationResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	invitationID, err := s.TokenService.VerifyInvitationToken(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.InvitationRepository.GetByTokenHash(ctx, crypto.HashToken(req.Token, s.Conf.JWT.HashKey()))
+	if errors.Is(err, myerrors.ErrInvitationNotFound) {
+		return nil, myerrors.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.ID.String() != invitationID || invitation.AcceptedAt != nil {
+		return nil, myerrors.ErrInvalidToken
+	}
+
+	created := false
+	user, err := s.UserService.GetUserByEmail(ctx, invitation.Email)
+	if errors.Is(err, myerrors.ErrUserNotFound) {
+		if user, err = s.newInvitedUser(invitation, req); err != nil {
+			return nil, err
+		}
+		created = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = s.InvitationRepository.Accept(ctx, invitation, user); err != nil {
+		if errors.Is(err, myerrors.ErrInvitationNotFound) {
+			return nil, myerrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	resp := &model.AcceptInvitationResponse{
+		OrganizationID: invitation.OrganizationID.String(),
+		UserID:         user.ID.String(),
+		AccountCreated: created,
+	}
+	if invitation.Organization != nil {
+		resp.OrganizationName = invitation.Organization.Name
+	}
+
+	return resp, nil
+}
+
+func (s *InvitationServiceImpl) checkNotMember(ctx context.Context, organizationID, email string) error {
+	user, err := s.UserService.GetUserByEmail(ctx, email)
+	if errors.Is(err, myerrors.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.OrganizationService.GetMembership(ctx, organizationID, user.ID.String())
+	if errors.Is(err, myerrors.ErrMemberNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return myerrors.ErrAlreadyMember
+}
+
+func (s *InvitationServiceImpl) newInvitedUser(
+	invitation *domain.OrganizationInvitation, req *model.AcceptInvitationRequest,
+) (*domain.User, error) {
+	if req.Name == "" || req.Password == "" {
+		return nil, myerrors.ErrAccountDetailsRequired
+	}
+
+	if err := s.PasswordPolicy.Check(req.Password, req.Name, invitation.Email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := crypto.HashPassword(req.Password)
+	if err != nil {
+		golog.Error("Error hashing password", err)
+		return nil, myerrors.ErrHashPassword
+	}
+
+	now := time.Now().UTC()
+
+	return &domain.User{
+		Name:              req.Name,
+		Email:             invitation.Email,
+		Password:          hashedPassword,
+		PasswordChangedAt: &now,
+		VerifiedEmail:     true,
+		Roles:             domain.NewUserRoles(domain.RoleUser),
+	}, nil
+}
+
+func newInvitationResponse(invitation *domain.OrganizationInvitation) *model.InvitationResponse {
+	resp := &model.InvitationResponse{
+		ID:             invitation.ID.String(),
+		OrganizationID: invitation.OrganizationID.String(),
+		Email:          invitation.Email,
+		Role:           invitation.Role,
+		ExpiresAt:      invitation.Expires,
+		CreatedAt:      invitation.CreatedAt,
+	}
+	if invitation.Organization != nil {
+		resp.OrganizationName = invitation.Organization.Name
+	}
+	if invitation.InvitedBy != nil {
+		resp.InvitedBy = invitation.InvitedBy.String()
+	}
+
+	return resp
+}
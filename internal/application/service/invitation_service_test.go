@@ -0,0 +1,265 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/crypto"
+	mockPassword "app/internal/pkg/password/mocks"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
+	"app/internal/pkg/validator"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type invitationServiceTestSuite struct {
+	suite.Suite
+	mockCtrl       *gomock.Controller
+	mockRepo       *mockRepository.MockInvitationRepository
+	mockOrgSvc     *mocks.MockOrganizationService
+	mockUserSvc    *mocks.MockUserService
+	mockTokenSvc   *mocks.MockTokenService
+	mockPassword   *mockPassword.MockPolicy
+	mockValidator  *mockValidator.MockValidator
+	service        *InvitationServiceImpl
+	organizationID uuid.UUID
+	ctx            context.Context
+	orgAdmin       policy.Subject
+}
+
+func TestInvitationService(t *testing.T) {
+	suite.Run(t, new(invitationServiceTestSuite))
+}
+
+func (s *invitationServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockRepo = mockRepository.NewMockInvitationRepository(s.mockCtrl)
+	s.mockOrgSvc = mocks.NewMockOrganizationService(s.mockCtrl)
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
+	s.mockPassword = mockPassword.NewMockPolicy(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.service = &InvitationServiceImpl{
+		Conf:                 &config.Config{JWT: config.JWTConfig{TokenHashKey: "test-hash-key"}},
+		InvitationRepository: s.mockRepo,
+		OrganizationService:  s.mockOrgSvc,
+		UserService:          s.mockUserSvc,
+		TokenService:         s.mockTokenSvc,
+		PasswordPolicy:       s.mockPassword,
+		Validator:            s.mockValidator,
+	}
+
+	s.organizationID = uuid.Must(uuid.NewV7())
+	s.ctx = tenant.WithOrganization(context.Background(), s.organizationID.String())
+	s.orgAdmin = policy.Subject{
+		UserID:         uuid.Must(uuid.NewV7()).String(),
+		OrganizationID: s.organizationID.String(),
+		Rights:         []string{"getUsers", "manageMembers", "manageUsers"},
+	}
+
+	s.mockValidator.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func (s *invitationServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *invitationServiceTestSuite) pendingInvitation(rawToken string) *domain.OrganizationInvitation {
+	return &domain.OrganizationInvitation{
+		ID:             uuid.Must(uuid.NewV7()),
+		OrganizationID: s.organizationID,
+		Email:          "invitee@example.com",
+		Role:           domain.RoleUser,
+		TokenHash:      crypto.HashToken(rawToken, "test-hash-key"),
+		Expires:        time.Now().Add(time.Hour),
+		Organization:   &domain.Organization{ID: s.organizationID, Name: "Acme Corp"},
+	}
+}
+
+// ==================== CreateInvitation Tests ====================
+
+func (s *invitationServiceTestSuite) TestCreateInvitation_Success() {
+	req := &model.CreateInvitationRequest{Email: "invitee@example.com"}
+	expires := time.Now().Add(time.Hour)
+
+	s.mockOrgSvc.EXPECT().CheckGrantable(s.ctx, s.orgAdmin, domain.RoleUser).Return(nil)
+	s.mockOrgSvc.EXPECT().GetOrganization(s.ctx, s.organizationID.String()).
+		Return(&model.OrganizationResponse{ID: s.organizationID.String(), Name: "Acme Corp"}, nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(nil, myerrors.ErrUserNotFound)
+	s.mockTokenSvc.EXPECT().GenerateInvitationToken(s.ctx, gomock.Any()).
+		Return(&domain.Token{Token: "raw", TokenHash: "hash", Expires: expires}, nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, invitation *domain.OrganizationInvitation) (*domain.OrganizationInvitation, error) {
+			s.Equal(s.organizationID, invitation.OrganizationID)
+			s.Equal(domain.RoleUser, invitation.Role)
+			s.Equal("hash", invitation.TokenHash)
+			s.Equal(expires, invitation.Expires)
+			s.Equal(s.orgAdmin.UserID, invitation.InvitedBy.String())
+			return invitation, nil
+		})
+
+	invitation, invitationToken, err := s.service.CreateInvitation(s.ctx, s.orgAdmin, req)
+
+	s.Require().NoError(err)
+	s.Equal("raw", invitationToken.Token)
+	s.Equal("Acme Corp", invitation.OrganizationName)
+	s.Equal(req.Email, invitation.Email)
+}
+
+func (s *invitationServiceTestSuite) TestCreateInvitation_RoleNotGrantable() {
+	req := &model.CreateInvitationRequest{Email: "invitee@example.com", Role: "admin"}
+
+	s.mockOrgSvc.EXPECT().CheckGrantable(s.ctx, s.orgAdmin, "admin").Return(myerrors.ErrRoleNotGrantable)
+
+	_, _, err := s.service.CreateInvitation(s.ctx, s.orgAdmin, req)
+
+	s.ErrorIs(err, myerrors.ErrRoleNotGrantable)
+}
+
+func (s *invitationServiceTestSuite) TestCreateInvitation_AlreadyMember() {
+	req := &model.CreateInvitationRequest{Email: "member@example.com"}
+	member := &domain.User{ID: uuid.Must(uuid.NewV7()), Email: req.Email}
+
+	s.mockOrgSvc.EXPECT().CheckGrantable(s.ctx, s.orgAdmin, domain.RoleUser).Return(nil)
+	s.mockOrgSvc.EXPECT().GetOrganization(s.ctx, s.organizationID.String()).
+		Return(&model.OrganizationResponse{ID: s.organizationID.String()}, nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, req.Email).Return(member, nil)
+	s.mockOrgSvc.EXPECT().GetMembership(s.ctx, s.organizationID.String(), member.ID.String()).
+		Return(&domain.OrganizationMember{}, nil)
+
+	_, _, err := s.service.CreateInvitation(s.ctx, s.orgAdmin, req)
+
+	s.ErrorIs(err, myerrors.ErrAlreadyMember)
+}
+
+func (s *invitationServiceTestSuite) TestCreateInvitation_NoOrganization() {
+	_, _, err := s.service.CreateInvitation(
+		context.Background(), s.orgAdmin, &model.CreateInvitationRequest{Email: "invitee@example.com"},
+	)
+
+	s.ErrorIs(err, myerrors.ErrNoOrganization)
+}
+
+// ==================== AcceptInvitation Tests ====================
+
+func (s *invitationServiceTestSuite) TestAcceptInvitation_CreatesAccount() {
+	invitation := s.pendingInvitation("raw")
+	req := &model.AcceptInvitationRequest{Token: "raw", Name: "Invitee", Password: "Password1!"}
+
+	s.mockTokenSvc.EXPECT().VerifyInvitationToken(gomock.Any(), "raw").Return(invitation.ID.String(), nil)
+	s.mockRepo.EXPECT().GetByTokenHash(gomock.Any(), invitation.TokenHash).Return(invitation, nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(gomock.Any(), invitation.Email).Return(nil, myerrors.ErrUserNotFound)
+	s.mockPassword.EXPECT().Check(req.Password, req.Name, invitation.Email).Return(nil)
+	s.mockRepo.EXPECT().Accept(gomock.Any(), invitation, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *domain.OrganizationInvitation, user *domain.User) error {
+			s.Equal(invitation.Email, user.Email)
+			s.True(user.VerifiedEmail)
+			s.True(crypto.CheckPasswordHash(req.Password, user.Password))
+			s.Equal([]string{domain.RoleUser}, user.RoleNames())
+			user.ID = uuid.Must(uuid.NewV7())
+			return nil
+		})
+
+	resp, err := s.service.AcceptInvitation(context.Background(), req)
+
+	s.Require().NoError(err)
+	s.True(resp.AccountCreated)
+	s.Equal("Acme Corp", resp.OrganizationName)
+	s.NotEmpty(resp.UserID)
+}
+
+func (s *invitationServiceTestSuite) TestAcceptInvitation_LinksExistingUser() {
+	invitation := s.pendingInvitation("raw")
+	existing := &domain.User{ID: uuid.Must(uuid.NewV7()), Email: invitation.Email}
+
+	s.mockTokenSvc.EXPECT().VerifyInvitationToken(gomock.Any(), "raw").Return(invitation.ID.String(), nil)
+	s.mockRepo.EXPECT().GetByTokenHash(gomock.Any(), invitation.TokenHash).Return(invitation, nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(gomock.Any(), invitation.Email).Return(existing, nil)
+	s.mockRepo.EXPECT().Accept(gomock.Any(), invitation, existing).Return(nil)
+
+	resp, err := s.service.AcceptInvitation(context.Background(), &model.AcceptInvitationRequest{Token: "raw"})
+
+	s.Require().NoError(err)
+	s.False(resp.AccountCreated)
+	s.Equal(existing.ID.String(), resp.UserID)
+}
+
+func (s *invitationServiceTestSuite) TestAcceptInvitation_AccountDetailsRequired() {
+	invitation := s.pendingInvitation("raw")
+
+	s.mockTokenSvc.EXPECT().VerifyInvitationToken(gomock.Any(), "raw").Return(invitation.ID.String(), nil)
+	s.mockRepo.EXPECT().GetByTokenHash(gomock.Any(), invitation.TokenHash).Return(invitation, nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(gomock.Any(), invitation.Email).Return(nil, myerrors.ErrUserNotFound)
+
+	_, err := s.service.AcceptInvitation(context.Background(), &model.AcceptInvitationRequest{Token: "raw"})
+
+	s.ErrorIs(err, myerrors.ErrAccountDetailsRequired)
+}
+
+func (s *invitationServiceTestSuite) TestAcceptInvitation_WeakPassword() {
+	invitation := s.pendingInvitation("raw")
+	req := &model.AcceptInvitationRequest{Token: "raw", Name: "Invitee", Password: "password"}
+	weak := validator.NewErrorMap(map[string]error{"password.upper": errors.New("password must contain an uppercase letter")})
+
+	s.mockTokenSvc.EXPECT().VerifyInvitationToken(gomock.Any(), "raw").Return(invitation.ID.String(), nil)
+	s.mockRepo.EXPECT().GetByTokenHash(gomock.Any(), invitation.TokenHash).Return(invitation, nil)
+	s.mockUserSvc.EXPECT().GetUserByEmail(gomock.Any(), invitation.Email).Return(nil, myerrors.ErrUserNotFound)
+	s.mockPassword.EXPECT().Check(req.Password, req.Name, invitation.Email).Return(weak)
+
+	_, err := s.service.AcceptInvitation(context.Background(), req)
+
+	s.ErrorIs(err, weak)
+}
+
+func (s *invitationServiceTestSuite) TestAcceptInvitation_Revoked() {
+	s.mockTokenSvc.EXPECT().VerifyInvitationToken(gomock.Any(), "raw").Return(uuid.NewString(), nil)
+	s.mockRepo.EXPECT().GetByTokenHash(gomock.Any(), gomock.Any()).Return(nil, myerrors.ErrInvitationNotFound)
+
+	_, err := s.service.AcceptInvitation(context.Background(), &model.AcceptInvitationRequest{Token: "raw"})
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *invitationServiceTestSuite) TestAcceptInvitation_AlreadyAccepted() {
+	invitation := s.pendingInvitation("raw")
+	acceptedAt := time.Now()
+	invitation.AcceptedAt = &acceptedAt
+
+	s.mockTokenSvc.EXPECT().VerifyInvitationToken(gomock.Any(), "raw").Return(invitation.ID.String(), nil)
+	s.mockRepo.EXPECT().GetByTokenHash(gomock.Any(), invitation.TokenHash).Return(invitation, nil)
+
+	_, err := s.service.AcceptInvitation(context.Background(), &model.AcceptInvitationRequest{Token: "raw"})
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *invitationServiceTestSuite) TestAcceptInvitation_InvalidToken() {
+	s.mockTokenSvc.EXPECT().VerifyInvitationToken(gomock.Any(), "raw").Return("", myerrors.ErrInvalidToken)
+
+	_, err := s.service.AcceptInvitation(context.Background(), &model.AcceptInvitationRequest{Token: "raw"})
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+// ==================== RevokeInvitation Tests ====================
+
+func (s *invitationServiceTestSuite) TestRevokeInvitation_ScopedToOrganization() {
+	invitationID := uuid.NewString()
+	s.mockRepo.EXPECT().Delete(s.ctx, s.organizationID.String(), invitationID).Return(myerrors.ErrInvitationNotFound)
+
+	err := s.service.RevokeInvitation(s.ctx, invitationID)
+
+	s.ErrorIs(err, myerrors.ErrInvitationNotFound)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/adapter/email"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/validator"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommynurwantoro/golog"
+)
+
+const (
+	defaultLockoutDuration   = 15 * time.Minute
+	defaultLockoutBackoffMax = time.Minute
+)
+
+//go:generate mockgen -source=lockout_service.go -destination=mocks/lockout_service.go -package=mocks
+type LockoutService interface {
+	CheckLogin(ctx context.Context, userID string) error
+	RecordFailedLogin(ctx context.Context, user *domain.User) error
+	RecordSuccessfulLogin(ctx context.Context, userID string) error
+	GetLockouts(ctx context.Context) ([]model.LockoutResponse, error)
+	GetLockout(ctx context.Context, userID string) (*model.LockoutResponse, error)
+	ClearLockout(ctx context.Context, userID string) error
+	UnlockAccount(ctx context.Context, req *model.UnlockAccountRequest) error
+}
+
+// LockoutServiceImpl sends the unlock email itself, since the account gets locked in the middle of a login
+// that only returns an error to its handler.
+type LockoutServiceImpl struct {
+	Conf              *config.Config               `inject:"config"`
+	LockoutRepository repository.LockoutRepository `inject:"lockoutRepository"`
+	UserService       UserService                  `inject:"userService"`
+	TokenService      TokenService                 `inject:"tokenService"`
+	EmailAdapter      email.EmailAdapter           `inject:"email"`
+	Validator         validator.Validator          `inject:"validator"`
+}
+
+// CheckLogin refuses a password login while the account is locked or the back-off of its last failure runs.
+func (s *LockoutServiceImpl) CheckLogin(ctx context.Context, userID string) error {
+	lockout, err := s.LockoutRepository.GetByUserID(ctx, userID)
+	if errors.Is(err, myerrors.ErrLockoutNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if lockout.Locked(now) {
+		return myerrors.ErrAccountLocked
+	}
+
+	if now.Before(lockout.LastFailedAt.Add(s.backoff(lockout.FailedAttempts))) {
+		return myerrors.ErrLoginThrottled
+	}
+
+	return nil
+}
+
+// RecordFailedLogin counts a wrong password. The failure that reaches lockout.max_attempts locks the account
+// for lockout.duration, emails the user an unlock link and fails with ErrAccountLocked; once the lock
+// expires, each further failure within the window locks it again.
+func (s *LockoutServiceImpl) RecordFailedLogin(ctx context.Context, user *domain.User) error {
+	now := time.Now().UTC()
+
+	var since time.Time
+	if s.Conf.Lockout.Window > 0 {
+		since = now.Add(-s.Conf.Lockout.Window)
+	}
+
+	lockout, err := s.LockoutRepository.RecordFailure(ctx, user.ID.String(), now, since)
+	if err != nil {
+		return err
+	}
+
+	maxAttempts := s.Conf.Lockout.MaxAttempts
+	if maxAttempts <= 0 || lockout.FailedAttempts < maxAttempts {
+		return nil
+	}
+
+	duration := s.Conf.Lockout.Duration
+	if duration <= 0 {
+		duration = defaultLockoutDuration
+	}
+
+	if err = s.LockoutRepository.Lock(ctx, user.ID.String(), now.Add(duration)); err != nil {
+		return err
+	}
+
+	golog.Info("Locked account " + user.ID.String() + " after failed logins")
+
+	// The lock holds even if the email cannot be sent; it expires or an admin clears it
+	unlockToken, err := s.TokenService.GenerateChallengeToken(
+		ctx, user.ID.String(), domain.TokenTypeUnlockAccount, now.Add(s.Conf.JWT.UnlockAccountExpire), nil,
+	)
+	if err != nil {
+		golog.Error("Error generating unlock account token", err)
+		return myerrors.ErrAccountLocked
+	}
+
+	if err = s.EmailAdapter.SendUnlockAccountEmail(user.Email, unlockToken.Token); err != nil {
+		golog.Error("Error sending unlock account email", err)
+	}
+
+	return myerrors.ErrAccountLocked
+}
+
+// RecordSuccessfulLogin forgets the failed logins of the user.
+func (s *LockoutServiceImpl) RecordSuccessfulLogin(ctx context.Context, userID string) error {
+	err := s.LockoutRepository.Delete(ctx, userID)
+	if errors.Is(err, myerrors.ErrLockoutNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// GetLockouts lists the users with failed logins, locked or not.
+func (s *LockoutServiceImpl) GetLockouts(ctx context.Context) ([]model.LockoutResponse, error) {
+	lockouts, err := s.LockoutRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	resp := make([]model.LockoutResponse, 0, len(lockouts))
+	for i := range lockouts {
+		resp = append(resp, *newLockoutResponse(&lockouts[i], now))
+	}
+
+	return resp, nil
+}
+
+func (s *LockoutServiceImpl) GetLockout(ctx context.Context, userID string) (*model.LockoutResponse, error) {
+	// Looking the user up first keeps users of other organizations out of reach
+	user, err := s.UserService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lockout, err := s.LockoutRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	lockout.User = user
+
+	return newLockoutResponse(lockout, time.Now().UTC()), nil
+}
+
+// ClearLockout lifts the lock of a user and forgets the failed logins.
+func (s *LockoutServiceImpl) ClearLockout(ctx context.Context, userID string) error {
+	if _, err := s.UserService.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.LockoutRepository.Delete(ctx, userID)
+}
+
+// UnlockAccount consumes the emailed unlock token and lifts the lock of its user.
+func (s *LockoutServiceImpl) UnlockAccount(ctx context.Context, req *model.UnlockAccountRequest) error {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return err
+	}
+
+	claims, err := s.TokenService.ConsumeChallengeToken(ctx, req.Token, domain.TokenTypeUnlockAccount)
+	if err != nil {
+		return err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return myerrors.ErrInvalidTokenUserID
+	}
+
+	return s.RecordSuccessfulLogin(ctx, userID)
+}
+
+// backoff is how long to wait after the last of failedAttempts failures: lockout.backoff_base after the
+// first, doubling up to lockout.backoff_max.
+func (s *LockoutServiceImpl) backoff(failedAttempts int) time.Duration {
+	delay := s.Conf.Lockout.BackoffBase
+	if delay <= 0 || failedAttempts <= 0 {
+		return 0
+	}
+
+	maxDelay := s.Conf.Lockout.BackoffMax
+	if maxDelay <= 0 {
+		maxDelay = defaultLockoutBackoffMax
+	}
+
+	for i := 1; i < failedAttempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
+
+func newLockoutResponse(lockout *domain.AccountLockout, now time.Time) *model.LockoutResponse {
+	resp := &model.LockoutResponse{
+		UserID:         lockout.UserID.String(),
+		FailedAttempts: lockout.FailedAttempts,
+		LastFailedAt:   lockout.LastFailedAt,
+		Locked:         lockout.Locked(now),
+	}
+	if resp.Locked {
+		resp.LockedUntil = lockout.LockedUntil
+	}
+	if lockout.User != nil {
+		resp.Name = lockout.User.Name
+		resp.Email = lockout.User.Email
+	}
+
+	return resp
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	mockEmail "app/internal/adapter/email/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type lockoutServiceTestSuite struct {
+	suite.Suite
+	mockCtrl      *gomock.Controller
+	mockRepo      *mockRepository.MockLockoutRepository
+	mockUserSvc   *mocks.MockUserService
+	mockTokenSvc  *mocks.MockTokenService
+	mockEmail     *mockEmail.MockEmailAdapter
+	mockValidator *mockValidator.MockValidator
+	service       *LockoutServiceImpl
+	ctx           context.Context
+	user          *domain.User
+}
+
+func TestLockoutService(t *testing.T) {
+	suite.Run(t, new(lockoutServiceTestSuite))
+}
+
+func (s *lockoutServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockRepo = mockRepository.NewMockLockoutRepository(s.mockCtrl)
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
+	s.mockEmail = mockEmail.NewMockEmailAdapter(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.service = &LockoutServiceImpl{
+		Conf: &config.Config{
+			JWT: config.JWTConfig{UnlockAccountExpire: time.Hour},
+			Lockout: config.LockoutConfig{
+				MaxAttempts: 3,
+				Duration:    15 * time.Minute,
+				Window:      time.Hour,
+				BackoffBase: time.Second,
+				BackoffMax:  30 * time.Second,
+			},
+		},
+		LockoutRepository: s.mockRepo,
+		UserService:       s.mockUserSvc,
+		TokenService:      s.mockTokenSvc,
+		EmailAdapter:      s.mockEmail,
+		Validator:         s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+	s.user = &domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Test User", Email: "test@example.com"}
+
+	s.mockValidator.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func (s *lockoutServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *lockoutServiceTestSuite) lockout(failedAttempts int, lastFailedAt time.Time) *domain.AccountLockout {
+	return &domain.AccountLockout{UserID: s.user.ID, FailedAttempts: failedAttempts, LastFailedAt: lastFailedAt}
+}
+
+// ==================== CheckLogin Tests ====================
+
+func (s *lockoutServiceTestSuite) TestCheckLogin_NoFailures() {
+	s.mockRepo.EXPECT().GetByUserID(s.ctx, s.user.ID.String()).Return(nil, myerrors.ErrLockoutNotFound)
+
+	s.NoError(s.service.CheckLogin(s.ctx, s.user.ID.String()))
+}
+
+func (s *lockoutServiceTestSuite) TestCheckLogin_Locked() {
+	lockout := s.lockout(3, time.Now().Add(-time.Minute))
+	until := time.Now().Add(time.Minute)
+	lockout.LockedUntil = &until
+
+	s.mockRepo.EXPECT().GetByUserID(s.ctx, s.user.ID.String()).Return(lockout, nil)
+
+	s.ErrorIs(s.service.CheckLogin(s.ctx, s.user.ID.String()), myerrors.ErrAccountLocked)
+}
+
+func (s *lockoutServiceTestSuite) TestCheckLogin_Throttled() {
+	// The second failure waits two seconds
+	s.mockRepo.EXPECT().GetByUserID(s.ctx, s.user.ID.String()).
+		Return(s.lockout(2, time.Now().Add(-time.Second)), nil)
+
+	s.ErrorIs(s.service.CheckLogin(s.ctx, s.user.ID.String()), myerrors.ErrLoginThrottled)
+}
+
+func (s *lockoutServiceTestSuite) TestCheckLogin_LockExpired() {
+	expired := time.Now().Add(-time.Second)
+	lockout := s.lockout(3, time.Now().Add(-time.Minute))
+	lockout.LockedUntil = &expired
+
+	s.mockRepo.EXPECT().GetByUserID(s.ctx, s.user.ID.String()).Return(lockout, nil)
+
+	s.NoError(s.service.CheckLogin(s.ctx, s.user.ID.String()))
+}
+
+func (s *lockoutServiceTestSuite) TestBackoff() {
+	s.Equal(time.Duration(0), s.service.backoff(0))
+	s.Equal(time.Second, s.service.backoff(1))
+	s.Equal(4*time.Second, s.service.backoff(3))
+	s.Equal(30*time.Second, s.service.backoff(100))
+
+	s.service.Conf.Lockout.BackoffBase = 0
+	s.Equal(time.Duration(0), s.service.backoff(5))
+}
+
+// ==================== RecordFailedLogin Tests ====================
+
+func (s *lockoutServiceTestSuite) TestRecordFailedLogin_BelowLimit() {
+	s.mockRepo.EXPECT().RecordFailure(s.ctx, s.user.ID.String(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, failedAt, since time.Time) (*domain.AccountLockout, error) {
+			s.Equal(time.Hour, failedAt.Sub(since))
+			return s.lockout(2, failedAt), nil
+		})
+
+	s.NoError(s.service.RecordFailedLogin(s.ctx, s.user))
+}
+
+func (s *lockoutServiceTestSuite) TestRecordFailedLogin_Locks() {
+	s.mockRepo.EXPECT().RecordFailure(s.ctx, s.user.ID.String(), gomock.Any(), gomock.Any()).
+		Return(s.lockout(3, time.Now()), nil)
+	s.mockRepo.EXPECT().Lock(s.ctx, s.user.ID.String(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, until time.Time) error {
+			s.WithinDuration(time.Now().Add(15*time.Minute), until, time.Second)
+			return nil
+		})
+	s.mockTokenSvc.EXPECT().
+		GenerateChallengeToken(s.ctx, s.user.ID.String(), domain.TokenTypeUnlockAccount, gomock.Any(), nil).
+		Return(&domain.Token{Token: "unlock-token"}, nil)
+	s.mockEmail.EXPECT().SendUnlockAccountEmail(s.user.Email, "unlock-token").Return(nil)
+
+	s.ErrorIs(s.service.RecordFailedLogin(s.ctx, s.user), myerrors.ErrAccountLocked)
+}
+
+func (s *lockoutServiceTestSuite) TestRecordFailedLogin_LocksWhenEmailFails() {
+	s.mockRepo.EXPECT().RecordFailure(s.ctx, s.user.ID.String(), gomock.Any(), gomock.Any()).
+		Return(s.lockout(3, time.Now()), nil)
+	s.mockRepo.EXPECT().Lock(s.ctx, s.user.ID.String(), gomock.Any()).Return(nil)
+	s.mockTokenSvc.EXPECT().GenerateChallengeToken(s.ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.Token{Token: "unlock-token"}, nil)
+	s.mockEmail.EXPECT().SendUnlockAccountEmail(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+	s.ErrorIs(s.service.RecordFailedLogin(s.ctx, s.user), myerrors.ErrAccountLocked)
+}
+
+func (s *lockoutServiceTestSuite) TestRecordFailedLogin_LockingDisabled() {
+	s.service.Conf.Lockout.MaxAttempts = 0
+
+	s.mockRepo.EXPECT().RecordFailure(s.ctx, s.user.ID.String(), gomock.Any(), gomock.Any()).
+		Return(s.lockout(50, time.Now()), nil)
+
+	s.NoError(s.service.RecordFailedLogin(s.ctx, s.user))
+}
+
+// ==================== RecordSuccessfulLogin Tests ====================
+
+func (s *lockoutServiceTestSuite) TestRecordSuccessfulLogin_NoFailures() {
+	s.mockRepo.EXPECT().Delete(s.ctx, s.user.ID.String()).Return(myerrors.ErrLockoutNotFound)
+
+	s.NoError(s.service.RecordSuccessfulLogin(s.ctx, s.user.ID.String()))
+}
+
+// ==================== Admin Tests ====================
+
+func (s *lockoutServiceTestSuite) TestGetLockout() {
+	until := time.Now().Add(time.Minute)
+	lockout := s.lockout(3, time.Now())
+	lockout.LockedUntil = &until
+
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(s.user, nil)
+	s.mockRepo.EXPECT().GetByUserID(s.ctx, s.user.ID.String()).Return(lockout, nil)
+
+	resp, err := s.service.GetLockout(s.ctx, s.user.ID.String())
+
+	s.Require().NoError(err)
+	s.True(resp.Locked)
+	s.Equal(3, resp.FailedAttempts)
+	s.Equal(s.user.Email, resp.Email)
+}
+
+func (s *lockoutServiceTestSuite) TestClearLockout_UserOutOfReach() {
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(nil, myerrors.ErrUserNotFound)
+
+	s.ErrorIs(s.service.ClearLockout(s.ctx, s.user.ID.String()), myerrors.ErrUserNotFound)
+}
+
+// ==================== UnlockAccount Tests ====================
+
+func (s *lockoutServiceTestSuite) TestUnlockAccount_Success() {
+	req := &model.UnlockAccountRequest{Token: "unlock-token"}
+
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(s.ctx, req.Token, domain.TokenTypeUnlockAccount).
+		Return(jwt.MapClaims{"user_id": s.user.ID.String()}, nil)
+	s.mockRepo.EXPECT().Delete(s.ctx, s.user.ID.String()).Return(nil)
+
+	s.NoError(s.service.UnlockAccount(s.ctx, req))
+}
+
+func (s *lockoutServiceTestSuite) TestUnlockAccount_InvalidToken() {
+	req := &model.UnlockAccountRequest{Token: "used-token"}
+
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(s.ctx, req.Token, domain.TokenTypeUnlockAccount).
+		Return(nil, myerrors.ErrInvalidToken)
+
+	s.ErrorIs(s.service.UnlockAccount(s.ctx, req), myerrors.ErrInvalidToken)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/validator"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+//go:generate mockgen -source=login_history_service.go -destination=mocks/login_history_service.go -package=mocks
+type LoginHistoryService interface {
+	RecordLogin(ctx context.Context, attempt *model.LoginAttempt) (bool, error)
+	GetLoginHistory(
+		ctx context.Context, userID string, req *model.GetLoginHistoryRequest,
+	) ([]model.LoginEventResponse, int64, error)
+}
+
+type LoginHistoryServiceImpl struct {
+	Conf                 *config.Config                  `inject:"config"`
+	LoginEventRepository repository.LoginEventRepository `inject:"loginEventRepository"`
+	UserService          UserService                     `inject:"userService"`
+	Validator            validator.Validator             `inject:"validator"`
+}
+
+// RecordLogin stores the attempt and reports whether it is a successful login from a device the user never
+// logged in from before, refreshed sessions included, so a refresh token used elsewhere is noticed. The first
+// login of a user never counts as a new device.
+func (s *LoginHistoryServiceImpl) RecordLogin(ctx context.Context, attempt *model.LoginAttempt) (bool, error) {
+	event := &domain.LoginEvent{
+		Email:         attempt.Email,
+		Method:        domain.LoginMethod(attempt.Method),
+		Success:       attempt.Success,
+		FailureReason: attempt.FailureReason,
+	}
+
+	if attempt.Device != nil {
+		event.IPAddress = attempt.Device.IPAddress
+		event.UserAgent = attempt.Device.UserAgent
+		event.DeviceFingerprint = crypto.HashToken(
+			attempt.Device.DeviceName+"\n"+attempt.Device.UserAgent, s.Conf.JWT.HashKey(),
+		)
+	}
+
+	userID := attempt.UserID
+	if userID == "" && attempt.Email != "" {
+		// Failed logins only know the email; attempts on unknown emails are kept without a user
+		if user, err := s.UserService.GetUserByEmail(ctx, attempt.Email); err == nil {
+			userID = user.ID.String()
+		}
+	}
+
+	if userID != "" {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return false, myerrors.ErrInvalidTokenUserID
+		}
+		event.UserID = &id
+	}
+
+	newDevice := false
+	if event.UserID != nil && event.Success {
+		var err error
+		if newDevice, err = s.isNewDevice(ctx, userID, event.DeviceFingerprint); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := s.LoginEventRepository.Create(ctx, event); err != nil {
+		return false, err
+	}
+
+	return newDevice, nil
+}
+
+func (s *LoginHistoryServiceImpl) GetLoginHistory(
+	ctx context.Context, userID string, req *model.GetLoginHistoryRequest,
+) ([]model.LoginEventResponse, int64, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating get login history request", err)
+		return nil, 0, myerrors.ErrInvalidRequest
+	}
+
+	// Looking the user up first keeps users of other organizations out of reach
+	if _, err := s.UserService.GetUserByID(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	events, totalResults, err := s.LoginEventRepository.GetByUserID(ctx, userID, req.Limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]model.LoginEventResponse, 0, len(events))
+	for i := range events {
+		resp = append(resp, model.LoginEventResponse{
+			ID:            events[i].ID.String(),
+			Method:        events[i].Method.String(),
+			Success:       events[i].Success,
+			FailureReason: events[i].FailureReason,
+			IPAddress:     events[i].IPAddress,
+			UserAgent:     events[i].UserAgent,
+			CreatedAt:     events[i].CreatedAt,
+		})
+	}
+
+	return resp, totalResults, nil
+}
+
+func (s *LoginHistoryServiceImpl) isNewDevice(ctx context.Context, userID, deviceFingerprint string) (bool, error) {
+	logins, err := s.LoginEventRepository.CountSuccessful(ctx, userID, "")
+	if err != nil || logins == 0 {
+		return false, err
+	}
+
+	fromDevice, err := s.LoginEventRepository.CountSuccessful(ctx, userID, deviceFingerprint)
+	if err != nil {
+		return false, err
+	}
+
+	return fromDevice == 0, nil
+}
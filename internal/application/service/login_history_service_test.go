@@ -0,0 +1,192 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type loginHistoryServiceTestSuite struct {
+	suite.Suite
+	mockCtrl      *gomock.Controller
+	mockRepo      *mockRepository.MockLoginEventRepository
+	mockUserSvc   *mocks.MockUserService
+	mockValidator *mockValidator.MockValidator
+	service       *LoginHistoryServiceImpl
+	ctx           context.Context
+	user          *domain.User
+	device        *model.DeviceInfo
+}
+
+func TestLoginHistoryService(t *testing.T) {
+	suite.Run(t, new(loginHistoryServiceTestSuite))
+}
+
+func (s *loginHistoryServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockRepo = mockRepository.NewMockLoginEventRepository(s.mockCtrl)
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.service = &LoginHistoryServiceImpl{
+		Conf:                 &config.Config{JWT: config.JWTConfig{TokenHashKey: "test-hash-key"}},
+		LoginEventRepository: s.mockRepo,
+		UserService:          s.mockUserSvc,
+		Validator:            s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+	s.user = &domain.User{ID: uuid.Must(uuid.NewV7()), Name: "Test User", Email: "test@example.com"}
+	s.device = &model.DeviceInfo{DeviceName: "laptop", UserAgent: "Mozilla/5.0", IPAddress: "203.0.113.7"}
+
+	s.mockValidator.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func (s *loginHistoryServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *loginHistoryServiceTestSuite) attempt(method domain.LoginMethod, success bool) *model.LoginAttempt {
+	return &model.LoginAttempt{
+		UserID:  s.user.ID.String(),
+		Email:   s.user.Email,
+		Method:  method.String(),
+		Device:  s.device,
+		Success: success,
+	}
+}
+
+// ==================== RecordLogin Tests ====================
+
+func (s *loginHistoryServiceTestSuite) TestRecordLogin_NewDevice() {
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), "").Return(int64(4), nil)
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), gomock.Not("")).Return(int64(0), nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *domain.LoginEvent) (*domain.LoginEvent, error) {
+			s.Equal(s.user.ID, *event.UserID)
+			s.Equal(domain.LoginMethodPassword, event.Method)
+			s.Equal("203.0.113.7", event.IPAddress)
+			s.NotEmpty(event.DeviceFingerprint)
+			return event, nil
+		})
+
+	newDevice, err := s.service.RecordLogin(s.ctx, s.attempt(domain.LoginMethodPassword, true))
+
+	s.Require().NoError(err)
+	s.True(newDevice)
+}
+
+func (s *loginHistoryServiceTestSuite) TestRecordLogin_KnownDevice() {
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), "").Return(int64(4), nil)
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), gomock.Not("")).Return(int64(2), nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).Return(&domain.LoginEvent{}, nil)
+
+	newDevice, err := s.service.RecordLogin(s.ctx, s.attempt(domain.LoginMethodOAuth, true))
+
+	s.Require().NoError(err)
+	s.False(newDevice)
+}
+
+func (s *loginHistoryServiceTestSuite) TestRecordLogin_FirstLogin() {
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), "").Return(int64(0), nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).Return(&domain.LoginEvent{}, nil)
+
+	newDevice, err := s.service.RecordLogin(s.ctx, s.attempt(domain.LoginMethodPassword, true))
+
+	s.Require().NoError(err)
+	s.False(newDevice)
+}
+
+func (s *loginHistoryServiceTestSuite) TestRecordLogin_RefreshFromNewDevice() {
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), "").Return(int64(4), nil)
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), gomock.Not("")).Return(int64(0), nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).Return(&domain.LoginEvent{}, nil)
+
+	newDevice, err := s.service.RecordLogin(s.ctx, s.attempt(domain.LoginMethodRefreshToken, true))
+
+	s.Require().NoError(err)
+	s.True(newDevice)
+}
+
+func (s *loginHistoryServiceTestSuite) TestRecordLogin_FailureLooksUpUser() {
+	attempt := s.attempt(domain.LoginMethodPassword, false)
+	attempt.UserID = ""
+	attempt.FailureReason = "invalid email or password"
+
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, s.user.Email).Return(s.user, nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *domain.LoginEvent) (*domain.LoginEvent, error) {
+			s.Equal(s.user.ID, *event.UserID)
+			s.False(event.Success)
+			s.Equal(attempt.FailureReason, event.FailureReason)
+			return event, nil
+		})
+
+	newDevice, err := s.service.RecordLogin(s.ctx, attempt)
+
+	s.Require().NoError(err)
+	s.False(newDevice)
+}
+
+func (s *loginHistoryServiceTestSuite) TestRecordLogin_UnknownEmail() {
+	attempt := s.attempt(domain.LoginMethodPassword, false)
+	attempt.UserID = ""
+
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, s.user.Email).Return(nil, myerrors.ErrUserNotFound)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *domain.LoginEvent) (*domain.LoginEvent, error) {
+			s.Nil(event.UserID)
+			return event, nil
+		})
+
+	_, err := s.service.RecordLogin(s.ctx, attempt)
+
+	s.NoError(err)
+}
+
+func (s *loginHistoryServiceTestSuite) TestRecordLogin_CreateFails() {
+	s.mockRepo.EXPECT().CountSuccessful(s.ctx, s.user.ID.String(), "").Return(int64(0), nil)
+	s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).Return(nil, myerrors.ErrCreateLoginEventFailed)
+
+	_, err := s.service.RecordLogin(s.ctx, s.attempt(domain.LoginMethodRefreshToken, true))
+
+	s.ErrorIs(err, myerrors.ErrCreateLoginEventFailed)
+}
+
+// ==================== GetLoginHistory Tests ====================
+
+func (s *loginHistoryServiceTestSuite) TestGetLoginHistory_Success() {
+	req := &model.GetLoginHistoryRequest{Page: 2, Limit: 10}
+	events := []domain.LoginEvent{
+		{ID: uuid.Must(uuid.NewV7()), Method: domain.LoginMethodOAuth, Success: true, IPAddress: "203.0.113.7"},
+	}
+
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(s.user, nil)
+	s.mockRepo.EXPECT().GetByUserID(s.ctx, s.user.ID.String(), 10, 10).Return(events, int64(11), nil)
+
+	resp, total, err := s.service.GetLoginHistory(s.ctx, s.user.ID.String(), req)
+
+	s.Require().NoError(err)
+	s.Equal(int64(11), total)
+	s.Require().Len(resp, 1)
+	s.Equal("oauth", resp[0].Method)
+	s.Equal("203.0.113.7", resp[0].IPAddress)
+}
+
+func (s *loginHistoryServiceTestSuite) TestGetLoginHistory_UserOutOfReach() {
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(nil, myerrors.ErrUserNotFound)
+
+	_, _, err := s.service.GetLoginHistory(s.ctx, s.user.ID.String(), &model.GetLoginHistoryRequest{Page: 1, Limit: 10})
+
+	s.ErrorIs(err, myerrors.ErrUserNotFound)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/validator"
+	"context"
+	cryptoRand "crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/tommynurwantoro/golog"
+)
+
+const (
+	totpPeriod           = 30
+	defaultRecoveryCodes = 10
+	// Recovery codes avoid characters that are easy to misread when copied from paper
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 10
+)
+
+//go:generate mockgen -source=mfa_service.go -destination=mocks/mfa_service.go -package=mocks
+type MFAService interface {
+	EnrollTOTP(ctx context.Context, user *domain.User) (string, string, error)
+	ConfirmTOTP(ctx context.Context, user *domain.User, req *model.ConfirmTOTPRequest) ([]string, error)
+	VerifyLogin(ctx context.Context, req *model.VerifyMFARequest) (*domain.User, error)
+}
+
+type MFAServiceImpl struct {
+	Conf           *config.Config           `inject:"config"`
+	MFARepository  repository.MFARepository `inject:"mfaRepository"`
+	UserService    UserService              `inject:"userService"`
+	TokenService   TokenService             `inject:"tokenService"`
+	LockoutService LockoutService           `inject:"lockoutService"`
+	Validate       validator.Validator      `inject:"validator"`
+}
+
+// EnrollTOTP generates a new secret for the user and returns it with its otpauth:// URL.
+// The secret only takes effect once a code generated from it is confirmed.
+func (s *MFAServiceImpl) EnrollTOTP(ctx context.Context, user *domain.User) (string, string, error) {
+	if user.TOTPEnabled {
+		return "", "", myerrors.ErrMFAAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.issuer(),
+		AccountName: user.Email,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		golog.Error("Error generating totp secret", err)
+		return "", "", myerrors.ErrGenerateMFASecretFailed
+	}
+
+	if err = s.MFARepository.SaveTOTPSecret(ctx, user.ID.String(), key.Secret()); err != nil {
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+// ConfirmTOTP enables TOTP once the user proves their authenticator works and returns fresh recovery codes.
+// The codes are only stored hashed, so this is the only time they can be shown.
+func (s *MFAServiceImpl) ConfirmTOTP(
+	ctx context.Context, user *domain.User, req *model.ConfirmTOTPRequest,
+) ([]string, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, myerrors.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, myerrors.ErrMFANotEnrolled
+	}
+
+	step, ok := matchTOTPStep(user.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		return nil, myerrors.ErrInvalidMFACode
+	}
+
+	count := s.Conf.MFA.RecoveryCodes
+	if count <= 0 {
+		count = defaultRecoveryCodes
+	}
+
+	codes := make([]string, 0, count)
+	recoveryCodes := make([]domain.RecoveryCode, 0, count)
+	for range count {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			golog.Error("Error generating recovery code", err)
+			return nil, myerrors.ErrGenerateMFASecretFailed
+		}
+		codes = append(codes, code)
+		recoveryCodes = append(recoveryCodes, domain.RecoveryCode{
+			UserID:   user.ID,
+			CodeHash: s.hashRecoveryCode(code),
+		})
+	}
+
+	if err := s.MFARepository.EnableTOTP(ctx, user.ID.String(), step, recoveryCodes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// VerifyLogin completes a two-step login with a TOTP code or an unused recovery code. The mfa_token works
+// once, and wrong codes count towards the lockout of the account like wrong passwords.
+func (s *MFAServiceImpl) VerifyLogin(ctx context.Context, req *model.VerifyMFARequest) (*domain.User, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.TokenService.ConsumeChallengeToken(ctx, req.MFAToken, domain.TokenTypeMFAPending)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, myerrors.ErrInvalidTokenUserID
+	}
+
+	user, err := s.UserService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, myerrors.ErrMFANotEnrolled
+	}
+
+	if err = s.LockoutService.CheckLogin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	code := normalizeMFACode(req.Code)
+	if isTOTPCode(code) {
+		step, ok := matchTOTPStep(user.TOTPSecret, code, time.Now())
+		if !ok {
+			return nil, s.recordFailedCode(ctx, user)
+		}
+		err = s.MFARepository.UseTOTPStep(ctx, userID, step)
+	} else {
+		err = s.MFARepository.UseRecoveryCode(ctx, userID, s.hashRecoveryCode(code), time.Now().UTC())
+	}
+	if errors.Is(err, myerrors.ErrInvalidMFACode) {
+		return nil, s.recordFailedCode(ctx, user)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.LockoutService.RecordSuccessfulLogin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// recordFailedCode counts a wrong code and returns the error to answer it with, ErrAccountLocked once the
+// account gets locked.
+func (s *MFAServiceImpl) recordFailedCode(ctx context.Context, user *domain.User) error {
+	if err := s.LockoutService.RecordFailedLogin(ctx, user); err != nil {
+		return err
+	}
+
+	return myerrors.ErrInvalidMFACode
+}
+
+func (s *MFAServiceImpl) issuer() string {
+	if s.Conf.MFA.Issuer != "" {
+		return s.Conf.MFA.Issuer
+	}
+	if s.Conf.AppName != "" {
+		return s.Conf.AppName
+	}
+	return "app"
+}
+
+func (s *MFAServiceImpl) hashRecoveryCode(code string) string {
+	return crypto.HashToken(normalizeMFACode(code), s.Conf.JWT.HashKey())
+}
+
+// matchTOTPStep accepts the current code and the ones next to it to absorb clock drift,
+// and returns the time step the code belongs to so it can only be used once.
+func matchTOTPStep(secret, code string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+	for _, skew := range []int64{0, -1, 1} {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(secret, at, opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return at.Unix() / totpPeriod, true
+		}
+	}
+
+	return 0, false
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != int(otp.DigitsSix) {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeMFACode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+// generateRecoveryCode returns a random code formatted as xxxxx-xxxxx.
+func generateRecoveryCode() (string, error) {
+	var builder strings.Builder
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+	for i := range recoveryCodeLength {
+		if i == recoveryCodeLength/2 {
+			builder.WriteByte('-')
+		}
+		n, err := cryptoRand.Int(cryptoRand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+
+	return builder.String(), nil
+}
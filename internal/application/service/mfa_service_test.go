@@ -0,0 +1,356 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/crypto"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type mfaServiceTestSuite struct {
+	suite.Suite
+	mockCtrl      *gomock.Controller
+	mockMFARepo   *mockRepository.MockMFARepository
+	mockUserSvc   *mocks.MockUserService
+	mockTokenSvc  *mocks.MockTokenService
+	mockLockout   *mocks.MockLockoutService
+	mockValidator *mockValidator.MockValidator
+	mfaService    *MFAServiceImpl
+	ctx           context.Context
+	testSecret    string
+}
+
+func TestMFAService(t *testing.T) {
+	suite.Run(t, new(mfaServiceTestSuite))
+}
+
+func (s *mfaServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockMFARepo = mockRepository.NewMockMFARepository(s.mockCtrl)
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
+	s.mockLockout = mocks.NewMockLockoutService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.testSecret = "test-secret-key-for-unit-testing"
+	conf := &config.Config{
+		AppName: "template",
+		JWT: config.JWTConfig{
+			Secret:           s.testSecret,
+			MFAPendingExpire: 5 * time.Minute,
+		},
+		MFA: config.MFAConfig{RecoveryCodes: 3},
+	}
+
+	s.mfaService = &MFAServiceImpl{
+		Conf:           conf,
+		MFARepository:  s.mockMFARepo,
+		UserService:    s.mockUserSvc,
+		TokenService:   s.mockTokenSvc,
+		LockoutService: s.mockLockout,
+		Validate:       s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+}
+
+func (s *mfaServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// Helper to create a user enrolled with a fresh TOTP secret
+func (s *mfaServiceTestSuite) createEnrolledUser(enabled bool) *domain.User {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "template", AccountName: "test@example.com"})
+	s.Require().NoError(err)
+
+	return &domain.User{
+		ID:          uuid.Must(uuid.NewV7()),
+		Email:       "test@example.com",
+		TOTPSecret:  key.Secret(),
+		TOTPEnabled: enabled,
+	}
+}
+
+func (s *mfaServiceTestSuite) currentCode(secret string) string {
+	code, err := totp.GenerateCodeCustom(secret, time.Now(), totp.ValidateOpts{
+		Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1,
+	})
+	s.Require().NoError(err)
+	return code
+}
+
+// Helper to expect the mfa_token of the user to be consumed
+func (s *mfaServiceTestSuite) expectPendingToken(mfaToken string, user *domain.User) {
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, mfaToken, domain.TokenTypeMFAPending).
+		Return(jwt.MapClaims{"user_id": user.ID.String()}, nil)
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, user.ID.String()).Return(user, nil)
+}
+
+// ==================== EnrollTOTP Tests ====================
+
+func (s *mfaServiceTestSuite) TestEnrollTOTP_Success() {
+	user := &domain.User{ID: uuid.Must(uuid.NewV7()), Email: "test@example.com"}
+
+	var savedSecret string
+	s.mockMFARepo.EXPECT().
+		SaveTOTPSecret(s.ctx, user.ID.String(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, secret string) error {
+			savedSecret = secret
+			return nil
+		})
+
+	secret, otpAuthURL, err := s.mfaService.EnrollTOTP(s.ctx, user)
+
+	s.Require().NoError(err)
+	s.Equal(savedSecret, secret)
+
+	parsed, err := url.Parse(otpAuthURL)
+	s.Require().NoError(err)
+	s.Equal("otpauth", parsed.Scheme)
+	s.Equal("totp", parsed.Host)
+	s.Equal(secret, parsed.Query().Get("secret"))
+	s.Equal("template", parsed.Query().Get("issuer"))
+}
+
+func (s *mfaServiceTestSuite) TestEnrollTOTP_AlreadyEnabled() {
+	_, _, err := s.mfaService.EnrollTOTP(s.ctx, s.createEnrolledUser(true))
+
+	s.ErrorIs(err, myerrors.ErrMFAAlreadyEnabled)
+}
+
+// ==================== ConfirmTOTP Tests ====================
+
+func (s *mfaServiceTestSuite) TestConfirmTOTP_Success() {
+	user := s.createEnrolledUser(false)
+	req := &model.ConfirmTOTPRequest{Code: s.currentCode(user.TOTPSecret)}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	var storedCodes []domain.RecoveryCode
+	s.mockMFARepo.EXPECT().
+		EnableTOTP(s.ctx, user.ID.String(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int64, recoveryCodes []domain.RecoveryCode) error {
+			storedCodes = recoveryCodes
+			return nil
+		})
+
+	codes, err := s.mfaService.ConfirmTOTP(s.ctx, user, req)
+
+	s.Require().NoError(err)
+	s.Len(codes, 3)
+	s.Require().Len(storedCodes, 3)
+	for i, code := range codes {
+		s.Regexp(`^[a-z2-9]{5}-[a-z2-9]{5}$`, code)
+		s.Equal(user.ID, storedCodes[i].UserID)
+		s.Equal(crypto.HashToken(strings.ReplaceAll(code, "-", ""), s.testSecret), storedCodes[i].CodeHash)
+	}
+}
+
+func (s *mfaServiceTestSuite) TestConfirmTOTP_InvalidCode() {
+	user := s.createEnrolledUser(false)
+	req := &model.ConfirmTOTPRequest{Code: "000000"}
+	if s.currentCode(user.TOTPSecret) == req.Code {
+		req.Code = "111111"
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	codes, err := s.mfaService.ConfirmTOTP(s.ctx, user, req)
+
+	s.Nil(codes)
+	s.ErrorIs(err, myerrors.ErrInvalidMFACode)
+}
+
+func (s *mfaServiceTestSuite) TestConfirmTOTP_NotEnrolled() {
+	user := &domain.User{ID: uuid.Must(uuid.NewV7())}
+	req := &model.ConfirmTOTPRequest{Code: "123456"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	_, err := s.mfaService.ConfirmTOTP(s.ctx, user, req)
+
+	s.ErrorIs(err, myerrors.ErrMFANotEnrolled)
+}
+
+func (s *mfaServiceTestSuite) TestConfirmTOTP_AlreadyEnabled() {
+	user := s.createEnrolledUser(true)
+	req := &model.ConfirmTOTPRequest{Code: s.currentCode(user.TOTPSecret)}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	_, err := s.mfaService.ConfirmTOTP(s.ctx, user, req)
+
+	s.ErrorIs(err, myerrors.ErrMFAAlreadyEnabled)
+}
+
+// ==================== VerifyLogin Tests ====================
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_TOTPCode() {
+	user := s.createEnrolledUser(true)
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: s.currentCode(user.TOTPSecret)}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.expectPendingToken(req.MFAToken, user)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, user.ID.String()).Return(nil)
+	s.mockMFARepo.EXPECT().
+		UseTOTPStep(s.ctx, user.ID.String(), gomock.Any()).
+		Return(nil)
+	s.mockLockout.EXPECT().RecordSuccessfulLogin(s.ctx, user.ID.String()).Return(nil)
+
+	result, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.NoError(err)
+	s.Equal(user, result)
+}
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_ReplayedTOTPCode() {
+	user := s.createEnrolledUser(true)
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: s.currentCode(user.TOTPSecret)}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.expectPendingToken(req.MFAToken, user)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, user.ID.String()).Return(nil)
+	s.mockMFARepo.EXPECT().
+		UseTOTPStep(s.ctx, user.ID.String(), gomock.Any()).
+		Return(myerrors.ErrInvalidMFACode)
+	s.mockLockout.EXPECT().RecordFailedLogin(s.ctx, user).Return(nil)
+
+	result, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.Nil(result)
+	s.ErrorIs(err, myerrors.ErrInvalidMFACode)
+}
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_RecoveryCode() {
+	user := s.createEnrolledUser(true)
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: " K3V9Q-2M7XP "}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.expectPendingToken(req.MFAToken, user)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, user.ID.String()).Return(nil)
+	s.mockMFARepo.EXPECT().
+		UseRecoveryCode(s.ctx, user.ID.String(), crypto.HashToken("k3v9q2m7xp", s.testSecret), gomock.Any()).
+		Return(nil)
+	s.mockLockout.EXPECT().RecordSuccessfulLogin(s.ctx, user.ID.String()).Return(nil)
+
+	result, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.NoError(err)
+	s.Equal(user, result)
+}
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_ReplayedToken() {
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: "123456"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, req.MFAToken, domain.TokenTypeMFAPending).
+		Return(nil, myerrors.ErrInvalidToken)
+
+	result, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.Nil(result)
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_WrongCodeCounted() {
+	user := s.createEnrolledUser(true)
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: "000000"}
+	if s.currentCode(user.TOTPSecret) == req.Code {
+		req.Code = "111111"
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.expectPendingToken(req.MFAToken, user)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, user.ID.String()).Return(nil)
+	s.mockLockout.EXPECT().RecordFailedLogin(s.ctx, user).Return(nil)
+
+	result, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.Nil(result)
+	s.ErrorIs(err, myerrors.ErrInvalidMFACode)
+}
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_WrongCodeLocksAccount() {
+	user := s.createEnrolledUser(true)
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: "k3v9q-2m7xp"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.expectPendingToken(req.MFAToken, user)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, user.ID.String()).Return(nil)
+	s.mockMFARepo.EXPECT().
+		UseRecoveryCode(s.ctx, user.ID.String(), gomock.Any(), gomock.Any()).
+		Return(myerrors.ErrInvalidMFACode)
+	s.mockLockout.EXPECT().RecordFailedLogin(s.ctx, user).Return(myerrors.ErrAccountLocked)
+
+	result, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.Nil(result)
+	s.ErrorIs(err, myerrors.ErrAccountLocked)
+}
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_Locked() {
+	user := s.createEnrolledUser(true)
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: s.currentCode(user.TOTPSecret)}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.expectPendingToken(req.MFAToken, user)
+	s.mockLockout.EXPECT().CheckLogin(s.ctx, user.ID.String()).Return(myerrors.ErrAccountLocked)
+
+	result, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.Nil(result)
+	s.ErrorIs(err, myerrors.ErrAccountLocked)
+}
+
+func (s *mfaServiceTestSuite) TestVerifyLogin_NotEnabled() {
+	user := s.createEnrolledUser(false)
+	req := &model.VerifyMFARequest{MFAToken: "mfa-pending-token", Code: s.currentCode(user.TOTPSecret)}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.expectPendingToken(req.MFAToken, user)
+
+	_, err := s.mfaService.VerifyLogin(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrMFANotEnrolled)
+}
+
+// ==================== matchTOTPStep Tests ====================
+
+func (s *mfaServiceTestSuite) TestMatchTOTPStep_AcceptsAdjacentSteps() {
+	secret := s.createEnrolledUser(false).TOTPSecret
+	now := time.Unix(1_700_000_010, 0)
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+	for _, offset := range []int64{-1, 0, 1} {
+		at := now.Add(time.Duration(offset*totpPeriod) * time.Second)
+		code, err := totp.GenerateCodeCustom(secret, at, opts)
+		s.Require().NoError(err)
+
+		step, ok := matchTOTPStep(secret, code, now)
+		s.True(ok)
+		s.Equal(at.Unix()/totpPeriod, step)
+	}
+
+	staleCode, err := totp.GenerateCodeCustom(secret, now.Add(-2*totpPeriod*time.Second), opts)
+	s.Require().NoError(err)
+	_, ok := matchTOTPStep(secret, staleCode, now)
+	s.False(ok)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/validator"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+// clientSecretSize is the number of random bytes in a client secret
+const clientSecretSize = 32
+
+// scopeTokenPattern is the scope-token syntax of RFC 6749 section 3.3 without the comma, which separates stored scopes
+var scopeTokenPattern = regexp.MustCompile(`^[\x21\x23-\x2B\x2D-\x5B\x5D-\x7E]+$`)
+
+//go:generate mockgen -source=oauth_client_service.go -destination=mocks/oauth_client_service.go -package=mocks
+type OAuthClientService interface {
+	CreateClient(ctx context.Context, req *model.CreateOAuthClientRequest) (*model.OAuthClientResponse, error)
+	GetClients(ctx context.Context) ([]model.OAuthClientResponse, error)
+	DeleteClient(ctx context.Context, clientID string) error
+	Authenticate(ctx context.Context, credentials *model.OAuthClientCredentials) (*domain.OAuthClient, error)
+}
+
+type OAuthClientServiceImpl struct {
+	Conf                  *config.Config                   `inject:"config"`
+	OAuthClientRepository repository.OAuthClientRepository `inject:"oauthClientRepository"`
+	TokenRepository       repository.TokenRepository       `inject:"tokenRepository"`
+	Revocation            RevocationService                `inject:"revocationService"`
+	Validator             validator.Validator              `inject:"validator"`
+}
+
+// CreateClient registers a client. The secret of a confidential client is returned once and only its hash is stored.
+func (s *OAuthClientServiceImpl) CreateClient(
+	ctx context.Context, req *model.CreateOAuthClientRequest,
+) (*model.OAuthClientResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if err := validateClientRegistration(req); err != nil {
+		return nil, err
+	}
+
+	client := &domain.OAuthClient{
+		Name:         req.Name,
+		RedirectURIs: strings.Join(req.RedirectURIs, ","),
+		GrantTypes:   strings.Join(slices.Compact(slices.Sorted(slices.Values(req.GrantTypes))), ","),
+		Scopes:       strings.Join(req.Scopes, ","),
+	}
+
+	secret := ""
+	if !req.Public {
+		secret = crypto.RandomString(clientSecretSize)
+		client.SecretHash = s.hashSecret(secret)
+	}
+
+	client, err := s.OAuthClientRepository.Create(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := newOAuthClientResponse(client)
+	resp.ClientSecret = secret
+
+	return resp, nil
+}
+
+func (s *OAuthClientServiceImpl) GetClients(ctx context.Context) ([]model.OAuthClientResponse, error) {
+	clients, err := s.OAuthClientRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]model.OAuthClientResponse, 0, len(clients))
+	for i := range clients {
+		resp = append(resp, *newOAuthClientResponse(&clients[i]))
+	}
+
+	return resp, nil
+}
+
+// DeleteClient removes the client together with the sessions and refresh tokens it holds. The sessions are
+// revoked first, so the access tokens already issued to the client stop working too.
+func (s *OAuthClientServiceImpl) DeleteClient(ctx context.Context, clientID string) error {
+	sessions, err := s.TokenRepository.GetSessionsByClientID(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err = s.Revocation.RevokeSession(ctx, session.ID.String()); err != nil {
+			return err
+		}
+	}
+
+	return s.OAuthClientRepository.Delete(ctx, clientID)
+}
+
+// Authenticate identifies the client calling the token, introspection or revocation endpoint.
+// Confidential clients must present their secret; public clients must not have one to present.
+func (s *OAuthClientServiceImpl) Authenticate(
+	ctx context.Context, credentials *model.OAuthClientCredentials,
+) (*domain.OAuthClient, error) {
+	if _, err := uuid.Parse(credentials.ClientID); err != nil {
+		return nil, myerrors.ErrInvalidClient
+	}
+
+	client, err := s.OAuthClientRepository.GetByID(ctx, credentials.ClientID)
+	if err != nil {
+		if errors.Is(err, myerrors.ErrOAuthClientNotFound) {
+			return nil, myerrors.ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if !client.Confidential() {
+		if credentials.ClientSecret != "" {
+			return nil, myerrors.ErrInvalidClient
+		}
+		return client, nil
+	}
+
+	secretHash := s.hashSecret(credentials.ClientSecret)
+	if credentials.ClientSecret == "" || subtle.ConstantTimeCompare([]byte(secretHash), []byte(client.SecretHash)) != 1 {
+		golog.Info("Invalid secret for oauth client " + client.ID.String())
+		return nil, myerrors.ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+func (s *OAuthClientServiceImpl) hashSecret(secret string) string {
+	return crypto.HashToken(secret, s.Conf.JWT.HashKey())
+}
+
+// validateClientRegistration rejects combinations the grants cannot work with.
+func validateClientRegistration(req *model.CreateOAuthClientRequest) error {
+	hasCodeGrant := slices.Contains(req.GrantTypes, domain.GrantTypeAuthorizationCode)
+
+	if hasCodeGrant && len(req.RedirectURIs) == 0 {
+		return myerrors.ErrInvalidRedirectURI
+	}
+
+	// Refresh tokens are only issued together with a user grant
+	if slices.Contains(req.GrantTypes, domain.GrantTypeRefreshToken) && !hasCodeGrant {
+		return myerrors.ErrInvalidRequest
+	}
+
+	// A public client cannot keep a secret, so it cannot act on its own behalf
+	if slices.Contains(req.GrantTypes, domain.GrantTypeClientCredentials) && req.Public {
+		return myerrors.ErrInvalidRequest
+	}
+
+	for _, scope := range req.Scopes {
+		if !scopeTokenPattern.MatchString(scope) {
+			return myerrors.ErrInvalidScope
+		}
+	}
+
+	return nil
+}
+
+func newOAuthClientResponse(client *domain.OAuthClient) *model.OAuthClientResponse {
+	return &model.OAuthClientResponse{
+		ClientID:     client.ID.String(),
+		Name:         client.Name,
+		Public:       !client.Confidential(),
+		RedirectURIs: client.RedirectURIList(),
+		GrantTypes:   client.GrantTypeList(),
+		Scopes:       client.ScopeList(),
+		CreatedAt:    client.CreatedAt,
+	}
+}
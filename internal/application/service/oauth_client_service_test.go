@@ -0,0 +1,233 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/crypto"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type oauthClientServiceTestSuite struct {
+	suite.Suite
+	mockCtrl           *gomock.Controller
+	mockClientRepo     *mockRepository.MockOAuthClientRepository
+	mockTokenRepo      *mockRepository.MockTokenRepository
+	mockRevocation     *mocks.MockRevocationService
+	mockValidator      *mockValidator.MockValidator
+	oauthClientService *OAuthClientServiceImpl
+	ctx                context.Context
+	testSecret         string
+}
+
+func TestOAuthClientService(t *testing.T) {
+	suite.Run(t, new(oauthClientServiceTestSuite))
+}
+
+func (s *oauthClientServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockClientRepo = mockRepository.NewMockOAuthClientRepository(s.mockCtrl)
+	s.mockTokenRepo = mockRepository.NewMockTokenRepository(s.mockCtrl)
+	s.mockRevocation = mocks.NewMockRevocationService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.testSecret = "test-secret-key-for-unit-testing"
+	s.oauthClientService = &OAuthClientServiceImpl{
+		Conf:                  &config.Config{JWT: config.JWTConfig{Secret: s.testSecret}},
+		OAuthClientRepository: s.mockClientRepo,
+		TokenRepository:       s.mockTokenRepo,
+		Revocation:            s.mockRevocation,
+		Validator:             s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+}
+
+func (s *oauthClientServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// ==================== CreateClient Tests ====================
+
+func (s *oauthClientServiceTestSuite) TestCreateClient_Confidential() {
+	req := &model.CreateOAuthClientRequest{
+		Name:         "Partner App",
+		RedirectURIs: []string{"https://partner.example.com/callback"},
+		GrantTypes:   []string{"refresh_token", "authorization_code", "refresh_token"},
+		Scopes:       []string{"profile", "email"},
+	}
+	var stored *domain.OAuthClient
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockClientRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+			client.ID = uuid.Must(uuid.NewV7())
+			stored = client
+			return client, nil
+		})
+
+	resp, err := s.oauthClientService.CreateClient(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.NotEmpty(resp.ClientSecret)
+	s.False(resp.Public)
+	s.Equal(crypto.HashToken(resp.ClientSecret, s.testSecret), stored.SecretHash)
+	s.Equal("authorization_code,refresh_token", stored.GrantTypes)
+	s.Equal("profile,email", stored.Scopes)
+	s.Equal([]string{"https://partner.example.com/callback"}, resp.RedirectURIs)
+}
+
+func (s *oauthClientServiceTestSuite) TestCreateClient_Public() {
+	req := &model.CreateOAuthClientRequest{
+		Name:         "Mobile App",
+		RedirectURIs: []string{"com.example.app://callback"},
+		GrantTypes:   []string{"authorization_code"},
+		Public:       true,
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockClientRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+			s.Empty(client.SecretHash)
+			return client, nil
+		})
+
+	resp, err := s.oauthClientService.CreateClient(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Empty(resp.ClientSecret)
+	s.True(resp.Public)
+}
+
+func (s *oauthClientServiceTestSuite) TestCreateClient_InvalidRegistration() {
+	cases := map[string]struct {
+		req *model.CreateOAuthClientRequest
+		err error
+	}{
+		"code grant without redirect uri": {
+			req: &model.CreateOAuthClientRequest{GrantTypes: []string{"authorization_code"}},
+			err: myerrors.ErrInvalidRedirectURI,
+		},
+		"refresh grant without code grant": {
+			req: &model.CreateOAuthClientRequest{GrantTypes: []string{"client_credentials", "refresh_token"}},
+			err: myerrors.ErrInvalidRequest,
+		},
+		"public client credentials": {
+			req: &model.CreateOAuthClientRequest{GrantTypes: []string{"client_credentials"}, Public: true},
+			err: myerrors.ErrInvalidRequest,
+		},
+		"malformed scope": {
+			req: &model.CreateOAuthClientRequest{GrantTypes: []string{"client_credentials"}, Scopes: []string{"read write"}},
+			err: myerrors.ErrInvalidScope,
+		},
+	}
+
+	for name, tc := range cases {
+		s.Run(name, func() {
+			s.mockValidator.EXPECT().Validate(s.ctx, tc.req).Return(nil)
+
+			resp, err := s.oauthClientService.CreateClient(s.ctx, tc.req)
+
+			s.Nil(resp)
+			s.Equal(tc.err, err)
+		})
+	}
+}
+
+// ==================== DeleteClient Tests ====================
+
+func (s *oauthClientServiceTestSuite) TestDeleteClient_RevokesSessions() {
+	clientID := uuid.Must(uuid.NewV7()).String()
+	sessions := []domain.Session{{ID: uuid.Must(uuid.NewV7())}, {ID: uuid.Must(uuid.NewV7())}}
+
+	gomock.InOrder(
+		s.mockTokenRepo.EXPECT().GetSessionsByClientID(s.ctx, clientID).Return(sessions, nil),
+		s.mockRevocation.EXPECT().RevokeSession(s.ctx, sessions[0].ID.String()).Return(nil),
+		s.mockRevocation.EXPECT().RevokeSession(s.ctx, sessions[1].ID.String()).Return(nil),
+		s.mockClientRepo.EXPECT().Delete(s.ctx, clientID).Return(nil),
+	)
+
+	err := s.oauthClientService.DeleteClient(s.ctx, clientID)
+
+	s.NoError(err)
+}
+
+func (s *oauthClientServiceTestSuite) TestDeleteClient_RevokeFails() {
+	// The client is kept while its access tokens could not be revoked
+	clientID := uuid.Must(uuid.NewV7()).String()
+	sessions := []domain.Session{{ID: uuid.Must(uuid.NewV7())}}
+
+	s.mockTokenRepo.EXPECT().GetSessionsByClientID(s.ctx, clientID).Return(sessions, nil)
+	s.mockRevocation.EXPECT().RevokeSession(s.ctx, sessions[0].ID.String()).Return(myerrors.ErrRevokeTokenFailed)
+	s.mockClientRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.oauthClientService.DeleteClient(s.ctx, clientID)
+
+	s.ErrorIs(err, myerrors.ErrRevokeTokenFailed)
+}
+
+// ==================== Authenticate Tests ====================
+
+func (s *oauthClientServiceTestSuite) TestAuthenticate_Confidential() {
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7()), SecretHash: crypto.HashToken("secret", s.testSecret)}
+
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, client.ID.String()).Return(client, nil)
+
+	result, err := s.oauthClientService.Authenticate(s.ctx, &model.OAuthClientCredentials{
+		ClientID: client.ID.String(), ClientSecret: "secret",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(client, result)
+}
+
+func (s *oauthClientServiceTestSuite) TestAuthenticate_WrongSecret() {
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7()), SecretHash: crypto.HashToken("secret", s.testSecret)}
+
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, client.ID.String()).Return(client, nil)
+
+	result, err := s.oauthClientService.Authenticate(s.ctx, &model.OAuthClientCredentials{
+		ClientID: client.ID.String(), ClientSecret: "wrong",
+	})
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidClient, err)
+}
+
+func (s *oauthClientServiceTestSuite) TestAuthenticate_Public() {
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7())}
+
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, client.ID.String()).Return(client, nil)
+
+	result, err := s.oauthClientService.Authenticate(s.ctx, &model.OAuthClientCredentials{ClientID: client.ID.String()})
+
+	s.Require().NoError(err)
+	s.Equal(client, result)
+}
+
+func (s *oauthClientServiceTestSuite) TestAuthenticate_UnknownClient() {
+	clientID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, clientID).Return(nil, myerrors.ErrOAuthClientNotFound)
+
+	result, err := s.oauthClientService.Authenticate(s.ctx, &model.OAuthClientCredentials{ClientID: clientID})
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidClient, err)
+}
+
+func (s *oauthClientServiceTestSuite) TestAuthenticate_MalformedClientID() {
+	result, err := s.oauthClientService.Authenticate(s.ctx, &model.OAuthClientCredentials{ClientID: "partner"})
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidClient, err)
+}
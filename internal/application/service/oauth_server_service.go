@@ -0,0 +1,488 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/token"
+	"app/internal/pkg/validator"
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tommynurwantoro/golog"
+)
+
+const (
+	defaultAuthorizationCodeExpire = time.Minute
+
+	tokenTypeHintAccess  = "access_token"
+	tokenTypeHintRefresh = "refresh_token"
+)
+
+//go:generate mockgen -source=oauth_server_service.go -destination=mocks/oauth_server_service.go -package=mocks
+type OAuthServerService interface {
+	ValidateAuthorization(ctx context.Context, req *model.OAuthAuthorizeRequest) (*model.OAuthConsentResponse, error)
+	Authorize(ctx context.Context, userID string, req *model.OAuthAuthorizeRequest) (*model.OAuthAuthorizeResponse, error)
+	Token(
+		ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenRequest, device *model.DeviceInfo,
+	) (*model.OAuthTokenResponse, error)
+	Introspect(
+		ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenHintRequest,
+	) (*model.OAuthIntrospectionResponse, error)
+	Revoke(ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenHintRequest) error
+}
+
+// OAuthServerServiceImpl is the authorization server for registered clients. Every token it hands out is
+// issued by TokenService, so client sessions are listed, rotated and revoked like any other session.
+type OAuthServerServiceImpl struct {
+	Conf                  *config.Config                   `inject:"config"`
+	OAuthClientRepository repository.OAuthClientRepository `inject:"oauthClientRepository"`
+	UserService           UserService                      `inject:"userService"`
+	TokenService          TokenService                     `inject:"tokenService"`
+	Revocation            RevocationService                `inject:"revocationService"`
+	Keys                  token.KeyManager                 `inject:"keyManager"`
+	Validator             validator.Validator              `inject:"validator"`
+}
+
+// ValidateAuthorization checks an authorization request before the user is asked for consent.
+func (s *OAuthServerServiceImpl) ValidateAuthorization(
+	ctx context.Context, req *model.OAuthAuthorizeRequest,
+) (*model.OAuthConsentResponse, error) {
+	client, scope, err := s.authorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OAuthConsentResponse{
+		ClientID:   client.ID.String(),
+		ClientName: client.Name,
+		Scopes:     strings.Fields(scope),
+	}, nil
+}
+
+// Authorize records the consent of the user as a single-use authorization code bound to the client,
+// redirect URI, scope and PKCE code challenge.
+func (s *OAuthServerServiceImpl) Authorize(
+	ctx context.Context, userID string, req *model.OAuthAuthorizeRequest,
+) (*model.OAuthAuthorizeResponse, error) {
+	client, scope, err := s.authorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	expire := s.Conf.AuthServer.CodeExpire
+	if expire <= 0 {
+		expire = defaultAuthorizationCodeExpire
+	}
+
+	code, err := s.TokenService.GenerateChallengeToken(
+		ctx, userID, domain.TokenTypeAuthorizationCode, time.Now().UTC().Add(expire), jwt.MapClaims{
+			"client_id":      client.ID.String(),
+			"redirect_uri":   req.RedirectURI,
+			"scope":          scope,
+			"code_challenge": req.CodeChallenge,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	redirectTo, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		return nil, myerrors.ErrInvalidRedirectURI
+	}
+
+	query := redirectTo.Query()
+	query.Set("code", code.Token)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirectTo.RawQuery = query.Encode()
+
+	return &model.OAuthAuthorizeResponse{RedirectTo: redirectTo.String()}, nil
+}
+
+// Token runs the grant requested at the token endpoint for an authenticated client.
+func (s *OAuthServerServiceImpl) Token(
+	ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenRequest, device *model.DeviceInfo,
+) (*model.OAuthTokenResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating oauth token request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	switch req.GrantType {
+	case domain.GrantTypeAuthorizationCode, domain.GrantTypeRefreshToken, domain.GrantTypeClientCredentials:
+	default:
+		return nil, myerrors.ErrUnsupportedGrantType
+	}
+
+	if !client.HasGrantType(req.GrantType) {
+		return nil, myerrors.ErrUnauthorizedClient
+	}
+
+	switch req.GrantType {
+	case domain.GrantTypeAuthorizationCode:
+		return s.authorizationCodeGrant(ctx, client, req)
+	case domain.GrantTypeRefreshToken:
+		return s.refreshTokenGrant(ctx, client, req, device)
+	default:
+		return s.clientCredentialsGrant(ctx, client, req)
+	}
+}
+
+// Introspect reports whether a token is active (RFC 7662). Only confidential clients, such as resource
+// servers, may introspect, and any failure to validate a token is reported as inactive.
+func (s *OAuthServerServiceImpl) Introspect(
+	ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenHintRequest,
+) (*model.OAuthIntrospectionResponse, error) {
+	if !client.Confidential() {
+		return nil, myerrors.ErrUnauthorizedClient
+	}
+
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating oauth introspection request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	inactive := &model.OAuthIntrospectionResponse{Active: false}
+
+	if claims, tokenType, ok := s.parseAccessToken(req.Token); ok {
+		if s.Revocation.IsRevoked(claims) {
+			return inactive, nil
+		}
+
+		resp := introspectionResponse(claims, tokenTypeHintAccess)
+		if tokenType == domain.TokenTypeClientAccess {
+			resp.Subject = resp.ClientID
+		}
+		return resp, nil
+	}
+
+	refreshToken, claims, err := s.refreshToken(ctx, req.Token)
+	if err != nil {
+		if isInvalidTokenError(err) {
+			return inactive, nil
+		}
+		return nil, err
+	}
+
+	if refreshToken.ConsumedAt != nil {
+		return inactive, nil
+	}
+
+	resp := introspectionResponse(claims, tokenTypeHintRefresh)
+	if refreshToken.Session != nil && refreshToken.Session.ClientID != nil {
+		resp.ClientID = refreshToken.Session.ClientID.String()
+		resp.Scope = refreshToken.Session.Scope
+	}
+
+	return resp, nil
+}
+
+// Revoke invalidates a token issued to the client (RFC 7009). Revoking a refresh token ends its session, which
+// revokes the access tokens issued with it. Unknown tokens and tokens of other clients are ignored.
+func (s *OAuthServerServiceImpl) Revoke(
+	ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenHintRequest,
+) error {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating oauth revocation request", err)
+		return myerrors.ErrInvalidRequest
+	}
+
+	if claims, _, ok := s.parseAccessToken(req.Token); ok {
+		if clientID, _ := claims["client_id"].(string); clientID != client.ID.String() {
+			return nil
+		}
+
+		jti, _ := claims["jti"].(string)
+		expiresAt, err := claims.GetExpirationTime()
+		if jti == "" || err != nil || expiresAt == nil {
+			return nil
+		}
+
+		return s.Revocation.RevokeToken(ctx, jti, expiresAt.Time)
+	}
+
+	refreshToken, _, err := s.refreshToken(ctx, req.Token)
+	if err != nil {
+		if isInvalidTokenError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if refreshToken.Session == nil || refreshToken.Session.ClientID == nil ||
+		*refreshToken.Session.ClientID != client.ID {
+		return nil
+	}
+
+	err = s.TokenService.DeleteSession(ctx, refreshToken.UserID.String(), refreshToken.Session.ID.String())
+	if errors.Is(err, myerrors.ErrSessionNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *OAuthServerServiceImpl) authorization(
+	ctx context.Context, req *model.OAuthAuthorizeRequest,
+) (*domain.OAuthClient, string, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, "", err
+	}
+
+	if req.ResponseType != "code" {
+		return nil, "", myerrors.ErrUnsupportedResponseType
+	}
+
+	client, err := s.OAuthClientRepository.GetByID(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, myerrors.ErrOAuthClientNotFound) {
+			return nil, "", myerrors.ErrInvalidClient
+		}
+		return nil, "", err
+	}
+
+	if !client.HasGrantType(domain.GrantTypeAuthorizationCode) {
+		return nil, "", myerrors.ErrUnauthorizedClient
+	}
+
+	if !client.HasRedirectURI(req.RedirectURI) {
+		return nil, "", myerrors.ErrInvalidRedirectURI
+	}
+
+	scope, err := grantedScope(client, req.Scope)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return client, scope, nil
+}
+
+func (s *OAuthServerServiceImpl) authorizationCodeGrant(
+	ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenRequest,
+) (*model.OAuthTokenResponse, error) {
+	// The code is only consumed for the client and redirect URI it was issued to, so another client
+	// that has seen it cannot burn it
+	claims, err := token.ParseToken(req.Code, s.Keys, domain.TokenTypeAuthorizationCode.String())
+	if err != nil {
+		return nil, myerrors.ErrInvalidGrant
+	}
+
+	clientID, _ := claims["client_id"].(string)
+	redirectURI, _ := claims["redirect_uri"].(string)
+	if clientID != client.ID.String() || redirectURI != req.RedirectURI {
+		return nil, myerrors.ErrInvalidGrant
+	}
+
+	// A wrong verifier burns the code, so it cannot be guessed over several requests
+	claims, err = s.TokenService.ConsumeChallengeToken(ctx, req.Code, domain.TokenTypeAuthorizationCode)
+	if err != nil {
+		if isInvalidTokenError(err) {
+			return nil, myerrors.ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	codeChallenge, _ := claims["code_challenge"].(string)
+	scope, _ := claims["scope"].(string)
+	userID, _ := claims["user_id"].(string)
+
+	if !crypto.VerifyPKCE(req.CodeVerifier, codeChallenge) {
+		return nil, myerrors.ErrInvalidGrant
+	}
+
+	if err = s.activeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := s.TokenService.GenerateClientAuthTokens(ctx, userID, client, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenResponse(accessToken, refreshToken, scope), nil
+}
+
+func (s *OAuthServerServiceImpl) refreshTokenGrant(
+	ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenRequest, device *model.DeviceInfo,
+) (*model.OAuthTokenResponse, error) {
+	refreshToken, err := s.TokenService.GetTokenByRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		if isInvalidTokenError(err) {
+			return nil, myerrors.ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	session := refreshToken.Session
+	if session == nil || session.ClientID == nil || *session.ClientID != client.ID {
+		return nil, myerrors.ErrInvalidGrant
+	}
+
+	// The refreshed tokens keep the scope of the original grant, a request may only repeat or narrow it
+	if req.Scope != "" && !isSubset(strings.Fields(req.Scope), strings.Fields(session.Scope)) {
+		return nil, myerrors.ErrInvalidScope
+	}
+
+	if err = s.activeUser(ctx, refreshToken.UserID.String()); err != nil {
+		return nil, err
+	}
+
+	accessToken, newRefreshToken, err := s.TokenService.RotateRefreshToken(ctx, refreshToken, device)
+	if err != nil {
+		if isInvalidTokenError(err) {
+			return nil, myerrors.ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	return s.tokenResponse(accessToken, newRefreshToken, session.Scope), nil
+}
+
+func (s *OAuthServerServiceImpl) clientCredentialsGrant(
+	ctx context.Context, client *domain.OAuthClient, req *model.OAuthTokenRequest,
+) (*model.OAuthTokenResponse, error) {
+	if !client.Confidential() {
+		return nil, myerrors.ErrUnauthorizedClient
+	}
+
+	scope, err := grantedScope(client, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.TokenService.GenerateClientAccessToken(ctx, client.ID.String(), scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenResponse(accessToken, nil, scope), nil
+}
+
+func (s *OAuthServerServiceImpl) activeUser(ctx context.Context, userID string) error {
+	if _, err := s.UserService.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, myerrors.ErrUserNotFound) {
+			return myerrors.ErrInvalidGrant
+		}
+		return err
+	}
+
+	return nil
+}
+
+// parseAccessToken accepts both user access tokens and client credentials tokens.
+func (s *OAuthServerServiceImpl) parseAccessToken(tokenStr string) (jwt.MapClaims, domain.TokenType, bool) {
+	for _, tokenType := range []domain.TokenType{domain.TokenTypeAccess, domain.TokenTypeClientAccess} {
+		if claims, err := token.ParseToken(tokenStr, s.Keys, tokenType.String()); err == nil {
+			return claims, tokenType, true
+		}
+	}
+
+	return nil, "", false
+}
+
+func (s *OAuthServerServiceImpl) refreshToken(
+	ctx context.Context, tokenStr string,
+) (*domain.Token, jwt.MapClaims, error) {
+	claims, err := token.ParseToken(tokenStr, s.Keys, domain.TokenTypeRefresh.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := s.TokenService.GetTokenByRefreshToken(ctx, tokenStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return refreshToken, claims, nil
+}
+
+func (s *OAuthServerServiceImpl) tokenResponse(
+	accessToken, refreshToken *domain.Token, scope string,
+) *model.OAuthTokenResponse {
+	resp := &model.OAuthTokenResponse{
+		AccessToken: accessToken.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.Conf.JWT.Expire.Seconds()),
+		Scope:       scope,
+	}
+
+	if refreshToken != nil {
+		resp.RefreshToken = refreshToken.Token
+	}
+
+	return resp
+}
+
+// grantedScope resolves the requested scope against the scopes registered for the client.
+// Requesting no scope grants all of them.
+func grantedScope(client *domain.OAuthClient, requested string) (string, error) {
+	allowed := client.ScopeList()
+	if requested == "" {
+		return strings.Join(allowed, " "), nil
+	}
+
+	scopes := strings.Fields(requested)
+	if !isSubset(scopes, allowed) {
+		return "", myerrors.ErrInvalidScope
+	}
+
+	return strings.Join(scopes, " "), nil
+}
+
+func isSubset(values, of []string) bool {
+	for _, value := range values {
+		if !slices.Contains(of, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// isInvalidTokenError tells a token that is invalid, expired or already used apart from a failure to check it.
+func isInvalidTokenError(err error) bool {
+	for _, tokenErr := range []error{
+		myerrors.ErrInvalidToken,
+		myerrors.ErrInvalidTokenClaims,
+		myerrors.ErrInvalidTokenType,
+		myerrors.ErrInvalidTokenUserID,
+		myerrors.ErrTokenNotFound,
+		myerrors.ErrRefreshTokenReused,
+	} {
+		if errors.Is(err, tokenErr) {
+			return true
+		}
+	}
+	return false
+}
+
+func introspectionResponse(claims jwt.MapClaims, tokenType string) *model.OAuthIntrospectionResponse {
+	resp := &model.OAuthIntrospectionResponse{
+		Active:    true,
+		TokenType: tokenType,
+	}
+
+	resp.Scope, _ = claims["scope"].(string)
+	resp.ClientID, _ = claims["client_id"].(string)
+	resp.Subject, _ = claims["user_id"].(string)
+	resp.JTI, _ = claims["jti"].(string)
+
+	if expiresAt, err := claims.GetExpirationTime(); err == nil && expiresAt != nil {
+		resp.ExpiresAt = expiresAt.Unix()
+	}
+	if issuedAt, err := claims.GetIssuedAt(); err == nil && issuedAt != nil {
+		resp.IssuedAt = issuedAt.Unix()
+	}
+
+	return resp
+}
@@ -0,0 +1,565 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	pkgToken "app/internal/pkg/token"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	testRedirectURI   = "https://partner.example.com/callback"
+)
+
+type oauthServerServiceTestSuite struct {
+	suite.Suite
+	mockCtrl           *gomock.Controller
+	mockClientRepo     *mockRepository.MockOAuthClientRepository
+	mockUserSvc        *mocks.MockUserService
+	mockTokenSvc       *mocks.MockTokenService
+	mockRevocation     *mocks.MockRevocationService
+	mockValidator      *mockValidator.MockValidator
+	keys               *pkgToken.KeyManagerImpl
+	oauthServerService *OAuthServerServiceImpl
+	ctx                context.Context
+	client             *domain.OAuthClient
+}
+
+func TestOAuthServerService(t *testing.T) {
+	suite.Run(t, new(oauthServerServiceTestSuite))
+}
+
+func (s *oauthServerServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockClientRepo = mockRepository.NewMockOAuthClientRepository(s.mockCtrl)
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
+	s.mockRevocation = mocks.NewMockRevocationService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	conf := &config.Config{
+		JWT:        config.JWTConfig{Secret: "test-secret-key-for-unit-testing", Expire: 30 * time.Minute},
+		AuthServer: config.AuthServerConfig{CodeExpire: time.Minute},
+	}
+	s.keys = &pkgToken.KeyManagerImpl{Conf: conf}
+	s.Require().NoError(s.keys.Startup())
+
+	s.oauthServerService = &OAuthServerServiceImpl{
+		Conf:                  conf,
+		OAuthClientRepository: s.mockClientRepo,
+		UserService:           s.mockUserSvc,
+		TokenService:          s.mockTokenSvc,
+		Revocation:            s.mockRevocation,
+		Keys:                  s.keys,
+		Validator:             s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+	s.client = &domain.OAuthClient{
+		ID:           uuid.Must(uuid.NewV7()),
+		Name:         "Partner App",
+		SecretHash:   "secret-hash",
+		RedirectURIs: testRedirectURI,
+		GrantTypes:   "authorization_code,client_credentials,refresh_token",
+		Scopes:       "profile,email",
+	}
+}
+
+func (s *oauthServerServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *oauthServerServiceTestSuite) authorizeRequest() *model.OAuthAuthorizeRequest {
+	return &model.OAuthAuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            s.client.ID.String(),
+		RedirectURI:         testRedirectURI,
+		Scope:               "profile",
+		State:               "af0ifjsldkj",
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: "S256",
+	}
+}
+
+func (s *oauthServerServiceTestSuite) signToken(claims jwt.MapClaims) string {
+	claims["jti"] = uuid.NewString()
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+
+	tokenStr, err := s.keys.Sign(claims)
+	s.Require().NoError(err)
+
+	return tokenStr
+}
+
+// ==================== ValidateAuthorization Tests ====================
+
+func (s *oauthServerServiceTestSuite) TestValidateAuthorization_Success() {
+	req := s.authorizeRequest()
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, s.client.ID.String()).Return(s.client, nil)
+
+	consent, err := s.oauthServerService.ValidateAuthorization(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal(s.client.ID.String(), consent.ClientID)
+	s.Equal("Partner App", consent.ClientName)
+	s.Equal([]string{"profile"}, consent.Scopes)
+}
+
+func (s *oauthServerServiceTestSuite) TestValidateAuthorization_UnknownClient() {
+	req := s.authorizeRequest()
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, s.client.ID.String()).Return(nil, myerrors.ErrOAuthClientNotFound)
+
+	consent, err := s.oauthServerService.ValidateAuthorization(s.ctx, req)
+
+	s.Nil(consent)
+	s.Equal(myerrors.ErrInvalidClient, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestValidateAuthorization_UnsupportedResponseType() {
+	req := s.authorizeRequest()
+	req.ResponseType = "token"
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	consent, err := s.oauthServerService.ValidateAuthorization(s.ctx, req)
+
+	s.Nil(consent)
+	s.Equal(myerrors.ErrUnsupportedResponseType, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestValidateAuthorization_RedirectURINotRegistered() {
+	req := s.authorizeRequest()
+	req.RedirectURI = "https://evil.example.com/callback"
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, s.client.ID.String()).Return(s.client, nil)
+
+	consent, err := s.oauthServerService.ValidateAuthorization(s.ctx, req)
+
+	s.Nil(consent)
+	s.Equal(myerrors.ErrInvalidRedirectURI, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestValidateAuthorization_InvalidScope() {
+	req := s.authorizeRequest()
+	req.Scope = "profile admin"
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, s.client.ID.String()).Return(s.client, nil)
+
+	consent, err := s.oauthServerService.ValidateAuthorization(s.ctx, req)
+
+	s.Nil(consent)
+	s.Equal(myerrors.ErrInvalidScope, err)
+}
+
+// ==================== Authorize Tests ====================
+
+func (s *oauthServerServiceTestSuite) TestAuthorize_Success() {
+	req := s.authorizeRequest()
+	userID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockClientRepo.EXPECT().GetByID(s.ctx, s.client.ID.String()).Return(s.client, nil)
+	s.mockTokenSvc.EXPECT().
+		GenerateChallengeToken(s.ctx, userID, domain.TokenTypeAuthorizationCode, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context, _ string, _ domain.TokenType, expiresAt time.Time, claims jwt.MapClaims,
+		) (*domain.Token, error) {
+			s.WithinDuration(time.Now().Add(time.Minute), expiresAt, 5*time.Second)
+			s.Equal(s.client.ID.String(), claims["client_id"])
+			s.Equal(testRedirectURI, claims["redirect_uri"])
+			s.Equal("profile", claims["scope"])
+			s.Equal(testCodeChallenge, claims["code_challenge"])
+			return &domain.Token{Token: "authorization-code"}, nil
+		})
+
+	resp, err := s.oauthServerService.Authorize(s.ctx, userID, req)
+
+	s.Require().NoError(err)
+	redirectTo, err := url.Parse(resp.RedirectTo)
+	s.Require().NoError(err)
+	s.Equal("partner.example.com", redirectTo.Host)
+	s.Equal("authorization-code", redirectTo.Query().Get("code"))
+	s.Equal("af0ifjsldkj", redirectTo.Query().Get("state"))
+}
+
+// ==================== Token Tests ====================
+
+func (s *oauthServerServiceTestSuite) codeClaims(userID string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"token_type":     domain.TokenTypeAuthorizationCode.String(),
+		"user_id":        userID,
+		"client_id":      s.client.ID.String(),
+		"redirect_uri":   testRedirectURI,
+		"scope":          "profile",
+		"code_challenge": testCodeChallenge,
+	}
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_AuthorizationCode_Success() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	code := s.signToken(s.codeClaims(userID))
+	req := &model.OAuthTokenRequest{
+		GrantType:    domain.GrantTypeAuthorizationCode,
+		Code:         code,
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(s.ctx, code, domain.TokenTypeAuthorizationCode).
+		Return(s.codeClaims(userID), nil)
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, userID).Return(&domain.User{}, nil)
+	s.mockTokenSvc.EXPECT().GenerateClientAuthTokens(s.ctx, userID, s.client, "profile").
+		Return(&domain.Token{Token: "access-token"}, &domain.Token{Token: "refresh-token"}, nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Require().NoError(err)
+	s.Equal("access-token", resp.AccessToken)
+	s.Equal("refresh-token", resp.RefreshToken)
+	s.Equal("Bearer", resp.TokenType)
+	s.Equal(int64(1800), resp.ExpiresIn)
+	s.Equal("profile", resp.Scope)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_AuthorizationCode_WrongVerifier() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	code := s.signToken(s.codeClaims(userID))
+	req := &model.OAuthTokenRequest{
+		GrantType:    domain.GrantTypeAuthorizationCode,
+		Code:         code,
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: "wrong-verifier-wrong-verifier-wrong-verifier",
+	}
+
+	// The code is burnt by a wrong verifier of its own client
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(s.ctx, code, domain.TokenTypeAuthorizationCode).
+		Return(s.codeClaims(userID), nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidGrant, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_AuthorizationCode_OtherClientKeepsCode() {
+	otherClient := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7()), GrantTypes: domain.GrantTypeAuthorizationCode}
+	req := &model.OAuthTokenRequest{
+		GrantType:    domain.GrantTypeAuthorizationCode,
+		Code:         s.signToken(s.codeClaims(uuid.NewString())),
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := s.oauthServerService.Token(s.ctx, otherClient, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidGrant, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_AuthorizationCode_WrongRedirectKeepsCode() {
+	req := &model.OAuthTokenRequest{
+		GrantType:    domain.GrantTypeAuthorizationCode,
+		Code:         s.signToken(s.codeClaims(uuid.NewString())),
+		RedirectURI:  "https://attacker.example.com/callback",
+		CodeVerifier: testCodeVerifier,
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidGrant, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_AuthorizationCode_UsedCode() {
+	code := s.signToken(s.codeClaims(uuid.NewString()))
+	req := &model.OAuthTokenRequest{
+		GrantType:   domain.GrantTypeAuthorizationCode,
+		Code:        code,
+		RedirectURI: testRedirectURI,
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(s.ctx, code, domain.TokenTypeAuthorizationCode).
+		Return(nil, myerrors.ErrTokenNotFound)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidGrant, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_UnsupportedGrantType() {
+	req := &model.OAuthTokenRequest{GrantType: "password"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrUnsupportedGrantType, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_GrantNotRegistered() {
+	s.client.GrantTypes = domain.GrantTypeAuthorizationCode
+	req := &model.OAuthTokenRequest{GrantType: domain.GrantTypeClientCredentials}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrUnauthorizedClient, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_RefreshToken_Success() {
+	userID := uuid.Must(uuid.NewV7())
+	device := &model.DeviceInfo{}
+	req := &model.OAuthTokenRequest{GrantType: domain.GrantTypeRefreshToken, RefreshToken: "refresh-token"}
+	refreshToken := &domain.Token{
+		UserID:  userID,
+		Session: &domain.Session{ClientID: &s.client.ID, Scope: "profile"},
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().GetTokenByRefreshToken(s.ctx, "refresh-token").Return(refreshToken, nil)
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, userID.String()).Return(&domain.User{}, nil)
+	s.mockTokenSvc.EXPECT().RotateRefreshToken(s.ctx, refreshToken, device).
+		Return(&domain.Token{Token: "new-access-token"}, &domain.Token{Token: "new-refresh-token"}, nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, device)
+
+	s.Require().NoError(err)
+	s.Equal("new-access-token", resp.AccessToken)
+	s.Equal("new-refresh-token", resp.RefreshToken)
+	s.Equal("profile", resp.Scope)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_RefreshToken_OtherClient() {
+	otherClientID := uuid.Must(uuid.NewV7())
+	req := &model.OAuthTokenRequest{GrantType: domain.GrantTypeRefreshToken, RefreshToken: "refresh-token"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().GetTokenByRefreshToken(s.ctx, "refresh-token").
+		Return(&domain.Token{Session: &domain.Session{ClientID: &otherClientID}}, nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidGrant, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_RefreshToken_WiderScope() {
+	req := &model.OAuthTokenRequest{
+		GrantType: domain.GrantTypeRefreshToken, RefreshToken: "refresh-token", Scope: "profile email",
+	}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().GetTokenByRefreshToken(s.ctx, "refresh-token").
+		Return(&domain.Token{Session: &domain.Session{ClientID: &s.client.ID, Scope: "profile"}}, nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrInvalidScope, err)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_ClientCredentials_Success() {
+	req := &model.OAuthTokenRequest{GrantType: domain.GrantTypeClientCredentials}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().GenerateClientAccessToken(s.ctx, s.client.ID.String(), "profile email").
+		Return(&domain.Token{Token: "client-access-token"}, nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Require().NoError(err)
+	s.Equal("client-access-token", resp.AccessToken)
+	s.Empty(resp.RefreshToken)
+	s.Equal("profile email", resp.Scope)
+}
+
+func (s *oauthServerServiceTestSuite) TestToken_ClientCredentials_PublicClient() {
+	s.client.SecretHash = ""
+	req := &model.OAuthTokenRequest{GrantType: domain.GrantTypeClientCredentials}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	resp, err := s.oauthServerService.Token(s.ctx, s.client, req, &model.DeviceInfo{})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrUnauthorizedClient, err)
+}
+
+// ==================== Introspect Tests ====================
+
+func (s *oauthServerServiceTestSuite) TestIntrospect_AccessToken() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	req := &model.OAuthTokenHintRequest{Token: s.signToken(jwt.MapClaims{
+		"token_type": domain.TokenTypeAccess.String(),
+		"user_id":    userID,
+		"client_id":  s.client.ID.String(),
+		"scope":      "profile",
+	})}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRevocation.EXPECT().IsRevoked(gomock.Any()).Return(false)
+
+	resp, err := s.oauthServerService.Introspect(s.ctx, s.client, req)
+
+	s.Require().NoError(err)
+	s.True(resp.Active)
+	s.Equal(userID, resp.Subject)
+	s.Equal(s.client.ID.String(), resp.ClientID)
+	s.Equal("profile", resp.Scope)
+	s.Equal("access_token", resp.TokenType)
+	s.NotZero(resp.ExpiresAt)
+}
+
+func (s *oauthServerServiceTestSuite) TestIntrospect_ClientAccessToken() {
+	req := &model.OAuthTokenHintRequest{Token: s.signToken(jwt.MapClaims{
+		"token_type": domain.TokenTypeClientAccess.String(),
+		"client_id":  s.client.ID.String(),
+	})}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRevocation.EXPECT().IsRevoked(gomock.Any()).Return(false)
+
+	resp, err := s.oauthServerService.Introspect(s.ctx, s.client, req)
+
+	s.Require().NoError(err)
+	s.True(resp.Active)
+	s.Equal(s.client.ID.String(), resp.Subject)
+}
+
+func (s *oauthServerServiceTestSuite) TestIntrospect_RevokedAccessToken() {
+	req := &model.OAuthTokenHintRequest{Token: s.signToken(jwt.MapClaims{
+		"token_type": domain.TokenTypeAccess.String(),
+		"user_id":    uuid.NewString(),
+	})}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRevocation.EXPECT().IsRevoked(gomock.Any()).Return(true)
+
+	resp, err := s.oauthServerService.Introspect(s.ctx, s.client, req)
+
+	s.Require().NoError(err)
+	s.Equal(&model.OAuthIntrospectionResponse{Active: false}, resp)
+}
+
+func (s *oauthServerServiceTestSuite) TestIntrospect_RefreshToken() {
+	tokenStr := s.signToken(jwt.MapClaims{"token_type": domain.TokenTypeRefresh.String(), "user_id": uuid.NewString()})
+	req := &model.OAuthTokenHintRequest{Token: tokenStr}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().GetTokenByRefreshToken(s.ctx, tokenStr).
+		Return(&domain.Token{Session: &domain.Session{ClientID: &s.client.ID, Scope: "profile"}}, nil)
+
+	resp, err := s.oauthServerService.Introspect(s.ctx, s.client, req)
+
+	s.Require().NoError(err)
+	s.True(resp.Active)
+	s.Equal("refresh_token", resp.TokenType)
+	s.Equal(s.client.ID.String(), resp.ClientID)
+	s.Equal("profile", resp.Scope)
+}
+
+func (s *oauthServerServiceTestSuite) TestIntrospect_InvalidToken() {
+	req := &model.OAuthTokenHintRequest{Token: "not-a-token"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	resp, err := s.oauthServerService.Introspect(s.ctx, s.client, req)
+
+	s.Require().NoError(err)
+	s.False(resp.Active)
+}
+
+func (s *oauthServerServiceTestSuite) TestIntrospect_PublicClient() {
+	s.client.SecretHash = ""
+
+	resp, err := s.oauthServerService.Introspect(s.ctx, s.client, &model.OAuthTokenHintRequest{Token: "token"})
+
+	s.Nil(resp)
+	s.Equal(myerrors.ErrUnauthorizedClient, err)
+}
+
+// ==================== Revoke Tests ====================
+
+func (s *oauthServerServiceTestSuite) TestRevoke_AccessToken() {
+	req := &model.OAuthTokenHintRequest{Token: s.signToken(jwt.MapClaims{
+		"token_type": domain.TokenTypeAccess.String(),
+		"user_id":    uuid.NewString(),
+		"client_id":  s.client.ID.String(),
+	})}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRevocation.EXPECT().RevokeToken(s.ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+	s.NoError(s.oauthServerService.Revoke(s.ctx, s.client, req))
+}
+
+func (s *oauthServerServiceTestSuite) TestRevoke_AccessTokenOfOtherClient() {
+	req := &model.OAuthTokenHintRequest{Token: s.signToken(jwt.MapClaims{
+		"token_type": domain.TokenTypeAccess.String(),
+		"user_id":    uuid.NewString(),
+		"client_id":  uuid.NewString(),
+	})}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	s.NoError(s.oauthServerService.Revoke(s.ctx, s.client, req))
+}
+
+func (s *oauthServerServiceTestSuite) TestRevoke_RefreshToken() {
+	userID := uuid.Must(uuid.NewV7())
+	sessionID := uuid.Must(uuid.NewV7())
+	tokenStr := s.signToken(jwt.MapClaims{"token_type": domain.TokenTypeRefresh.String(), "user_id": userID.String()})
+	req := &model.OAuthTokenHintRequest{Token: tokenStr}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().GetTokenByRefreshToken(s.ctx, tokenStr).Return(&domain.Token{
+		UserID:  userID,
+		Session: &domain.Session{ID: sessionID, ClientID: &s.client.ID},
+	}, nil)
+	s.mockTokenSvc.EXPECT().DeleteSession(s.ctx, userID.String(), sessionID.String()).Return(nil)
+
+	s.NoError(s.oauthServerService.Revoke(s.ctx, s.client, req))
+}
+
+func (s *oauthServerServiceTestSuite) TestRevoke_UnknownToken() {
+	req := &model.OAuthTokenHintRequest{Token: "not-a-token"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	s.NoError(s.oauthServerService.Revoke(s.ctx, s.client, req))
+}
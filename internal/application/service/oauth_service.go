@@ -0,0 +1,115 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/crypto"
+	"app/internal/pkg/validator"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+// oauthSecretSize is the number of random bytes in a state, nonce and PKCE code verifier
+const oauthSecretSize = 32
+
+//go:generate mockgen -source=oauth_service.go -destination=mocks/oauth_service.go -package=mocks
+type OAuthService interface {
+	CreateState(ctx context.Context, req *model.OAuthStateRequest) (*model.OAuthAuthorization, error)
+	ConsumeState(ctx context.Context, provider, state string) (*domain.OAuthState, error)
+	IssueLoginCode(ctx context.Context, userID string) (*domain.Token, error)
+	RedeemLoginCode(ctx context.Context, req *model.OAuthCodeRequest) (*domain.User, error)
+}
+
+type OAuthServiceImpl struct {
+	Conf                 *config.Config                  `inject:"config"`
+	OAuthStateRepository repository.OAuthStateRepository `inject:"oauthStateRepository"`
+	UserRepository       repository.UserRepository       `inject:"userRepository"`
+	TokenService         TokenService                    `inject:"tokenService"`
+	Validator            validator.Validator             `inject:"validator"`
+}
+
+// CreateState starts an authorization. The state, nonce and PKCE code verifier are kept server side
+// until the callback, so the browser only ever holds the state.
+func (s *OAuthServiceImpl) CreateState(
+	ctx context.Context, req *model.OAuthStateRequest,
+) (*model.OAuthAuthorization, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating oauth state request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	if req.RedirectURI != "" && !slices.Contains(s.Conf.OAuth2.RedirectURIs, req.RedirectURI) {
+		return nil, myerrors.ErrInvalidRedirectURI
+	}
+
+	now := time.Now().UTC()
+	if _, err := s.OAuthStateRepository.DeleteExpired(ctx, now); err != nil {
+		return nil, err
+	}
+
+	authorization := &model.OAuthAuthorization{
+		State:        crypto.RandomString(oauthSecretSize),
+		Nonce:        crypto.RandomString(oauthSecretSize),
+		CodeVerifier: crypto.RandomString(oauthSecretSize),
+	}
+
+	state := &domain.OAuthState{
+		StateHash:    crypto.HashToken(authorization.State, s.Conf.JWT.HashKey()),
+		Provider:     req.Provider,
+		Nonce:        authorization.Nonce,
+		CodeVerifier: authorization.CodeVerifier,
+		RedirectURI:  req.RedirectURI,
+		ExpiresAt:    now.Add(s.Conf.OAuth2.StateExpire),
+	}
+	if req.UserID != "" {
+		userID := uuid.MustParse(req.UserID)
+		state.UserID = &userID
+	}
+
+	if _, err := s.OAuthStateRepository.Create(ctx, state); err != nil {
+		return nil, err
+	}
+
+	return authorization, nil
+}
+
+// ConsumeState returns the authorization started with state for provider. Each state works once.
+func (s *OAuthServiceImpl) ConsumeState(ctx context.Context, provider, state string) (*domain.OAuthState, error) {
+	if state == "" {
+		return nil, myerrors.ErrInvalidOAuthState
+	}
+
+	return s.OAuthStateRepository.Consume(ctx, crypto.HashToken(state, s.Conf.JWT.HashKey()), provider, time.Now().UTC())
+}
+
+// IssueLoginCode issues the single-use code handed to the front-end in place of auth tokens.
+func (s *OAuthServiceImpl) IssueLoginCode(ctx context.Context, userID string) (*domain.Token, error) {
+	return s.TokenService.GenerateChallengeToken(ctx, userID, domain.TokenTypeOAuthCode,
+		time.Now().UTC().Add(s.Conf.OAuth2.CodeExpire), nil)
+}
+
+func (s *OAuthServiceImpl) RedeemLoginCode(ctx context.Context, req *model.OAuthCodeRequest) (*domain.User, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating oauth code request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	claims, err := s.TokenService.ConsumeChallengeToken(ctx, req.Code, domain.TokenTypeOAuthCode)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, myerrors.ErrInvalidTokenUserID
+	}
+
+	return s.UserRepository.GetByID(ctx, userID)
+}
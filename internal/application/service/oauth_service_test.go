@@ -0,0 +1,233 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/crypto"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type oauthServiceTestSuite struct {
+	suite.Suite
+	mockCtrl      *gomock.Controller
+	mockStateRepo *mockRepository.MockOAuthStateRepository
+	mockUserRepo  *mockRepository.MockUserRepository
+	mockTokenSvc  *mocks.MockTokenService
+	mockValidator *mockValidator.MockValidator
+	oauthService  *OAuthServiceImpl
+	ctx           context.Context
+	testSecret    string
+}
+
+func TestOAuthService(t *testing.T) {
+	suite.Run(t, new(oauthServiceTestSuite))
+}
+
+func (s *oauthServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockStateRepo = mockRepository.NewMockOAuthStateRepository(s.mockCtrl)
+	s.mockUserRepo = mockRepository.NewMockUserRepository(s.mockCtrl)
+	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.testSecret = "test-secret-key-for-unit-testing"
+	s.oauthService = &OAuthServiceImpl{
+		Conf: &config.Config{
+			JWT: config.JWTConfig{Secret: s.testSecret},
+			OAuth2: config.OAuth2Config{
+				StateExpire:  10 * time.Minute,
+				CodeExpire:   time.Minute,
+				RedirectURIs: []string{"http://localhost:3000/oauth/done"},
+			},
+		},
+		OAuthStateRepository: s.mockStateRepo,
+		UserRepository:       s.mockUserRepo,
+		TokenService:         s.mockTokenSvc,
+		Validator:            s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+}
+
+func (s *oauthServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// ==================== CreateState Tests ====================
+
+func (s *oauthServiceTestSuite) TestCreateState_Success() {
+	req := &model.OAuthStateRequest{Provider: "google", RedirectURI: "http://localhost:3000/oauth/done"}
+	var stored *domain.OAuthState
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockStateRepo.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(int64(0), nil)
+	s.mockStateRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, state *domain.OAuthState) (*domain.OAuthState, error) {
+			stored = state
+			return state, nil
+		})
+
+	authorization, err := s.oauthService.CreateState(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.NotEmpty(authorization.State)
+	s.NotEmpty(authorization.Nonce)
+	s.NotEmpty(authorization.CodeVerifier)
+	s.NotEqual(authorization.State, authorization.Nonce)
+
+	s.Equal(crypto.HashToken(authorization.State, s.testSecret), stored.StateHash)
+	s.Equal("google", stored.Provider)
+	s.Equal(authorization.Nonce, stored.Nonce)
+	s.Equal(authorization.CodeVerifier, stored.CodeVerifier)
+	s.Equal("http://localhost:3000/oauth/done", stored.RedirectURI)
+	s.Nil(stored.UserID)
+	s.WithinDuration(time.Now().Add(10*time.Minute), stored.ExpiresAt, 5*time.Second)
+}
+
+func (s *oauthServiceTestSuite) TestCreateState_WithUserID() {
+	userID := uuid.Must(uuid.NewV7())
+	req := &model.OAuthStateRequest{Provider: "google", UserID: userID.String()}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockStateRepo.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(int64(0), nil)
+	s.mockStateRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, state *domain.OAuthState) (*domain.OAuthState, error) {
+			s.Require().NotNil(state.UserID)
+			s.Equal(userID, *state.UserID)
+			return state, nil
+		})
+
+	_, err := s.oauthService.CreateState(s.ctx, req)
+
+	s.NoError(err)
+}
+
+func (s *oauthServiceTestSuite) TestCreateState_RedirectURINotAllowed() {
+	req := &model.OAuthStateRequest{Provider: "google", RedirectURI: "https://evil.example.com/oauth/done"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	authorization, err := s.oauthService.CreateState(s.ctx, req)
+
+	s.Nil(authorization)
+	s.Equal(myerrors.ErrInvalidRedirectURI, err)
+}
+
+func (s *oauthServiceTestSuite) TestCreateState_ValidationError() {
+	req := &model.OAuthStateRequest{}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(errors.New("validation error"))
+
+	authorization, err := s.oauthService.CreateState(s.ctx, req)
+
+	s.Nil(authorization)
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
+
+func (s *oauthServiceTestSuite) TestCreateState_SaveError() {
+	req := &model.OAuthStateRequest{Provider: "google"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockStateRepo.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(int64(0), nil)
+	s.mockStateRepo.EXPECT().Create(s.ctx, gomock.Any()).Return(nil, myerrors.ErrSaveOAuthStateFailed)
+
+	authorization, err := s.oauthService.CreateState(s.ctx, req)
+
+	s.Nil(authorization)
+	s.Equal(myerrors.ErrSaveOAuthStateFailed, err)
+}
+
+// ==================== ConsumeState Tests ====================
+
+func (s *oauthServiceTestSuite) TestConsumeState_Success() {
+	state := &domain.OAuthState{Provider: "google"}
+
+	s.mockStateRepo.EXPECT().
+		Consume(s.ctx, crypto.HashToken("raw-state", s.testSecret), "google", gomock.Any()).
+		Return(state, nil)
+
+	result, err := s.oauthService.ConsumeState(s.ctx, "google", "raw-state")
+
+	s.Require().NoError(err)
+	s.Equal(state, result)
+}
+
+func (s *oauthServiceTestSuite) TestConsumeState_Empty() {
+	result, err := s.oauthService.ConsumeState(s.ctx, "google", "")
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidOAuthState, err)
+}
+
+// ==================== IssueLoginCode Tests ====================
+
+func (s *oauthServiceTestSuite) TestIssueLoginCode_Success() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	token := &domain.Token{Token: "login-code"}
+
+	s.mockTokenSvc.EXPECT().
+		GenerateChallengeToken(s.ctx, userID, domain.TokenTypeOAuthCode, gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, _ string, _ domain.TokenType, expiresAt time.Time, _ jwt.MapClaims) (*domain.Token, error) {
+			s.WithinDuration(time.Now().Add(time.Minute), expiresAt, 5*time.Second)
+			return token, nil
+		})
+
+	result, err := s.oauthService.IssueLoginCode(s.ctx, userID)
+
+	s.Require().NoError(err)
+	s.Equal(token, result)
+}
+
+// ==================== RedeemLoginCode Tests ====================
+
+func (s *oauthServiceTestSuite) TestRedeemLoginCode_Success() {
+	req := &model.OAuthCodeRequest{Code: "login-code"}
+	user := &domain.User{ID: uuid.Must(uuid.NewV7())}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(s.ctx, "login-code", domain.TokenTypeOAuthCode).
+		Return(jwt.MapClaims{"user_id": user.ID.String()}, nil)
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, user.ID.String()).Return(user, nil)
+
+	result, err := s.oauthService.RedeemLoginCode(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal(user, result)
+}
+
+func (s *oauthServiceTestSuite) TestRedeemLoginCode_InvalidCode() {
+	req := &model.OAuthCodeRequest{Code: "used-code"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockTokenSvc.EXPECT().ConsumeChallengeToken(s.ctx, "used-code", domain.TokenTypeOAuthCode).
+		Return(nil, myerrors.ErrInvalidToken)
+
+	result, err := s.oauthService.RedeemLoginCode(s.ctx, req)
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidToken, err)
+}
+
+func (s *oauthServiceTestSuite) TestRedeemLoginCode_ValidationError() {
+	req := &model.OAuthCodeRequest{}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(errors.New("validation error"))
+
+	result, err := s.oauthService.RedeemLoginCode(s.ctx, req)
+
+	s.Nil(result)
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
+	"app/internal/pkg/validator"
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+// RoleOrganizationAdmin is given to the admin named when an organization is created
+const RoleOrganizationAdmin = "org-admin"
+
+// organizationSlugPattern keeps slugs usable in URLs and subdomains
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+//go:generate mockgen -source=organization_service.go -destination=mocks/organization_service.go -package=mocks
+type OrganizationService interface {
+	GetOrganizations(ctx context.Context) ([]model.OrganizationResponse, error)
+	GetOrganization(ctx context.Context, id string) (*model.OrganizationResponse, error)
+	CreateOrganization(ctx context.Context, req *model.CreateOrganizationRequest) (*model.OrganizationResponse, error)
+	GetMemberships(ctx context.Context, userID string) ([]model.MembershipResponse, error)
+	GetMembership(ctx context.Context, organizationID, userID string) (*domain.OrganizationMember, error)
+	DefaultMembership(ctx context.Context, userID string) (*domain.OrganizationMember, error)
+	GetMembers(ctx context.Context) ([]model.MemberResponse, error)
+	SetMember(ctx context.Context, subject policy.Subject, userID string, req *model.SetMemberRequest) error
+	RemoveMember(ctx context.Context, subject policy.Subject, userID string) error
+	CheckGrantable(ctx context.Context, subject policy.Subject, role string) error
+}
+
+// OrganizationServiceImpl manages organizations and their members. Member operations act on the organization
+// of the request, see the tenant package.
+type OrganizationServiceImpl struct {
+	OrganizationRepository repository.OrganizationRepository `inject:"organizationRepository"`
+	RoleService            RoleService                       `inject:"roleService"`
+	Revocation             RevocationService                 `inject:"revocationService"`
+	Validator              validator.Validator               `inject:"validator"`
+}
+
+func (s *OrganizationServiceImpl) GetOrganizations(ctx context.Context) ([]model.OrganizationResponse, error) {
+	organizations, err := s.OrganizationRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]model.OrganizationResponse, 0, len(organizations))
+	for i := range organizations {
+		resp = append(resp, *newOrganizationResponse(&organizations[i]))
+	}
+
+	return resp, nil
+}
+
+func (s *OrganizationServiceImpl) GetOrganization(ctx context.Context, id string) (*model.OrganizationResponse, error) {
+	organization, err := s.OrganizationRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrganizationResponse(organization), nil
+}
+
+func (s *OrganizationServiceImpl) CreateOrganization(
+	ctx context.Context, req *model.CreateOrganizationRequest,
+) (*model.OrganizationResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating create organization request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	if !organizationSlugPattern.MatchString(req.Slug) {
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	organization := &domain.Organization{Name: req.Name, Slug: req.Slug}
+	if req.AdminID != "" {
+		organization.Members = []domain.OrganizationMember{
+			{UserID: uuid.MustParse(req.AdminID), Role: RoleOrganizationAdmin},
+		}
+	}
+
+	organization, err := s.OrganizationRepository.Create(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrganizationResponse(organization), nil
+}
+
+// GetMemberships lists the organizations of the user, the first one being the default of new tokens.
+func (s *OrganizationServiceImpl) GetMemberships(ctx context.Context, userID string) ([]model.MembershipResponse, error) {
+	members, err := s.OrganizationRepository.GetMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]model.MembershipResponse, 0, len(members))
+	for _, member := range members {
+		resp = append(resp, model.MembershipResponse{
+			OrganizationID: member.OrganizationID.String(),
+			Name:           member.Organization.Name,
+			Slug:           member.Organization.Slug,
+			Role:           member.Role,
+			JoinedAt:       member.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *OrganizationServiceImpl) GetMembership(
+	ctx context.Context, organizationID, userID string,
+) (*domain.OrganizationMember, error) {
+	return s.OrganizationRepository.GetMembership(ctx, organizationID, userID)
+}
+
+// DefaultMembership returns the oldest membership of the user, nil if the user belongs to no organization.
+func (s *OrganizationServiceImpl) DefaultMembership(ctx context.Context, userID string) (*domain.OrganizationMember, error) {
+	members, err := s.OrganizationRepository.GetMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	return &members[0], nil
+}
+
+func (s *OrganizationServiceImpl) GetMembers(ctx context.Context) ([]model.MemberResponse, error) {
+	organizationID := tenant.OrganizationID(ctx)
+	if organizationID == "" {
+		return nil, myerrors.ErrNoOrganization
+	}
+
+	members, err := s.OrganizationRepository.GetMembers(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]model.MemberResponse, 0, len(members))
+	for _, member := range members {
+		resp = append(resp, model.MemberResponse{
+			UserID:   member.UserID.String(),
+			Name:     member.User.Name,
+			Email:    member.User.Email,
+			Role:     member.Role,
+			JoinedAt: member.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+// SetMember gives a member of the current organization a role. Only subjects who manage every organization can
+// add users who are not members yet, and a subject cannot grant or take away a role with rights it lacks.
+// The access tokens of the user are revoked, since they carry the rights of the old role.
+func (s *OrganizationServiceImpl) SetMember(
+	ctx context.Context, subject policy.Subject, userID string, req *model.SetMemberRequest,
+) error {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating set member request", err)
+		return myerrors.ErrInvalidRequest
+	}
+
+	organizationID := tenant.OrganizationID(ctx)
+	if organizationID == "" {
+		return myerrors.ErrNoOrganization
+	}
+
+	member, err := s.OrganizationRepository.GetMembership(ctx, organizationID, userID)
+	if errors.Is(err, myerrors.ErrMemberNotFound) {
+		if !subject.HasRight("manageOrganizations") {
+			return myerrors.ErrMemberNotFound
+		}
+	} else if err != nil {
+		return err
+	} else if err = s.CheckGrantable(ctx, subject, member.Role); err != nil {
+		return err
+	}
+
+	if err = s.CheckGrantable(ctx, subject, req.Role); err != nil {
+		return err
+	}
+
+	err = s.OrganizationRepository.SetMember(ctx, &domain.OrganizationMember{
+		OrganizationID: uuid.MustParse(organizationID),
+		UserID:         uuid.MustParse(userID),
+		Role:           req.Role,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.Revocation.RevokeUser(ctx, userID)
+}
+
+// RemoveMember removes a user from the current organization. The account itself is kept.
+func (s *OrganizationServiceImpl) RemoveMember(ctx context.Context, subject policy.Subject, userID string) error {
+	organizationID := tenant.OrganizationID(ctx)
+	if organizationID == "" {
+		return myerrors.ErrNoOrganization
+	}
+
+	member, err := s.OrganizationRepository.GetMembership(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.CheckGrantable(ctx, subject, member.Role); err != nil {
+		return err
+	}
+
+	if err = s.OrganizationRepository.DeleteMember(ctx, organizationID, userID); err != nil {
+		return err
+	}
+
+	return s.Revocation.RevokeUser(ctx, userID)
+}
+
+// CheckGrantable fails unless the subject has every right of the role, so members cannot escalate
+// their own rights or those of others.
+func (s *OrganizationServiceImpl) CheckGrantable(ctx context.Context, subject policy.Subject, role string) error {
+	rights, err := s.RoleService.Rights(ctx, role)
+	if err != nil {
+		if errors.Is(err, myerrors.ErrRoleNotFound) {
+			return myerrors.ErrInvalidRole
+		}
+		return err
+	}
+
+	for _, right := range rights {
+		if !subject.HasRight(right) {
+			return myerrors.ErrRoleNotGrantable
+		}
+	}
+
+	return nil
+}
+
+func newOrganizationResponse(organization *domain.Organization) *model.OrganizationResponse {
+	return &model.OrganizationResponse{
+		ID:        organization.ID.String(),
+		Name:      organization.Name,
+		Slug:      organization.Slug,
+		CreatedAt: organization.CreatedAt,
+	}
+}
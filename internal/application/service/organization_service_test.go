@@ -0,0 +1,206 @@
+package service
+
+import (
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type organizationServiceTestSuite struct {
+	suite.Suite
+	mockCtrl       *gomock.Controller
+	mockOrgRepo    *mockRepository.MockOrganizationRepository
+	mockRoleSvc    *mocks.MockRoleService
+	mockRevocation *mocks.MockRevocationService
+	mockValidator  *mockValidator.MockValidator
+	service        *OrganizationServiceImpl
+	organizationID uuid.UUID
+	userID         uuid.UUID
+	ctx            context.Context
+	orgAdmin       policy.Subject
+}
+
+func TestOrganizationService(t *testing.T) {
+	suite.Run(t, new(organizationServiceTestSuite))
+}
+
+func (s *organizationServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockOrgRepo = mockRepository.NewMockOrganizationRepository(s.mockCtrl)
+	s.mockRoleSvc = mocks.NewMockRoleService(s.mockCtrl)
+	s.mockRevocation = mocks.NewMockRevocationService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.service = &OrganizationServiceImpl{
+		OrganizationRepository: s.mockOrgRepo,
+		RoleService:            s.mockRoleSvc,
+		Revocation:             s.mockRevocation,
+		Validator:              s.mockValidator,
+	}
+
+	s.organizationID = uuid.Must(uuid.NewV7())
+	s.userID = uuid.Must(uuid.NewV7())
+	s.ctx = tenant.WithOrganization(context.Background(), s.organizationID.String())
+	s.orgAdmin = policy.Subject{
+		UserID:         uuid.Must(uuid.NewV7()).String(),
+		OrganizationID: s.organizationID.String(),
+		Rights:         []string{"getUsers", "manageMembers", "manageUsers"},
+	}
+
+	s.mockRoleSvc.EXPECT().Rights(gomock.Any(), "user").Return([]string{}, nil).AnyTimes()
+	s.mockRoleSvc.EXPECT().Rights(gomock.Any(), "org-admin").
+		Return([]string{"getUsers", "manageMembers", "manageUsers"}, nil).AnyTimes()
+	s.mockRoleSvc.EXPECT().Rights(gomock.Any(), "admin").
+		Return([]string{"getUsers", "manageMembers", "manageOrganizations", "manageRoles", "manageUsers"}, nil).AnyTimes()
+}
+
+func (s *organizationServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *organizationServiceTestSuite) member(role string) *domain.OrganizationMember {
+	return &domain.OrganizationMember{OrganizationID: s.organizationID, UserID: s.userID, Role: role}
+}
+
+// ==================== CreateOrganization Tests ====================
+
+func (s *organizationServiceTestSuite) TestCreateOrganization_WithAdmin() {
+	req := &model.CreateOrganizationRequest{Name: "Acme Corp", Slug: "acme-corp", AdminID: s.userID.String()}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockOrgRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, organization *domain.Organization) (*domain.Organization, error) {
+			s.Equal([]domain.OrganizationMember{{UserID: s.userID, Role: RoleOrganizationAdmin}}, organization.Members)
+			organization.ID = s.organizationID
+			return organization, nil
+		})
+
+	resp, err := s.service.CreateOrganization(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal(s.organizationID.String(), resp.ID)
+	s.Equal("acme-corp", resp.Slug)
+}
+
+func (s *organizationServiceTestSuite) TestCreateOrganization_InvalidSlug() {
+	req := &model.CreateOrganizationRequest{Name: "Acme Corp", Slug: "Acme Corp"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	_, err := s.service.CreateOrganization(s.ctx, req)
+
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
+
+// ==================== DefaultMembership Tests ====================
+
+func (s *organizationServiceTestSuite) TestDefaultMembership() {
+	s.mockOrgRepo.EXPECT().GetMemberships(s.ctx, s.userID.String()).
+		Return([]domain.OrganizationMember{*s.member("user"), {Role: "org-admin"}}, nil)
+
+	member, err := s.service.DefaultMembership(s.ctx, s.userID.String())
+
+	s.Require().NoError(err)
+	s.Equal("user", member.Role)
+}
+
+func (s *organizationServiceTestSuite) TestDefaultMembership_None() {
+	s.mockOrgRepo.EXPECT().GetMemberships(s.ctx, s.userID.String()).Return(nil, nil)
+
+	member, err := s.service.DefaultMembership(s.ctx, s.userID.String())
+
+	s.Require().NoError(err)
+	s.Nil(member)
+}
+
+// ==================== SetMember Tests ====================
+
+func (s *organizationServiceTestSuite) TestSetMember_ChangesRole() {
+	req := &model.SetMemberRequest{Role: "org-admin"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockOrgRepo.EXPECT().GetMembership(s.ctx, s.organizationID.String(), s.userID.String()).Return(s.member("user"), nil)
+	s.mockOrgRepo.EXPECT().SetMember(s.ctx, s.member("org-admin")).Return(nil)
+	s.mockRevocation.EXPECT().RevokeUser(s.ctx, s.userID.String()).Return(nil)
+
+	s.NoError(s.service.SetMember(s.ctx, s.orgAdmin, s.userID.String(), req))
+}
+
+func (s *organizationServiceTestSuite) TestSetMember_CannotGrantMoreRights() {
+	req := &model.SetMemberRequest{Role: "admin"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockOrgRepo.EXPECT().GetMembership(s.ctx, s.organizationID.String(), s.userID.String()).Return(s.member("user"), nil)
+
+	err := s.service.SetMember(s.ctx, s.orgAdmin, s.userID.String(), req)
+
+	s.Equal(myerrors.ErrRoleNotGrantable, err)
+}
+
+func (s *organizationServiceTestSuite) TestSetMember_CannotDemoteHigherMember() {
+	req := &model.SetMemberRequest{Role: "user"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockOrgRepo.EXPECT().GetMembership(s.ctx, s.organizationID.String(), s.userID.String()).Return(s.member("admin"), nil)
+
+	err := s.service.SetMember(s.ctx, s.orgAdmin, s.userID.String(), req)
+
+	s.Equal(myerrors.ErrRoleNotGrantable, err)
+}
+
+func (s *organizationServiceTestSuite) TestSetMember_OnlyPlatformAdminsAddUsers() {
+	req := &model.SetMemberRequest{Role: "user"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil).Times(2)
+	s.mockOrgRepo.EXPECT().GetMembership(s.ctx, s.organizationID.String(), s.userID.String()).
+		Return(nil, myerrors.ErrMemberNotFound).Times(2)
+
+	err := s.service.SetMember(s.ctx, s.orgAdmin, s.userID.String(), req)
+	s.Equal(myerrors.ErrMemberNotFound, err)
+
+	platformAdmin := policy.Subject{UserID: uuid.Must(uuid.NewV7()).String(), Rights: []string{"manageOrganizations"}}
+	s.mockOrgRepo.EXPECT().SetMember(s.ctx, s.member("user")).Return(nil)
+	s.mockRevocation.EXPECT().RevokeUser(s.ctx, s.userID.String()).Return(nil)
+
+	s.NoError(s.service.SetMember(s.ctx, platformAdmin, s.userID.String(), req))
+}
+
+func (s *organizationServiceTestSuite) TestSetMember_NoOrganization() {
+	req := &model.SetMemberRequest{Role: "user"}
+	ctx := context.Background()
+
+	s.mockValidator.EXPECT().Validate(ctx, req).Return(nil)
+
+	err := s.service.SetMember(ctx, s.orgAdmin, s.userID.String(), req)
+
+	s.Equal(myerrors.ErrNoOrganization, err)
+}
+
+// ==================== RemoveMember Tests ====================
+
+func (s *organizationServiceTestSuite) TestRemoveMember_Success() {
+	s.mockOrgRepo.EXPECT().GetMembership(s.ctx, s.organizationID.String(), s.userID.String()).Return(s.member("user"), nil)
+	s.mockOrgRepo.EXPECT().DeleteMember(s.ctx, s.organizationID.String(), s.userID.String()).Return(nil)
+	s.mockRevocation.EXPECT().RevokeUser(s.ctx, s.userID.String()).Return(nil)
+
+	s.NoError(s.service.RemoveMember(s.ctx, s.orgAdmin, s.userID.String()))
+}
+
+func (s *organizationServiceTestSuite) TestRemoveMember_HigherMember() {
+	s.mockOrgRepo.EXPECT().GetMembership(s.ctx, s.organizationID.String(), s.userID.String()).Return(s.member("admin"), nil)
+
+	err := s.service.RemoveMember(s.ctx, s.orgAdmin, s.userID.String())
+
+	s.Equal(myerrors.ErrRoleNotGrantable, err)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/domain"
+	"app/internal/domain/repository"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/tommynurwantoro/golog"
+)
+
+const defaultRevocationSyncInterval = time.Minute
+
+//go:generate mockgen -source=revocation_service.go -destination=mocks/revocation_service.go -package=mocks
+type RevocationService interface {
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeUser(ctx context.Context, userID string) error
+	IsRevoked(claims jwt.MapClaims) bool
+	Sync(ctx context.Context) error
+}
+
+// RevocationServiceImpl keeps the revoked_tokens table in memory so JWTAuth never queries the database.
+// Other instances pick up new entries on their next sync.
+type RevocationServiceImpl struct {
+	Conf                   *config.Config                    `inject:"config"`
+	RevokedTokenRepository repository.RevokedTokenRepository `inject:"revokedTokenRepository"`
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time
+	sessions map[string]time.Time
+	users    map[string]time.Time
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func (s *RevocationServiceImpl) Startup() error {
+	if err := s.Sync(context.Background()); err != nil {
+		return err
+	}
+
+	interval := s.Conf.JWT.RevocationSyncInterval
+	if interval <= 0 {
+		interval = defaultRevocationSyncInterval
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.syncLoop(interval)
+
+	return nil
+}
+
+func (s *RevocationServiceImpl) Shutdown() error {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+	}
+	return nil
+}
+
+func (s *RevocationServiceImpl) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return s.revoke(ctx, &domain.RevokedToken{JTI: &jti, ExpiresAt: expiresAt})
+}
+
+func (s *RevocationServiceImpl) RevokeSession(ctx context.Context, sessionID string) error {
+	parsedSessionID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return err
+	}
+
+	return s.revoke(ctx, &domain.RevokedToken{SessionID: &parsedSessionID, ExpiresAt: s.accessTokenHorizon()})
+}
+
+func (s *RevocationServiceImpl) RevokeUser(ctx context.Context, userID string) error {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.revoke(ctx, &domain.RevokedToken{UserID: &parsedUserID, ExpiresAt: s.accessTokenHorizon()})
+}
+
+func (s *RevocationServiceImpl) IsRevoked(claims jwt.MapClaims) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if jti, ok := claims["jti"].(string); ok {
+		if _, revoked := s.tokens[jti]; revoked {
+			return true
+		}
+	}
+
+	if sessionID, ok := claims["session_id"].(string); ok {
+		if _, revoked := s.sessions[sessionID]; revoked {
+			return true
+		}
+	}
+
+	if userID, ok := claims["user_id"].(string); ok {
+		if revokedAt, revoked := s.users[userID]; revoked {
+			issuedAt, err := claims.GetIssuedAt()
+			// Tokens without iat cannot prove they were issued after the revocation
+			if err != nil || issuedAt == nil || issuedAt.Unix() <= revokedAt.Unix() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Sync prunes expired entries from the database and reloads the cache from it.
+func (s *RevocationServiceImpl) Sync(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	if _, err := s.RevokedTokenRepository.DeleteExpired(ctx, now); err != nil {
+		return err
+	}
+
+	revokedTokens, err := s.RevokedTokenRepository.GetActive(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[string]time.Time)
+	sessions := make(map[string]time.Time)
+	users := make(map[string]time.Time)
+	for i := range revokedTokens {
+		addRevokedToken(&revokedTokens[i], tokens, sessions, users)
+	}
+
+	s.mu.Lock()
+	s.tokens, s.sessions, s.users = tokens, sessions, users
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *RevocationServiceImpl) syncLoop(interval time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.Sync(context.Background()); err != nil {
+				golog.Error("Error syncing revoked tokens", err)
+			}
+		}
+	}
+}
+
+func (s *RevocationServiceImpl) revoke(ctx context.Context, revokedToken *domain.RevokedToken) error {
+	revokedToken.RevokedAt = time.Now().UTC()
+
+	if _, err := s.RevokedTokenRepository.Create(ctx, revokedToken); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.tokens == nil {
+		s.tokens, s.sessions, s.users = make(map[string]time.Time), make(map[string]time.Time), make(map[string]time.Time)
+	}
+	addRevokedToken(revokedToken, s.tokens, s.sessions, s.users)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// accessTokenHorizon is when every access token issued up to now has expired on its own.
+func (s *RevocationServiceImpl) accessTokenHorizon() time.Time {
+	return time.Now().UTC().Add(s.Conf.JWT.Expire)
+}
+
+func addRevokedToken(revokedToken *domain.RevokedToken, tokens, sessions, users map[string]time.Time) {
+	switch {
+	case revokedToken.JTI != nil:
+		tokens[*revokedToken.JTI] = revokedToken.ExpiresAt
+	case revokedToken.SessionID != nil:
+		sessions[revokedToken.SessionID.String()] = revokedToken.ExpiresAt
+	case revokedToken.UserID != nil:
+		// Keep the latest revocation so every token issued before it stays denied
+		if revokedAt, ok := users[revokedToken.UserID.String()]; !ok || revokedToken.RevokedAt.After(revokedAt) {
+			users[revokedToken.UserID.String()] = revokedToken.RevokedAt
+		}
+	}
+}
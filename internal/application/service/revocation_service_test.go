@@ -0,0 +1,141 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type revocationServiceTestSuite struct {
+	suite.Suite
+	mockCtrl        *gomock.Controller
+	mockRevokedRepo *mockRepository.MockRevokedTokenRepository
+	service         *RevocationServiceImpl
+	ctx             context.Context
+}
+
+func TestRevocationService(t *testing.T) {
+	suite.Run(t, new(revocationServiceTestSuite))
+}
+
+func (s *revocationServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockRevokedRepo = mockRepository.NewMockRevokedTokenRepository(s.mockCtrl)
+	s.service = &RevocationServiceImpl{
+		Conf:                   &config.Config{JWT: config.JWTConfig{Expire: 30 * time.Minute}},
+		RevokedTokenRepository: s.mockRevokedRepo,
+	}
+	s.ctx = context.Background()
+}
+
+func (s *revocationServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *revocationServiceTestSuite) expectCreate() {
+	s.mockRevokedRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, revokedToken *domain.RevokedToken) (*domain.RevokedToken, error) {
+			return revokedToken, nil
+		})
+}
+
+// ==================== IsRevoked Tests ====================
+
+func (s *revocationServiceTestSuite) TestIsRevoked_Token() {
+	s.expectCreate()
+
+	s.NoError(s.service.RevokeToken(s.ctx, "revoked-jti", time.Now().Add(time.Hour)))
+
+	s.True(s.service.IsRevoked(jwt.MapClaims{"jti": "revoked-jti"}))
+	s.False(s.service.IsRevoked(jwt.MapClaims{"jti": "other-jti"}))
+}
+
+func (s *revocationServiceTestSuite) TestIsRevoked_Session() {
+	sessionID := uuid.Must(uuid.NewV7()).String()
+	s.mockRevokedRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, revokedToken *domain.RevokedToken) (*domain.RevokedToken, error) {
+			s.Equal(sessionID, revokedToken.SessionID.String())
+			s.WithinDuration(time.Now().Add(30*time.Minute), revokedToken.ExpiresAt, time.Second)
+			return revokedToken, nil
+		})
+
+	s.NoError(s.service.RevokeSession(s.ctx, sessionID))
+
+	s.True(s.service.IsRevoked(jwt.MapClaims{"jti": "any", "session_id": sessionID}))
+	s.False(s.service.IsRevoked(jwt.MapClaims{"jti": "any", "session_id": uuid.Must(uuid.NewV7()).String()}))
+}
+
+func (s *revocationServiceTestSuite) TestIsRevoked_UserOnlyBeforeRevocation() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	s.expectCreate()
+
+	s.NoError(s.service.RevokeUser(s.ctx, userID))
+
+	s.True(s.service.IsRevoked(jwt.MapClaims{"user_id": userID, "iat": float64(time.Now().Add(-time.Minute).Unix())}))
+	s.True(s.service.IsRevoked(jwt.MapClaims{"user_id": userID}))
+	s.False(s.service.IsRevoked(jwt.MapClaims{"user_id": userID, "iat": float64(time.Now().Add(time.Minute).Unix())}))
+	s.False(s.service.IsRevoked(jwt.MapClaims{"user_id": uuid.Must(uuid.NewV7()).String(), "iat": float64(time.Now().Unix())}))
+}
+
+// ==================== Revoke Tests ====================
+
+func (s *revocationServiceTestSuite) TestRevokeToken_RepositoryError() {
+	s.mockRevokedRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(nil, myerrors.ErrRevokeTokenFailed)
+
+	err := s.service.RevokeToken(s.ctx, "jti", time.Now().Add(time.Hour))
+
+	s.ErrorIs(err, myerrors.ErrRevokeTokenFailed)
+	s.False(s.service.IsRevoked(jwt.MapClaims{"jti": "jti"}))
+}
+
+func (s *revocationServiceTestSuite) TestRevokeSession_InvalidSessionID() {
+	s.Error(s.service.RevokeSession(s.ctx, "not-a-uuid"))
+}
+
+// ==================== Sync Tests ====================
+
+func (s *revocationServiceTestSuite) TestSync_ReplacesCacheWithActiveEntries() {
+	s.expectCreate()
+	s.NoError(s.service.RevokeToken(s.ctx, "pruned-jti", time.Now().Add(time.Hour)))
+
+	jti := "synced-jti"
+	s.mockRevokedRepo.EXPECT().
+		DeleteExpired(s.ctx, gomock.Any()).
+		Return(int64(1), nil)
+	s.mockRevokedRepo.EXPECT().
+		GetActive(s.ctx, gomock.Any()).
+		Return([]domain.RevokedToken{{JTI: &jti, ExpiresAt: time.Now().Add(time.Hour)}}, nil)
+
+	s.NoError(s.service.Sync(s.ctx))
+
+	s.True(s.service.IsRevoked(jwt.MapClaims{"jti": "synced-jti"}))
+	s.False(s.service.IsRevoked(jwt.MapClaims{"jti": "pruned-jti"}))
+}
+
+func (s *revocationServiceTestSuite) TestSync_KeepsCacheOnError() {
+	s.expectCreate()
+	s.NoError(s.service.RevokeToken(s.ctx, "revoked-jti", time.Now().Add(time.Hour)))
+
+	s.mockRevokedRepo.EXPECT().
+		DeleteExpired(s.ctx, gomock.Any()).
+		Return(int64(0), nil)
+	s.mockRevokedRepo.EXPECT().
+		GetActive(s.ctx, gomock.Any()).
+		Return(nil, myerrors.ErrGetRevokedTokensFailed)
+
+	s.ErrorIs(s.service.Sync(s.ctx), myerrors.ErrGetRevokedTokensFailed)
+	s.True(s.service.IsRevoked(jwt.MapClaims{"jti": "revoked-jti"}))
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/validator"
+	"context"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+const defaultRoleCacheTTL = time.Minute
+
+// roleNamePattern keeps role names readable in URLs, logs and search queries
+var roleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+//go:generate mockgen -source=role_service.go -destination=mocks/role_service.go -package=mocks
+type RoleService interface {
+	GetRoles(ctx context.Context) ([]model.RoleResponse, error)
+	GetRole(ctx context.Context, id string) (*model.RoleResponse, error)
+	CreateRole(ctx context.Context, req *model.CreateRoleRequest) (*model.RoleResponse, error)
+	UpdateRole(ctx context.Context, id string, req *model.UpdateRoleRequest) (*model.RoleResponse, error)
+	DeleteRole(ctx context.Context, id string) error
+	GetPermissions(ctx context.Context) ([]model.PermissionResponse, error)
+	Rights(ctx context.Context, roles ...string) ([]string, error)
+	UserRights(ctx context.Context, userID string, organizationRoles ...string) ([]string, error)
+}
+
+// RoleServiceImpl manages the roles stored in the database and keeps the rights of every role in memory,
+// so JWTAuth does not query the database on each request. Changes made here drop the copy at once;
+// other instances reload theirs once it is older than roles.cache_ttl.
+type RoleServiceImpl struct {
+	Conf           *config.Config            `inject:"config"`
+	RoleRepository repository.RoleRepository `inject:"roleRepository"`
+	Validator      validator.Validator       `inject:"validator"`
+
+	mu       sync.RWMutex
+	rights   map[string][]string
+	loadedAt time.Time
+	// generation is bumped on every change, so a reload that started before it is not kept
+	generation uint64
+}
+
+func (s *RoleServiceImpl) GetRoles(ctx context.Context) ([]model.RoleResponse, error) {
+	roles, err := s.RoleRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]model.RoleResponse, 0, len(roles))
+	for i := range roles {
+		resp = append(resp, *newRoleResponse(&roles[i]))
+	}
+
+	return resp, nil
+}
+
+func (s *RoleServiceImpl) GetRole(ctx context.Context, id string) (*model.RoleResponse, error) {
+	role, err := s.RoleRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRoleResponse(role), nil
+}
+
+func (s *RoleServiceImpl) CreateRole(ctx context.Context, req *model.CreateRoleRequest) (*model.RoleResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	permissions, err := s.permissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.RoleRepository.Create(ctx, &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return newRoleResponse(role), nil
+}
+
+func (s *RoleServiceImpl) UpdateRole(
+	ctx context.Context, id string, req *model.UpdateRoleRequest,
+) (*model.RoleResponse, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if req.Description == nil && req.Permissions == nil {
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	role, err := s.RoleRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+
+	if req.Permissions != nil {
+		if role.Permissions, err = s.permissions(ctx, *req.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
+	role, err = s.RoleRepository.Update(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return newRoleResponse(role), nil
+}
+
+// DeleteRole removes a role no user has. The default role of new users is kept.
+func (s *RoleServiceImpl) DeleteRole(ctx context.Context, id string) error {
+	role, err := s.RoleRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if role.Name == domain.RoleUser {
+		return myerrors.ErrDefaultRole
+	}
+
+	if err = s.RoleRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidate()
+
+	return nil
+}
+
+func (s *RoleServiceImpl) GetPermissions(ctx context.Context) ([]model.PermissionResponse, error) {
+	permissions, err := s.RoleRepository.GetPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]model.PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		resp = append(resp, model.PermissionResponse{Name: permission.Name, Description: permission.Description})
+	}
+
+	return resp, nil
+}
+
+// Rights returns the permissions granted by any of the roles, or ErrRoleNotFound if one of them does not exist.
+func (s *RoleServiceImpl) Rights(ctx context.Context, roles ...string) ([]string, error) {
+	rights, err := s.roleRights(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := []string{}
+	for _, role := range roles {
+		roleRights, ok := rights[role]
+		if !ok {
+			return nil, myerrors.ErrRoleNotFound
+		}
+		granted = append(granted, roleRights...)
+	}
+
+	return slices.Compact(slices.Sorted(slices.Values(granted))), nil
+}
+
+// UserRights returns the permissions of all roles the user holds, together with those of the user's roles
+// in the organization the user acts in.
+func (s *RoleServiceImpl) UserRights(ctx context.Context, userID string, organizationRoles ...string) ([]string, error) {
+	roles, err := s.RoleRepository.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Rights(ctx, append(roles, organizationRoles...)...)
+}
+
+func (s *RoleServiceImpl) roleRights(ctx context.Context) (map[string][]string, error) {
+	ttl := s.Conf.Roles.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultRoleCacheTTL
+	}
+
+	s.mu.RLock()
+	rights, loadedAt, generation := s.rights, s.loadedAt, s.generation
+	s.mu.RUnlock()
+
+	if rights != nil && time.Since(loadedAt) < ttl {
+		return rights, nil
+	}
+
+	roles, err := s.RoleRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rights = make(map[string][]string, len(roles))
+	for i := range roles {
+		rights[roles[i].Name] = roles[i].PermissionNames()
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.rights = rights
+		s.loadedAt = time.Now()
+	}
+	s.mu.Unlock()
+
+	return rights, nil
+}
+
+func (s *RoleServiceImpl) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rights = nil
+	s.generation++
+}
+
+// permissions loads the named permissions and fails if one of them does not exist.
+func (s *RoleServiceImpl) permissions(ctx context.Context, names []string) ([]domain.Permission, error) {
+	names = slices.Compact(slices.Sorted(slices.Values(names)))
+
+	permissions, err := s.RoleRepository.GetPermissionsByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(permissions) != len(names) {
+		return nil, myerrors.ErrPermissionNotFound
+	}
+
+	return permissions, nil
+}
+
+func newRoleResponse(role *domain.Role) *model.RoleResponse {
+	return &model.RoleResponse{
+		ID:          role.ID.String(),
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.PermissionNames(),
+		CreatedAt:   role.CreatedAt,
+	}
+}
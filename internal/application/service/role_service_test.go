@@ -0,0 +1,213 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type roleServiceTestSuite struct {
+	suite.Suite
+	mockCtrl      *gomock.Controller
+	mockRoleRepo  *mockRepository.MockRoleRepository
+	mockValidator *mockValidator.MockValidator
+	roleService   *RoleServiceImpl
+	ctx           context.Context
+	roles         []domain.Role
+}
+
+func TestRoleService(t *testing.T) {
+	suite.Run(t, new(roleServiceTestSuite))
+}
+
+func (s *roleServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockRoleRepo = mockRepository.NewMockRoleRepository(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+
+	s.roleService = &RoleServiceImpl{
+		Conf:           &config.Config{Roles: config.RolesConfig{CacheTTL: time.Hour}},
+		RoleRepository: s.mockRoleRepo,
+		Validator:      s.mockValidator,
+	}
+
+	s.ctx = context.Background()
+	s.roles = []domain.Role{
+		{ID: uuid.Must(uuid.NewV7()), Name: "admin", Permissions: []domain.Permission{{Name: "getUsers"}, {Name: "manageUsers"}}},
+		{ID: uuid.Must(uuid.NewV7()), Name: "user"},
+	}
+}
+
+func (s *roleServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// ==================== Rights Tests ====================
+
+func (s *roleServiceTestSuite) TestRights_CachesRoles() {
+	s.mockRoleRepo.EXPECT().GetAll(s.ctx).Return(s.roles, nil).Times(1)
+
+	rights, err := s.roleService.Rights(s.ctx, "admin")
+	s.Require().NoError(err)
+	s.Equal([]string{"getUsers", "manageUsers"}, rights)
+
+	rights, err = s.roleService.Rights(s.ctx, "user")
+	s.Require().NoError(err)
+	s.Empty(rights)
+}
+
+func (s *roleServiceTestSuite) TestRights_UnknownRole() {
+	s.mockRoleRepo.EXPECT().GetAll(s.ctx).Return(s.roles, nil)
+
+	_, err := s.roleService.Rights(s.ctx, "superuser")
+
+	s.Equal(myerrors.ErrRoleNotFound, err)
+}
+
+func (s *roleServiceTestSuite) TestRights_MergesRoles() {
+	s.roles[1].Permissions = []domain.Permission{{Name: "getUsers"}, {Name: "manageOAuthClients"}}
+	s.mockRoleRepo.EXPECT().GetAll(s.ctx).Return(s.roles, nil)
+
+	rights, err := s.roleService.Rights(s.ctx, "user", "admin")
+
+	s.Require().NoError(err)
+	s.Equal([]string{"getUsers", "manageOAuthClients", "manageUsers"}, rights)
+}
+
+func (s *roleServiceTestSuite) TestUserRights() {
+	userID := uuid.Must(uuid.NewV7()).String()
+	s.mockRoleRepo.EXPECT().GetUserRoles(s.ctx, userID).Return([]string{"admin", "user"}, nil)
+	s.mockRoleRepo.EXPECT().GetAll(s.ctx).Return(s.roles, nil)
+
+	rights, err := s.roleService.UserRights(s.ctx, userID)
+
+	s.Require().NoError(err)
+	s.Equal([]string{"getUsers", "manageUsers"}, rights)
+}
+
+func (s *roleServiceTestSuite) TestRights_ReloadsAfterTTL() {
+	s.roleService.Conf.Roles.CacheTTL = time.Nanosecond
+	s.mockRoleRepo.EXPECT().GetAll(s.ctx).Return(s.roles, nil).Times(2)
+
+	_, err := s.roleService.Rights(s.ctx, "admin")
+	s.Require().NoError(err)
+	time.Sleep(time.Millisecond)
+	_, err = s.roleService.Rights(s.ctx, "admin")
+	s.Require().NoError(err)
+}
+
+// ==================== CreateRole Tests ====================
+
+func (s *roleServiceTestSuite) TestCreateRole_Success() {
+	req := &model.CreateRoleRequest{Name: "support", Permissions: []string{"getUsers", "getUsers"}}
+	getUsers := domain.Permission{ID: uuid.Must(uuid.NewV7()), Name: "getUsers"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRoleRepo.EXPECT().GetPermissionsByNames(s.ctx, []string{"getUsers"}).
+		Return([]domain.Permission{getUsers}, nil)
+	s.mockRoleRepo.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, role *domain.Role) (*domain.Role, error) {
+			role.ID = uuid.Must(uuid.NewV7())
+			return role, nil
+		})
+
+	resp, err := s.roleService.CreateRole(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal("support", resp.Name)
+	s.Equal([]string{"getUsers"}, resp.Permissions)
+}
+
+func (s *roleServiceTestSuite) TestCreateRole_InvalidName() {
+	req := &model.CreateRoleRequest{Name: "support team"}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	_, err := s.roleService.CreateRole(s.ctx, req)
+
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
+
+func (s *roleServiceTestSuite) TestCreateRole_UnknownPermission() {
+	req := &model.CreateRoleRequest{Name: "support", Permissions: []string{"getUsers", "unknown"}}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRoleRepo.EXPECT().GetPermissionsByNames(s.ctx, []string{"getUsers", "unknown"}).
+		Return([]domain.Permission{{Name: "getUsers"}}, nil)
+
+	_, err := s.roleService.CreateRole(s.ctx, req)
+
+	s.Equal(myerrors.ErrPermissionNotFound, err)
+}
+
+// ==================== UpdateRole Tests ====================
+
+func (s *roleServiceTestSuite) TestUpdateRole_InvalidatesCache() {
+	s.mockRoleRepo.EXPECT().GetAll(s.ctx).Return(s.roles, nil)
+	_, err := s.roleService.Rights(s.ctx, "user")
+	s.Require().NoError(err)
+
+	permissions := []string{"getUsers"}
+	req := &model.UpdateRoleRequest{Permissions: &permissions}
+	user := s.roles[1]
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockRoleRepo.EXPECT().GetByID(s.ctx, user.ID.String()).Return(&user, nil)
+	s.mockRoleRepo.EXPECT().GetPermissionsByNames(s.ctx, permissions).
+		Return([]domain.Permission{{Name: "getUsers"}}, nil)
+	s.mockRoleRepo.EXPECT().Update(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, role *domain.Role) (*domain.Role, error) {
+			return role, nil
+		})
+
+	_, err = s.roleService.UpdateRole(s.ctx, user.ID.String(), req)
+	s.Require().NoError(err)
+
+	s.mockRoleRepo.EXPECT().GetAll(s.ctx).
+		Return([]domain.Role{s.roles[0], {Name: "user", Permissions: []domain.Permission{{Name: "getUsers"}}}}, nil)
+
+	rights, err := s.roleService.Rights(s.ctx, "user")
+	s.Require().NoError(err)
+	s.Equal([]string{"getUsers"}, rights)
+}
+
+func (s *roleServiceTestSuite) TestUpdateRole_NothingToChange() {
+	req := &model.UpdateRoleRequest{}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+
+	_, err := s.roleService.UpdateRole(s.ctx, s.roles[0].ID.String(), req)
+
+	s.Equal(myerrors.ErrInvalidRequest, err)
+}
+
+// ==================== DeleteRole Tests ====================
+
+func (s *roleServiceTestSuite) TestDeleteRole_Success() {
+	support := domain.Role{ID: uuid.Must(uuid.NewV7()), Name: "support"}
+
+	s.mockRoleRepo.EXPECT().GetByID(s.ctx, support.ID.String()).Return(&support, nil)
+	s.mockRoleRepo.EXPECT().Delete(s.ctx, support.ID.String()).Return(nil)
+
+	s.NoError(s.roleService.DeleteRole(s.ctx, support.ID.String()))
+}
+
+func (s *roleServiceTestSuite) TestDeleteRole_DefaultRole() {
+	user := s.roles[1]
+
+	s.mockRoleRepo.EXPECT().GetByID(s.ctx, user.ID.String()).Return(&user, nil)
+
+	err := s.roleService.DeleteRole(s.ctx, user.ID.String())
+
+	s.Equal(myerrors.ErrDefaultRole, err)
+}
@@ -10,6 +10,8 @@ import (
 	"app/internal/pkg/token"
 	"app/internal/pkg/validator"
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,17 +24,56 @@ type TokenService interface {
 	DeleteToken(ctx context.Context, tokenType domain.TokenType, userID string) error
 	DeleteAllToken(ctx context.Context, userID string) error
 	GetTokenByRefreshToken(ctx context.Context, refreshToken string) (*domain.Token, error)
-	GenerateAuthTokens(ctx context.Context, userID string) (*domain.Token, *domain.Token, error)
-	GenerateAccessToken(ctx context.Context, userID string) (*domain.Token, error)
-	GenerateResetPasswordToken(ctx context.Context, req *model.ForgotPasswordRequest) (*domain.Token, error)
+	GenerateAuthTokens(ctx context.Context, userID string, device *model.DeviceInfo) (*domain.Token, *domain.Token, error)
+	GenerateAccessToken(ctx context.Context, userID, sessionID string) (*domain.Token, error)
+	GenerateClientAuthTokens(
+		ctx context.Context, userID string, client *domain.OAuthClient, scope string,
+	) (*domain.Token, *domain.Token, error)
+	GenerateClientAccessToken(ctx context.Context, clientID, scope string) (*domain.Token, error)
+	RotateRefreshToken(
+		ctx context.Context, refreshToken *domain.Token, device *model.DeviceInfo,
+	) (*domain.Token, *domain.Token, error)
+	GenerateResetPasswordToken(ctx context.Context, userID string) (*domain.Token, error)
 	GenerateVerifyEmailToken(ctx context.Context, userID string) (*domain.Token, error)
+	GenerateMFAPendingToken(ctx context.Context, userID string) (*domain.Token, error)
+	GenerateMagicLinkToken(ctx context.Context, req *model.MagicLinkRequest) (*domain.Token, error)
+	GenerateInvitationToken(ctx context.Context, invitation *domain.OrganizationInvitation) (*domain.Token, error)
+	VerifyInvitationToken(ctx context.Context, invitationToken string) (string, error)
+	GenerateChallengeToken(
+		ctx context.Context, userID string, tokenType domain.TokenType, expires time.Time, claims jwt.MapClaims,
+	) (*domain.Token, error)
+	ConsumeChallengeToken(ctx context.Context, challengeToken string, tokenType domain.TokenType) (jwt.MapClaims, error)
+	GetSessions(ctx context.Context, userID string) ([]domain.Session, error)
+	TouchSession(ctx context.Context, sessionID string) error
+	DeleteSession(ctx context.Context, userID, sessionID string) error
+	DeleteOtherSessions(ctx context.Context, userID, currentSessionID string) error
+	RevokeAccessToken(ctx context.Context, accessToken string) error
 }
 
 type TokenServiceImpl struct {
-	Conf            *config.Config             `inject:"config"`
-	TokenRepository repository.TokenRepository `inject:"tokenRepository"`
-	UserService     UserService                `inject:"userService"`
-	Validator       validator.Validator        `inject:"validator"`
+	Conf                    *config.Config                     `inject:"config"`
+	TokenRepository         repository.TokenRepository         `inject:"tokenRepository"`
+	SecurityEventRepository repository.SecurityEventRepository `inject:"securityEventRepository"`
+	UserService             UserService                        `inject:"userService"`
+	Validator               validator.Validator                `inject:"validator"`
+	Keys                    token.KeyManager                   `inject:"keyManager"`
+	Revocation              RevocationService                  `inject:"revocationService"`
+	RoleService             RoleService                        `inject:"roleService"`
+	OrganizationService     OrganizationService                `inject:"organizationService"`
+}
+
+// Startup refuses to run without a key for the stored token hashes. jwt.secret provides it only for HMAC
+// signing keys, so asymmetric key rings need jwt.token_hash_key.
+func (s *TokenServiceImpl) Startup() error {
+	if s.Conf.JWT.HashKey() == "" {
+		return errors.New("jwt.token_hash_key is required when jwt.secret is not set")
+	}
+
+	return nil
+}
+
+func (s *TokenServiceImpl) Shutdown() error {
+	return nil
 }
 
 func (s *TokenServiceImpl) DeleteToken(ctx context.Context, tokenType domain.TokenType, userID string) error {
@@ -40,19 +81,24 @@ func (s *TokenServiceImpl) DeleteToken(ctx context.Context, tokenType domain.Tok
 }
 
 func (s *TokenServiceImpl) DeleteAllToken(ctx context.Context, userID string) error {
-	return s.TokenRepository.DeleteAll(ctx, userID)
+	if err := s.TokenRepository.DeleteAll(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.Revocation.RevokeUser(ctx, userID)
 }
 
 func (s *TokenServiceImpl) GetTokenByRefreshToken(ctx context.Context, refreshToken string) (*domain.Token, error) {
-	userID, err := token.VerifyToken(refreshToken, s.Conf.JWT.Secret, domain.TokenTypeRefresh.String())
+	userID, err := token.VerifyToken(refreshToken, s.Keys, domain.TokenTypeRefresh.String())
 	if err != nil {
 		return nil, err
 	}
 
-	tokenDoc, err := s.TokenRepository.GetByTokenAndUserID(ctx, refreshToken, userID)
+	tokenDoc, err := s.TokenRepository.GetByTokenHashAndUserID(ctx, s.hashToken(refreshToken), userID)
 	if err != nil {
 		return nil, err
 	}
+	tokenDoc.Token = refreshToken
 
 	return tokenDoc, nil
 }
@@ -60,29 +106,75 @@ func (s *TokenServiceImpl) GetTokenByRefreshToken(ctx context.Context, refreshTo
 func (s *TokenServiceImpl) GenerateAuthTokens(
 	ctx context.Context,
 	userID string,
+	device *model.DeviceInfo,
 ) (*domain.Token, *domain.Token, error) {
-	accessTokenExpires := time.Now().UTC().Add(s.Conf.JWT.Expire)
-	accessToken, err := s.generateToken(userID, accessTokenExpires, domain.TokenTypeAccess)
+	if err := s.checkPasswordAge(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	if device == nil {
+		device = &model.DeviceInfo{}
+	}
+
+	session, err := s.TokenRepository.CreateSession(ctx, &domain.Session{
+		UserID:     uuid.MustParse(userID),
+		DeviceName: device.DeviceName,
+		UserAgent:  device.UserAgent,
+		IPAddress:  device.IPAddress,
+		LastUsedAt: time.Now().UTC(),
+	})
 	if err != nil {
-		golog.Error("Error generating access token", err)
-		return nil, nil, myerrors.ErrGenerateTokenFailed
+		return nil, nil, err
 	}
 
-	accessTokenDomain := &domain.Token{
-		Token:   accessToken,
-		UserID:  uuid.MustParse(userID),
-		Type:    domain.TokenTypeAccess,
-		Expires: accessTokenExpires,
+	accessTokenDomain, err := s.GenerateAccessToken(ctx, userID, session.ID.String())
+	if err != nil {
+		return nil, nil, err
 	}
 
-	refreshTokenExpires := time.Now().UTC().Add(time.Hour * 24 * time.Duration(s.Conf.JWT.RefreshExpire))
-	refreshToken, err := s.generateToken(userID, refreshTokenExpires, domain.TokenTypeRefresh)
+	refreshTokenDomain, err := s.createRefreshToken(ctx, userID, &session.ID, nil)
 	if err != nil {
-		golog.Error("Error generating refresh token", err)
-		return nil, nil, myerrors.ErrGenerateTokenFailed
+		return nil, nil, err
+	}
+
+	return accessTokenDomain, refreshTokenDomain, nil
+}
+
+func (s *TokenServiceImpl) GenerateAccessToken(ctx context.Context, userID, sessionID string) (*domain.Token, error) {
+	return s.generateAccessToken(ctx, userID, sessionID, nil)
+}
+
+// GenerateClientAuthTokens opens a session for an OAuth client acting on behalf of the user. The access token
+// carries the client and the granted scope, and only the permissions of the user that the scope names; a
+// refresh token is only issued to clients allowed the refresh grant.
+func (s *TokenServiceImpl) GenerateClientAuthTokens(
+	ctx context.Context, userID string, client *domain.OAuthClient, scope string,
+) (*domain.Token, *domain.Token, error) {
+	if err := s.checkPasswordAge(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	session, err := s.TokenRepository.CreateSession(ctx, &domain.Session{
+		UserID:     uuid.MustParse(userID),
+		DeviceName: client.Name,
+		ClientID:   &client.ID,
+		Scope:      scope,
+		LastUsedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accessTokenDomain, err := s.generateAccessToken(ctx, userID, session.ID.String(), clientClaims(session))
+	if err != nil {
+		return nil, nil, err
 	}
 
-	refreshTokenDomain, err := s.saveToken(ctx, refreshToken, userID, domain.TokenTypeRefresh, refreshTokenExpires)
+	if !client.HasGrantType(domain.GrantTypeRefreshToken) {
+		return accessTokenDomain, nil, nil
+	}
+
+	refreshTokenDomain, err := s.createRefreshToken(ctx, userID, &session.ID, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -90,9 +182,62 @@ func (s *TokenServiceImpl) GenerateAuthTokens(
 	return accessTokenDomain, refreshTokenDomain, nil
 }
 
-func (s *TokenServiceImpl) GenerateAccessToken(_ context.Context, userID string) (*domain.Token, error) {
+// GenerateClientAccessToken issues the token of the client credentials grant. It has no user or session
+// and, like access tokens, is not stored.
+func (s *TokenServiceImpl) GenerateClientAccessToken(_ context.Context, clientID, scope string) (*domain.Token, error) {
+	expires := time.Now().UTC().Add(s.Conf.JWT.Expire)
+	clientAccessToken, err := s.generateToken("", expires, domain.TokenTypeClientAccess, jwt.MapClaims{
+		"client_id": clientID,
+		"scope":     scope,
+	})
+	if err != nil {
+		golog.Error("Error generating client access token", err)
+		return nil, myerrors.ErrGenerateTokenFailed
+	}
+
+	return &domain.Token{
+		Token:   clientAccessToken,
+		Type:    domain.TokenTypeClientAccess,
+		Expires: expires,
+	}, nil
+}
+
+// generateAccessToken signs an access token carrying the permissions of the user's roles at the time of issue,
+// so JWTAuth can authorize from the token when jwt.authorize_from_token is set. Users in organizations act in
+// their oldest one until they pick another with the X-Organization-ID header, and get the rights of their role there.
+func (s *TokenServiceImpl) generateAccessToken(
+	ctx context.Context, userID, sessionID string, extraClaims jwt.MapClaims,
+) (*domain.Token, error) {
+	claims := jwt.MapClaims{"session_id": sessionID}
+
+	member, err := s.OrganizationService.DefaultMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var organizationRoles []string
+	if member != nil {
+		claims["organization_id"] = member.OrganizationID.String()
+		organizationRoles = append(organizationRoles, member.Role)
+	}
+
+	permissions, err := s.RoleService.UserRights(ctx, userID, organizationRoles...)
+	if errors.Is(err, myerrors.ErrRoleNotFound) {
+		permissions = []string{}
+	} else if err != nil {
+		return nil, err
+	}
+	// Tokens of OAuth clients only carry the permissions their scope names
+	if scope, ok := extraClaims["scope"].(string); ok {
+		permissions = domain.ScopeRights(permissions, scope)
+	}
+	claims["permissions"] = permissions
+	for key, value := range extraClaims {
+		claims[key] = value
+	}
+
 	accessTokenExpires := time.Now().UTC().Add(s.Conf.JWT.Expire)
-	accessToken, err := s.generateToken(userID, accessTokenExpires, domain.TokenTypeAccess)
+	accessToken, err := s.generateToken(userID, accessTokenExpires, domain.TokenTypeAccess, claims)
 	if err != nil {
 		golog.Error("Error generating access token", err)
 		return nil, myerrors.ErrGenerateTokenFailed
@@ -105,35 +250,68 @@ func (s *TokenServiceImpl) GenerateAccessToken(_ context.Context, userID string)
 		Expires: accessTokenExpires,
 	}
 
+	if sessionID != "" {
+		parsedSessionID := uuid.MustParse(sessionID)
+		accessTokenDomain.SessionID = &parsedSessionID
+	}
+
 	return accessTokenDomain, nil
 }
 
-func (s *TokenServiceImpl) GenerateResetPasswordToken(
-	ctx context.Context, req *model.ForgotPasswordRequest,
-) (*domain.Token, error) {
-	if err := s.Validator.Validate(ctx, req); err != nil {
-		golog.Error("Error validating reset password request", err)
-		return nil, myerrors.ErrInvalidRequest
+// RotateRefreshToken consumes the presented refresh token and issues its successor in the same family.
+// Presenting an already consumed token revokes the whole family, since one of the two holders is an attacker.
+func (s *TokenServiceImpl) RotateRefreshToken(
+	ctx context.Context, refreshToken *domain.Token, device *model.DeviceInfo,
+) (*domain.Token, *domain.Token, error) {
+	if refreshToken.ConsumedAt != nil {
+		return nil, nil, s.revokeReusedToken(ctx, refreshToken, device)
 	}
 
-	user, err := s.UserService.GetUserByEmail(ctx, req.Email)
+	if err := s.checkPasswordAge(ctx, refreshToken.UserID.String()); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.TokenRepository.ConsumeToken(ctx, refreshToken.ID.String(), time.Now().UTC()); err != nil {
+		if errors.Is(err, myerrors.ErrTokenAlreadyConsumed) {
+			return nil, nil, s.revokeReusedToken(ctx, refreshToken, device)
+		}
+		return nil, nil, err
+	}
+
+	userID := refreshToken.UserID.String()
+	sessionID := ""
+	if refreshToken.SessionID != nil {
+		sessionID = refreshToken.SessionID.String()
+		if err := s.TouchSession(ctx, sessionID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	accessTokenDomain, err := s.generateAccessToken(ctx, userID, sessionID, clientClaims(refreshToken.Session))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if !crypto.CheckPasswordHash(req.Password, user.Password) {
-		return nil, myerrors.ErrInvalidPassword
+	refreshTokenDomain, err := s.createRefreshToken(ctx, userID, refreshToken.SessionID, &refreshToken.ID)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	return accessTokenDomain, refreshTokenDomain, nil
+}
+
+// GenerateResetPasswordToken issues the token of the reset password email. The password of the user is
+// checked by AuthService.ForgotPassword.
+func (s *TokenServiceImpl) GenerateResetPasswordToken(ctx context.Context, userID string) (*domain.Token, error) {
 	expires := time.Now().UTC().Add(s.Conf.JWT.ResetPasswordExpire)
-	resetPasswordToken, err := s.generateToken(user.ID.String(), expires, domain.TokenTypeResetPassword)
+	resetPasswordToken, err := s.generateToken(userID, expires, domain.TokenTypeResetPassword, nil)
 	if err != nil {
 		golog.Error("Error signing reset password token", err)
 		return nil, myerrors.ErrGenerateTokenFailed
 	}
 
 	resetPasswordTokenDomain, err := s.saveToken(
-		ctx, resetPasswordToken, user.ID.String(), domain.TokenTypeResetPassword, expires,
+		ctx, resetPasswordToken, userID, domain.TokenTypeResetPassword, expires,
 	)
 	if err != nil {
 		return nil, err
@@ -144,7 +322,7 @@ func (s *TokenServiceImpl) GenerateResetPasswordToken(
 
 func (s *TokenServiceImpl) GenerateVerifyEmailToken(ctx context.Context, userID string) (*domain.Token, error) {
 	expires := time.Now().UTC().Add(s.Conf.JWT.VerifyEmailExpire)
-	verifyEmailToken, err := s.generateToken(userID, expires, domain.TokenTypeVerifyEmail)
+	verifyEmailToken, err := s.generateToken(userID, expires, domain.TokenTypeVerifyEmail, nil)
 	if err != nil {
 		golog.Error("Error generating verify email token", err)
 		return nil, myerrors.ErrGenerateTokenFailed
@@ -158,31 +336,308 @@ func (s *TokenServiceImpl) GenerateVerifyEmailToken(ctx context.Context, userID
 	return verifyEmailTokenDomain, nil
 }
 
-func (s *TokenServiceImpl) generateToken(userID string, expires time.Time, tokenType domain.TokenType) (string, error) {
+// GenerateMFAPendingToken issues the short-lived token that proves the password step of a two-step login.
+// It is stored and consumed by the first code sent with it, so a wrong code means logging in again.
+func (s *TokenServiceImpl) GenerateMFAPendingToken(ctx context.Context, userID string) (*domain.Token, error) {
+	return s.GenerateChallengeToken(
+		ctx, userID, domain.TokenTypeMFAPending, time.Now().UTC().Add(s.Conf.JWT.MFAPendingExpire), nil,
+	)
+}
+
+// GenerateMagicLinkToken issues the single-use login token sent by email. Requesting a new link invalidates the last one.
+func (s *TokenServiceImpl) GenerateMagicLinkToken(
+	ctx context.Context, req *model.MagicLinkRequest,
+) (*domain.Token, error) {
+	if err := s.Validator.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().UTC().Add(s.Conf.JWT.MagicLinkExpire)
+	magicLinkToken, err := s.generateToken(user.ID.String(), expires, domain.TokenTypeMagicLink, nil)
+	if err != nil {
+		golog.Error("Error generating magic link token", err)
+		return nil, myerrors.ErrGenerateTokenFailed
+	}
+
+	return s.saveToken(ctx, magicLinkToken, user.ID.String(), domain.TokenTypeMagicLink, expires)
+}
+
+// GenerateInvitationToken signs the token of an organization invitation. It is not stored in the tokens table,
+// since it belongs to an email address that may have no account yet; the invitation keeps its hash instead.
+func (s *TokenServiceImpl) GenerateInvitationToken(
+	_ context.Context, invitation *domain.OrganizationInvitation,
+) (*domain.Token, error) {
+	expires := time.Now().UTC().Add(s.Conf.JWT.InvitationExpire)
+	invitationToken, err := s.generateToken("", expires, domain.TokenTypeInvitation, jwt.MapClaims{
+		"invitation_id":   invitation.ID.String(),
+		"organization_id": invitation.OrganizationID.String(),
+		"email":           invitation.Email,
+	})
+	if err != nil {
+		golog.Error("Error generating invitation token", err)
+		return nil, myerrors.ErrGenerateTokenFailed
+	}
+
+	return &domain.Token{
+		Token:     invitationToken,
+		TokenHash: s.hashToken(invitationToken),
+		Type:      domain.TokenTypeInvitation,
+		Expires:   expires,
+	}, nil
+}
+
+// VerifyInvitationToken checks the signature, type and expiry of an invitation token and returns the invitation ID.
+// Whether the invitation is still pending is up to the caller.
+func (s *TokenServiceImpl) VerifyInvitationToken(_ context.Context, invitationToken string) (string, error) {
+	claims, err := token.ParseToken(invitationToken, s.Keys, domain.TokenTypeInvitation.String())
+	if err != nil {
+		return "", err
+	}
+
+	invitationID, ok := claims["invitation_id"].(string)
+	if !ok {
+		return "", myerrors.ErrInvalidTokenClaims
+	}
+
+	return invitationID, nil
+}
+
+// GenerateChallengeToken stores a short-lived token carrying the state of a multi-step ceremony in its claims.
+// Starting a new ceremony of the same type replaces the previous one, except for authorization codes.
+func (s *TokenServiceImpl) GenerateChallengeToken(
+	ctx context.Context, userID string, tokenType domain.TokenType, expires time.Time, claims jwt.MapClaims,
+) (*domain.Token, error) {
+	challengeToken, err := s.generateToken(userID, expires, tokenType, claims)
+	if err != nil {
+		golog.Error("Error generating challenge token", err)
+		return nil, myerrors.ErrGenerateTokenFailed
+	}
+
+	return s.saveToken(ctx, challengeToken, userID, tokenType, expires)
+}
+
+// ConsumeChallengeToken verifies a stored single-use token and marks it consumed so it cannot be replayed.
+func (s *TokenServiceImpl) ConsumeChallengeToken(
+	ctx context.Context, challengeToken string, tokenType domain.TokenType,
+) (jwt.MapClaims, error) {
+	claims, err := token.ParseToken(challengeToken, s.Keys, tokenType.String())
+	if err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, myerrors.ErrInvalidTokenUserID
+	}
+
+	tokenDoc, err := s.TokenRepository.GetByTokenHashAndUserID(ctx, s.hashToken(challengeToken), userID)
+	if err != nil {
+		if errors.Is(err, myerrors.ErrTokenNotFound) {
+			return nil, myerrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if err = s.TokenRepository.ConsumeToken(ctx, tokenDoc.ID.String(), time.Now().UTC()); err != nil {
+		if errors.Is(err, myerrors.ErrTokenAlreadyConsumed) {
+			return nil, myerrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (s *TokenServiceImpl) GetSessions(ctx context.Context, userID string) ([]domain.Session, error) {
+	return s.TokenRepository.GetSessionsByUserID(ctx, userID)
+}
+
+func (s *TokenServiceImpl) TouchSession(ctx context.Context, sessionID string) error {
+	return s.TokenRepository.TouchSession(ctx, sessionID, time.Now().UTC())
+}
+
+func (s *TokenServiceImpl) DeleteSession(ctx context.Context, userID, sessionID string) error {
+	if err := s.TokenRepository.DeleteSession(ctx, sessionID, userID); err != nil {
+		return err
+	}
+
+	return s.Revocation.RevokeSession(ctx, sessionID)
+}
+
+func (s *TokenServiceImpl) DeleteOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	if currentSessionID == "" {
+		return myerrors.ErrInvalidTokenSession
+	}
+
+	sessions, err := s.TokenRepository.GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.TokenRepository.DeleteOtherSessions(ctx, userID, currentSessionID); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID.String() == currentSessionID {
+			continue
+		}
+		if err = s.Revocation.RevokeSession(ctx, session.ID.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevokeAccessToken denies a single access token until it expires. Tokens that are already invalid are ignored.
+func (s *TokenServiceImpl) RevokeAccessToken(ctx context.Context, accessToken string) error {
+	claims, err := token.ParseToken(accessToken, s.Keys, domain.TokenTypeAccess.String())
+	if err != nil {
+		return nil
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return nil
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil
+	}
+
+	return s.Revocation.RevokeToken(ctx, jti, expiresAt.Time)
+}
+
+func (s *TokenServiceImpl) createRefreshToken(
+	ctx context.Context, userID string, sessionID, parentID *uuid.UUID,
+) (*domain.Token, error) {
+	expires := time.Now().UTC().Add(time.Hour * 24 * time.Duration(s.Conf.JWT.RefreshExpire))
+	refreshToken, err := s.generateToken(userID, expires, domain.TokenTypeRefresh, nil)
+	if err != nil {
+		golog.Error("Error generating refresh token", err)
+		return nil, myerrors.ErrGenerateTokenFailed
+	}
+
+	return s.TokenRepository.Create(ctx, &domain.Token{
+		Token:     refreshToken,
+		TokenHash: s.hashToken(refreshToken),
+		UserID:    uuid.MustParse(userID),
+		SessionID: sessionID,
+		ParentID:  parentID,
+		Type:      domain.TokenTypeRefresh,
+		Expires:   expires,
+	})
+}
+
+// checkPasswordAge refuses auth tokens to a user whose password is older than password.max_age, whatever the
+// way they logged in. Only a password login answers with a change token, so the user has to log in that way.
+func (s *TokenServiceImpl) checkPasswordAge(ctx context.Context, userID string) error {
+	user, err := s.UserService.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.PasswordExpired(s.Conf.Password.MaxAge, s.Conf.Password.ExpireRoles, time.Now()) {
+		return myerrors.ErrPasswordExpired
+	}
+
+	return nil
+}
+
+func (s *TokenServiceImpl) revokeReusedToken(
+	ctx context.Context, refreshToken *domain.Token, device *model.DeviceInfo,
+) error {
+	userID := refreshToken.UserID.String()
+	golog.Info(fmt.Sprintf("Refresh token reuse detected for user %s, revoking token family", userID))
+
+	// Refresh tokens issued before sessions existed have no family, so revoke all of them
+	var err error
+	if refreshToken.SessionID == nil {
+		if err = s.TokenRepository.Delete(ctx, domain.TokenTypeRefresh, userID); err == nil {
+			err = s.Revocation.RevokeUser(ctx, userID)
+		}
+	} else {
+		if err = s.TokenRepository.RevokeTokenFamily(ctx, refreshToken.SessionID.String()); err == nil {
+			err = s.Revocation.RevokeSession(ctx, refreshToken.SessionID.String())
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if device == nil {
+		device = &model.DeviceInfo{}
+	}
+
+	// The family is already revoked, so a failure to audit should not change the response
+	if _, errEvent := s.SecurityEventRepository.Create(ctx, &domain.SecurityEvent{
+		UserID:    refreshToken.UserID,
+		Type:      domain.SecurityEventRefreshTokenReuse,
+		IPAddress: device.IPAddress,
+		UserAgent: device.UserAgent,
+		Detail:    fmt.Sprintf("consumed refresh token %s was presented again", refreshToken.ID),
+	}); errEvent != nil {
+		golog.Error("Error recording refresh token reuse", errEvent)
+	}
+
+	return myerrors.ErrRefreshTokenReused
+}
+
+func (s *TokenServiceImpl) generateToken(
+	userID string, expires time.Time, tokenType domain.TokenType, extraClaims jwt.MapClaims,
+) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id":    userID,
-		"issued_at":  time.Now().Unix(),
+		"issued_at":  now.Unix(),
 		"expires_at": expires.Unix(),
 		"token_type": tokenType.String(),
+		// Tokens signed within the same second would otherwise be identical, which breaks rotation
+		"jti": uuid.NewString(),
+		// Registered claims so other services verifying against the JWKS enforce expiry
+		"iat": now.Unix(),
+		"exp": expires.Unix(),
+	}
+	if userID == "" {
+		delete(claims, "user_id")
+	}
+	for key, value := range extraClaims {
+		claims[key] = value
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString([]byte(s.Conf.JWT.Secret))
+	return s.Keys.Sign(claims)
 }
 
+// saveToken stores a token of the user in place of the previous one of its type. Authorization codes are
+// kept side by side, since a user may authorize several clients at once; they are single-use and
+// short-lived, so only the expired ones are removed.
 func (s *TokenServiceImpl) saveToken(
 	ctx context.Context, token, userID string, tokenType domain.TokenType, expires time.Time,
 ) (*domain.Token, error) {
-	if err := s.TokenRepository.Delete(ctx, tokenType, userID); err != nil {
+	var err error
+	if tokenType == domain.TokenTypeAuthorizationCode {
+		err = s.TokenRepository.DeleteExpired(ctx, tokenType, userID, time.Now().UTC())
+	} else {
+		err = s.TokenRepository.Delete(ctx, tokenType, userID)
+	}
+	if err != nil {
 		golog.Error("Error deleting token", err)
 		return nil, myerrors.ErrDeleteTokenFailed
 	}
 
 	tokenDoc := &domain.Token{
-		Token:   token,
-		UserID:  uuid.MustParse(userID),
-		Type:    tokenType,
-		Expires: expires,
+		Token:     token,
+		TokenHash: s.hashToken(token),
+		UserID:    uuid.MustParse(userID),
+		Type:      tokenType,
+		Expires:   expires,
 	}
 
 	savedToken, err := s.TokenRepository.Create(ctx, tokenDoc)
@@ -192,3 +647,20 @@ func (s *TokenServiceImpl) saveToken(
 
 	return savedToken, nil
 }
+
+// clientClaims keeps the client and scope of an OAuth session in every access token issued for it.
+func clientClaims(session *domain.Session) jwt.MapClaims {
+	if session == nil || session.ClientID == nil {
+		return nil
+	}
+
+	return jwt.MapClaims{
+		"client_id": session.ClientID.String(),
+		"scope":     session.Scope,
+	}
+}
+
+// hashToken keys the stored token hash with jwt.token_hash_key, falling back to the signing secret.
+func (s *TokenServiceImpl) hashToken(token string) string {
+	return crypto.HashToken(token, s.Conf.JWT.HashKey())
+}
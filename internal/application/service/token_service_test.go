@@ -7,6 +7,7 @@ import (
 	"app/internal/domain"
 	"app/internal/domain/myerrors"
 	"app/internal/pkg/crypto"
+	pkgToken "app/internal/pkg/token"
 	mockRepository "app/internal/adapter/database/repository/mocks"
 	mockValidator "app/internal/pkg/validator/mocks"
 	"context"
@@ -23,11 +24,16 @@ type tokenServiceTestSuite struct {
 	suite.Suite
 	mockCtrl       *gomock.Controller
 	mockTokenRepo  *mockRepository.MockTokenRepository
+	mockEventRepo  *mockRepository.MockSecurityEventRepository
+	mockRevocation *mocks.MockRevocationService
 	mockUserSvc    *mocks.MockUserService
+	mockRoleSvc    *mocks.MockRoleService
+	mockOrgSvc     *mocks.MockOrganizationService
 	mockValidator  *mockValidator.MockValidator
 	tokenService   *TokenServiceImpl
 	ctx            context.Context
 	testUUID       uuid.UUID
+	testUser       *domain.User
 	testSecret     string
 	hashedPass     string
 }
@@ -39,23 +45,38 @@ func TestTokenService(t *testing.T) {
 func (s *tokenServiceTestSuite) SetupTest() {
 	s.mockCtrl = gomock.NewController(s.T())
 	s.mockTokenRepo = mockRepository.NewMockTokenRepository(s.mockCtrl)
+	s.mockEventRepo = mockRepository.NewMockSecurityEventRepository(s.mockCtrl)
+	s.mockRevocation = mocks.NewMockRevocationService(s.mockCtrl)
 	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockRoleSvc = mocks.NewMockRoleService(s.mockCtrl)
+	s.mockRoleSvc.EXPECT().UserRights(gomock.Any(), gomock.Any()).Return([]string{"getUsers"}, nil).AnyTimes()
+	s.mockOrgSvc = mocks.NewMockOrganizationService(s.mockCtrl)
+	s.mockOrgSvc.EXPECT().DefaultMembership(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
 
 	s.testSecret = "test-secret-key-for-unit-testing"
-	s.tokenService = &TokenServiceImpl{
-		Conf: &config.Config{
-			JWT: config.JWTConfig{
-				Secret:              s.testSecret,
-				Expire:              30 * time.Minute,
-				RefreshExpire:       7,
-				ResetPasswordExpire: 15 * time.Minute,
-				VerifyEmailExpire:   24 * time.Hour,
-			},
+	conf := &config.Config{
+		JWT: config.JWTConfig{
+			Secret:              s.testSecret,
+			Expire:              30 * time.Minute,
+			RefreshExpire:       7,
+			ResetPasswordExpire: 15 * time.Minute,
+			VerifyEmailExpire:   24 * time.Hour,
 		},
-		TokenRepository: s.mockTokenRepo,
-		UserService:     s.mockUserSvc,
-		Validator:       s.mockValidator,
+	}
+	keys := &pkgToken.KeyManagerImpl{Conf: conf}
+	s.Require().NoError(keys.Startup())
+
+	s.tokenService = &TokenServiceImpl{
+		Conf:                    conf,
+		Keys:                    keys,
+		TokenRepository:         s.mockTokenRepo,
+		SecurityEventRepository: s.mockEventRepo,
+		Revocation:              s.mockRevocation,
+		UserService:             s.mockUserSvc,
+		RoleService:             s.mockRoleSvc,
+		OrganizationService:     s.mockOrgSvc,
+		Validator:               s.mockValidator,
 	}
 
 	s.ctx = context.Background()
@@ -64,6 +85,15 @@ func (s *tokenServiceTestSuite) SetupTest() {
 	var err error
 	s.hashedPass, err = crypto.HashPassword("password123")
 	s.Require().NoError(err)
+
+	// Tokens are only issued while the password of the user has not expired
+	s.testUser = &domain.User{
+		ID:        s.testUUID,
+		Password:  s.hashedPass,
+		Roles:     domain.NewUserRoles(domain.RoleUser),
+		CreatedAt: time.Now().Add(-48 * time.Hour),
+	}
+	s.mockUserSvc.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(s.testUser, nil).AnyTimes()
 }
 
 func (s *tokenServiceTestSuite) TearDownTest() {
@@ -132,6 +162,10 @@ func (s *tokenServiceTestSuite) TestDeleteAllToken_Success() {
 		DeleteAll(s.ctx, userID).
 		Return(nil)
 
+	s.mockRevocation.EXPECT().
+		RevokeUser(s.ctx, userID).
+		Return(nil)
+
 	err := s.tokenService.DeleteAllToken(s.ctx, userID)
 
 	s.NoError(err)
@@ -150,22 +184,61 @@ func (s *tokenServiceTestSuite) TestDeleteAllToken_RepositoryError() {
 	s.Equal(myerrors.ErrDeleteAllTokenFailed, err)
 }
 
+func (s *tokenServiceTestSuite) TestDeleteAllToken_RevokeError() {
+	userID := s.testUUID.String()
+
+	s.mockTokenRepo.EXPECT().
+		DeleteAll(s.ctx, userID).
+		Return(nil)
+
+	s.mockRevocation.EXPECT().
+		RevokeUser(s.ctx, userID).
+		Return(myerrors.ErrRevokeTokenFailed)
+
+	err := s.tokenService.DeleteAllToken(s.ctx, userID)
+
+	s.ErrorIs(err, myerrors.ErrRevokeTokenFailed)
+}
+
 // ==================== GetTokenByRefreshToken Tests ====================
 
 func (s *tokenServiceTestSuite) TestGetTokenByRefreshToken_Success() {
 	userID := s.testUUID.String()
 	refreshToken := s.createTestJWTToken(userID, domain.TokenTypeRefresh)
 	testToken := s.createTestToken(domain.TokenTypeRefresh)
-	testToken.Token = refreshToken
 
 	s.mockTokenRepo.EXPECT().
-		GetByTokenAndUserID(s.ctx, refreshToken, userID).
+		GetByTokenHashAndUserID(s.ctx, crypto.HashToken(refreshToken, s.testSecret), userID).
 		Return(testToken, nil)
 
 	result, err := s.tokenService.GetTokenByRefreshToken(s.ctx, refreshToken)
 
 	s.NoError(err)
-	s.Equal(testToken, result)
+	s.Equal(testToken.ID, result.ID)
+	s.Equal(refreshToken, result.Token)
+}
+
+func (s *tokenServiceTestSuite) TestGetTokenByRefreshToken_UsesTokenHashKey() {
+	userID := s.testUUID.String()
+	refreshToken := s.createTestJWTToken(userID, domain.TokenTypeRefresh)
+	s.tokenService.Conf.JWT.TokenHashKey = "separate-hash-key"
+
+	s.mockTokenRepo.EXPECT().
+		GetByTokenHashAndUserID(s.ctx, crypto.HashToken(refreshToken, "separate-hash-key"), userID).
+		Return(s.createTestToken(domain.TokenTypeRefresh), nil)
+
+	_, err := s.tokenService.GetTokenByRefreshToken(s.ctx, refreshToken)
+
+	s.NoError(err)
+}
+
+func (s *tokenServiceTestSuite) TestStartup_RequiresHashKey() {
+	s.tokenService.Conf.JWT.Secret = ""
+	s.tokenService.Conf.JWT.TokenHashKey = ""
+	s.Error(s.tokenService.Startup())
+
+	s.tokenService.Conf.JWT.TokenHashKey = "separate-hash-key"
+	s.NoError(s.tokenService.Startup())
 }
 
 func (s *tokenServiceTestSuite) TestGetTokenByRefreshToken_InvalidToken() {
@@ -195,7 +268,7 @@ func (s *tokenServiceTestSuite) TestGetTokenByRefreshToken_TokenNotFound() {
 	refreshToken := s.createTestJWTToken(userID, domain.TokenTypeRefresh)
 
 	s.mockTokenRepo.EXPECT().
-		GetByTokenAndUserID(s.ctx, refreshToken, userID).
+		GetByTokenHashAndUserID(s.ctx, crypto.HashToken(refreshToken, s.testSecret), userID).
 		Return(nil, myerrors.ErrTokenNotFound)
 
 	result, err := s.tokenService.GetTokenByRefreshToken(s.ctx, refreshToken)
@@ -210,7 +283,7 @@ func (s *tokenServiceTestSuite) TestGetTokenByRefreshToken_RepositoryError() {
 	refreshToken := s.createTestJWTToken(userID, domain.TokenTypeRefresh)
 
 	s.mockTokenRepo.EXPECT().
-		GetByTokenAndUserID(s.ctx, refreshToken, userID).
+		GetByTokenHashAndUserID(s.ctx, crypto.HashToken(refreshToken, s.testSecret), userID).
 		Return(nil, myerrors.ErrGetTokenByUserIDFailed)
 
 	result, err := s.tokenService.GetTokenByRefreshToken(s.ctx, refreshToken)
@@ -224,10 +297,18 @@ func (s *tokenServiceTestSuite) TestGetTokenByRefreshToken_RepositoryError() {
 
 func (s *tokenServiceTestSuite) TestGenerateAuthTokens_Success() {
 	userID := s.testUUID.String()
+	sessionID := uuid.Must(uuid.NewV7())
+	device := &model.DeviceInfo{DeviceName: "Pixel 9", UserAgent: "test-agent", IPAddress: "203.0.113.10"}
 
 	s.mockTokenRepo.EXPECT().
-		Delete(s.ctx, domain.TokenTypeRefresh, userID).
-		Return(nil)
+		CreateSession(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+			s.Equal("Pixel 9", session.DeviceName)
+			s.Equal("test-agent", session.UserAgent)
+			s.Equal("203.0.113.10", session.IPAddress)
+			session.ID = sessionID
+			return session, nil
+		})
 
 	s.mockTokenRepo.EXPECT().
 		Create(s.ctx, gomock.Any()).
@@ -236,7 +317,7 @@ func (s *tokenServiceTestSuite) TestGenerateAuthTokens_Success() {
 			return token, nil
 		})
 
-	accessToken, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID)
+	accessToken, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID, device)
 
 	s.NoError(err)
 	s.NotNil(accessToken)
@@ -245,19 +326,85 @@ func (s *tokenServiceTestSuite) TestGenerateAuthTokens_Success() {
 	s.Equal(domain.TokenTypeRefresh, refreshToken.Type)
 	s.Equal(uuid.MustParse(userID), accessToken.UserID)
 	s.Equal(uuid.MustParse(userID), refreshToken.UserID)
+	s.Equal(sessionID, *accessToken.SessionID)
+	s.Equal(sessionID, *refreshToken.SessionID)
 }
 
-func (s *tokenServiceTestSuite) TestGenerateAuthTokens_DeleteOldTokenError() {
+func (s *tokenServiceTestSuite) TestGenerateAuthTokens_PasswordExpired() {
+	// Magic links, passkeys, OpenID Connect, MFA and device logins all get their tokens here
+	s.tokenService.Conf.Password.MaxAge = 24 * time.Hour
+	s.mockTokenRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	accessToken, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, s.testUUID.String(), nil)
+
+	s.ErrorIs(err, myerrors.ErrPasswordExpired)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
+}
+
+func (s *tokenServiceTestSuite) TestGenerateAuthTokens_PasswordChanged() {
+	s.tokenService.Conf.Password.MaxAge = 24 * time.Hour
+	changedAt := time.Now().Add(-time.Hour)
+	s.testUser.PasswordChangedAt = &changedAt
+
+	s.mockTokenRepo.EXPECT().
+		CreateSession(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+			session.ID = uuid.Must(uuid.NewV7())
+			return session, nil
+		})
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			return token, nil
+		})
+
+	_, _, err := s.tokenService.GenerateAuthTokens(s.ctx, s.testUUID.String(), nil)
+
+	s.NoError(err)
+}
+
+func (s *tokenServiceTestSuite) TestGenerateAuthTokens_KeepsOtherSessions() {
 	userID := s.testUUID.String()
 
+	// Logging in again must never delete the tokens of other devices
+	s.mockTokenRepo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
 	s.mockTokenRepo.EXPECT().
-		Delete(s.ctx, domain.TokenTypeRefresh, userID).
-		Return(myerrors.ErrDeleteTokenFailed)
+		CreateSession(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+			session.ID = uuid.Must(uuid.NewV7())
+			return session, nil
+		}).
+		Times(2)
 
-	accessToken, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID)
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			token.ID = uuid.Must(uuid.NewV7())
+			return token, nil
+		}).
+		Times(2)
+
+	_, laptopToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID, &model.DeviceInfo{DeviceName: "laptop"})
+	s.Require().NoError(err)
+	_, phoneToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID, &model.DeviceInfo{DeviceName: "phone"})
+	s.Require().NoError(err)
+
+	s.NotEqual(*laptopToken.SessionID, *phoneToken.SessionID)
+}
+
+func (s *tokenServiceTestSuite) TestGenerateAuthTokens_CreateSessionError() {
+	userID := s.testUUID.String()
+
+	s.mockTokenRepo.EXPECT().
+		CreateSession(s.ctx, gomock.Any()).
+		Return(nil, myerrors.ErrCreateSessionFailed)
+
+	accessToken, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID, nil)
 
 	s.Error(err)
-	s.Equal(myerrors.ErrDeleteTokenFailed, err)
+	s.Equal(myerrors.ErrCreateSessionFailed, err)
 	s.Nil(accessToken)
 	s.Nil(refreshToken)
 }
@@ -266,14 +413,17 @@ func (s *tokenServiceTestSuite) TestGenerateAuthTokens_CreateTokenError() {
 	userID := s.testUUID.String()
 
 	s.mockTokenRepo.EXPECT().
-		Delete(s.ctx, domain.TokenTypeRefresh, userID).
-		Return(nil)
+		CreateSession(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+			session.ID = uuid.Must(uuid.NewV7())
+			return session, nil
+		})
 
 	s.mockTokenRepo.EXPECT().
 		Create(s.ctx, gomock.Any()).
 		Return(nil, myerrors.ErrSaveTokenFailed)
 
-	accessToken, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID)
+	accessToken, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID, nil)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrSaveTokenFailed, err)
@@ -286,7 +436,7 @@ func (s *tokenServiceTestSuite) TestGenerateAuthTokens_CreateTokenError() {
 func (s *tokenServiceTestSuite) TestGenerateAccessToken_Success() {
 	userID := s.testUUID.String()
 
-	accessToken, err := s.tokenService.GenerateAccessToken(s.ctx, userID)
+	accessToken, err := s.tokenService.GenerateAccessToken(s.ctx, userID, "")
 
 	s.NoError(err)
 	s.NotNil(accessToken)
@@ -295,148 +445,164 @@ func (s *tokenServiceTestSuite) TestGenerateAccessToken_Success() {
 	s.NotEmpty(accessToken.Token)
 }
 
-// ==================== GenerateResetPasswordToken Tests ====================
+func (s *tokenServiceTestSuite) TestGenerateAccessToken_CarriesPermissions() {
+	accessToken, err := s.tokenService.GenerateAccessToken(s.ctx, s.testUUID.String(), "")
+	s.Require().NoError(err)
 
-func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_Success() {
-	req := &model.ForgotPasswordRequest{
-		Email:    "test@example.com",
-		Password: "password123",
-	}
+	claims, err := pkgToken.ParseToken(accessToken.Token, s.tokenService.Keys, domain.TokenTypeAccess.String())
+	s.Require().NoError(err)
+	s.Equal([]any{"getUsers"}, claims["permissions"])
+	s.NotContains(claims, "organization_id")
+}
 
-	testUser := &domain.User{
-		ID:            s.testUUID,
-		Name:          "Test User",
-		Email:         "test@example.com",
-		Password:      s.hashedPass,
-		Role:          "user",
-		VerifiedEmail: true,
-	}
+func (s *tokenServiceTestSuite) TestGenerateAccessToken_DefaultOrganization() {
+	userID := s.testUUID.String()
+	member := &domain.OrganizationMember{OrganizationID: uuid.Must(uuid.NewV7()), UserID: s.testUUID, Role: "org-admin"}
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(nil)
+	mockOrgSvc := mocks.NewMockOrganizationService(s.mockCtrl)
+	mockOrgSvc.EXPECT().DefaultMembership(s.ctx, userID).Return(member, nil)
+	s.mockRoleSvc.EXPECT().UserRights(s.ctx, userID, "org-admin").Return([]string{"getUsers", "manageMembers"}, nil)
+	s.tokenService.OrganizationService = mockOrgSvc
 
-	s.mockUserSvc.EXPECT().
-		GetUserByEmail(s.ctx, req.Email).
-		Return(testUser, nil)
+	accessToken, err := s.tokenService.GenerateAccessToken(s.ctx, userID, "")
+	s.Require().NoError(err)
 
-	s.mockTokenRepo.EXPECT().
-		Delete(s.ctx, domain.TokenTypeResetPassword, testUser.ID.String()).
-		Return(nil)
+	claims, err := pkgToken.ParseToken(accessToken.Token, s.tokenService.Keys, domain.TokenTypeAccess.String())
+	s.Require().NoError(err)
+	s.Equal(member.OrganizationID.String(), claims["organization_id"])
+	s.Equal([]any{"getUsers", "manageMembers"}, claims["permissions"])
+}
+
+// ==================== Client Token Tests ====================
 
+func (s *tokenServiceTestSuite) TestGenerateClientAuthTokens_Success() {
+	userID := s.testUUID.String()
+	sessionID := uuid.Must(uuid.NewV7())
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7()), Name: "Partner", GrantTypes: "authorization_code,refresh_token"}
+
+	s.mockTokenRepo.EXPECT().
+		CreateSession(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+			s.Equal("Partner", session.DeviceName)
+			s.Equal(&client.ID, session.ClientID)
+			s.Equal("profile", session.Scope)
+			session.ID = sessionID
+			return session, nil
+		})
 	s.mockTokenRepo.EXPECT().
 		Create(s.ctx, gomock.Any()).
 		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
-			token.ID = s.testUUID
 			return token, nil
 		})
 
-	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, req)
+	accessToken, refreshToken, err := s.tokenService.GenerateClientAuthTokens(s.ctx, userID, client, "profile")
 
-	s.NoError(err)
-	s.NotNil(result)
-	s.Equal(domain.TokenTypeResetPassword, result.Type)
-	s.Equal(testUser.ID, result.UserID)
-}
+	s.Require().NoError(err)
+	s.Require().NotNil(refreshToken)
+	s.Equal(sessionID, *refreshToken.SessionID)
 
-func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_ValidationError() {
-	req := &model.ForgotPasswordRequest{
-		Email:    "invalid-email",
-		Password: "",
-	}
+	claims, err := pkgToken.ParseToken(accessToken.Token, s.tokenService.Keys, domain.TokenTypeAccess.String())
+	s.Require().NoError(err)
+	s.Equal(client.ID.String(), claims["client_id"])
+	s.Equal("profile", claims["scope"])
+	s.Equal(sessionID.String(), claims["session_id"])
+	s.Empty(claims["permissions"])
+}
 
-	validationErr := myerrors.ErrInvalidRequest
+func (s *tokenServiceTestSuite) TestGenerateClientAuthTokens_ScopeGrantsRights() {
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7()), Name: "Partner", GrantTypes: "authorization_code"}
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(validationErr)
+	s.mockTokenRepo.EXPECT().
+		CreateSession(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+			session.ID = uuid.Must(uuid.NewV7())
+			return session, nil
+		})
 
-	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, req)
+	accessToken, _, err := s.tokenService.GenerateClientAuthTokens(s.ctx, s.testUUID.String(), client, "profile getUsers")
 
-	s.Error(err)
-	s.Equal(myerrors.ErrInvalidRequest, err)
-	s.Nil(result)
+	s.Require().NoError(err)
+	claims, err := pkgToken.ParseToken(accessToken.Token, s.tokenService.Keys, domain.TokenTypeAccess.String())
+	s.Require().NoError(err)
+	s.Equal([]any{"getUsers"}, claims["permissions"])
 }
 
-func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_UserNotFound() {
-	req := &model.ForgotPasswordRequest{
-		Email:    "nonexistent@example.com",
-		Password: "password123",
-	}
+func (s *tokenServiceTestSuite) TestGenerateClientAuthTokens_PasswordExpired() {
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7()), Name: "Partner", GrantTypes: "authorization_code"}
+	s.tokenService.Conf.Password.MaxAge = 24 * time.Hour
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(nil)
+	_, _, err := s.tokenService.GenerateClientAuthTokens(s.ctx, s.testUUID.String(), client, "profile")
 
-	s.mockUserSvc.EXPECT().
-		GetUserByEmail(s.ctx, req.Email).
-		Return(nil, myerrors.ErrUserNotFound)
+	s.ErrorIs(err, myerrors.ErrPasswordExpired)
+}
 
-	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, req)
+func (s *tokenServiceTestSuite) TestGenerateClientAuthTokens_NoRefreshGrant() {
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV7()), Name: "Partner", GrantTypes: "authorization_code"}
 
-	s.Error(err)
-	s.Equal(myerrors.ErrUserNotFound, err)
-	s.Nil(result)
-}
+	s.mockTokenRepo.EXPECT().
+		CreateSession(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+			session.ID = uuid.Must(uuid.NewV7())
+			return session, nil
+		})
 
-func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_InvalidPassword() {
-	req := &model.ForgotPasswordRequest{
-		Email:    "test@example.com",
-		Password: "wrongpassword",
-	}
+	accessToken, refreshToken, err := s.tokenService.GenerateClientAuthTokens(s.ctx, s.testUUID.String(), client, "")
 
-	testUser := &domain.User{
-		ID:            s.testUUID,
-		Name:          "Test User",
-		Email:         "test@example.com",
-		Password:      s.hashedPass,
-		Role:          "user",
-		VerifiedEmail: true,
-	}
+	s.Require().NoError(err)
+	s.NotNil(accessToken)
+	s.Nil(refreshToken)
+}
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(nil)
+func (s *tokenServiceTestSuite) TestGenerateClientAccessToken_Success() {
+	clientID := uuid.Must(uuid.NewV7()).String()
 
-	s.mockUserSvc.EXPECT().
-		GetUserByEmail(s.ctx, req.Email).
-		Return(testUser, nil)
+	accessToken, err := s.tokenService.GenerateClientAccessToken(s.ctx, clientID, "reports")
 
-	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, req)
+	s.Require().NoError(err)
+	s.Equal(domain.TokenTypeClientAccess, accessToken.Type)
 
+	claims, err := pkgToken.ParseToken(accessToken.Token, s.tokenService.Keys, domain.TokenTypeClientAccess.String())
+	s.Require().NoError(err)
+	s.Equal(clientID, claims["client_id"])
+	s.Equal("reports", claims["scope"])
+
+	// JWTAuth only accepts user access tokens
+	_, err = pkgToken.ParseToken(accessToken.Token, s.tokenService.Keys, domain.TokenTypeAccess.String())
 	s.Error(err)
-	s.Equal(myerrors.ErrInvalidPassword, err)
-	s.Nil(result)
 }
 
-func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_DeleteTokenError() {
-	req := &model.ForgotPasswordRequest{
-		Email:    "test@example.com",
-		Password: "password123",
-	}
+// ==================== GenerateResetPasswordToken Tests ====================
 
-	testUser := &domain.User{
-		ID:            s.testUUID,
-		Name:          "Test User",
-		Email:         "test@example.com",
-		Password:      s.hashedPass,
-		Role:          "user",
-		VerifiedEmail: true,
-	}
+func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_Success() {
+	userID := s.testUUID.String()
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
+	s.mockTokenRepo.EXPECT().
+		Delete(s.ctx, domain.TokenTypeResetPassword, userID).
 		Return(nil)
 
-	s.mockUserSvc.EXPECT().
-		GetUserByEmail(s.ctx, req.Email).
-		Return(testUser, nil)
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			token.ID = s.testUUID
+			return token, nil
+		})
+
+	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, userID)
+
+	s.NoError(err)
+	s.NotNil(result)
+	s.Equal(domain.TokenTypeResetPassword, result.Type)
+	s.Equal(s.testUUID, result.UserID)
+}
+
+func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_DeleteTokenError() {
+	userID := s.testUUID.String()
 
 	s.mockTokenRepo.EXPECT().
-		Delete(s.ctx, domain.TokenTypeResetPassword, testUser.ID.String()).
+		Delete(s.ctx, domain.TokenTypeResetPassword, userID).
 		Return(myerrors.ErrDeleteTokenFailed)
 
-	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, req)
+	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, userID)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrDeleteTokenFailed, err)
@@ -444,37 +610,17 @@ func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_DeleteTokenError(
 }
 
 func (s *tokenServiceTestSuite) TestGenerateResetPasswordToken_CreateTokenError() {
-	req := &model.ForgotPasswordRequest{
-		Email:    "test@example.com",
-		Password: "password123",
-	}
-
-	testUser := &domain.User{
-		ID:            s.testUUID,
-		Name:          "Test User",
-		Email:         "test@example.com",
-		Password:      s.hashedPass,
-		Role:          "user",
-		VerifiedEmail: true,
-	}
-
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(nil)
-
-	s.mockUserSvc.EXPECT().
-		GetUserByEmail(s.ctx, req.Email).
-		Return(testUser, nil)
+	userID := s.testUUID.String()
 
 	s.mockTokenRepo.EXPECT().
-		Delete(s.ctx, domain.TokenTypeResetPassword, testUser.ID.String()).
+		Delete(s.ctx, domain.TokenTypeResetPassword, userID).
 		Return(nil)
 
 	s.mockTokenRepo.EXPECT().
 		Create(s.ctx, gomock.Any()).
 		Return(nil, myerrors.ErrSaveTokenFailed)
 
-	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, req)
+	result, err := s.tokenService.GenerateResetPasswordToken(s.ctx, userID)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrSaveTokenFailed, err)
@@ -503,6 +649,7 @@ func (s *tokenServiceTestSuite) TestGenerateVerifyEmailToken_Success() {
 	s.NotNil(result)
 	s.Equal(domain.TokenTypeVerifyEmail, result.Type)
 	s.Equal(uuid.MustParse(userID), result.UserID)
+	s.Equal(crypto.HashToken(result.Token, s.testSecret), result.TokenHash)
 }
 
 func (s *tokenServiceTestSuite) TestGenerateVerifyEmailToken_DeleteTokenError() {
@@ -542,7 +689,9 @@ func (s *tokenServiceTestSuite) TestGenerateVerifyEmailToken_CreateTokenError()
 func (s *tokenServiceTestSuite) TestGenerateToken_TokenContainsCorrectClaims() {
 	userID := s.testUUID.String()
 
-	accessToken, err := s.tokenService.GenerateAccessToken(s.ctx, userID)
+	sessionID := uuid.Must(uuid.NewV7()).String()
+
+	accessToken, err := s.tokenService.GenerateAccessToken(s.ctx, userID, sessionID)
 
 	s.NoError(err)
 
@@ -557,6 +706,7 @@ func (s *tokenServiceTestSuite) TestGenerateToken_TokenContainsCorrectClaims() {
 	s.True(ok)
 	s.Equal(userID, claims["user_id"])
 	s.Equal(domain.TokenTypeAccess.String(), claims["token_type"])
+	s.Equal(sessionID, claims["session_id"])
 }
 
 // ==================== saveToken Tests (via public methods) ====================
@@ -568,7 +718,7 @@ func (s *tokenServiceTestSuite) TestSaveToken_DeletesOldTokenFirst() {
 	callOrder := []string{}
 
 	s.mockTokenRepo.EXPECT().
-		Delete(s.ctx, domain.TokenTypeRefresh, userID).
+		Delete(s.ctx, domain.TokenTypeVerifyEmail, userID).
 		DoAndReturn(func(_ context.Context, _ domain.TokenType, _ string) error {
 			callOrder = append(callOrder, "delete")
 			return nil
@@ -582,9 +732,527 @@ func (s *tokenServiceTestSuite) TestSaveToken_DeletesOldTokenFirst() {
 			return token, nil
 		})
 
-	_, refreshToken, err := s.tokenService.GenerateAuthTokens(s.ctx, userID)
+	verifyEmailToken, err := s.tokenService.GenerateVerifyEmailToken(s.ctx, userID)
 
 	s.NoError(err)
-	s.NotNil(refreshToken)
+	s.NotNil(verifyEmailToken)
 	s.Equal([]string{"delete", "create"}, callOrder)
 }
+
+// ==================== RotateRefreshToken Tests ====================
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_Success() {
+	sessionID := uuid.Must(uuid.NewV7())
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+	oldToken.SessionID = &sessionID
+
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(s.ctx, oldToken.ID.String(), gomock.Any()).
+		Return(nil)
+
+	s.mockTokenRepo.EXPECT().
+		TouchSession(s.ctx, sessionID.String(), gomock.Any()).
+		Return(nil)
+
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			token.ID = uuid.Must(uuid.NewV7())
+			return token, nil
+		})
+
+	accessToken, refreshToken, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+
+	s.NoError(err)
+	s.Equal(sessionID, *accessToken.SessionID)
+	s.Equal(sessionID, *refreshToken.SessionID)
+	s.Equal(oldToken.ID, *refreshToken.ParentID)
+	s.Equal(crypto.HashToken(refreshToken.Token, s.testSecret), refreshToken.TokenHash)
+	s.Equal(domain.TokenTypeRefresh, refreshToken.Type)
+	s.NotEqual(oldToken.Token, refreshToken.Token)
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_PasswordExpired() {
+	// The refresh token is kept, but only works again once the password was changed
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+	oldToken.UserID = s.testUUID
+	s.tokenService.Conf.Password.MaxAge = 24 * time.Hour
+
+	s.mockTokenRepo.EXPECT().ConsumeToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	accessToken, refreshToken, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+
+	s.ErrorIs(err, myerrors.ErrPasswordExpired)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_KeepsClientClaims() {
+	sessionID := uuid.Must(uuid.NewV7())
+	clientID := uuid.Must(uuid.NewV7())
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+	oldToken.SessionID = &sessionID
+	oldToken.Session = &domain.Session{ID: sessionID, ClientID: &clientID, Scope: "profile"}
+
+	s.mockTokenRepo.EXPECT().ConsumeToken(s.ctx, oldToken.ID.String(), gomock.Any()).Return(nil)
+	s.mockTokenRepo.EXPECT().TouchSession(s.ctx, sessionID.String(), gomock.Any()).Return(nil)
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			return token, nil
+		})
+
+	accessToken, _, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+	s.Require().NoError(err)
+
+	claims, err := pkgToken.ParseToken(accessToken.Token, s.tokenService.Keys, domain.TokenTypeAccess.String())
+	s.Require().NoError(err)
+	s.Equal(clientID.String(), claims["client_id"])
+	s.Equal("profile", claims["scope"])
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_ConsumedTokenRevokesFamily() {
+	sessionID := uuid.Must(uuid.NewV7())
+	consumedAt := time.Now().Add(-time.Minute)
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+	oldToken.SessionID = &sessionID
+	oldToken.ConsumedAt = &consumedAt
+	device := &model.DeviceInfo{UserAgent: "attacker-agent", IPAddress: "198.51.100.7"}
+
+	s.mockTokenRepo.EXPECT().
+		RevokeTokenFamily(s.ctx, sessionID.String()).
+		Return(nil)
+
+	s.mockRevocation.EXPECT().
+		RevokeSession(s.ctx, sessionID.String()).
+		Return(nil)
+
+	s.mockEventRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *domain.SecurityEvent) (*domain.SecurityEvent, error) {
+			s.Equal(s.testUUID, event.UserID)
+			s.Equal(domain.SecurityEventRefreshTokenReuse, event.Type)
+			s.Equal("198.51.100.7", event.IPAddress)
+			s.Equal("attacker-agent", event.UserAgent)
+			return event, nil
+		})
+
+	accessToken, refreshToken, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, device)
+
+	s.ErrorIs(err, myerrors.ErrRefreshTokenReused)
+	s.Nil(accessToken)
+	s.Nil(refreshToken)
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_ConcurrentRotationRevokesFamily() {
+	sessionID := uuid.Must(uuid.NewV7())
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+	oldToken.SessionID = &sessionID
+
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(s.ctx, oldToken.ID.String(), gomock.Any()).
+		Return(myerrors.ErrTokenAlreadyConsumed)
+
+	s.mockTokenRepo.EXPECT().
+		RevokeTokenFamily(s.ctx, sessionID.String()).
+		Return(nil)
+
+	s.mockRevocation.EXPECT().
+		RevokeSession(s.ctx, sessionID.String()).
+		Return(nil)
+
+	s.mockEventRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(nil, myerrors.ErrCreateSecurityEventFailed)
+
+	_, _, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+
+	s.ErrorIs(err, myerrors.ErrRefreshTokenReused)
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_ReusedLegacyTokenRevokesAllRefreshTokens() {
+	consumedAt := time.Now().Add(-time.Minute)
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+	oldToken.ConsumedAt = &consumedAt
+
+	s.mockTokenRepo.EXPECT().
+		Delete(s.ctx, domain.TokenTypeRefresh, s.testUUID.String()).
+		Return(nil)
+
+	s.mockRevocation.EXPECT().
+		RevokeUser(s.ctx, s.testUUID.String()).
+		Return(nil)
+
+	s.mockEventRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(&domain.SecurityEvent{}, nil)
+
+	_, _, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+
+	s.ErrorIs(err, myerrors.ErrRefreshTokenReused)
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_RevokeFamilyError() {
+	sessionID := uuid.Must(uuid.NewV7())
+	consumedAt := time.Now().Add(-time.Minute)
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+	oldToken.SessionID = &sessionID
+	oldToken.ConsumedAt = &consumedAt
+
+	s.mockTokenRepo.EXPECT().
+		RevokeTokenFamily(s.ctx, sessionID.String()).
+		Return(myerrors.ErrRevokeTokenFamily)
+
+	_, _, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+
+	s.ErrorIs(err, myerrors.ErrRevokeTokenFamily)
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_ConsumeError() {
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(s.ctx, oldToken.ID.String(), gomock.Any()).
+		Return(myerrors.ErrConsumeTokenFailed)
+
+	_, _, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+
+	s.ErrorIs(err, myerrors.ErrConsumeTokenFailed)
+}
+
+func (s *tokenServiceTestSuite) TestRotateRefreshToken_CreateTokenError() {
+	oldToken := s.createTestToken(domain.TokenTypeRefresh)
+
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(s.ctx, oldToken.ID.String(), gomock.Any()).
+		Return(nil)
+
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(nil, myerrors.ErrSaveTokenFailed)
+
+	_, _, err := s.tokenService.RotateRefreshToken(s.ctx, oldToken, nil)
+
+	s.ErrorIs(err, myerrors.ErrSaveTokenFailed)
+}
+
+// ==================== GenerateMFAPendingToken Tests ====================
+
+func (s *tokenServiceTestSuite) TestGenerateMFAPendingToken_Success() {
+	s.tokenService.Conf.JWT.MFAPendingExpire = 5 * time.Minute
+
+	s.mockTokenRepo.EXPECT().
+		Delete(s.ctx, domain.TokenTypeMFAPending, s.testUUID.String()).
+		Return(nil)
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			s.NotEmpty(token.TokenHash)
+			return token, nil
+		})
+
+	mfaToken, err := s.tokenService.GenerateMFAPendingToken(s.ctx, s.testUUID.String())
+
+	s.Require().NoError(err)
+	s.Equal(domain.TokenTypeMFAPending, mfaToken.Type)
+	s.WithinDuration(time.Now().Add(5*time.Minute), mfaToken.Expires, time.Second)
+
+	userID, err := pkgToken.VerifyToken(mfaToken.Token, s.tokenService.Keys, domain.TokenTypeMFAPending.String())
+	s.NoError(err)
+	s.Equal(s.testUUID.String(), userID)
+
+	_, err = pkgToken.VerifyToken(mfaToken.Token, s.tokenService.Keys, domain.TokenTypeAccess.String())
+	s.Error(err)
+}
+
+// ==================== GenerateMagicLinkToken Tests ====================
+
+func (s *tokenServiceTestSuite) TestGenerateMagicLinkToken_Success() {
+	s.tokenService.Conf.JWT.MagicLinkExpire = 15 * time.Minute
+	req := &model.MagicLinkRequest{Email: "test@example.com"}
+	testUser := &domain.User{ID: s.testUUID, Email: req.Email}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+	s.mockUserSvc.EXPECT().
+		GetUserByEmail(s.ctx, req.Email).
+		Return(testUser, nil)
+	s.mockTokenRepo.EXPECT().
+		Delete(s.ctx, domain.TokenTypeMagicLink, testUser.ID.String()).
+		Return(nil)
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			s.NotEqual(token.Token, token.TokenHash)
+			return token, nil
+		})
+
+	result, err := s.tokenService.GenerateMagicLinkToken(s.ctx, req)
+
+	s.Require().NoError(err)
+	s.Equal(domain.TokenTypeMagicLink, result.Type)
+	s.WithinDuration(time.Now().Add(15*time.Minute), result.Expires, time.Second)
+
+	userID, err := pkgToken.VerifyToken(result.Token, s.tokenService.Keys, domain.TokenTypeMagicLink.String())
+	s.NoError(err)
+	s.Equal(s.testUUID.String(), userID)
+}
+
+func (s *tokenServiceTestSuite) TestGenerateMagicLinkToken_UserNotFound() {
+	req := &model.MagicLinkRequest{Email: "nonexistent@example.com"}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+	s.mockUserSvc.EXPECT().
+		GetUserByEmail(s.ctx, req.Email).
+		Return(nil, myerrors.ErrUserNotFound)
+
+	result, err := s.tokenService.GenerateMagicLinkToken(s.ctx, req)
+
+	s.ErrorIs(err, myerrors.ErrUserNotFound)
+	s.Nil(result)
+}
+
+// ==================== Invitation Token Tests ====================
+
+func (s *tokenServiceTestSuite) TestInvitationToken_Success() {
+	s.tokenService.Conf.JWT.InvitationExpire = 24 * time.Hour
+	invitation := &domain.OrganizationInvitation{
+		ID: uuid.New(), OrganizationID: uuid.New(), Email: "invitee@example.com",
+	}
+
+	result, err := s.tokenService.GenerateInvitationToken(s.ctx, invitation)
+
+	s.Require().NoError(err)
+	s.Equal(domain.TokenTypeInvitation, result.Type)
+	s.NotEqual(result.Token, result.TokenHash)
+	s.WithinDuration(time.Now().Add(24*time.Hour), result.Expires, time.Second)
+
+	invitationID, err := s.tokenService.VerifyInvitationToken(s.ctx, result.Token)
+	s.Require().NoError(err)
+	s.Equal(invitation.ID.String(), invitationID)
+
+	// Invitations are not addressed to a user
+	_, err = pkgToken.VerifyToken(result.Token, s.tokenService.Keys, domain.TokenTypeInvitation.String())
+	s.ErrorIs(err, myerrors.ErrInvalidTokenUserID)
+}
+
+func (s *tokenServiceTestSuite) TestVerifyInvitationToken_WrongType() {
+	mfaToken, err := s.tokenService.generateToken(
+		s.testUUID.String(), time.Now().Add(time.Minute), domain.TokenTypeMFAPending, nil,
+	)
+	s.Require().NoError(err)
+
+	_, err = s.tokenService.VerifyInvitationToken(s.ctx, mfaToken)
+
+	s.ErrorIs(err, myerrors.ErrInvalidTokenType)
+}
+
+// ==================== Challenge Token Tests ====================
+
+func (s *tokenServiceTestSuite) TestChallengeToken_AuthorizationCodesCoexist() {
+	// Authorizing a second client must not void the unredeemed code of the first
+	userID := s.testUUID.String()
+
+	s.mockTokenRepo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockTokenRepo.EXPECT().
+		DeleteExpired(s.ctx, domain.TokenTypeAuthorizationCode, userID, gomock.Any()).
+		Return(nil).
+		Times(2)
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			return token, nil
+		}).
+		Times(2)
+
+	for _, clientID := range []string{"first-client", "second-client"} {
+		_, err := s.tokenService.GenerateChallengeToken(
+			s.ctx, userID, domain.TokenTypeAuthorizationCode, time.Now().Add(time.Minute),
+			jwt.MapClaims{"client_id": clientID},
+		)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *tokenServiceTestSuite) TestChallengeToken_SingleUse() {
+	userID := s.testUUID.String()
+	var stored *domain.Token
+
+	s.mockTokenRepo.EXPECT().
+		Delete(s.ctx, domain.TokenTypeWebAuthnLogin, userID).
+		Return(nil)
+	s.mockTokenRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.Token) (*domain.Token, error) {
+			token.ID = uuid.Must(uuid.NewV7())
+			stored = token
+			return token, nil
+		})
+
+	challengeToken, err := s.tokenService.GenerateChallengeToken(
+		s.ctx, userID, domain.TokenTypeWebAuthnLogin, time.Now().Add(time.Minute), jwt.MapClaims{"state": "abc"},
+	)
+	s.Require().NoError(err)
+	s.NotEqual(challengeToken.Token, stored.TokenHash)
+
+	s.mockTokenRepo.EXPECT().
+		GetByTokenHashAndUserID(s.ctx, stored.TokenHash, userID).
+		Return(stored, nil).
+		Times(2)
+	gomock.InOrder(
+		s.mockTokenRepo.EXPECT().ConsumeToken(s.ctx, stored.ID.String(), gomock.Any()).Return(nil),
+		s.mockTokenRepo.EXPECT().ConsumeToken(s.ctx, stored.ID.String(), gomock.Any()).
+			Return(myerrors.ErrTokenAlreadyConsumed),
+	)
+
+	claims, err := s.tokenService.ConsumeChallengeToken(s.ctx, challengeToken.Token, domain.TokenTypeWebAuthnLogin)
+	s.Require().NoError(err)
+	s.Equal("abc", claims["state"])
+
+	_, err = s.tokenService.ConsumeChallengeToken(s.ctx, challengeToken.Token, domain.TokenTypeWebAuthnLogin)
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *tokenServiceTestSuite) TestConsumeChallengeToken_WrongType() {
+	challengeToken, err := s.tokenService.generateToken(
+		s.testUUID.String(), time.Now().Add(time.Minute), domain.TokenTypeWebAuthnRegistration, nil,
+	)
+	s.Require().NoError(err)
+
+	_, err = s.tokenService.ConsumeChallengeToken(s.ctx, challengeToken, domain.TokenTypeWebAuthnLogin)
+	s.ErrorIs(err, myerrors.ErrInvalidTokenType)
+}
+
+func (s *tokenServiceTestSuite) TestConsumeChallengeToken_NotStored() {
+	challengeToken, err := s.tokenService.generateToken(
+		s.testUUID.String(), time.Now().Add(time.Minute), domain.TokenTypeWebAuthnLogin, nil,
+	)
+	s.Require().NoError(err)
+
+	s.mockTokenRepo.EXPECT().
+		GetByTokenHashAndUserID(s.ctx, gomock.Any(), s.testUUID.String()).
+		Return(nil, myerrors.ErrTokenNotFound)
+
+	_, err = s.tokenService.ConsumeChallengeToken(s.ctx, challengeToken, domain.TokenTypeWebAuthnLogin)
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+// ==================== Session Tests ====================
+
+func (s *tokenServiceTestSuite) TestGetSessions_Success() {
+	userID := s.testUUID.String()
+	sessions := []domain.Session{{ID: uuid.Must(uuid.NewV7()), UserID: s.testUUID, DeviceName: "laptop"}}
+
+	s.mockTokenRepo.EXPECT().
+		GetSessionsByUserID(s.ctx, userID).
+		Return(sessions, nil)
+
+	result, err := s.tokenService.GetSessions(s.ctx, userID)
+
+	s.NoError(err)
+	s.Equal(sessions, result)
+}
+
+func (s *tokenServiceTestSuite) TestTouchSession_Success() {
+	sessionID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockTokenRepo.EXPECT().
+		TouchSession(s.ctx, sessionID, gomock.Any()).
+		Return(nil)
+
+	err := s.tokenService.TouchSession(s.ctx, sessionID)
+
+	s.NoError(err)
+}
+
+func (s *tokenServiceTestSuite) TestDeleteSession_NotFound() {
+	userID := s.testUUID.String()
+	sessionID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockTokenRepo.EXPECT().
+		DeleteSession(s.ctx, sessionID, userID).
+		Return(myerrors.ErrSessionNotFound)
+
+	err := s.tokenService.DeleteSession(s.ctx, userID, sessionID)
+
+	s.Error(err)
+	s.Equal(myerrors.ErrSessionNotFound, err)
+}
+
+func (s *tokenServiceTestSuite) TestDeleteSession_RevokesAccessTokens() {
+	userID := s.testUUID.String()
+	sessionID := uuid.Must(uuid.NewV7()).String()
+
+	s.mockTokenRepo.EXPECT().
+		DeleteSession(s.ctx, sessionID, userID).
+		Return(nil)
+
+	s.mockRevocation.EXPECT().
+		RevokeSession(s.ctx, sessionID).
+		Return(nil)
+
+	err := s.tokenService.DeleteSession(s.ctx, userID, sessionID)
+
+	s.NoError(err)
+}
+
+func (s *tokenServiceTestSuite) TestDeleteOtherSessions_Success() {
+	userID := s.testUUID.String()
+	currentSession := domain.Session{ID: uuid.Must(uuid.NewV7()), UserID: s.testUUID}
+	otherSession := domain.Session{ID: uuid.Must(uuid.NewV7()), UserID: s.testUUID}
+
+	s.mockTokenRepo.EXPECT().
+		GetSessionsByUserID(s.ctx, userID).
+		Return([]domain.Session{currentSession, otherSession}, nil)
+
+	s.mockTokenRepo.EXPECT().
+		DeleteOtherSessions(s.ctx, userID, currentSession.ID.String()).
+		Return(nil)
+
+	s.mockRevocation.EXPECT().
+		RevokeSession(s.ctx, otherSession.ID.String()).
+		Return(nil)
+
+	err := s.tokenService.DeleteOtherSessions(s.ctx, userID, currentSession.ID.String())
+
+	s.NoError(err)
+}
+
+func (s *tokenServiceTestSuite) TestDeleteOtherSessions_MissingCurrentSession() {
+	err := s.tokenService.DeleteOtherSessions(s.ctx, s.testUUID.String(), "")
+
+	s.Error(err)
+	s.Equal(myerrors.ErrInvalidTokenSession, err)
+}
+
+// ==================== RevokeAccessToken Tests ====================
+
+func (s *tokenServiceTestSuite) TestRevokeAccessToken_Success() {
+	accessToken, err := s.tokenService.GenerateAccessToken(s.ctx, s.testUUID.String(), uuid.Must(uuid.NewV7()).String())
+	s.Require().NoError(err)
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(accessToken.Token, claims)
+	s.Require().NoError(err)
+
+	s.mockRevocation.EXPECT().
+		RevokeToken(s.ctx, claims["jti"], gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, expiresAt time.Time) error {
+			s.WithinDuration(accessToken.Expires, expiresAt, time.Second)
+			return nil
+		})
+
+	err = s.tokenService.RevokeAccessToken(s.ctx, accessToken.Token)
+
+	s.NoError(err)
+}
+
+func (s *tokenServiceTestSuite) TestRevokeAccessToken_IgnoresInvalidToken() {
+	refreshToken := s.createTestJWTToken(s.testUUID.String(), domain.TokenTypeRefresh)
+
+	s.NoError(s.tokenService.RevokeAccessToken(s.ctx, "not-a-jwt"))
+	s.NoError(s.tokenService.RevokeAccessToken(s.ctx, refreshToken))
+}
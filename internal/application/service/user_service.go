@@ -1,15 +1,22 @@
 package service
 
 import (
+	"app/config"
 	"app/internal/application/model"
 	"app/internal/domain"
 	"app/internal/domain/myerrors"
 	"app/internal/domain/repository"
 	"app/internal/pkg/crypto"
+	"app/internal/pkg/password"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
 	"app/internal/pkg/validator"
 	"context"
 	"errors"
+	"slices"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/tommynurwantoro/golog"
 )
 
@@ -21,13 +28,21 @@ type UserService interface {
 	CreateUser(ctx context.Context, req *model.CreateUserRequest) (*domain.User, error)
 	UpdatePassOrVerify(ctx context.Context, req *model.UpdatePassOrVerifyRequest, id string) error
 	UpdateUser(ctx context.Context, req *model.UpdateUserRequest) (*domain.User, error)
+	UpdateUserRoles(ctx context.Context, id string, req *model.UpdateUserRolesRequest) (*domain.User, error)
+	UpdateUserAttributes(ctx context.Context, id string, req *model.UpdateUserAttributesRequest) (*domain.User, error)
 	DeleteUser(ctx context.Context, id string) error
-	CreateGoogleUser(ctx context.Context, req *model.CreateGoogleUserRequest) (*domain.User, error)
+	AuthorizeUser(ctx context.Context, subject policy.Subject, action policy.Action, id string, env map[string]any) error
 }
 
 type UserServiceImpl struct {
-	UserRepository repository.UserRepository `inject:"userRepository"`
-	Validator      validator.Validator       `inject:"validator"`
+	Conf                      *config.Config                       `inject:"config"`
+	UserRepository            repository.UserRepository            `inject:"userRepository"`
+	PasswordHistoryRepository repository.PasswordHistoryRepository `inject:"passwordHistoryRepository"`
+	RoleService               RoleService                          `inject:"roleService"`
+	Revocation                RevocationService                    `inject:"revocationService"`
+	AccessPolicy              AccessPolicyService                  `inject:"accessPolicyService"`
+	PasswordPolicy            password.Policy                      `inject:"passwordPolicy"`
+	Validator                 validator.Validator                  `inject:"validator"`
 }
 
 func (u *UserServiceImpl) GetUsers(ctx context.Context, req *model.GetUserRequest) ([]domain.User, int64, error) {
@@ -69,17 +84,38 @@ func (u *UserServiceImpl) CreateUser(ctx context.Context, req *model.CreateUserR
 		return nil, myerrors.ErrInvalidRequest
 	}
 
+	if err := u.checkRoles(ctx, req.Roles); err != nil {
+		return nil, err
+	}
+
+	// Roles apply in every organization, so users created inside one only get the default role there
+	organizationID := tenant.OrganizationID(ctx)
+	if organizationID != "" && slices.ContainsFunc(req.Roles, func(role string) bool { return role != domain.RoleUser }) {
+		return nil, myerrors.ErrRoleNotGrantable
+	}
+
+	if err := u.PasswordPolicy.Check(req.Password, req.Name, req.Email); err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := crypto.HashPassword(req.Password)
 	if err != nil {
 		golog.Error("Error hashing password", err)
 		return nil, myerrors.ErrHashPassword
 	}
 
+	now := time.Now().UTC()
 	user := &domain.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: hashedPassword,
-		Role:     req.Role,
+		Name:              req.Name,
+		Email:             req.Email,
+		Password:          hashedPassword,
+		PasswordChangedAt: &now,
+		Roles:             domain.NewUserRoles(slices.Compact(slices.Sorted(slices.Values(req.Roles)))...),
+	}
+	if organizationID != "" {
+		user.Memberships = []domain.OrganizationMember{
+			{OrganizationID: uuid.MustParse(organizationID), Role: domain.RoleUser},
+		}
 	}
 
 	newUser, err := u.UserRepository.Create(ctx, user)
@@ -100,19 +136,32 @@ func (u *UserServiceImpl) UpdateUser(ctx context.Context, req *model.UpdateUserR
 		return nil, myerrors.ErrInvalidRequest
 	}
 
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, myerrors.ErrUserNotFound
+	}
+
+	updateBody := &domain.User{
+		ID:    userID,
+		Name:  req.Name,
+		Email: req.Email,
+	}
+
+	var user *domain.User
 	if req.Password != "" {
+		if user, err = u.checkNewPassword(ctx, req.Password, req.UserID, req.Name, req.Email); err != nil {
+			return nil, err
+		}
+
 		hashedPassword, err := crypto.HashPassword(req.Password)
 		if err != nil {
 			golog.Error("Error hashing password", err)
 			return nil, myerrors.ErrHashPassword
 		}
-		req.Password = hashedPassword
-	}
 
-	updateBody := &domain.User{
-		Name:     req.Name,
-		Password: req.Password,
-		Email:    req.Email,
+		now := time.Now().UTC()
+		updateBody.Password = hashedPassword
+		updateBody.PasswordChangedAt = &now
 	}
 
 	updatedUser, err := u.UserRepository.Update(ctx, updateBody)
@@ -120,9 +169,58 @@ func (u *UserServiceImpl) UpdateUser(ctx context.Context, req *model.UpdateUserR
 		return nil, err
 	}
 
+	if user != nil {
+		if err = u.rememberPassword(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
 	return updatedUser, nil
 }
 
+// UpdateUserRoles replaces the roles of a user. The access tokens of the user are revoked,
+// since they carry the permissions of the old roles.
+func (u *UserServiceImpl) UpdateUserRoles(
+	ctx context.Context, id string, req *model.UpdateUserRolesRequest,
+) (*domain.User, error) {
+	if err := u.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating update user roles request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	if err := u.checkRoles(ctx, req.Roles); err != nil {
+		return nil, err
+	}
+
+	roles := slices.Compact(slices.Sorted(slices.Values(req.Roles)))
+	if err := u.UserRepository.SetRoles(ctx, id, domain.NewUserRoles(roles...)); err != nil {
+		return nil, err
+	}
+
+	if err := u.Revocation.RevokeUser(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return u.UserRepository.GetByID(ctx, id)
+}
+
+// UpdateUserAttributes replaces the attributes of a user, which apply to the next request of
+// or on that user.
+func (u *UserServiceImpl) UpdateUserAttributes(
+	ctx context.Context, id string, req *model.UpdateUserAttributesRequest,
+) (*domain.User, error) {
+	if err := u.Validator.Validate(ctx, req); err != nil {
+		golog.Error("Error validating update user attributes request", err)
+		return nil, myerrors.ErrInvalidRequest
+	}
+
+	if err := u.UserRepository.SetAttributes(ctx, id, req.Attributes); err != nil {
+		return nil, err
+	}
+
+	return u.UserRepository.GetByID(ctx, id)
+}
+
 func (u *UserServiceImpl) UpdatePassOrVerify(
 	ctx context.Context,
 	req *model.UpdatePassOrVerifyRequest,
@@ -137,18 +235,26 @@ func (u *UserServiceImpl) UpdatePassOrVerify(
 		return myerrors.ErrInvalidRequest
 	}
 
+	updateBody := &domain.User{
+		VerifiedEmail: req.VerifiedEmail,
+	}
+
+	var user *domain.User
 	if req.Password != "" {
+		var err error
+		if user, err = u.checkNewPassword(ctx, req.Password, id, "", ""); err != nil {
+			return err
+		}
+
 		hashedPassword, err := crypto.HashPassword(req.Password)
 		if err != nil {
 			golog.Error("Error hashing password", err)
 			return myerrors.ErrHashPassword
 		}
-		req.Password = hashedPassword
-	}
 
-	updateBody := &domain.User{
-		Password:      req.Password,
-		VerifiedEmail: req.VerifiedEmail,
+		now := time.Now().UTC()
+		updateBody.Password = hashedPassword
+		updateBody.PasswordChangedAt = &now
 	}
 
 	err := u.UserRepository.UpdatePassOrVerify(ctx, updateBody, id)
@@ -156,6 +262,10 @@ func (u *UserServiceImpl) UpdatePassOrVerify(
 		return err
 	}
 
+	if user != nil {
+		return u.rememberPassword(ctx, user)
+	}
+
 	return nil
 }
 
@@ -168,39 +278,131 @@ func (u *UserServiceImpl) DeleteUser(ctx context.Context, id string) error {
 	return nil
 }
 
-func (u *UserServiceImpl) CreateGoogleUser(
-	ctx context.Context,
-	req *model.CreateGoogleUserRequest,
-) (*domain.User, error) {
-	if err := u.Validator.Validate(ctx, req); err != nil {
-		golog.Error("Error validating create google user request", err)
-		return nil, myerrors.ErrInvalidRequest
-	}
+// AuthorizeUser checks whether the subject may perform the action on the user account, with the roles and
+// attributes of that account as resource attributes. An unknown account is authorized on its id alone,
+// so only subjects allowed to act on it learn that it does not exist.
+func (u *UserServiceImpl) AuthorizeUser(
+	ctx context.Context, subject policy.Subject, action policy.Action, id string, env map[string]any,
+) error {
+	resource := policy.Resource{Type: policy.ResourceUser, ID: id, OwnerID: id}
 
-	userFromDB, err := u.UserRepository.GetByEmail(ctx, req.Email)
-	if err != nil {
-		if errors.Is(err, myerrors.ErrUserNotFound) {
-			newUser, createErr := u.UserRepository.Create(ctx, &domain.User{
-				Name:          req.Name,
-				Email:         req.Email,
-				VerifiedEmail: req.VerifiedEmail,
-			})
-			if createErr != nil {
-				return nil, createErr
+	if _, err := uuid.Parse(id); err == nil {
+		user, err := u.UserRepository.GetByID(ctx, id)
+		if err != nil && !errors.Is(err, myerrors.ErrUserNotFound) {
+			return err
+		}
+		if user != nil {
+			resource.Attributes = make(map[string]any, len(user.Attributes)+1)
+			for name, value := range user.Attributes {
+				resource.Attributes[name] = value
 			}
+			resource.Attributes["roles"] = user.RoleNames()
 
-			return newUser, nil
+			if err = u.checkOutranked(ctx, subject, action, user); err != nil {
+				return err
+			}
 		}
+	}
 
-		golog.Error("Error getting user by email", err)
-		return nil, myerrors.ErrGetUserFailed
+	return u.AccessPolicy.Authorize(ctx, policy.Request{
+		Subject:     subject,
+		Action:      action,
+		Resource:    resource,
+		Environment: env,
+	})
+}
+
+// checkOutranked forbids changing another user whose roles grant rights the subject lacks, so an admin of
+// an organization cannot take over the account of a platform admin who is a member.
+func (u *UserServiceImpl) checkOutranked(
+	ctx context.Context, subject policy.Subject, action policy.Action, user *domain.User,
+) error {
+	if action == policy.ReadUser || user.ID.String() == subject.UserID {
+		return nil
 	}
 
-	userFromDB.VerifiedEmail = req.VerifiedEmail
-	updatedUser, err := u.UserRepository.Update(ctx, userFromDB)
+	rights, err := u.RoleService.Rights(ctx, user.RoleNames()...)
+	if err != nil && !errors.Is(err, myerrors.ErrRoleNotFound) {
+		return err
+	}
+
+	for _, right := range rights {
+		if !subject.HasRight(right) {
+			return myerrors.ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+// checkRoles fails with ErrInvalidRole when one of the roles does not exist.
+func (u *UserServiceImpl) checkRoles(ctx context.Context, roles []string) error {
+	if _, err := u.RoleService.Rights(ctx, roles...); err != nil {
+		if errors.Is(err, myerrors.ErrRoleNotFound) {
+			return myerrors.ErrInvalidRole
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkNewPassword applies the password policy to a new password of the user, with the name and email it is
+// being updated to if any, and refuses the last password.history passwords. It returns the user as it is
+// before the update.
+func (u *UserServiceImpl) checkNewPassword(
+	ctx context.Context, password, id, name, email string,
+) (*domain.User, error) {
+	user, err := u.UserRepository.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return updatedUser, nil
+	if err = u.PasswordPolicy.Check(password, user.Name, user.Email, name, email); err != nil {
+		return nil, err
+	}
+
+	history := u.Conf.Password.History
+	if history <= 0 {
+		return user, nil
+	}
+
+	hashes := make([]string, 0, history)
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+
+	if history > 1 {
+		previous, err := u.PasswordHistoryRepository.GetRecent(ctx, id, history-1)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range previous {
+			hashes = append(hashes, entry.Password)
+		}
+	}
+
+	for _, hash := range hashes {
+		if crypto.CheckPasswordHash(password, hash) {
+			return nil, myerrors.ErrPasswordReused
+		}
+	}
+
+	return user, nil
+}
+
+// rememberPassword keeps the replaced password of the user in the history, trimmed to the password.history
+// minus the current one.
+func (u *UserServiceImpl) rememberPassword(ctx context.Context, user *domain.User) error {
+	keep := u.Conf.Password.History - 1
+	if keep <= 0 || user.Password == "" {
+		return nil
+	}
+
+	entry := &domain.PasswordHistory{UserID: user.ID, Password: user.Password}
+	if err := u.PasswordHistoryRepository.Create(ctx, entry); err != nil {
+		return err
+	}
+
+	return u.PasswordHistoryRepository.Prune(ctx, user.ID.String(), keep)
 }
@@ -1,11 +1,17 @@
 package service
 
 import (
+	"app/config"
 	"app/internal/application/model"
 	"app/internal/domain"
 	"app/internal/domain/myerrors"
 	"app/internal/pkg/crypto"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
+	"app/internal/pkg/validator"
 	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/service/mocks"
+	mockPassword "app/internal/pkg/password/mocks"
 	mockValidator "app/internal/pkg/validator/mocks"
 	"context"
 	"errors"
@@ -22,6 +28,11 @@ type userServiceTestSuite struct {
 	mockCtrl        *gomock.Controller
 	mockUserRepo    *mockRepository.MockUserRepository
 	mockValidator   *mockValidator.MockValidator
+	mockRoleSvc     *mocks.MockRoleService
+	mockRevocation  *mocks.MockRevocationService
+	mockPolicy      *mocks.MockAccessPolicyService
+	mockPassword    *mockPassword.MockPolicy
+	mockHistory     *mockRepository.MockPasswordHistoryRepository
 	userService     *UserServiceImpl
 	ctx             context.Context
 	testUUID        uuid.UUID
@@ -37,10 +48,21 @@ func (s *userServiceTestSuite) SetupTest() {
 	s.mockCtrl = gomock.NewController(s.T())
 	s.mockUserRepo = mockRepository.NewMockUserRepository(s.mockCtrl)
 	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+	s.mockRoleSvc = mocks.NewMockRoleService(s.mockCtrl)
+	s.mockRevocation = mocks.NewMockRevocationService(s.mockCtrl)
+	s.mockPolicy = mocks.NewMockAccessPolicyService(s.mockCtrl)
+	s.mockPassword = mockPassword.NewMockPolicy(s.mockCtrl)
+	s.mockHistory = mockRepository.NewMockPasswordHistoryRepository(s.mockCtrl)
 
 	s.userService = &UserServiceImpl{
-		UserRepository: s.mockUserRepo,
-		Validator:      s.mockValidator,
+		Conf:                      &config.Config{Password: config.PasswordConfig{History: 3}},
+		UserRepository:            s.mockUserRepo,
+		Validator:                 s.mockValidator,
+		RoleService:               s.mockRoleSvc,
+		Revocation:                s.mockRevocation,
+		AccessPolicy:              s.mockPolicy,
+		PasswordPolicy:            s.mockPassword,
+		PasswordHistoryRepository: s.mockHistory,
 	}
 
 	s.ctx = context.Background()
@@ -63,7 +85,7 @@ func (s *userServiceTestSuite) createTestUser() *domain.User {
 		Name:          "Test User",
 		Email:         "test@example.com",
 		Password:      s.hashedPass,
-		Role:          "user",
+		Roles:         domain.NewUserRoles("user"),
 		VerifiedEmail: false,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
@@ -78,7 +100,7 @@ func (s *userServiceTestSuite) createTestUserList() []domain.User {
 			Name:          "User One",
 			Email:         "user1@example.com",
 			Password:      s.hashedPass,
-			Role:          "user",
+			Roles:         domain.NewUserRoles("user"),
 			VerifiedEmail: false,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
@@ -88,7 +110,7 @@ func (s *userServiceTestSuite) createTestUserList() []domain.User {
 			Name:          "User Two",
 			Email:         "user2@example.com",
 			Password:      s.hashedPass,
-			Role:          "admin",
+			Roles:         domain.NewUserRoles("admin"),
 			VerifiedEmail: true,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
@@ -295,13 +317,21 @@ func (s *userServiceTestSuite) TestCreateUser_Success() {
 		Name:     "New User",
 		Email:    "newuser@example.com",
 		Password: "password123",
-		Role:     "user",
+		Roles:    []string{"user"},
 	}
 
 	s.mockValidator.EXPECT().
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockRoleSvc.EXPECT().
+		Rights(s.ctx, "user").
+		Return([]string{}, nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, req.Name, req.Email).
+		Return(nil)
+
 	s.mockUserRepo.EXPECT().
 		Create(s.ctx, gomock.Any()).
 		DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
@@ -314,7 +344,7 @@ func (s *userServiceTestSuite) TestCreateUser_Success() {
 	s.NoError(err)
 	s.Equal(req.Name, result.Name)
 	s.Equal(req.Email, result.Email)
-	s.Equal(req.Role, result.Role)
+	s.Equal(req.Roles, result.RoleNames())
 	s.NotEqual("password123", result.Password) // Should be hashed
 }
 
@@ -323,7 +353,7 @@ func (s *userServiceTestSuite) TestCreateUser_ValidationError() {
 		Name:     "",
 		Email:    "invalid-email",
 		Password: "short",
-		Role:     "",
+		Roles:    []string{},
 	}
 
 	validationErr := errors.New("validation failed")
@@ -344,13 +374,21 @@ func (s *userServiceTestSuite) TestCreateUser_EmailAlreadyInUse() {
 		Name:     "New User",
 		Email:    "existing@example.com",
 		Password: "password123",
-		Role:     "user",
+		Roles:    []string{"user"},
 	}
 
 	s.mockValidator.EXPECT().
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockRoleSvc.EXPECT().
+		Rights(s.ctx, "user").
+		Return([]string{}, nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, req.Name, req.Email).
+		Return(nil)
+
 	s.mockUserRepo.EXPECT().
 		Create(s.ctx, gomock.Any()).
 		Return(nil, myerrors.ErrEmailAlreadyInUse)
@@ -367,13 +405,21 @@ func (s *userServiceTestSuite) TestCreateUser_RepositoryError() {
 		Name:     "New User",
 		Email:    "newuser@example.com",
 		Password: "password123",
-		Role:     "user",
+		Roles:    []string{"user"},
 	}
 
 	s.mockValidator.EXPECT().
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockRoleSvc.EXPECT().
+		Rights(s.ctx, "user").
+		Return([]string{}, nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, req.Name, req.Email).
+		Return(nil)
+
 	s.mockUserRepo.EXPECT().
 		Create(s.ctx, gomock.Any()).
 		Return(nil, myerrors.ErrCreateUserFailed)
@@ -385,6 +431,110 @@ func (s *userServiceTestSuite) TestCreateUser_RepositoryError() {
 	s.Nil(result)
 }
 
+func (s *userServiceTestSuite) TestCreateUser_UnknownRole() {
+	req := &model.CreateUserRequest{
+		Name:     "New User",
+		Email:    "newuser@example.com",
+		Password: "password123",
+		Roles:    []string{"superuser"},
+	}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+
+	s.mockRoleSvc.EXPECT().
+		Rights(s.ctx, "superuser").
+		Return(nil, myerrors.ErrRoleNotFound)
+
+	result, err := s.userService.CreateUser(s.ctx, req)
+
+	s.Error(err)
+	s.Equal(myerrors.ErrInvalidRole, err)
+	s.Nil(result)
+}
+
+func (s *userServiceTestSuite) TestCreateUser_PasswordPolicy() {
+	req := &model.CreateUserRequest{
+		Name:     "New User",
+		Email:    "newuser@example.com",
+		Password: "password123",
+		Roles:    []string{"user"},
+	}
+
+	policyErr := validator.NewErrorMap(map[string]error{
+		"password.upper": errors.New("password must contain an uppercase letter"),
+	})
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+
+	s.mockRoleSvc.EXPECT().
+		Rights(s.ctx, "user").
+		Return([]string{}, nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, req.Name, req.Email).
+		Return(policyErr)
+
+	result, err := s.userService.CreateUser(s.ctx, req)
+
+	s.Equal(policyErr, err)
+	s.Nil(result)
+}
+
+// ==================== UpdateUserRoles Tests ====================
+
+func (s *userServiceTestSuite) TestUpdateUserRoles_Success() {
+	userID := s.testUUID.String()
+	req := &model.UpdateUserRolesRequest{Roles: []string{"user", "admin", "user"}}
+	updatedUser := s.createTestUser()
+	updatedUser.Roles = domain.NewUserRoles("admin", "user")
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+
+	s.mockRoleSvc.EXPECT().
+		Rights(s.ctx, "user", "admin", "user").
+		Return([]string{"getUsers"}, nil)
+
+	s.mockUserRepo.EXPECT().
+		SetRoles(s.ctx, userID, domain.NewUserRoles("admin", "user")).
+		Return(nil)
+
+	s.mockRevocation.EXPECT().
+		RevokeUser(s.ctx, userID).
+		Return(nil)
+
+	s.mockUserRepo.EXPECT().
+		GetByID(s.ctx, userID).
+		Return(updatedUser, nil)
+
+	result, err := s.userService.UpdateUserRoles(s.ctx, userID, req)
+
+	s.NoError(err)
+	s.Equal([]string{"admin", "user"}, result.RoleNames())
+}
+
+func (s *userServiceTestSuite) TestUpdateUserRoles_UnknownRole() {
+	req := &model.UpdateUserRolesRequest{Roles: []string{"superuser"}}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
+
+	s.mockRoleSvc.EXPECT().
+		Rights(s.ctx, "superuser").
+		Return(nil, myerrors.ErrRoleNotFound)
+
+	result, err := s.userService.UpdateUserRoles(s.ctx, s.testUUID.String(), req)
+
+	s.Equal(myerrors.ErrInvalidRole, err)
+	s.Nil(result)
+}
+
 // ==================== UpdateUser Tests ====================
 
 func (s *userServiceTestSuite) TestUpdateUser_Success_NameOnly() {
@@ -398,7 +548,7 @@ func (s *userServiceTestSuite) TestUpdateUser_Success_NameOnly() {
 		Name:          "Updated Name",
 		Email:         "test@example.com",
 		Password:      s.hashedPass,
-		Role:          "user",
+		Roles:         domain.NewUserRoles("user"),
 		VerifiedEmail: false,
 	}
 
@@ -427,7 +577,7 @@ func (s *userServiceTestSuite) TestUpdateUser_Success_EmailOnly() {
 		Name:          "Test User",
 		Email:         "newemail@example.com",
 		Password:      s.hashedPass,
-		Role:          "user",
+		Roles:         domain.NewUserRoles("user"),
 		VerifiedEmail: false,
 	}
 
@@ -457,7 +607,7 @@ func (s *userServiceTestSuite) TestUpdateUser_Success_WithPassword() {
 		Name:          "Updated Name",
 		Email:         "test@example.com",
 		Password:      s.hashedPass,
-		Role:          "user",
+		Roles:         domain.NewUserRoles("user"),
 		VerifiedEmail: false,
 	}
 
@@ -465,6 +615,18 @@ func (s *userServiceTestSuite) TestUpdateUser_Success_WithPassword() {
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockUserRepo.EXPECT().
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", req.Name, req.Email).
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return(nil, nil)
+
 	s.mockUserRepo.EXPECT().
 		Update(s.ctx, gomock.Any()).
 		DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
@@ -473,6 +635,14 @@ func (s *userServiceTestSuite) TestUpdateUser_Success_WithPassword() {
 			return updatedUser, nil
 		})
 
+	s.mockHistory.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		Prune(s.ctx, s.testUUID.String(), 2).
+		Return(nil)
+
 	result, err := s.userService.UpdateUser(s.ctx, req)
 
 	s.NoError(err)
@@ -492,7 +662,7 @@ func (s *userServiceTestSuite) TestUpdateUser_Success_AllFields() {
 		Name:          "New Name",
 		Email:         "newemail@example.com",
 		Password:      s.hashedPass,
-		Role:          "user",
+		Roles:         domain.NewUserRoles("user"),
 		VerifiedEmail: false,
 	}
 
@@ -500,10 +670,30 @@ func (s *userServiceTestSuite) TestUpdateUser_Success_AllFields() {
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockUserRepo.EXPECT().
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", req.Name, req.Email).
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return(nil, nil)
+
 	s.mockUserRepo.EXPECT().
 		Update(s.ctx, gomock.Any()).
 		Return(updatedUser, nil)
 
+	s.mockHistory.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		Prune(s.ctx, s.testUUID.String(), 2).
+		Return(nil)
+
 	result, err := s.userService.UpdateUser(s.ctx, req)
 
 	s.NoError(err)
@@ -603,6 +793,18 @@ func (s *userServiceTestSuite) TestUpdatePassOrVerify_Success_PasswordOnly() {
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockUserRepo.EXPECT().
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return(nil, nil)
+
 	s.mockUserRepo.EXPECT().
 		UpdatePassOrVerify(s.ctx, gomock.Any(), userID).
 		DoAndReturn(func(_ context.Context, user *domain.User, _ string) error {
@@ -611,6 +813,14 @@ func (s *userServiceTestSuite) TestUpdatePassOrVerify_Success_PasswordOnly() {
 			return nil
 		})
 
+	s.mockHistory.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		Prune(s.ctx, s.testUUID.String(), 2).
+		Return(nil)
+
 	err := s.userService.UpdatePassOrVerify(s.ctx, req, userID)
 
 	s.NoError(err)
@@ -648,10 +858,30 @@ func (s *userServiceTestSuite) TestUpdatePassOrVerify_Success_BothFields() {
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockUserRepo.EXPECT().
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return(nil, nil)
+
 	s.mockUserRepo.EXPECT().
 		UpdatePassOrVerify(s.ctx, gomock.Any(), userID).
 		Return(nil)
 
+	s.mockHistory.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		Prune(s.ctx, s.testUUID.String(), 2).
+		Return(nil)
+
 	err := s.userService.UpdatePassOrVerify(s.ctx, req, userID)
 
 	s.NoError(err)
@@ -699,6 +929,18 @@ func (s *userServiceTestSuite) TestUpdatePassOrVerify_RepositoryError() {
 		Validate(s.ctx, req).
 		Return(nil)
 
+	s.mockUserRepo.EXPECT().
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return(nil, nil)
+
 	s.mockUserRepo.EXPECT().
 		UpdatePassOrVerify(s.ctx, gomock.Any(), userID).
 		Return(myerrors.ErrUpdatePassOrVerifyFailed)
@@ -709,53 +951,90 @@ func (s *userServiceTestSuite) TestUpdatePassOrVerify_RepositoryError() {
 	s.Equal(myerrors.ErrUpdatePassOrVerifyFailed, err)
 }
 
-// ==================== DeleteUser Tests ====================
+func (s *userServiceTestSuite) TestUpdatePassOrVerify_PasswordPolicy() {
+	req := &model.UpdatePassOrVerifyRequest{
+		Password: "Test-User-2026",
+	}
 
-func (s *userServiceTestSuite) TestDeleteUser_Success() {
-	userID := s.testUUID.String()
+	policyErr := validator.NewErrorMap(map[string]error{
+		"password.user_info": errors.New("password must not contain your name or email"),
+	})
 
-	s.mockUserRepo.EXPECT().
-		Delete(s.ctx, userID).
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
 		Return(nil)
 
-	err := s.userService.DeleteUser(s.ctx, userID)
+	s.mockUserRepo.EXPECT().
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
 
-	s.NoError(err)
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(policyErr)
+
+	err := s.userService.UpdatePassOrVerify(s.ctx, req, s.testUUID.String())
+
+	s.Equal(policyErr, err)
 }
 
-func (s *userServiceTestSuite) TestDeleteUser_UserNotFound() {
-	userID := s.testUUID.String()
+func (s *userServiceTestSuite) TestUpdatePassOrVerify_CurrentPasswordReused() {
+	req := &model.UpdatePassOrVerifyRequest{
+		Password: "password123",
+	}
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
 
 	s.mockUserRepo.EXPECT().
-		Delete(s.ctx, userID).
-		Return(myerrors.ErrUserNotFound)
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
 
-	err := s.userService.DeleteUser(s.ctx, userID)
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(nil)
 
-	s.Error(err)
-	s.Equal(myerrors.ErrUserNotFound, err)
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return(nil, nil)
+
+	err := s.userService.UpdatePassOrVerify(s.ctx, req, s.testUUID.String())
+
+	s.Equal(myerrors.ErrPasswordReused, err)
 }
 
-func (s *userServiceTestSuite) TestDeleteUser_RepositoryError() {
-	userID := s.testUUID.String()
+func (s *userServiceTestSuite) TestUpdatePassOrVerify_HistoryPasswordReused() {
+	req := &model.UpdatePassOrVerifyRequest{
+		Password: "oldpassword123",
+	}
+
+	oldHash, err := crypto.HashPassword("oldpassword123")
+	s.Require().NoError(err)
+
+	s.mockValidator.EXPECT().
+		Validate(s.ctx, req).
+		Return(nil)
 
 	s.mockUserRepo.EXPECT().
-		Delete(s.ctx, userID).
-		Return(myerrors.ErrDeleteUserFailed)
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
 
-	err := s.userService.DeleteUser(s.ctx, userID)
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(nil)
 
-	s.Error(err)
-	s.Equal(myerrors.ErrDeleteUserFailed, err)
-}
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return([]domain.PasswordHistory{{UserID: s.testUUID, Password: oldHash}}, nil)
 
-// ==================== CreateGoogleUser Tests ====================
+	err = s.userService.UpdatePassOrVerify(s.ctx, req, s.testUUID.String())
 
-func (s *userServiceTestSuite) TestCreateGoogleUser_Success_NewUser() {
-	req := &model.CreateGoogleUserRequest{
-		Name:          "Google User",
-		Email:         "google@example.com",
-		VerifiedEmail: true,
+	s.Equal(myerrors.ErrPasswordReused, err)
+}
+
+func (s *userServiceTestSuite) TestUpdatePassOrVerify_RemembersReplacedPassword() {
+	req := &model.UpdatePassOrVerifyRequest{
+		Password: "newpassword123",
 	}
 
 	s.mockValidator.EXPECT().
@@ -763,51 +1042,46 @@ func (s *userServiceTestSuite) TestCreateGoogleUser_Success_NewUser() {
 		Return(nil)
 
 	s.mockUserRepo.EXPECT().
-		GetByEmail(s.ctx, req.Email).
-		Return(nil, myerrors.ErrUserNotFound)
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(nil)
+
+	s.mockHistory.EXPECT().
+		GetRecent(s.ctx, s.testUUID.String(), 2).
+		Return(nil, nil)
 
 	s.mockUserRepo.EXPECT().
+		UpdatePassOrVerify(s.ctx, gomock.Any(), s.testUUID.String()).
+		DoAndReturn(func(_ context.Context, user *domain.User, _ string) error {
+			s.NotNil(user.PasswordChangedAt)
+			return nil
+		})
+
+	s.mockHistory.EXPECT().
 		Create(s.ctx, gomock.Any()).
-		DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
-			user.ID = s.testUUID
-			return user, nil
+		DoAndReturn(func(_ context.Context, entry *domain.PasswordHistory) error {
+			s.Equal(s.testUUID, entry.UserID)
+			s.Equal(s.hashedPass, entry.Password)
+			return nil
 		})
 
-	result, err := s.userService.CreateGoogleUser(s.ctx, req)
+	s.mockHistory.EXPECT().
+		Prune(s.ctx, s.testUUID.String(), 2).
+		Return(nil)
+
+	err := s.userService.UpdatePassOrVerify(s.ctx, req, s.testUUID.String())
 
 	s.NoError(err)
-	s.Equal(req.Name, result.Name)
-	s.Equal(req.Email, result.Email)
-	s.Equal(req.VerifiedEmail, result.VerifiedEmail)
 }
 
-func (s *userServiceTestSuite) TestCreateGoogleUser_Success_ExistingUser() {
-	req := &model.CreateGoogleUserRequest{
-		Name:          "Google User",
-		Email:         "existing@example.com",
-		VerifiedEmail: true,
-	}
+func (s *userServiceTestSuite) TestUpdatePassOrVerify_HistoryDisabled() {
+	s.userService.Conf.Password.History = 0
 
-	existingUser := &domain.User{
-		ID:            s.testUUID,
-		Name:          "Existing User",
-		Email:         "existing@example.com",
-		Password:      s.hashedPass,
-		Role:          "user",
-		VerifiedEmail: false,
-		CreatedAt:     time.Now().Add(-24 * time.Hour),
-		UpdatedAt:     time.Now().Add(-24 * time.Hour),
-	}
-
-	updatedUser := &domain.User{
-		ID:            s.testUUID,
-		Name:          "Existing User",
-		Email:         "existing@example.com",
-		Password:      s.hashedPass,
-		Role:          "user",
-		VerifiedEmail: true,
-		CreatedAt:     existingUser.CreatedAt,
-		UpdatedAt:     time.Now(),
+	req := &model.UpdatePassOrVerifyRequest{
+		Password: "password123",
 	}
 
 	s.mockValidator.EXPECT().
@@ -815,121 +1089,188 @@ func (s *userServiceTestSuite) TestCreateGoogleUser_Success_ExistingUser() {
 		Return(nil)
 
 	s.mockUserRepo.EXPECT().
-		GetByEmail(s.ctx, req.Email).
-		Return(existingUser, nil)
+		GetByID(s.ctx, s.testUUID.String()).
+		Return(s.createTestUser(), nil)
+
+	s.mockPassword.EXPECT().
+		Check(req.Password, "Test User", "test@example.com", "", "").
+		Return(nil)
 
 	s.mockUserRepo.EXPECT().
-		Update(s.ctx, gomock.Any()).
-		DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
-			s.True(user.VerifiedEmail)
-			return updatedUser, nil
-		})
+		UpdatePassOrVerify(s.ctx, gomock.Any(), s.testUUID.String()).
+		Return(nil)
 
-	result, err := s.userService.CreateGoogleUser(s.ctx, req)
+	err := s.userService.UpdatePassOrVerify(s.ctx, req, s.testUUID.String())
 
 	s.NoError(err)
-	s.Equal(existingUser.Email, result.Email)
-	s.True(result.VerifiedEmail)
 }
 
-func (s *userServiceTestSuite) TestCreateGoogleUser_ValidationError() {
-	req := &model.CreateGoogleUserRequest{
-		Name:  "",
-		Email: "invalid-email",
-	}
+// ==================== DeleteUser Tests ====================
 
-	validationErr := errors.New("validation failed")
+func (s *userServiceTestSuite) TestDeleteUser_Success() {
+	userID := s.testUUID.String()
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(validationErr)
+	s.mockUserRepo.EXPECT().
+		Delete(s.ctx, userID).
+		Return(nil)
 
-	result, err := s.userService.CreateGoogleUser(s.ctx, req)
+	err := s.userService.DeleteUser(s.ctx, userID)
 
-	s.Error(err)
-	s.Equal(myerrors.ErrInvalidRequest, err)
-	s.Nil(result)
+	s.NoError(err)
 }
 
-func (s *userServiceTestSuite) TestCreateGoogleUser_GetUserError() {
-	req := &model.CreateGoogleUserRequest{
-		Name:          "Google User",
-		Email:         "google@example.com",
-		VerifiedEmail: true,
-	}
+func (s *userServiceTestSuite) TestDeleteUser_UserNotFound() {
+	userID := s.testUUID.String()
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(nil)
+	s.mockUserRepo.EXPECT().
+		Delete(s.ctx, userID).
+		Return(myerrors.ErrUserNotFound)
+
+	err := s.userService.DeleteUser(s.ctx, userID)
+
+	s.Error(err)
+	s.Equal(myerrors.ErrUserNotFound, err)
+}
+
+func (s *userServiceTestSuite) TestDeleteUser_RepositoryError() {
+	userID := s.testUUID.String()
 
 	s.mockUserRepo.EXPECT().
-		GetByEmail(s.ctx, req.Email).
-		Return(nil, myerrors.ErrGetUserFailed)
+		Delete(s.ctx, userID).
+		Return(myerrors.ErrDeleteUserFailed)
 
-	result, err := s.userService.CreateGoogleUser(s.ctx, req)
+	err := s.userService.DeleteUser(s.ctx, userID)
 
 	s.Error(err)
-	s.Equal(myerrors.ErrGetUserFailed, err)
-	s.Nil(result)
+	s.Equal(myerrors.ErrDeleteUserFailed, err)
 }
 
-func (s *userServiceTestSuite) TestCreateGoogleUser_CreateError() {
-	req := &model.CreateGoogleUserRequest{
-		Name:          "Google User",
-		Email:         "google@example.com",
-		VerifiedEmail: true,
-	}
+// ==================== UpdateUserAttributes Tests ====================
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(nil)
+func (s *userServiceTestSuite) TestUpdateUserAttributes_Success() {
+	id := s.testUUID.String()
+	req := &model.UpdateUserAttributesRequest{Attributes: map[string]string{"region": "eu"}}
+	user := s.createTestUser()
+	user.Attributes = req.Attributes
 
-	s.mockUserRepo.EXPECT().
-		GetByEmail(s.ctx, req.Email).
-		Return(nil, myerrors.ErrUserNotFound)
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserRepo.EXPECT().SetAttributes(s.ctx, id, req.Attributes).Return(nil)
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, id).Return(user, nil)
 
-	s.mockUserRepo.EXPECT().
-		Create(s.ctx, gomock.Any()).
-		Return(nil, myerrors.ErrCreateUserFailed)
+	result, err := s.userService.UpdateUserAttributes(s.ctx, id, req)
 
-	result, err := s.userService.CreateGoogleUser(s.ctx, req)
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"region": "eu"}, result.Attributes)
+}
 
-	s.Error(err)
-	s.Equal(myerrors.ErrCreateUserFailed, err)
-	s.Nil(result)
+func (s *userServiceTestSuite) TestUpdateUserAttributes_UserNotFound() {
+	id := s.testUUID.String()
+	req := &model.UpdateUserAttributesRequest{Attributes: map[string]string{"region": "eu"}}
+
+	s.mockValidator.EXPECT().Validate(s.ctx, req).Return(nil)
+	s.mockUserRepo.EXPECT().SetAttributes(s.ctx, id, req.Attributes).Return(myerrors.ErrUserNotFound)
+
+	_, err := s.userService.UpdateUserAttributes(s.ctx, id, req)
+
+	s.Equal(myerrors.ErrUserNotFound, err)
 }
 
-func (s *userServiceTestSuite) TestCreateGoogleUser_UpdateError() {
-	req := &model.CreateGoogleUserRequest{
-		Name:          "Google User",
-		Email:         "existing@example.com",
-		VerifiedEmail: true,
-	}
+// ==================== AuthorizeUser Tests ====================
+
+func (s *userServiceTestSuite) TestAuthorizeUser_PassesAccountAttributes() {
+	id := s.testUUID.String()
+	user := s.createTestUser()
+	user.Attributes = map[string]string{"region": "eu"}
+	user.Roles = domain.NewUserRoles("admin")
+	subject := policy.Subject{UserID: s.testUUID2.String(), Roles: []string{"support"}}
+	env := map[string]any{"ip": "10.0.0.1"}
+
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, id).Return(user, nil)
+	s.mockPolicy.EXPECT().Authorize(s.ctx, policy.Request{
+		Subject: subject,
+		Action:  policy.ReadUser,
+		Resource: policy.Resource{
+			Type:       policy.ResourceUser,
+			ID:         id,
+			OwnerID:    id,
+			Attributes: map[string]any{"region": "eu", "roles": []string{"admin"}},
+		},
+		Environment: env,
+	}).Return(myerrors.ErrForbidden)
 
-	existingUser := &domain.User{
-		ID:            s.testUUID,
-		Name:          "Existing User",
-		Email:         "existing@example.com",
-		Password:      s.hashedPass,
-		Role:          "user",
-		VerifiedEmail: false,
-	}
+	err := s.userService.AuthorizeUser(s.ctx, subject, policy.ReadUser, id, env)
 
-	s.mockValidator.EXPECT().
-		Validate(s.ctx, req).
-		Return(nil)
+	s.Equal(myerrors.ErrForbidden, err)
+}
 
-	s.mockUserRepo.EXPECT().
-		GetByEmail(s.ctx, req.Email).
-		Return(existingUser, nil)
+func (s *userServiceTestSuite) TestAuthorizeUser_UnknownUser() {
+	id := s.testUUID.String()
+	subject := policy.Subject{UserID: id}
 
-	s.mockUserRepo.EXPECT().
-		Update(s.ctx, gomock.Any()).
-		Return(nil, myerrors.ErrUpdateUserFailed)
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, id).Return(nil, myerrors.ErrUserNotFound)
+	s.mockPolicy.EXPECT().Authorize(s.ctx, policy.Request{
+		Subject:  subject,
+		Action:   policy.DeleteUser,
+		Resource: policy.Resource{Type: policy.ResourceUser, ID: id, OwnerID: id},
+	}).Return(nil)
 
-	result, err := s.userService.CreateGoogleUser(s.ctx, req)
+	s.NoError(s.userService.AuthorizeUser(s.ctx, subject, policy.DeleteUser, id, nil))
+}
 
-	s.Error(err)
-	s.Equal(myerrors.ErrUpdateUserFailed, err)
-	s.Nil(result)
+func (s *userServiceTestSuite) TestAuthorizeUser_InvalidIDSkipsLookup() {
+	subject := policy.Subject{UserID: s.testUUID.String()}
+
+	s.mockPolicy.EXPECT().Authorize(s.ctx, gomock.Any()).Return(myerrors.ErrForbidden)
+
+	err := s.userService.AuthorizeUser(s.ctx, subject, policy.ReadUser, "not-a-uuid", nil)
+
+	s.Equal(myerrors.ErrForbidden, err)
+}
+
+func (s *userServiceTestSuite) TestAuthorizeUser_OutrankedUser() {
+	id := s.testUUID.String()
+	user := s.createTestUser()
+	user.Roles = domain.NewUserRoles("admin")
+	subject := policy.Subject{UserID: s.testUUID2.String(), Rights: []string{"getUsers", "manageUsers"}}
+
+	s.mockUserRepo.EXPECT().GetByID(s.ctx, id).Return(user, nil)
+	s.mockRoleSvc.EXPECT().Rights(s.ctx, "admin").Return([]string{"getUsers", "manageRoles", "manageUsers"}, nil)
+
+	err := s.userService.AuthorizeUser(s.ctx, subject, policy.UpdateUser, id, nil)
+
+	s.Equal(myerrors.ErrForbidden, err)
+}
+
+// ==================== Organization Tests ====================
+
+func (s *userServiceTestSuite) TestCreateUser_JoinsOrganization() {
+	organizationID := uuid.Must(uuid.NewV7())
+	ctx := tenant.WithOrganization(s.ctx, organizationID.String())
+	req := &model.CreateUserRequest{Name: "New User", Email: "newuser@example.com", Password: "password123", Roles: []string{"user"}}
+
+	s.mockValidator.EXPECT().Validate(ctx, req).Return(nil)
+	s.mockRoleSvc.EXPECT().Rights(ctx, "user").Return([]string{}, nil)
+	s.mockPassword.EXPECT().Check(req.Password, req.Name, req.Email).Return(nil)
+	s.mockUserRepo.EXPECT().Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
+			s.Equal([]domain.OrganizationMember{{OrganizationID: organizationID, Role: "user"}}, user.Memberships)
+			user.ID = s.testUUID
+			return user, nil
+		})
+
+	_, err := s.userService.CreateUser(ctx, req)
+
+	s.NoError(err)
+}
+
+func (s *userServiceTestSuite) TestCreateUser_OrganizationCannotGrantRoles() {
+	ctx := tenant.WithOrganization(s.ctx, uuid.Must(uuid.NewV7()).String())
+	req := &model.CreateUserRequest{Name: "New User", Email: "newuser@example.com", Password: "password123", Roles: []string{"user", "admin"}}
+
+	s.mockValidator.EXPECT().Validate(ctx, req).Return(nil)
+	s.mockRoleSvc.EXPECT().Rights(ctx, "user", "admin").Return([]string{"manageRoles"}, nil)
+
+	_, err := s.userService.CreateUser(ctx, req)
+
+	s.Equal(myerrors.ErrRoleNotGrantable, err)
 }
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"app/config"
+	"app/internal/application/model"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/domain/repository"
+	"app/internal/pkg/validator"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tommynurwantoro/golog"
+)
+
+const (
+	defaultWebAuthnTimeout = 5 * time.Minute
+	// webAuthnSessionClaim carries the ceremony state inside the challenge token
+	webAuthnSessionClaim = "webauthn_session"
+)
+
+//go:generate mockgen -source=webauthn_service.go -destination=mocks/webauthn_service.go -package=mocks
+type WebAuthnService interface {
+	BeginRegistration(ctx context.Context, user *domain.User) (*model.WebAuthnRegistrationBeginResponse, error)
+	FinishRegistration(
+		ctx context.Context, user *domain.User, req *model.WebAuthnRegistrationFinishRequest,
+	) (*domain.WebAuthnCredential, error)
+	BeginLogin(ctx context.Context, req *model.WebAuthnLoginBeginRequest) (*model.WebAuthnLoginBeginResponse, error)
+	FinishLogin(ctx context.Context, req *model.WebAuthnLoginFinishRequest) (*domain.User, error)
+}
+
+// WebAuthnServiceImpl runs the passkey ceremonies. The state between the begin and finish steps
+// travels in a stored challenge token, so any instance can finish a ceremony and each one works once.
+type WebAuthnServiceImpl struct {
+	Conf                         *config.Config                          `inject:"config"`
+	WebAuthnCredentialRepository repository.WebAuthnCredentialRepository `inject:"webAuthnCredentialRepository"`
+	TokenService                 TokenService                            `inject:"tokenService"`
+	UserService                  UserService                             `inject:"userService"`
+	Validate                     validator.Validator                     `inject:"validator"`
+
+	webAuthn *webauthn.WebAuthn
+}
+
+func (s *WebAuthnServiceImpl) Startup() error {
+	// Passkeys stay disabled until a relying party is configured
+	if s.Conf.WebAuthn.RPID == "" {
+		return nil
+	}
+
+	displayName := s.Conf.WebAuthn.RPDisplayName
+	if displayName == "" {
+		displayName = s.Conf.AppName
+	}
+
+	timeout := s.timeout()
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          s.Conf.WebAuthn.RPID,
+		RPDisplayName: displayName,
+		RPOrigins:     s.Conf.WebAuthn.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: timeout},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: timeout},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.webAuthn = webAuthn
+	return nil
+}
+
+func (s *WebAuthnServiceImpl) Shutdown() error {
+	return nil
+}
+
+func (s *WebAuthnServiceImpl) BeginRegistration(
+	ctx context.Context, user *domain.User,
+) (*model.WebAuthnRegistrationBeginResponse, error) {
+	if s.webAuthn == nil {
+		return nil, myerrors.ErrWebAuthnNotConfigured
+	}
+
+	webAuthnUser, err := s.loadUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := s.webAuthn.BeginRegistration(webAuthnUser,
+		webauthn.WithExclusions(webauthn.Credentials(webAuthnUser.credentials).CredentialDescriptors()),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementPreferred,
+			UserVerification: protocol.VerificationRequired,
+		}),
+	)
+	if err != nil {
+		golog.Error("Error beginning webauthn registration", err)
+		return nil, myerrors.ErrBeginWebAuthnFailed
+	}
+
+	challengeToken, err := s.saveSession(ctx, user.ID.String(), domain.TokenTypeWebAuthnRegistration, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WebAuthnRegistrationBeginResponse{
+		ChallengeToken:          challengeToken.Token,
+		ChallengeTokenExpiresAt: challengeToken.Expires,
+		Options:                 creation,
+	}, nil
+}
+
+func (s *WebAuthnServiceImpl) FinishRegistration(
+	ctx context.Context, user *domain.User, req *model.WebAuthnRegistrationFinishRequest,
+) (*domain.WebAuthnCredential, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if s.webAuthn == nil {
+		return nil, myerrors.ErrWebAuthnNotConfigured
+	}
+
+	session, err := s.consumeSession(ctx, req.ChallengeToken, domain.TokenTypeWebAuthnRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if session.userID != user.ID.String() {
+		return nil, myerrors.ErrInvalidToken
+	}
+
+	webAuthnUser, err := s.loadUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		golog.Error("Error parsing webauthn registration response", err)
+		return nil, myerrors.ErrInvalidWebAuthnResponse
+	}
+
+	credential, err := s.webAuthn.CreateCredential(webAuthnUser, session.SessionData, parsed)
+	if err != nil {
+		golog.Error("Error verifying webauthn registration response", err)
+		return nil, myerrors.ErrInvalidWebAuthnResponse
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return s.WebAuthnCredentialRepository.Create(ctx, &domain.WebAuthnCredential{
+		UserID:          user.ID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		SignCount:       credential.Authenticator.SignCount,
+		AAGUID:          credential.Authenticator.AAGUID,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		Name:            req.Name,
+	})
+}
+
+// BeginLogin starts a passkey login for the account with the given email, offering only its own passkeys.
+func (s *WebAuthnServiceImpl) BeginLogin(
+	ctx context.Context, req *model.WebAuthnLoginBeginRequest,
+) (*model.WebAuthnLoginBeginResponse, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if s.webAuthn == nil {
+		return nil, myerrors.ErrWebAuthnNotConfigured
+	}
+
+	user, err := s.UserService.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	webAuthnUser, err := s.loadUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webAuthnUser.credentials) == 0 {
+		return nil, myerrors.ErrWebAuthnNoCredentials
+	}
+
+	assertion, session, err := s.webAuthn.BeginLogin(webAuthnUser,
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		golog.Error("Error beginning webauthn login", err)
+		return nil, myerrors.ErrBeginWebAuthnFailed
+	}
+
+	challengeToken, err := s.saveSession(ctx, user.ID.String(), domain.TokenTypeWebAuthnLogin, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WebAuthnLoginBeginResponse{
+		ChallengeToken:          challengeToken.Token,
+		ChallengeTokenExpiresAt: challengeToken.Expires,
+		Options:                 assertion,
+	}, nil
+}
+
+// FinishLogin verifies the assertion and returns the user it belongs to.
+// User verification is required, so a passkey login already counts as two factors.
+func (s *WebAuthnServiceImpl) FinishLogin(
+	ctx context.Context, req *model.WebAuthnLoginFinishRequest,
+) (*domain.User, error) {
+	if err := s.Validate.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if s.webAuthn == nil {
+		return nil, myerrors.ErrWebAuthnNotConfigured
+	}
+
+	session, err := s.consumeSession(ctx, req.ChallengeToken, domain.TokenTypeWebAuthnLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.GetUserByID(ctx, session.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	webAuthnUser, err := s.loadUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		golog.Error("Error parsing webauthn login response", err)
+		return nil, myerrors.ErrInvalidWebAuthnResponse
+	}
+
+	credential, err := s.webAuthn.ValidateLogin(webAuthnUser, session.SessionData, parsed)
+	if err != nil {
+		golog.Error("Error verifying webauthn login response", err)
+		return nil, myerrors.ErrInvalidWebAuthnResponse
+	}
+
+	// A signature counter that did not move forward means the private key may have been copied
+	if credential.Authenticator.CloneWarning {
+		golog.Info(fmt.Sprintf("WebAuthn signature counter did not increase for user %s", user.ID))
+		return nil, myerrors.ErrInvalidWebAuthnResponse
+	}
+
+	for i := range webAuthnUser.stored {
+		stored := &webAuthnUser.stored[i]
+		if !bytes.Equal(stored.CredentialID, credential.ID) {
+			continue
+		}
+
+		now := time.Now().UTC()
+		stored.SignCount = credential.Authenticator.SignCount
+		stored.BackupState = credential.Flags.BackupState
+		stored.LastUsedAt = &now
+		if err = s.WebAuthnCredentialRepository.UpdateAfterLogin(ctx, stored); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return user, nil
+}
+
+func (s *WebAuthnServiceImpl) timeout() time.Duration {
+	if s.Conf.WebAuthn.Timeout > 0 {
+		return s.Conf.WebAuthn.Timeout
+	}
+	return defaultWebAuthnTimeout
+}
+
+func (s *WebAuthnServiceImpl) loadUser(ctx context.Context, user *domain.User) (*webAuthnUser, error) {
+	stored, err := s.WebAuthnCredentialRepository.GetByUserID(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return newWebAuthnUser(user, stored), nil
+}
+
+func (s *WebAuthnServiceImpl) saveSession(
+	ctx context.Context, userID string, tokenType domain.TokenType, session *webauthn.SessionData,
+) (*domain.Token, error) {
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		golog.Error("Error encoding webauthn session", err)
+		return nil, myerrors.ErrBeginWebAuthnFailed
+	}
+
+	return s.TokenService.GenerateChallengeToken(ctx, userID, tokenType, time.Now().UTC().Add(s.timeout()),
+		jwt.MapClaims{webAuthnSessionClaim: string(sessionJSON)})
+}
+
+type webAuthnSession struct {
+	webauthn.SessionData
+	userID string
+}
+
+func (s *WebAuthnServiceImpl) consumeSession(
+	ctx context.Context, challengeToken string, tokenType domain.TokenType,
+) (*webAuthnSession, error) {
+	claims, err := s.TokenService.ConsumeChallengeToken(ctx, challengeToken, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := claims["user_id"].(string)
+	sessionJSON, ok := claims[webAuthnSessionClaim].(string)
+	if !ok {
+		return nil, myerrors.ErrInvalidTokenClaims
+	}
+
+	session := &webAuthnSession{userID: userID}
+	if err = json.Unmarshal([]byte(sessionJSON), &session.SessionData); err != nil {
+		golog.Error("Error decoding webauthn session", err)
+		return nil, myerrors.ErrInvalidTokenClaims
+	}
+
+	return session, nil
+}
+
+// webAuthnUser adapts a user and their stored passkeys to the webauthn.User interface.
+// The user handle is the raw UUID, which reveals nothing about the account to the authenticator.
+type webAuthnUser struct {
+	user        *domain.User
+	stored      []domain.WebAuthnCredential
+	credentials []webauthn.Credential
+}
+
+func newWebAuthnUser(user *domain.User, stored []domain.WebAuthnCredential) *webAuthnUser {
+	credentials := make([]webauthn.Credential, 0, len(stored))
+	for _, credential := range stored {
+		var transports []protocol.AuthenticatorTransport
+		for _, transport := range strings.Split(credential.Transports, ",") {
+			if transport != "" {
+				transports = append(transports, protocol.AuthenticatorTransport(transport))
+			}
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: credential.BackupEligible,
+				BackupState:    credential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+
+	return &webAuthnUser{user: user, stored: stored, credentials: credentials}
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Name
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
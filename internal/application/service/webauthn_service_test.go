@@ -0,0 +1,443 @@
+package service
+
+import (
+	"app/config"
+	mockRepository "app/internal/adapter/database/repository/mocks"
+	"app/internal/application/model"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	mockValidator "app/internal/pkg/validator/mocks"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:8888"
+)
+
+// softAuthenticator is a minimal platform authenticator: one ES256 key, "none" attestation,
+// and user verification on every ceremony.
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator() (*softAuthenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID := make([]byte, 32)
+	if _, err = rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+
+	return &softAuthenticator{key: key, credentialID: credentialID}, nil
+}
+
+func (a *softAuthenticator) authData(rpID string, flags protocol.AuthenticatorFlags, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, byte(flags))
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attested...)
+}
+
+func (a *softAuthenticator) clientData(ceremony protocol.CeremonyType, challenge protocol.URLEncodedBase64, origin string) []byte {
+	clientData, _ := json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: challenge.String(),
+		Origin:    origin,
+	})
+	return clientData
+}
+
+// Create answers navigator.credentials.create() and returns the PublicKeyCredential as JSON.
+func (a *softAuthenticator) Create(options *protocol.CredentialCreation, origin string) ([]byte, error) {
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attested := make([]byte, 16) // AAGUID left empty, as "none" attestation allows
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credentialID)))
+	attested = append(attested, a.credentialID...)
+	attested = append(attested, publicKey...)
+
+	flags := protocol.FlagUserPresent | protocol.FlagUserVerified | protocol.FlagAttestedCredentialData
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(options.Response.RelyingParty.ID, flags, attested),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    a.clientData(protocol.CreateCeremony, options.Response.Challenge, origin),
+			"attestationObject": protocol.URLEncodedBase64(attestationObject),
+		},
+	})
+}
+
+// Get answers navigator.credentials.get() and returns the PublicKeyCredential as JSON.
+func (a *softAuthenticator) Get(options *protocol.CredentialAssertion, origin string, userHandle []byte) ([]byte, error) {
+	a.signCount++
+
+	authData := a.authData(options.Response.RelyingPartyID, protocol.FlagUserPresent|protocol.FlagUserVerified, nil)
+	clientData := a.clientData(protocol.AssertCeremony, options.Response.Challenge, origin)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    protocol.URLEncodedBase64(clientData),
+			"authenticatorData": protocol.URLEncodedBase64(authData),
+			"signature":         protocol.URLEncodedBase64(signature),
+			"userHandle":        protocol.URLEncodedBase64(userHandle),
+		},
+	})
+}
+
+type webAuthnServiceTestSuite struct {
+	suite.Suite
+	mockCtrl           *gomock.Controller
+	mockCredentialRepo *mockRepository.MockWebAuthnCredentialRepository
+	mockTokenSvc       *mocks.MockTokenService
+	mockUserSvc        *mocks.MockUserService
+	mockValidator      *mockValidator.MockValidator
+	webAuthnService    *WebAuthnServiceImpl
+	ctx                context.Context
+	user               *domain.User
+	authenticator      *softAuthenticator
+	credentials        []domain.WebAuthnCredential
+	challenges         map[string]jwt.MapClaims
+}
+
+func TestWebAuthnService(t *testing.T) {
+	suite.Run(t, new(webAuthnServiceTestSuite))
+}
+
+func (s *webAuthnServiceTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockCredentialRepo = mockRepository.NewMockWebAuthnCredentialRepository(s.mockCtrl)
+	s.mockTokenSvc = mocks.NewMockTokenService(s.mockCtrl)
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockValidator = mockValidator.NewMockValidator(s.mockCtrl)
+	s.mockValidator.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	s.webAuthnService = &WebAuthnServiceImpl{
+		Conf: &config.Config{
+			AppName: "template",
+			WebAuthn: config.WebAuthnConfig{
+				RPID:      testRPID,
+				RPOrigins: []string{testOrigin},
+				Timeout:   time.Minute,
+			},
+		},
+		WebAuthnCredentialRepository: s.mockCredentialRepo,
+		TokenService:                 s.mockTokenSvc,
+		UserService:                  s.mockUserSvc,
+		Validate:                     s.mockValidator,
+	}
+	s.Require().NoError(s.webAuthnService.Startup())
+
+	s.ctx = context.Background()
+	s.user = &domain.User{
+		ID:    uuid.Must(uuid.NewV7()),
+		Name:  "Test User",
+		Email: "test@example.com",
+	}
+
+	var err error
+	s.authenticator, err = newSoftAuthenticator()
+	s.Require().NoError(err)
+
+	s.credentials = nil
+	s.challenges = map[string]jwt.MapClaims{}
+	s.fakeStores()
+}
+
+func (s *webAuthnServiceTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// fakeStores backs the challenge tokens and the credential table with in-memory state.
+func (s *webAuthnServiceTestSuite) fakeStores() {
+	s.mockTokenSvc.EXPECT().
+		GenerateChallengeToken(s.ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context, userID string, tokenType domain.TokenType, expires time.Time, claims jwt.MapClaims,
+		) (*domain.Token, error) {
+			challengeToken := uuid.NewString()
+			claims["user_id"] = userID
+			claims["token_type"] = tokenType.String()
+			s.challenges[challengeToken] = claims
+			return &domain.Token{Token: challengeToken, Type: tokenType, Expires: expires}, nil
+		}).AnyTimes()
+	s.mockTokenSvc.EXPECT().
+		ConsumeChallengeToken(s.ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, challengeToken string, tokenType domain.TokenType) (jwt.MapClaims, error) {
+			claims, ok := s.challenges[challengeToken]
+			if !ok || claims["token_type"] != tokenType.String() {
+				return nil, myerrors.ErrInvalidToken
+			}
+			delete(s.challenges, challengeToken)
+			return claims, nil
+		}).AnyTimes()
+
+	s.mockCredentialRepo.EXPECT().
+		GetByUserID(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, userID string) ([]domain.WebAuthnCredential, error) {
+			var credentials []domain.WebAuthnCredential
+			for _, credential := range s.credentials {
+				if credential.UserID.String() == userID {
+					credentials = append(credentials, credential)
+				}
+			}
+			return credentials, nil
+		}).AnyTimes()
+	s.mockCredentialRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, credential *domain.WebAuthnCredential) (*domain.WebAuthnCredential, error) {
+			credential.ID = uuid.Must(uuid.NewV7())
+			s.credentials = append(s.credentials, *credential)
+			return credential, nil
+		}).AnyTimes()
+	s.mockCredentialRepo.EXPECT().
+		UpdateAfterLogin(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, credential *domain.WebAuthnCredential) error {
+			for i := range s.credentials {
+				if s.credentials[i].ID == credential.ID {
+					s.credentials[i] = *credential
+					return nil
+				}
+			}
+			return myerrors.ErrWebAuthnNoCredentials
+		}).AnyTimes()
+}
+
+func (s *webAuthnServiceTestSuite) register() *domain.WebAuthnCredential {
+	begin, err := s.webAuthnService.BeginRegistration(s.ctx, s.user)
+	s.Require().NoError(err)
+
+	response, err := s.authenticator.Create(begin.Options, testOrigin)
+	s.Require().NoError(err)
+
+	credential, err := s.webAuthnService.FinishRegistration(s.ctx, s.user, &model.WebAuthnRegistrationFinishRequest{
+		ChallengeToken: begin.ChallengeToken,
+		Name:           "Laptop",
+		Credential:     response,
+	})
+	s.Require().NoError(err)
+
+	return credential
+}
+
+func (s *webAuthnServiceTestSuite) beginLogin() *model.WebAuthnLoginBeginResponse {
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, s.user.Email).Return(s.user, nil)
+
+	begin, err := s.webAuthnService.BeginLogin(s.ctx, &model.WebAuthnLoginBeginRequest{Email: s.user.Email})
+	s.Require().NoError(err)
+
+	return begin
+}
+
+// ==================== Registration Tests ====================
+
+func (s *webAuthnServiceTestSuite) TestRegistration_SoftwareAuthenticator() {
+	credential := s.register()
+
+	s.Equal(s.user.ID, credential.UserID)
+	s.Equal(s.authenticator.credentialID, credential.CredentialID)
+	s.Equal("none", credential.AttestationType)
+	s.Equal("Laptop", credential.Name)
+	s.Len(s.credentials, 1)
+}
+
+func (s *webAuthnServiceTestSuite) TestBeginRegistration_ExcludesRegisteredPasskeys() {
+	s.register()
+
+	begin, err := s.webAuthnService.BeginRegistration(s.ctx, s.user)
+
+	s.Require().NoError(err)
+	s.Require().Len(begin.Options.Response.CredentialExcludeList, 1)
+	s.Equal(s.authenticator.credentialID, []byte(begin.Options.Response.CredentialExcludeList[0].CredentialID))
+}
+
+func (s *webAuthnServiceTestSuite) TestFinishRegistration_WrongOrigin() {
+	begin, err := s.webAuthnService.BeginRegistration(s.ctx, s.user)
+	s.Require().NoError(err)
+
+	response, err := s.authenticator.Create(begin.Options, "https://evil.example.com")
+	s.Require().NoError(err)
+
+	_, err = s.webAuthnService.FinishRegistration(s.ctx, s.user, &model.WebAuthnRegistrationFinishRequest{
+		ChallengeToken: begin.ChallengeToken,
+		Credential:     response,
+	})
+
+	s.ErrorIs(err, myerrors.ErrInvalidWebAuthnResponse)
+	s.Empty(s.credentials)
+}
+
+func (s *webAuthnServiceTestSuite) TestFinishRegistration_ChallengeOfAnotherUser() {
+	begin, err := s.webAuthnService.BeginRegistration(s.ctx, s.user)
+	s.Require().NoError(err)
+
+	response, err := s.authenticator.Create(begin.Options, testOrigin)
+	s.Require().NoError(err)
+
+	otherUser := &domain.User{ID: uuid.Must(uuid.NewV7()), Email: "other@example.com"}
+	_, err = s.webAuthnService.FinishRegistration(s.ctx, otherUser, &model.WebAuthnRegistrationFinishRequest{
+		ChallengeToken: begin.ChallengeToken,
+		Credential:     response,
+	})
+
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *webAuthnServiceTestSuite) TestBeginRegistration_NotConfigured() {
+	s.webAuthnService.Conf.WebAuthn.RPID = ""
+	s.webAuthnService.webAuthn = nil
+	s.Require().NoError(s.webAuthnService.Startup())
+
+	_, err := s.webAuthnService.BeginRegistration(s.ctx, s.user)
+
+	s.ErrorIs(err, myerrors.ErrWebAuthnNotConfigured)
+}
+
+// ==================== Login Tests ====================
+
+func (s *webAuthnServiceTestSuite) TestLogin_SoftwareAuthenticator() {
+	s.register()
+	begin := s.beginLogin()
+
+	response, err := s.authenticator.Get(begin.Options, testOrigin, s.user.ID[:])
+	s.Require().NoError(err)
+
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(s.user, nil)
+
+	user, err := s.webAuthnService.FinishLogin(s.ctx, &model.WebAuthnLoginFinishRequest{
+		ChallengeToken: begin.ChallengeToken,
+		Credential:     response,
+	})
+
+	s.Require().NoError(err)
+	s.Equal(s.user.ID, user.ID)
+	s.Equal(uint32(1), s.credentials[0].SignCount)
+	s.NotNil(s.credentials[0].LastUsedAt)
+}
+
+func (s *webAuthnServiceTestSuite) TestFinishLogin_ChallengeIsSingleUse() {
+	s.register()
+	begin := s.beginLogin()
+
+	response, err := s.authenticator.Get(begin.Options, testOrigin, s.user.ID[:])
+	s.Require().NoError(err)
+
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(s.user, nil)
+
+	req := &model.WebAuthnLoginFinishRequest{ChallengeToken: begin.ChallengeToken, Credential: response}
+	_, err = s.webAuthnService.FinishLogin(s.ctx, req)
+	s.Require().NoError(err)
+
+	_, err = s.webAuthnService.FinishLogin(s.ctx, req)
+	s.ErrorIs(err, myerrors.ErrInvalidToken)
+}
+
+func (s *webAuthnServiceTestSuite) TestFinishLogin_InvalidSignature() {
+	s.register()
+	begin := s.beginLogin()
+
+	// A different key with the same credential id cannot produce a valid assertion
+	impostor, err := newSoftAuthenticator()
+	s.Require().NoError(err)
+	impostor.credentialID = s.authenticator.credentialID
+
+	response, err := impostor.Get(begin.Options, testOrigin, s.user.ID[:])
+	s.Require().NoError(err)
+
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(s.user, nil)
+
+	_, err = s.webAuthnService.FinishLogin(s.ctx, &model.WebAuthnLoginFinishRequest{
+		ChallengeToken: begin.ChallengeToken,
+		Credential:     response,
+	})
+
+	s.ErrorIs(err, myerrors.ErrInvalidWebAuthnResponse)
+}
+
+func (s *webAuthnServiceTestSuite) TestFinishLogin_ClonedAuthenticator() {
+	s.register()
+	s.credentials[0].SignCount = 10
+	begin := s.beginLogin()
+
+	response, err := s.authenticator.Get(begin.Options, testOrigin, s.user.ID[:])
+	s.Require().NoError(err)
+
+	s.mockUserSvc.EXPECT().GetUserByID(s.ctx, s.user.ID.String()).Return(s.user, nil)
+
+	_, err = s.webAuthnService.FinishLogin(s.ctx, &model.WebAuthnLoginFinishRequest{
+		ChallengeToken: begin.ChallengeToken,
+		Credential:     response,
+	})
+
+	s.ErrorIs(err, myerrors.ErrInvalidWebAuthnResponse)
+	s.Equal(uint32(10), s.credentials[0].SignCount)
+}
+
+func (s *webAuthnServiceTestSuite) TestBeginLogin_NoPasskeys() {
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, s.user.Email).Return(s.user, nil)
+
+	_, err := s.webAuthnService.BeginLogin(s.ctx, &model.WebAuthnLoginBeginRequest{Email: s.user.Email})
+
+	s.ErrorIs(err, myerrors.ErrWebAuthnNoCredentials)
+}
+
+func (s *webAuthnServiceTestSuite) TestBeginLogin_UserNotFound() {
+	s.mockUserSvc.EXPECT().GetUserByEmail(s.ctx, "unknown@example.com").Return(nil, myerrors.ErrUserNotFound)
+
+	_, err := s.webAuthnService.BeginLogin(s.ctx, &model.WebAuthnLoginBeginRequest{Email: "unknown@example.com"})
+
+	s.ErrorIs(err, myerrors.ErrUserNotFound)
+}
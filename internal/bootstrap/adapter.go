@@ -12,10 +12,25 @@ func RegisterAdapters() {
 	appContainer.RegisterService("database", new(database.Gorm))
 	appContainer.RegisterService("rest", new(rest.Fiber))
 	appContainer.RegisterService("email", new(email.EmailAdapterImpl))
-	appContainer.RegisterService("oauth", new(oauth.GoogleAdapterImpl))
+	appContainer.RegisterService("oauth", new(oauth.OIDCAdapterImpl))
 }
 
 func RegisterRepositories() {
 	appContainer.RegisterService("userRepository", new(repository.UserRepositoryImpl))
 	appContainer.RegisterService("tokenRepository", new(repository.TokenRepositoryImpl))
+	appContainer.RegisterService("securityEventRepository", new(repository.SecurityEventRepositoryImpl))
+	appContainer.RegisterService("revokedTokenRepository", new(repository.RevokedTokenRepositoryImpl))
+	appContainer.RegisterService("mfaRepository", new(repository.MFARepositoryImpl))
+	appContainer.RegisterService("webAuthnCredentialRepository", new(repository.WebAuthnCredentialRepositoryImpl))
+	appContainer.RegisterService("userIdentityRepository", new(repository.UserIdentityRepositoryImpl))
+	appContainer.RegisterService("oauthStateRepository", new(repository.OAuthStateRepositoryImpl))
+	appContainer.RegisterService("oauthClientRepository", new(repository.OAuthClientRepositoryImpl))
+	appContainer.RegisterService("deviceAuthorizationRepository", new(repository.DeviceAuthorizationRepositoryImpl))
+	appContainer.RegisterService("roleRepository", new(repository.RoleRepositoryImpl))
+	appContainer.RegisterService("organizationRepository", new(repository.OrganizationRepositoryImpl))
+	appContainer.RegisterService("invitationRepository", new(repository.InvitationRepositoryImpl))
+	appContainer.RegisterService("apiKeyRepository", new(repository.APIKeyRepositoryImpl))
+	appContainer.RegisterService("lockoutRepository", new(repository.LockoutRepositoryImpl))
+	appContainer.RegisterService("loginEventRepository", new(repository.LoginEventRepositoryImpl))
+	appContainer.RegisterService("passwordHistoryRepository", new(repository.PasswordHistoryRepositoryImpl))
 }
@@ -11,7 +11,22 @@ func RegisterServices() {
 	appContainer.RegisterService("healthCheckService", new(service.HealthCheckServiceImpl))
 	appContainer.RegisterService("authService", new(service.AuthServiceImpl))
 	appContainer.RegisterService("userService", new(service.UserServiceImpl))
+	appContainer.RegisterService("roleService", new(service.RoleServiceImpl))
+	appContainer.RegisterService("accessPolicyService", new(service.AccessPolicyServiceImpl))
+	appContainer.RegisterService("organizationService", new(service.OrganizationServiceImpl))
+	appContainer.RegisterService("invitationService", new(service.InvitationServiceImpl))
+	appContainer.RegisterService("apiKeyService", new(service.APIKeyServiceImpl))
+	appContainer.RegisterService("lockoutService", new(service.LockoutServiceImpl))
+	appContainer.RegisterService("loginHistoryService", new(service.LoginHistoryServiceImpl))
 	appContainer.RegisterService("tokenService", new(service.TokenServiceImpl))
+	appContainer.RegisterService("revocationService", new(service.RevocationServiceImpl))
+	appContainer.RegisterService("mfaService", new(service.MFAServiceImpl))
+	appContainer.RegisterService("webAuthnService", new(service.WebAuthnServiceImpl))
+	appContainer.RegisterService("identityService", new(service.IdentityServiceImpl))
+	appContainer.RegisterService("oauthService", new(service.OAuthServiceImpl))
+	appContainer.RegisterService("oauthClientService", new(service.OAuthClientServiceImpl))
+	appContainer.RegisterService("oauthServerService", new(service.OAuthServerServiceImpl))
+	appContainer.RegisterService("deviceAuthorizationService", new(service.DeviceAuthorizationServiceImpl))
 }
 
 func RegisterMiddleware() {
@@ -22,5 +37,18 @@ func RegisterHandlers() {
 	appContainer.RegisterService("healthCheckHandler", new(handler.HealthCheckHandlerImpl))
 	appContainer.RegisterService("authHandler", new(handler.AuthHandlerImpl))
 	appContainer.RegisterService("userHandler", new(handler.UserHandlerImpl))
+	appContainer.RegisterService("roleHandler", new(handler.RoleHandlerImpl))
+	appContainer.RegisterService("organizationHandler", new(handler.OrganizationHandlerImpl))
+	appContainer.RegisterService("sessionHandler", new(handler.SessionHandlerImpl))
+	appContainer.RegisterService("mfaHandler", new(handler.MFAHandlerImpl))
+	appContainer.RegisterService("webAuthnHandler", new(handler.WebAuthnHandlerImpl))
+	appContainer.RegisterService("identityHandler", new(handler.IdentityHandlerImpl))
+	appContainer.RegisterService("oauthServerHandler", new(handler.OAuthServerHandlerImpl))
+	appContainer.RegisterService("oauthClientHandler", new(handler.OAuthClientHandlerImpl))
+	appContainer.RegisterService("deviceHandler", new(handler.DeviceHandlerImpl))
+	appContainer.RegisterService("apiKeyHandler", new(handler.APIKeyHandlerImpl))
+	appContainer.RegisterService("lockoutHandler", new(handler.LockoutHandlerImpl))
+	appContainer.RegisterService("loginHistoryHandler", new(handler.LoginHistoryHandlerImpl))
+	appContainer.RegisterService("wellKnownHandler", new(handler.WellKnownHandlerImpl))
 	appContainer.RegisterService("router", new(router.Router))
 }
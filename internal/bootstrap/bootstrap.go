@@ -3,6 +3,8 @@ package bootstrap
 import (
 	"app/config"
 	"app/internal/adapter/rest"
+	"app/internal/pkg/password"
+	"app/internal/pkg/token"
 	"app/internal/pkg/validator"
 	"context"
 	"errors"
@@ -22,6 +24,8 @@ func RunService(conf *config.Config) {
 
 	appContainer.RegisterService("config", conf)
 	appContainer.RegisterService("validator", validator.NewGoValidator())
+	appContainer.RegisterService("keyManager", new(token.KeyManagerImpl))
+	appContainer.RegisterService("passwordPolicy", new(password.PolicyImpl))
 
 	RegisterAdapters()
 	RegisterRepositories()
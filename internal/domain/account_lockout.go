@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountLockout counts the failed password logins of a user since the last successful one. Users without
+// failures have no row.
+type AccountLockout struct {
+	UserID         uuid.UUID `gorm:"primaryKey;not null"`
+	FailedAttempts int       `gorm:"not null"`
+	LastFailedAt   time.Time `gorm:"not null"`
+	// LockedUntil is set when the failures reach lockout.max_attempts
+	LockedUntil *time.Time
+	User        *User `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (l *AccountLockout) Locked(now time.Time) bool {
+	return l.LockedUntil != nil && now.Before(*l.LockedUntil)
+}
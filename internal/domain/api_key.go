@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognize
+const APIKeyPrefix = "ak_"
+
+// APIKey lets scripts and integrations call the API without a password. The key is "<prefix>.<secret>": the
+// prefix finds the key and only the hash of the secret is kept. A personal key acts as its user with at most
+// the rights of its scopes; a service key belongs to no user and has exactly the rights of its scopes.
+type APIKey struct {
+	ID        uuid.UUID  `gorm:"primaryKey;not null"`
+	UserID    *uuid.UUID `gorm:"index"`
+	CreatedBy *uuid.UUID
+	Name      string `gorm:"not null"`
+	Prefix    string `gorm:"not null;uniqueIndex"`
+	// SecretHash is the HMAC of the secret, see crypto.HashToken
+	SecretHash string `gorm:"not null"`
+	// Scopes is a comma separated list of permissions
+	Scopes     string `gorm:"not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime:milli"`
+}
+
+func (k *APIKey) ScopeList() []string {
+	return splitList(k.Scopes)
+}
+
+// Service reports whether the key belongs to no user.
+func (k *APIKey) Service() bool {
+	return k.UserID == nil
+}
+
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
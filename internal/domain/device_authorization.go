@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+)
+
+// DeviceAuthorization is a pending device login (RFC 8628). The device polls with the device code while the
+// user approves the user code in a browser. Only hashes of both codes are kept.
+type DeviceAuthorization struct {
+	ID             uuid.UUID `gorm:"primaryKey;not null"`
+	DeviceCodeHash string    `gorm:"not null;uniqueIndex"`
+	UserCodeHash   string    `gorm:"not null;uniqueIndex"`
+	// DeviceName, UserAgent and IPAddress describe the device, the session is opened with them
+	DeviceName string `gorm:"not null"`
+	UserAgent  string `gorm:"not null"`
+	IPAddress  string `gorm:"not null"`
+	Status     string `gorm:"not null"`
+	// UserID is set once the user approves or denies the device
+	UserID *uuid.UUID
+	// PollInterval is the number of seconds the device must wait between two polls
+	PollInterval int `gorm:"not null"`
+	LastPolledAt *time.Time
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime:milli"`
+}
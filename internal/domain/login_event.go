@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginEvent records a login attempt, successful or not, and where it came from.
+type LoginEvent struct {
+	ID            uuid.UUID   `gorm:"primaryKey;not null"`
+	UserID        *uuid.UUID  `gorm:"index"`
+	Email         string      `gorm:"not null"`
+	Method        LoginMethod `gorm:"not null"`
+	Success       bool        `gorm:"not null"`
+	FailureReason string      `gorm:"not null"`
+	IPAddress     string      `gorm:"not null"`
+	UserAgent     string      `gorm:"not null"`
+	// DeviceFingerprint is a hash of the device name and user agent, see LoginHistoryService
+	DeviceFingerprint string    `gorm:"not null"`
+	CreatedAt         time.Time `gorm:"autoCreateTime:milli"`
+}
+
+type LoginMethod string
+
+const (
+	LoginMethodPassword     LoginMethod = "password"
+	LoginMethodRefreshToken LoginMethod = "refreshToken"
+	LoginMethodOAuth        LoginMethod = "oauth"
+)
+
+func (m LoginMethod) String() string {
+	return string(m)
+}
@@ -0,0 +1,13 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrUnknownScope       = errors.New("scopes must be existing permissions")
+	ErrScopeNotGrantable  = errors.New("you cannot give a key a scope you do not have")
+	ErrInvalidExpiry      = errors.New("expiry must be in the future")
+	ErrSaveAPIKeyFailed   = errors.New("failed to save api key")
+	ErrGetAPIKeyFailed    = errors.New("failed to get api key")
+	ErrDeleteAPIKeyFailed = errors.New("failed to delete api key")
+)
@@ -7,4 +7,5 @@ import (
 var (
 	ErrInvalidRequest = errors.New("invalid request")
 	ErrHashPassword   = errors.New("error hashing password")
+	ErrForbidden      = errors.New("you don't have permission to access this resource")
 )
@@ -0,0 +1,12 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrAccountLocked       = errors.New("account is locked after too many failed logins, check your email to unlock it")
+	ErrLoginThrottled      = errors.New("too many failed logins, please try again later")
+	ErrLockoutNotFound     = errors.New("account has no failed logins")
+	ErrGetLockoutFailed    = errors.New("failed to get account lockout")
+	ErrSaveLockoutFailed   = errors.New("failed to save account lockout")
+	ErrDeleteLockoutFailed = errors.New("failed to delete account lockout")
+)
@@ -0,0 +1,8 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrCreateLoginEventFailed = errors.New("failed to create login event")
+	ErrGetLoginEventFailed    = errors.New("failed to get login events")
+)
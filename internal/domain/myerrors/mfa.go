@@ -0,0 +1,12 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrMFAAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled          = errors.New("two-factor authentication is not enrolled")
+	ErrInvalidMFACode          = errors.New("invalid two-factor authentication code")
+	ErrGenerateMFASecretFailed = errors.New("failed to generate two-factor authentication secret")
+	ErrSaveMFAFailed           = errors.New("failed to save two-factor authentication")
+	ErrUseMFACodeFailed        = errors.New("failed to use two-factor authentication code")
+)
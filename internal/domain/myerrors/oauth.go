@@ -0,0 +1,22 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrOAuthProviderNotFound = errors.New("oauth provider not found")
+	ErrInvalidOAuthState     = errors.New("invalid oauth state")
+	ErrInvalidRedirectURI    = errors.New("redirect uri is not allowed")
+	ErrOAuthExchangeFailed   = errors.New("failed to exchange oauth authorization code")
+	ErrInvalidIDToken        = errors.New("invalid id token")
+	ErrOAuthEmailMissing     = errors.New("oauth provider did not return an email address")
+	ErrOAuthDiscoveryFailed  = errors.New("failed to discover oauth provider")
+	ErrOAuthAccountNotLinked = errors.New("an account with this email already exists, log in and link the provider first")
+	ErrIdentityNotFound      = errors.New("identity not found")
+	ErrIdentityAlreadyLinked = errors.New("identity already linked to an account")
+	ErrLastLoginMethod       = errors.New("cannot unlink the last way to log in")
+	ErrSaveIdentityFailed    = errors.New("failed to save identity")
+	ErrGetIdentityFailed     = errors.New("failed to get identity")
+	ErrDeleteIdentityFailed  = errors.New("failed to delete identity")
+	ErrSaveOAuthStateFailed  = errors.New("failed to save oauth state")
+	ErrUseOAuthStateFailed   = errors.New("failed to use oauth state")
+)
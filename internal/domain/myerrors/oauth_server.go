@@ -0,0 +1,28 @@
+package myerrors
+
+import "errors"
+
+// Errors of the authorization server, named after the error codes of RFC 6749 section 5.2
+var (
+	ErrInvalidClient           = errors.New("client authentication failed")
+	ErrInvalidGrant            = errors.New("invalid, expired or revoked grant")
+	ErrUnauthorizedClient      = errors.New("client is not allowed to use this grant type")
+	ErrUnsupportedGrantType    = errors.New("unsupported grant type")
+	ErrUnsupportedResponseType = errors.New("unsupported response type")
+	ErrInvalidScope            = errors.New("requested scope is not allowed")
+	ErrOAuthClientNotFound     = errors.New("oauth client not found")
+	ErrSaveOAuthClientFailed   = errors.New("failed to save oauth client")
+	ErrGetOAuthClientFailed    = errors.New("failed to get oauth client")
+	ErrDeleteOAuthClientFailed = errors.New("failed to delete oauth client")
+)
+
+// Errors of the device authorization grant, named after the error codes of RFC 8628 section 3.5
+var (
+	ErrAuthorizationPending          = errors.New("the user has not approved the device yet")
+	ErrSlowDown                      = errors.New("polling too often, slow down")
+	ErrAccessDenied                  = errors.New("the user denied the device")
+	ErrExpiredToken                  = errors.New("device code expired")
+	ErrInvalidUserCode               = errors.New("invalid or expired user code")
+	ErrSaveDeviceAuthorizationFailed = errors.New("failed to save device authorization")
+	ErrGetDeviceAuthorizationFailed  = errors.New("failed to get device authorization")
+)
@@ -0,0 +1,20 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrOrganizationNotFound      = errors.New("organization not found")
+	ErrOrganizationAlreadyExists = errors.New("organization already exists")
+	ErrNoOrganization            = errors.New("no organization selected, set the X-Organization-ID header")
+	ErrNotOrganizationMember     = errors.New("you are not a member of this organization")
+	ErrMemberNotFound            = errors.New("member not found")
+	ErrRoleNotGrantable          = errors.New("you cannot grant a role with permissions you do not have")
+	ErrSaveOrganizationFailed    = errors.New("failed to save organization")
+	ErrGetOrganizationFailed     = errors.New("failed to get organization")
+	ErrDeleteMemberFailed        = errors.New("failed to remove member")
+	ErrAlreadyMember             = errors.New("user is already a member of this organization")
+	ErrInvitationNotFound        = errors.New("invitation not found")
+	ErrAccountDetailsRequired    = errors.New("name and password are required to create the account")
+	ErrSaveInvitationFailed      = errors.New("failed to save invitation")
+	ErrGetInvitationFailed       = errors.New("failed to get invitation")
+)
@@ -0,0 +1,11 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrPasswordReused              = errors.New("password was used recently, choose a different one")
+	ErrPasswordExpired             = errors.New("password expired, log in with the password to change it")
+	ErrGetPasswordHistoryFailed    = errors.New("failed to get password history")
+	ErrCreatePasswordHistoryFailed = errors.New("failed to create password history")
+	ErrDeletePasswordHistoryFailed = errors.New("failed to delete password history")
+)
@@ -0,0 +1,9 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrRevokeTokenFailed         = errors.New("failed to revoke token")
+	ErrGetRevokedTokensFailed    = errors.New("failed to get revoked tokens")
+	ErrDeleteRevokedTokensFailed = errors.New("failed to delete revoked tokens")
+)
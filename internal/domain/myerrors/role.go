@@ -0,0 +1,15 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrRoleNotFound       = errors.New("role not found")
+	ErrInvalidRole        = errors.New("role does not exist")
+	ErrRoleAlreadyExists  = errors.New("role already exists")
+	ErrRoleInUse          = errors.New("role is still assigned to users or organization members")
+	ErrDefaultRole        = errors.New("the default role cannot be deleted")
+	ErrPermissionNotFound = errors.New("permission not found")
+	ErrSaveRoleFailed     = errors.New("failed to save role")
+	ErrGetRoleFailed      = errors.New("failed to get role")
+	ErrDeleteRoleFailed   = errors.New("failed to delete role")
+)
@@ -0,0 +1,7 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrCreateSecurityEventFailed = errors.New("failed to create security event")
+)
@@ -0,0 +1,12 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrCreateSessionFailed = errors.New("failed to create session")
+	ErrGetSessionsFailed   = errors.New("failed to get sessions")
+	ErrUpdateSessionFailed = errors.New("failed to update session")
+	ErrDeleteSessionFailed = errors.New("failed to delete session")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidTokenSession = errors.New("invalid token session")
+)
@@ -13,4 +13,8 @@ var (
 	ErrInvalidTokenClaims     = errors.New("invalid token claims")
 	ErrInvalidTokenType       = errors.New("invalid token type")
 	ErrInvalidTokenUserID     = errors.New("invalid token user id")
+	ErrConsumeTokenFailed     = errors.New("failed to consume token")
+	ErrTokenAlreadyConsumed   = errors.New("token already consumed")
+	ErrRevokeTokenFamily      = errors.New("failed to revoke token family")
+	ErrRefreshTokenReused     = errors.New("refresh token reused")
 )
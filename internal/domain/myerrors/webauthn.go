@@ -0,0 +1,13 @@
+package myerrors
+
+import "errors"
+
+var (
+	ErrWebAuthnNotConfigured        = errors.New("webauthn is not configured")
+	ErrWebAuthnNoCredentials        = errors.New("no passkey registered")
+	ErrWebAuthnCredentialExists     = errors.New("passkey already registered")
+	ErrInvalidWebAuthnResponse      = errors.New("invalid webauthn response")
+	ErrBeginWebAuthnFailed          = errors.New("failed to begin webauthn ceremony")
+	ErrSaveWebAuthnCredentialFailed = errors.New("failed to save webauthn credential")
+	ErrGetWebAuthnCredentialsFailed = errors.New("failed to get webauthn credentials")
+)
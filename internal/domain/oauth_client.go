@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeRefreshToken      = "refresh_token"
+	// GrantTypeDeviceCode is polled by first-party devices at /auth/device/token, it needs no registered client
+	GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// OAuthClient is an application registered to obtain tokens from the authorization server. Its ID is the client_id.
+// Confidential clients authenticate with a secret of which only the hash is kept; public clients such as mobile
+// apps have no secret and must prove each authorization code with PKCE.
+type OAuthClient struct {
+	ID         uuid.UUID `gorm:"primaryKey;not null"`
+	Name       string    `gorm:"not null"`
+	SecretHash string    `gorm:"not null"`
+	// RedirectURIs, GrantTypes and Scopes are comma separated lists
+	RedirectURIs string    `gorm:"not null"`
+	GrantTypes   string    `gorm:"not null"`
+	Scopes       string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt    time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (c *OAuthClient) Confidential() bool {
+	return c.SecretHash != ""
+}
+
+func (c *OAuthClient) HasGrantType(grantType string) bool {
+	return slices.Contains(splitList(c.GrantTypes), grantType)
+}
+
+// HasRedirectURI matches the registered redirect URIs exactly, as required for clients without a secret.
+func (c *OAuthClient) HasRedirectURI(redirectURI string) bool {
+	return slices.Contains(splitList(c.RedirectURIs), redirectURI)
+}
+
+func (c *OAuthClient) RedirectURIList() []string {
+	return splitList(c.RedirectURIs)
+}
+
+func (c *OAuthClient) GrantTypeList() []string {
+	return splitList(c.GrantTypes)
+}
+
+func (c *OAuthClient) ScopeList() []string {
+	return splitList(c.Scopes)
+}
+
+// ScopeRights keeps the rights that a scope granted to an OAuth client names. Scopes such as profile grant
+// no right, so a client only acts with the permissions it was explicitly granted.
+func ScopeRights(rights []string, scope string) []string {
+	scopes := strings.Fields(scope)
+
+	granted := make([]string, 0, len(rights))
+	for _, right := range rights {
+		if slices.Contains(scopes, right) {
+			granted = append(granted, right)
+		}
+	}
+	return granted
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthState is a pending OpenID Connect authorization, stored until the provider redirects back.
+// Only the hash of the state is kept, the browser holds the value in a cookie.
+type OAuthState struct {
+	ID           uuid.UUID `gorm:"primaryKey;not null"`
+	StateHash    string    `gorm:"not null;uniqueIndex"`
+	Provider     string    `gorm:"not null"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	// RedirectURI is the allow-listed front-end page that receives a one-time code instead of the JSON response
+	RedirectURI string `gorm:"not null"`
+	// UserID is set when a logged in user links the provider account instead of logging in
+	UserID    *uuid.UUID
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization is a tenant, usually a customer company. Its members only see the users of the organization.
+type Organization struct {
+	ID        uuid.UUID            `gorm:"primaryKey;not null"`
+	Name      string               `gorm:"not null"`
+	Slug      string               `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time            `gorm:"autoCreateTime:milli"`
+	UpdatedAt time.Time            `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+	Members   []OrganizationMember `gorm:"foreignKey:OrganizationID"`
+}
+
+// OrganizationMember makes a user a member of an organization. Role is one of the stored roles; its
+// permissions apply while the user acts in the organization, on top of the roles of the user.
+type OrganizationMember struct {
+	OrganizationID uuid.UUID     `gorm:"primaryKey;not null"`
+	UserID         uuid.UUID     `gorm:"primaryKey;not null"`
+	Role           string        `gorm:"not null;default:'user'"`
+	CreatedAt      time.Time     `gorm:"autoCreateTime:milli"`
+	Organization   *Organization `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+	User           *User         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// OrganizationInvitation invites an email address to join an organization with a role. The invitation token
+// is only sent by email; the database keeps its hash. Accepted invitations are kept with AcceptedAt set.
+type OrganizationInvitation struct {
+	ID             uuid.UUID `gorm:"primaryKey;not null"`
+	OrganizationID uuid.UUID `gorm:"not null"`
+	Email          string    `gorm:"not null"`
+	Role           string    `gorm:"not null;default:'user'"`
+	InvitedBy      *uuid.UUID
+	TokenHash      string    `gorm:"not null;uniqueIndex"`
+	Expires        time.Time `gorm:"not null"`
+	AcceptedAt     *time.Time
+	CreatedAt      time.Time     `gorm:"autoCreateTime:milli"`
+	Organization   *Organization `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+}
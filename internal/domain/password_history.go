@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistory keeps the hash of a password the user replaced, so it cannot be used again.
+type PasswordHistory struct {
+	ID        uuid.UUID `gorm:"primaryKey;not null"`
+	UserID    uuid.UUID `gorm:"index;not null"`
+	Password  string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the authenticator is lost.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"primaryKey;not null" json:"id"`
+	UserID    uuid.UUID  `gorm:"not null" json:"user_id"`
+	CodeHash  string     `gorm:"not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime:milli" json:"created_at"`
+	User      *User      `gorm:"foreignKey:user_id;references:id" json:"-"`
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=api_key_repository.go -destination=../../adapter/database/repository/mocks/api_key_repository.go -package=mocks
+type APIKeyRepository interface {
+	GetAll(ctx context.Context) ([]domain.APIKey, error)
+	GetByUserID(ctx context.Context, userID string) ([]domain.APIKey, error)
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	Create(ctx context.Context, apiKey *domain.APIKey) (*domain.APIKey, error)
+	Delete(ctx context.Context, id string) error
+	Touch(ctx context.Context, id string, usedAt time.Time) error
+}
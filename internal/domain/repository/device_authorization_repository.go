@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=device_authorization_repository.go -destination=../../adapter/database/repository/mocks/device_authorization_repository.go -package=mocks
+type DeviceAuthorizationRepository interface {
+	Create(ctx context.Context, authorization *domain.DeviceAuthorization) (*domain.DeviceAuthorization, error)
+	GetByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*domain.DeviceAuthorization, error)
+	RecordPoll(ctx context.Context, id string, polledAt time.Time, pollInterval int) error
+	Decide(
+		ctx context.Context, userCodeHash, userID, status string, now time.Time,
+	) (*domain.DeviceAuthorization, error)
+	Consume(ctx context.Context, id string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=invitation_repository.go -destination=../../adapter/database/repository/mocks/invitation_repository.go -package=mocks
+type InvitationRepository interface {
+	GetPending(ctx context.Context, organizationID string) ([]domain.OrganizationInvitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.OrganizationInvitation, error)
+	Create(ctx context.Context, invitation *domain.OrganizationInvitation) (*domain.OrganizationInvitation, error)
+	Delete(ctx context.Context, organizationID, id string) error
+	Accept(ctx context.Context, invitation *domain.OrganizationInvitation, user *domain.User) error
+}
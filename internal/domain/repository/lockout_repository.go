@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=lockout_repository.go -destination=../../adapter/database/repository/mocks/lockout_repository.go -package=mocks
+type LockoutRepository interface {
+	GetAll(ctx context.Context) ([]domain.AccountLockout, error)
+	GetByUserID(ctx context.Context, userID string) (*domain.AccountLockout, error)
+	RecordFailure(ctx context.Context, userID string, failedAt, since time.Time) (*domain.AccountLockout, error)
+	Lock(ctx context.Context, userID string, until time.Time) error
+	Delete(ctx context.Context, userID string) error
+}
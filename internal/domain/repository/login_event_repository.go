@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=login_event_repository.go -destination=../../adapter/database/repository/mocks/login_event_repository.go -package=mocks
+type LoginEventRepository interface {
+	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]domain.LoginEvent, int64, error)
+	CountSuccessful(ctx context.Context, userID, deviceFingerprint string) (int64, error)
+	Create(ctx context.Context, event *domain.LoginEvent) (*domain.LoginEvent, error)
+}
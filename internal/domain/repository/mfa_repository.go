@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=mfa_repository.go -destination=../../adapter/database/repository/mocks/mfa_repository.go -package=mocks
+type MFARepository interface {
+	SaveTOTPSecret(ctx context.Context, userID, secret string) error
+	EnableTOTP(ctx context.Context, userID string, step int64, recoveryCodes []domain.RecoveryCode) error
+	UseTOTPStep(ctx context.Context, userID string, step int64) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=oauth_client_repository.go -destination=../../adapter/database/repository/mocks/oauth_client_repository.go -package=mocks
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error)
+	GetByID(ctx context.Context, clientID string) (*domain.OAuthClient, error)
+	GetAll(ctx context.Context) ([]domain.OAuthClient, error)
+	Delete(ctx context.Context, clientID string) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=oauth_state_repository.go -destination=../../adapter/database/repository/mocks/oauth_state_repository.go -package=mocks
+type OAuthStateRepository interface {
+	Create(ctx context.Context, state *domain.OAuthState) (*domain.OAuthState, error)
+	Consume(ctx context.Context, stateHash, provider string, now time.Time) (*domain.OAuthState, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=organization_repository.go -destination=../../adapter/database/repository/mocks/organization_repository.go -package=mocks
+type OrganizationRepository interface {
+	GetAll(ctx context.Context) ([]domain.Organization, error)
+	GetByID(ctx context.Context, id string) (*domain.Organization, error)
+	Create(ctx context.Context, organization *domain.Organization) (*domain.Organization, error)
+	GetMemberships(ctx context.Context, userID string) ([]domain.OrganizationMember, error)
+	GetMembership(ctx context.Context, organizationID, userID string) (*domain.OrganizationMember, error)
+	GetMembers(ctx context.Context, organizationID string) ([]domain.OrganizationMember, error)
+	SetMember(ctx context.Context, member *domain.OrganizationMember) error
+	DeleteMember(ctx context.Context, organizationID, userID string) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=password_history_repository.go -destination=../../adapter/database/repository/mocks/password_history_repository.go -package=mocks
+type PasswordHistoryRepository interface {
+	// GetRecent returns the last replaced passwords of the user, newest first
+	GetRecent(ctx context.Context, userID string, limit int) ([]domain.PasswordHistory, error)
+	Create(ctx context.Context, entry *domain.PasswordHistory) error
+	// Prune deletes all but the newest keep entries of the user
+	Prune(ctx context.Context, userID string, keep int) error
+}
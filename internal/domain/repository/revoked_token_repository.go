@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=revoked_token_repository.go -destination=../../adapter/database/repository/mocks/revoked_token_repository.go -package=mocks
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, revokedToken *domain.RevokedToken) (*domain.RevokedToken, error)
+	GetActive(ctx context.Context, now time.Time) ([]domain.RevokedToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=role_repository.go -destination=../../adapter/database/repository/mocks/role_repository.go -package=mocks
+type RoleRepository interface {
+	GetAll(ctx context.Context) ([]domain.Role, error)
+	GetByID(ctx context.Context, id string) (*domain.Role, error)
+	Create(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	Update(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	Delete(ctx context.Context, id string) error
+	GetUserRoles(ctx context.Context, userID string) ([]string, error)
+	GetPermissions(ctx context.Context) ([]domain.Permission, error)
+	GetPermissionsByNames(ctx context.Context, names []string) ([]domain.Permission, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=security_event_repository.go -destination=../../adapter/database/repository/mocks/security_event_repository.go -package=mocks
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *domain.SecurityEvent) (*domain.SecurityEvent, error)
+}
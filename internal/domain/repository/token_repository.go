@@ -3,12 +3,22 @@ package repository
 import (
 	"app/internal/domain"
 	"context"
+	"time"
 )
 
 //go:generate mockgen -source=token_repository.go -destination=../../adapter/database/repository/mocks/token_repository.go -package=mocks
 type TokenRepository interface {
 	Create(ctx context.Context, token *domain.Token) (*domain.Token, error)
 	Delete(ctx context.Context, tokenType domain.TokenType, userID string) error
+	DeleteExpired(ctx context.Context, tokenType domain.TokenType, userID string, now time.Time) error
 	DeleteAll(ctx context.Context, userID string) error
-	GetByTokenAndUserID(ctx context.Context, token, userID string) (*domain.Token, error)
+	GetByTokenHashAndUserID(ctx context.Context, tokenHash, userID string) (*domain.Token, error)
+	ConsumeToken(ctx context.Context, tokenID string, consumedAt time.Time) error
+	RevokeTokenFamily(ctx context.Context, sessionID string) error
+	CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error)
+	GetSessionsByUserID(ctx context.Context, userID string) ([]domain.Session, error)
+	GetSessionsByClientID(ctx context.Context, clientID string) ([]domain.Session, error)
+	TouchSession(ctx context.Context, sessionID string, lastUsedAt time.Time) error
+	DeleteSession(ctx context.Context, sessionID, userID string) error
+	DeleteOtherSessions(ctx context.Context, userID, currentSessionID string) error
 }
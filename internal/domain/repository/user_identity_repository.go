@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=user_identity_repository.go -destination=../../adapter/database/repository/mocks/user_identity_repository.go -package=mocks
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error)
+	CreateWithUser(ctx context.Context, user *domain.User, identity *domain.UserIdentity) (*domain.User, error)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	GetByUserID(ctx context.Context, userID string) ([]domain.UserIdentity, error)
+	Delete(ctx context.Context, userID, identityID string) error
+}
@@ -12,6 +12,8 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	Create(ctx context.Context, user *domain.User) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) (*domain.User, error)
+	SetRoles(ctx context.Context, id string, roles []domain.UserRole) error
+	SetAttributes(ctx context.Context, id string, attributes map[string]string) error
 	UpdatePassOrVerify(ctx context.Context, user *domain.User, id string) error
 	Delete(ctx context.Context, id string) error
 }
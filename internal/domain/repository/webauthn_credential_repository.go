@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"app/internal/domain"
+	"context"
+)
+
+//go:generate mockgen -source=webauthn_credential_repository.go -destination=../../adapter/database/repository/mocks/webauthn_credential_repository.go -package=mocks
+type WebAuthnCredentialRepository interface {
+	Create(ctx context.Context, credential *domain.WebAuthnCredential) (*domain.WebAuthnCredential, error)
+	GetByUserID(ctx context.Context, userID string) ([]domain.WebAuthnCredential, error)
+	UpdateAfterLogin(ctx context.Context, credential *domain.WebAuthnCredential) error
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedToken denies access tokens before their natural expiry. An entry matches a single token by JTI,
+// every token of a session, or every token of a user issued up to RevokedAt.
+type RevokedToken struct {
+	ID        uuid.UUID  `gorm:"primaryKey;not null" json:"id"`
+	JTI       *string    `gorm:"column:jti" json:"jti"`
+	SessionID *uuid.UUID `json:"session_id"`
+	UserID    *uuid.UUID `json:"user_id"`
+	RevokedAt time.Time  `gorm:"not null" json:"revoked_at"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleUser is given to users who register themselves, so it cannot be deleted
+const RoleUser = "user"
+
+// Role is a named set of permissions. Users reference their roles by name.
+type Role struct {
+	ID          uuid.UUID    `gorm:"primaryKey;not null"`
+	Name        string       `gorm:"not null;uniqueIndex"`
+	Description string       `gorm:"not null"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime:milli"`
+	UpdatedAt   time.Time    `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+// PermissionNames returns the rights the role grants, as checked by JWTAuth.
+func (r *Role) PermissionNames() []string {
+	names := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+// UserRole assigns a role to a user. A user holds at least one role and gets the permissions of all of them.
+type UserRole struct {
+	UserID    uuid.UUID `gorm:"primaryKey;not null"`
+	Role      string    `gorm:"primaryKey;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
+
+// MarshalJSON writes the role name, so users list their roles as ["user", "admin"].
+func (r UserRole) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Role)
+}
+
+// Permission is a right checked by the code, such as manageUsers. Permissions are added by migrations
+// together with the code that checks them.
+type Permission struct {
+	ID          uuid.UUID `gorm:"primaryKey;not null"`
+	Name        string    `gorm:"not null;uniqueIndex"`
+	Description string    `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime:milli"`
+}
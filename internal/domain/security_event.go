@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SecurityEvent struct {
+	ID        uuid.UUID         `gorm:"primaryKey;not null" json:"id"`
+	UserID    uuid.UUID         `gorm:"not null" json:"user_id"`
+	Type      SecurityEventType `gorm:"not null" json:"type"`
+	IPAddress string            `gorm:"not null" json:"ip_address"`
+	UserAgent string            `gorm:"not null" json:"user_agent"`
+	Detail    string            `gorm:"not null" json:"detail"`
+	CreatedAt time.Time         `gorm:"autoCreateTime:milli" json:"created_at"`
+	User      *User             `gorm:"foreignKey:user_id;references:id" json:"-"`
+}
+
+type SecurityEventType string
+
+const (
+	SecurityEventRefreshTokenReuse SecurityEventType = "refreshTokenReuse"
+)
+
+func (t SecurityEventType) String() string {
+	return string(t)
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	ID         uuid.UUID `gorm:"primaryKey;not null" json:"id"`
+	UserID     uuid.UUID `gorm:"not null" json:"user_id"`
+	DeviceName string    `gorm:"not null" json:"device_name"`
+	UserAgent  string    `gorm:"not null" json:"user_agent"`
+	IPAddress  string    `gorm:"not null" json:"ip_address"`
+	LastUsedAt time.Time `gorm:"not null" json:"last_used_at"`
+	// ClientID and Scope are set on sessions opened by an OAuth client on behalf of the user
+	ClientID  *uuid.UUID `json:"client_id,omitempty"`
+	Scope     string     `gorm:"not null" json:"scope,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime:milli" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoCreateTime:milli;autoUpdateTime:milli" json:"-"`
+	User      *User      `gorm:"foreignKey:user_id;references:id" json:"-"`
+	Token     []Token    `gorm:"foreignKey:session_id;references:id" json:"-"`
+}
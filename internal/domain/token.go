@@ -7,14 +7,21 @@ import (
 )
 
 type Token struct {
-	ID        uuid.UUID `gorm:"primaryKey;not null" json:"id"`
-	Token     string    `gorm:"not null" json:"token"`
+	ID uuid.UUID `gorm:"primaryKey;not null" json:"id"`
+	// Token holds the raw value only until it is handed to the client; the database keeps TokenHash
+	Token     string    `gorm:"-" json:"token"`
+	TokenHash string    `gorm:"not null" json:"-"`
 	UserID    uuid.UUID `gorm:"not null"`
+	SessionID *uuid.UUID
+	ParentID  *uuid.UUID
 	Type      TokenType `gorm:"not null" json:"type"`
 	Expires   time.Time `gorm:"not null" json:"expires"`
-	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
-	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
-	User      *User     `gorm:"foreignKey:user_id;references:id"`
+	// ConsumedAt is set once a refresh token has been rotated; presenting it again means it leaked
+	ConsumedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt  time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+	User       *User     `gorm:"foreignKey:user_id;references:id"`
+	Session    *Session  `gorm:"foreignKey:session_id;references:id"`
 }
 
 type TokenType string
@@ -24,6 +31,23 @@ const (
 	TokenTypeRefresh       TokenType = "refresh"
 	TokenTypeResetPassword TokenType = "resetPassword"
 	TokenTypeVerifyEmail   TokenType = "verifyEmail"
+	TokenTypeMFAPending    TokenType = "mfaPending"
+	TokenTypeMagicLink     TokenType = "magicLink"
+	TokenTypeOAuthCode     TokenType = "oauthCode"
+	// TokenTypeAuthorizationCode is the code the authorization server hands to OAuth clients
+	TokenTypeAuthorizationCode TokenType = "authorizationCode"
+	// TokenTypeClientAccess is issued to a client acting on its own behalf, so JWTAuth never accepts it for a user
+	TokenTypeClientAccess TokenType = "clientAccess"
+	// WebAuthn ceremony challenges are stored as tokens so each one can only be answered once
+	TokenTypeWebAuthnRegistration TokenType = "webauthnRegistration"
+	TokenTypeWebAuthnLogin        TokenType = "webauthnLogin"
+	// TokenTypeInvitation is addressed to an email rather than a user, so it carries no user_id
+	TokenTypeInvitation TokenType = "invitation"
+	// TokenTypeUnlockAccount is emailed when an account gets locked after failed logins
+	TokenTypeUnlockAccount TokenType = "unlockAccount"
+	// TokenTypeChangePassword replaces the auth tokens of a login whose password expired, it only allows
+	// choosing a new password
+	TokenTypeChangePassword TokenType = "changePassword"
 )
 
 func (t TokenType) String() string {
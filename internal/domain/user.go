@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,9 +12,59 @@ type User struct {
 	Name          string    `gorm:"not null" json:"name"`
 	Email         string    `gorm:"uniqueIndex;not null" json:"email"`
 	Password      string    `gorm:"not null" json:"-"`
-	Role          string    `gorm:"default:user;not null" json:"role"`
 	VerifiedEmail bool      `gorm:"default:false;not null" json:"verified_email"`
-	CreatedAt     time.Time `gorm:"autoCreateTime:milli" json:"-"`
-	UpdatedAt     time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli" json:"-"`
-	Token         []Token   `gorm:"foreignKey:user_id;references:id" json:"-"`
+	// TOTPSecret is set on enrollment; login only asks for a code once TOTPEnabled is confirmed
+	TOTPSecret  string `gorm:"column:totp_secret;default:'';not null" json:"-"`
+	TOTPEnabled bool   `gorm:"column:totp_enabled;default:false;not null" json:"totp_enabled"`
+	// TOTPLastStep is the time step of the last accepted code, so a code cannot be replayed
+	TOTPLastStep int64 `gorm:"column:totp_last_step;default:0;not null" json:"-"`
+	// Attributes are set by admins, such as the region of support staff, and compared by attribute policies
+	Attributes map[string]string `gorm:"serializer:json;type:jsonb;default:'{}';not null" json:"attributes"`
+	// PasswordChangedAt starts the password.max_age of the password, CreatedAt is used while unset
+	PasswordChangedAt *time.Time `json:"-"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime:milli" json:"-"`
+	UpdatedAt         time.Time  `gorm:"autoCreateTime:milli;autoUpdateTime:milli" json:"-"`
+	Token             []Token    `gorm:"foreignKey:user_id;references:id" json:"-"`
+	Roles             []UserRole `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"roles"`
+	// Memberships are only set to create the user inside an organization
+	Memberships []OrganizationMember `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// RoleNames returns the names of the roles the user holds.
+func (u *User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		names = append(names, role.Role)
+	}
+	return names
+}
+
+// PasswordExpired reports whether the password is older than maxAge, for users holding one of expireRoles or
+// for everyone when expireRoles is empty. Users without a password never expire.
+func (u *User) PasswordExpired(maxAge time.Duration, expireRoles []string, now time.Time) bool {
+	if maxAge <= 0 || u.Password == "" {
+		return false
+	}
+
+	if len(expireRoles) > 0 && !slices.ContainsFunc(u.RoleNames(), func(role string) bool {
+		return slices.Contains(expireRoles, role)
+	}) {
+		return false
+	}
+
+	changedAt := u.CreatedAt
+	if u.PasswordChangedAt != nil {
+		changedAt = *u.PasswordChangedAt
+	}
+
+	return now.After(changedAt.Add(maxAge))
+}
+
+// NewUserRoles assigns the named roles, used when creating a user or replacing the roles.
+func NewUserRoles(names ...string) []UserRole {
+	roles := make([]UserRole, 0, len(names))
+	for _, name := range names {
+		roles = append(roles, UserRole{Role: name})
+	}
+	return roles
 }
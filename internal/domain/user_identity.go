@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an OpenID Connect provider.
+// Logins are matched on Provider and Subject, Email is what the provider reported when the identity was linked.
+type UserIdentity struct {
+	ID        uuid.UUID `gorm:"primaryKey;not null" json:"id"`
+	UserID    uuid.UUID `gorm:"not null" json:"user_id"`
+	Provider  string    `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject" json:"provider"`
+	Subject   string    `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject" json:"subject"`
+	Email     string    `gorm:"not null" json:"email"`
+	LinkedAt  time.Time `gorm:"not null" json:"linked_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime:milli" json:"-"`
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli" json:"-"`
+	User      *User     `gorm:"foreignKey:user_id;references:id" json:"-"`
+}
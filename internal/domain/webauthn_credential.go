@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebAuthnCredential is a passkey registered by a user. Only the public key is stored.
+type WebAuthnCredential struct {
+	ID              uuid.UUID `gorm:"primaryKey;not null" json:"id"`
+	UserID          uuid.UUID `gorm:"not null" json:"user_id"`
+	CredentialID    []byte    `gorm:"not null;uniqueIndex" json:"-"`
+	PublicKey       []byte    `gorm:"not null" json:"-"`
+	AttestationType string    `gorm:"not null" json:"attestation_type"`
+	// Transports is the comma separated list of transports reported by the authenticator
+	Transports     string     `gorm:"not null" json:"transports"`
+	SignCount      uint32     `gorm:"not null" json:"-"`
+	AAGUID         []byte     `json:"-"`
+	BackupEligible bool       `gorm:"not null" json:"backup_eligible"`
+	BackupState    bool       `gorm:"not null" json:"backup_state"`
+	Name           string     `gorm:"not null" json:"name"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime:milli" json:"created_at"`
+	User           *User      `gorm:"foreignKey:user_id;references:id" json:"-"`
+}
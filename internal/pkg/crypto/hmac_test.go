@@ -0,0 +1,32 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type hmacTestSuite struct {
+	suite.Suite
+}
+
+func TestHmac(t *testing.T) {
+	suite.Run(t, new(hmacTestSuite))
+}
+
+func (s *hmacTestSuite) TestHashToken_Deterministic() {
+	hash1 := HashToken("token", "key")
+	hash2 := HashToken("token", "key")
+
+	s.Equal(hash1, hash2)
+	s.Len(hash1, 64)
+	s.NotEqual("token", hash1)
+}
+
+func (s *hmacTestSuite) TestHashToken_DependsOnKey() {
+	s.NotEqual(HashToken("token", "key1"), HashToken("token", "key2"))
+}
+
+func (s *hmacTestSuite) TestHashToken_DependsOnToken() {
+	s.NotEqual(HashToken("token1", "key"), HashToken("token2", "key"))
+}
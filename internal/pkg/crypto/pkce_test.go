@@ -0,0 +1,38 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Code verifier and challenge from RFC 7636 appendix B
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+type pkceTestSuite struct {
+	suite.Suite
+}
+
+func TestPKCE(t *testing.T) {
+	suite.Run(t, new(pkceTestSuite))
+}
+
+func (s *pkceTestSuite) TestVerifyPKCE_RFCExample() {
+	s.True(VerifyPKCE(testCodeVerifier, testCodeChallenge))
+}
+
+func (s *pkceTestSuite) TestVerifyPKCE_WrongVerifier() {
+	s.False(VerifyPKCE("other-verifier", testCodeChallenge))
+}
+
+func (s *pkceTestSuite) TestVerifyPKCE_PlainChallengeRejected() {
+	s.False(VerifyPKCE(testCodeVerifier, testCodeVerifier))
+}
+
+func (s *pkceTestSuite) TestVerifyPKCE_Empty() {
+	s.False(VerifyPKCE("", testCodeChallenge))
+	s.False(VerifyPKCE(testCodeVerifier, ""))
+}
@@ -0,0 +1,14 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// RandomString returns size random bytes encoded as unpadded base64url, safe to use in URLs and cookies.
+func RandomString(size int) string {
+	b := make([]byte, size)
+	// crypto/rand.Read never returns an error, it crashes the program if the system source fails
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
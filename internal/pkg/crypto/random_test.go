@@ -0,0 +1,29 @@
+package crypto
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type randomTestSuite struct {
+	suite.Suite
+}
+
+func TestRandom(t *testing.T) {
+	suite.Run(t, new(randomTestSuite))
+}
+
+func (s *randomTestSuite) TestRandomString_Length() {
+	// 32 bytes is the smallest PKCE code verifier size (43 characters)
+	s.Len(RandomString(32), 43)
+}
+
+func (s *randomTestSuite) TestRandomString_URLSafe() {
+	s.Regexp(regexp.MustCompile(`^[A-Za-z0-9_-]+$`), RandomString(64))
+}
+
+func (s *randomTestSuite) TestRandomString_Unique() {
+	s.NotEqual(RandomString(32), RandomString(32))
+}
@@ -5,8 +5,12 @@ import (
 	"app/internal/application/service"
 	"app/internal/domain"
 	"app/internal/domain/myerrors"
+	"app/internal/pkg/policy"
+	"app/internal/pkg/tenant"
 	"app/internal/pkg/token"
 	"errors"
+	"slices"
+	"strings"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -16,16 +20,59 @@ import (
 
 type Auth interface {
 	JWTAuth(requiredRights ...string) fiber.Handler
+	SessionAuth(requiredRights ...string) fiber.Handler
+	Authorize(action policy.Action, resource ResourceFunc) fiber.Handler
+	AuthorizeUser(action policy.Action, param string) fiber.Handler
 }
 
 type AuthImpl struct {
-	Conf        *config.Config      `inject:"config"`
-	UserService service.UserService `inject:"userService"`
+	Conf                *config.Config              `inject:"config"`
+	UserService         service.UserService         `inject:"userService"`
+	Keys                token.KeyManager            `inject:"keyManager"`
+	Revocation          service.RevocationService   `inject:"revocationService"`
+	RoleService         service.RoleService         `inject:"roleService"`
+	AccessPolicy        service.AccessPolicyService `inject:"accessPolicyService"`
+	OrganizationService service.OrganizationService `inject:"organizationService"`
+	APIKeyService       service.APIKeyService       `inject:"apiKeyService"`
 }
 
+// apiKeyScheme is the authorization scheme of API keys, sent as "Authorization: ApiKey <key>"
+const apiKeyScheme = "ApiKey"
+
+// JWTAuth authenticates the request with a Bearer access token or an API key and checks the required rights.
+// Access tokens of OAuth clients act with the rights of the user that their scope names.
 func (a *AuthImpl) JWTAuth(requiredRights ...string) fiber.Handler {
+	jwtAuth := a.jwtAuth(requiredRights, false)
+
+	return func(c *fiber.Ctx) error {
+		scheme, key, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		if strings.EqualFold(scheme, apiKeyScheme) {
+			return a.apiKeyAuth(c, strings.TrimSpace(key), requiredRights)
+		}
+
+		return jwtAuth(c)
+	}
+}
+
+// SessionAuth is JWTAuth for the routes that manage the account, credentials and sessions of the user. It only
+// accepts the access token of a login, so a leaked API key or a token granted to an OAuth client cannot be
+// turned into a new password, key or session.
+func (a *AuthImpl) SessionAuth(requiredRights ...string) fiber.Handler {
+	jwtAuth := a.jwtAuth(requiredRights, true)
+
+	return func(c *fiber.Ctx) error {
+		scheme, _, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		if strings.EqualFold(scheme, apiKeyScheme) {
+			return myerrors.ErrForbidden
+		}
+
+		return jwtAuth(c)
+	}
+}
+
+func (a *AuthImpl) jwtAuth(requiredRights []string, sessionOnly bool) fiber.Handler {
 	return jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{Key: []byte(a.Conf.JWT.Secret)},
+		KeyFunc: a.Keys.Keyfunc,
 		ErrorHandler: func(_ *fiber.Ctx, err error) error {
 			golog.Error("Error verifying token", err)
 			return myerrors.ErrInvalidToken
@@ -35,24 +82,86 @@ func (a *AuthImpl) JWTAuth(requiredRights ...string) fiber.Handler {
 			if !ok {
 				return myerrors.ErrInvalidToken
 			}
-			userID, err := token.VerifyToken(user.Raw, a.Conf.JWT.Secret, domain.TokenTypeAccess.String())
-			if err != nil {
+			claims, err := token.ParseToken(user.Raw, a.Keys, domain.TokenTypeAccess.String())
+			if err != nil || a.Revocation.IsRevoked(claims) {
 				return myerrors.ErrInvalidToken
 			}
 
+			userID, ok := claims["user_id"].(string)
+			if !ok {
+				return myerrors.ErrInvalidToken
+			}
+
+			// Tokens issued to OAuth clients carry the client and the scope it was granted
+			_, fromClient := claims["client_id"].(string)
+			if fromClient && sessionOnly {
+				return myerrors.ErrForbidden
+			}
+
+			sessionID, _ := claims["session_id"].(string)
+			c.Locals("sessionId", sessionID)
+
+			requestedOrganization := c.Get(tenant.Header)
+			tokenOrganization, _ := claims["organization_id"].(string)
+
+			// Routes that require rights do not read the user, so the lookup can be skipped
+			// when the permissions in the token are trusted and apply to the organization of the request
+			if len(requiredRights) > 0 && a.Conf.JWT.AuthorizeFromToken &&
+				(requestedOrganization == "" || requestedOrganization == tokenOrganization) {
+				subject := policy.Subject{UserID: userID, OrganizationID: tokenOrganization, Rights: tokenPermissions(claims)}
+				if fromClient {
+					subject.Rights = clientRights(subject.Rights, claims)
+				}
+				if tokenOrganization != "" {
+					tenant.SetOrganization(c, tokenOrganization)
+				}
+				c.Locals("subject", subject)
+
+				if !hasAllRights(subject.Rights, requiredRights) {
+					return myerrors.ErrForbidden
+				}
+				return c.Next()
+			}
+
 			_user, err := a.UserService.GetUserByID(c.Context(), userID)
-			if err != nil && !errors.Is(err, myerrors.ErrUserNotFound) {
+			if errors.Is(err, myerrors.ErrUserNotFound) {
+				return myerrors.ErrInvalidToken
+			}
+			if err != nil {
 				golog.Error("Error getting user by id", err)
 				return myerrors.ErrGetUserFailed
 			}
 
 			c.Locals("user", _user)
 
-			if len(requiredRights) > 0 {
-				userRights, hasRight := config.RoleRights[_user.Role]
-				if (!hasRight || !hasAllRights(userRights, requiredRights)) && c.Params("userId") != userID {
-					return fiber.NewError(fiber.StatusForbidden, "you don't have permission to access this resource")
+			subject := policy.Subject{
+				UserID:     userID,
+				Roles:      _user.RoleNames(),
+				Rights:     tokenPermissions(claims),
+				Attributes: _user.Attributes,
+			}
+
+			organizationID := requestedOrganization
+			if organizationID == "" {
+				organizationID = tokenOrganization
+			}
+
+			// The permissions claim holds the rights in the organization of the token only
+			if !a.Conf.JWT.AuthorizeFromToken || organizationID != tokenOrganization {
+				if err = a.enterOrganization(c, &subject, organizationID); err != nil {
+					return err
 				}
+			} else if organizationID != "" {
+				subject.OrganizationID = organizationID
+				tenant.SetOrganization(c, organizationID)
+			}
+			if fromClient {
+				subject.Rights = clientRights(subject.Rights, claims)
+			}
+			c.Locals("subject", subject)
+
+			if !hasAllRights(subject.Rights, requiredRights) {
+				return myerrors.ErrForbidden
 			}
 
 			return c.Next()
@@ -60,6 +169,193 @@ func (a *AuthImpl) JWTAuth(requiredRights ...string) fiber.Handler {
 	})
 }
 
+// apiKeyAuth authenticates a request made with an API key. A personal key acts as its user in the organization of
+// the X-Organization-ID header, or else the user's first organization, with the rights the user has there that
+// are also scopes of the key. A service key has the rights of its scopes and no user, so it is refused by routes
+// that read the user and can only enter an organization with the manageOrganizations scope.
+func (a *AuthImpl) apiKeyAuth(c *fiber.Ctx, key string, requiredRights []string) error {
+	apiKey, err := a.APIKeyService.Authenticate(c.Context(), key)
+	if err != nil {
+		return err
+	}
+
+	c.Locals("apiKey", apiKey)
+	c.Locals("sessionId", "")
+
+	organizationID := c.Get(tenant.Header)
+	var subject policy.Subject
+
+	if apiKey.Service() {
+		subject.Rights = apiKey.ScopeList()
+
+		if organizationID != "" {
+			if !subject.HasRight("manageOrganizations") {
+				return myerrors.ErrNotOrganizationMember
+			}
+			if _, err = a.OrganizationService.GetOrganization(c.Context(), organizationID); err != nil {
+				return err
+			}
+			subject.OrganizationID = organizationID
+			tenant.SetOrganization(c, organizationID)
+		}
+	} else {
+		_user, err := a.UserService.GetUserByID(c.Context(), apiKey.UserID.String())
+		if errors.Is(err, myerrors.ErrUserNotFound) {
+			return myerrors.ErrInvalidToken
+		}
+		if err != nil {
+			golog.Error("Error getting user by id", err)
+			return myerrors.ErrGetUserFailed
+		}
+
+		c.Locals("user", _user)
+
+		if organizationID == "" {
+			member, err := a.OrganizationService.DefaultMembership(c.Context(), _user.ID.String())
+			if err != nil {
+				return err
+			}
+			if member != nil {
+				organizationID = member.OrganizationID.String()
+			}
+		}
+
+		subject = policy.Subject{UserID: _user.ID.String(), Roles: _user.RoleNames(), Attributes: _user.Attributes}
+		if err = a.enterOrganization(c, &subject, organizationID); err != nil {
+			return err
+		}
+
+		scopes := apiKey.ScopeList()
+		subject.Rights = slices.DeleteFunc(subject.Rights, func(right string) bool {
+			return !slices.Contains(scopes, right)
+		})
+	}
+
+	c.Locals("subject", subject)
+
+	if !hasAllRights(subject.Rights, requiredRights) {
+		return myerrors.ErrForbidden
+	}
+
+	return c.Next()
+}
+
+// enterOrganization makes the organization, if any, the tenant of the request and sets the rights of the subject
+// to those of its roles and of its role in the organization. Only members may act in an organization, apart from
+// users who manage every organization.
+func (a *AuthImpl) enterOrganization(c *fiber.Ctx, subject *policy.Subject, organizationID string) error {
+	rights, err := a.RoleService.Rights(c.Context(), subject.Roles...)
+	if err != nil && !errors.Is(err, myerrors.ErrRoleNotFound) {
+		golog.Error("Error getting role rights", err)
+		return myerrors.ErrGetRoleFailed
+	}
+	subject.Rights = rights
+
+	if organizationID == "" {
+		return nil
+	}
+
+	member, err := a.OrganizationService.GetMembership(c.Context(), organizationID, subject.UserID)
+	switch {
+	case errors.Is(err, myerrors.ErrMemberNotFound):
+		if !subject.HasRight("manageOrganizations") {
+			return myerrors.ErrNotOrganizationMember
+		}
+		if _, err = a.OrganizationService.GetOrganization(c.Context(), organizationID); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		subject.Rights, err = a.RoleService.Rights(c.Context(), append(subject.Roles, member.Role)...)
+		if err != nil && !errors.Is(err, myerrors.ErrRoleNotFound) {
+			golog.Error("Error getting role rights", err)
+			return myerrors.ErrGetRoleFailed
+		}
+	}
+
+	subject.OrganizationID = organizationID
+	tenant.SetOrganization(c, organizationID)
+
+	return nil
+}
+
+// Authorize checks the action against the attribute policies, then policy.Rules, for the resource of the request.
+// It runs after JWTAuth, which sets the subject.
+func (a *AuthImpl) Authorize(action policy.Action, resource ResourceFunc) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		subject, ok := c.Locals("subject").(policy.Subject)
+		if !ok {
+			return myerrors.ErrInvalidToken
+		}
+
+		err := a.AccessPolicy.Authorize(c.Context(), policy.Request{
+			Subject:     subject,
+			Action:      action,
+			Resource:    resource(c),
+			Environment: environment(c),
+		})
+		if err != nil {
+			return err
+		}
+
+		return c.Next()
+	}
+}
+
+// AuthorizeUser is Authorize for the user account named by a route parameter, whose roles and attributes
+// the policies can compare.
+func (a *AuthImpl) AuthorizeUser(action policy.Action, param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		subject, ok := c.Locals("subject").(policy.Subject)
+		if !ok {
+			return myerrors.ErrInvalidToken
+		}
+
+		err := a.UserService.AuthorizeUser(c.Context(), subject, action, c.Params(param), environment(c))
+		if err != nil {
+			return err
+		}
+
+		return c.Next()
+	}
+}
+
+// environment holds the attributes of the request; the service adds the time.
+func environment(c *fiber.Ctx) map[string]any {
+	return map[string]any{"ip": c.IP()}
+}
+
+// ResourceFunc reads the resource a request acts on, usually from the route parameters.
+type ResourceFunc func(c *fiber.Ctx) policy.Resource
+
+// UserParam reads the user named by a route parameter, who owns its own account.
+func UserParam(param string) ResourceFunc {
+	return func(c *fiber.Ctx) policy.Resource {
+		userID := c.Params(param)
+		return policy.Resource{Type: policy.ResourceUser, ID: userID, OwnerID: userID}
+	}
+}
+
+// tokenPermissions reads the permissions claim, which decodes as a list of any.
+func tokenPermissions(claims jwt.MapClaims) []string {
+	values, _ := claims["permissions"].([]any)
+
+	permissions := make([]string, 0, len(values))
+	for _, value := range values {
+		if permission, ok := value.(string); ok {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
+// clientRights keeps the rights that the scope of an OAuth client token grants.
+func clientRights(rights []string, claims jwt.MapClaims) []string {
+	scope, _ := claims["scope"].(string)
+	return domain.ScopeRights(rights, scope)
+}
+
 func hasAllRights(userRights, requiredRights []string) bool {
 	rightSet := make(map[string]struct{}, len(userRights))
 	for _, right := range userRights {
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"app/config"
+	"app/internal/application/service/mocks"
+	"app/internal/domain"
+	"app/internal/domain/myerrors"
+	"app/internal/pkg/token"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type authTestSuite struct {
+	suite.Suite
+	mockCtrl       *gomock.Controller
+	mockUserSvc    *mocks.MockUserService
+	mockRevocation *mocks.MockRevocationService
+	mockRoleSvc    *mocks.MockRoleService
+	mockAPIKeySvc  *mocks.MockAPIKeyService
+	keys           *token.KeyManagerImpl
+	auth           *AuthImpl
+	app            *fiber.App
+	user           *domain.User
+}
+
+func TestAuth(t *testing.T) {
+	suite.Run(t, new(authTestSuite))
+}
+
+func (s *authTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockUserSvc = mocks.NewMockUserService(s.mockCtrl)
+	s.mockRevocation = mocks.NewMockRevocationService(s.mockCtrl)
+	s.mockRoleSvc = mocks.NewMockRoleService(s.mockCtrl)
+	s.mockAPIKeySvc = mocks.NewMockAPIKeyService(s.mockCtrl)
+
+	conf := &config.Config{JWT: config.JWTConfig{Secret: "test-secret-key-for-unit-testing"}}
+	s.keys = &token.KeyManagerImpl{Conf: conf}
+	s.Require().NoError(s.keys.Startup())
+
+	s.auth = &AuthImpl{
+		Conf:          conf,
+		UserService:   s.mockUserSvc,
+		Keys:          s.keys,
+		Revocation:    s.mockRevocation,
+		RoleService:   s.mockRoleSvc,
+		APIKeyService: s.mockAPIKeySvc,
+	}
+
+	s.user = &domain.User{ID: uuid.Must(uuid.NewV7()), Roles: domain.NewUserRoles(domain.RoleUser)}
+
+	statusMap := map[error]int{
+		myerrors.ErrInvalidToken: fiber.StatusUnauthorized,
+		myerrors.ErrForbidden:    fiber.StatusForbidden,
+	}
+	s.app = fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return c.SendStatus(gethttpstatus(err, statusMap))
+		},
+	})
+
+	ok := func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	s.app.Patch("/v1/users/:userId", s.auth.SessionAuth(), ok)
+	s.app.Post("/v1/api-keys", s.auth.SessionAuth(), ok)
+	s.app.Post("/v1/mfa/totp", s.auth.SessionAuth(), ok)
+	s.app.Post("/auth/webauthn/register/begin", s.auth.SessionAuth(), ok)
+	s.app.Post("/auth/device/approve", s.auth.SessionAuth(), ok)
+	s.app.Post("/oauth/authorize", s.auth.SessionAuth(), ok)
+	s.app.Get("/v1/users", s.auth.JWTAuth("manageUsers"), ok)
+}
+
+func (s *authTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// Helper to sign an access token of the test user with extra claims
+func (s *authTestSuite) accessToken(extraClaims jwt.MapClaims) string {
+	claims := jwt.MapClaims{
+		"user_id":     s.user.ID.String(),
+		"token_type":  domain.TokenTypeAccess.String(),
+		"session_id":  uuid.NewString(),
+		"permissions": []string{"manageUsers"},
+		"exp":         time.Now().Add(time.Minute).Unix(),
+	}
+	for key, value := range extraClaims {
+		claims[key] = value
+	}
+
+	accessToken, err := s.keys.Sign(claims)
+	s.Require().NoError(err)
+	return accessToken
+}
+
+func (s *authTestSuite) send(method, path, authorization string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(fiber.HeaderAuthorization, authorization)
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp.StatusCode
+}
+
+func (s *authTestSuite) TestSessionAuth_AcceptsLoginToken() {
+	s.mockRevocation.EXPECT().IsRevoked(gomock.Any()).Return(false)
+	s.mockUserSvc.EXPECT().GetUserByID(gomock.Any(), s.user.ID.String()).Return(s.user, nil)
+	s.mockRoleSvc.EXPECT().Rights(gomock.Any(), domain.RoleUser).Return([]string{}, nil)
+
+	status := s.send(fiber.MethodPatch, "/v1/users/"+s.user.ID.String(), "Bearer "+s.accessToken(nil))
+
+	s.Equal(fiber.StatusOK, status)
+}
+
+func (s *authTestSuite) TestSessionAuth_RefusesAPIKeys() {
+	// The key is refused before it is even looked up, whatever its scopes
+	routes := map[string]string{
+		"/v1/users/" + s.user.ID.String(): fiber.MethodPatch,
+		"/v1/api-keys":                    fiber.MethodPost,
+		"/v1/mfa/totp":                    fiber.MethodPost,
+		"/auth/webauthn/register/begin":   fiber.MethodPost,
+		"/auth/device/approve":            fiber.MethodPost,
+		"/oauth/authorize":                fiber.MethodPost,
+	}
+
+	for path, method := range routes {
+		s.Equal(fiber.StatusForbidden, s.send(method, path, "ApiKey ak_zeroscope_secret"), path)
+	}
+}
+
+func (s *authTestSuite) TestSessionAuth_RefusesClientTokens() {
+	s.mockRevocation.EXPECT().IsRevoked(gomock.Any()).Return(false).AnyTimes()
+	clientToken := s.accessToken(jwt.MapClaims{"client_id": uuid.NewString(), "scope": "profile"})
+
+	s.Equal(fiber.StatusForbidden, s.send(fiber.MethodPatch, "/v1/users/"+s.user.ID.String(), "Bearer "+clientToken))
+	s.Equal(fiber.StatusForbidden, s.send(fiber.MethodPost, "/v1/api-keys", "Bearer "+clientToken))
+}
+
+func (s *authTestSuite) TestJWTAuth_ClientTokenNeedsScope() {
+	s.mockRevocation.EXPECT().IsRevoked(gomock.Any()).Return(false).AnyTimes()
+	s.mockUserSvc.EXPECT().GetUserByID(gomock.Any(), s.user.ID.String()).Return(s.user, nil).AnyTimes()
+	s.mockRoleSvc.EXPECT().Rights(gomock.Any(), domain.RoleUser).Return([]string{"manageUsers"}, nil).AnyTimes()
+
+	profileToken := s.accessToken(jwt.MapClaims{"client_id": uuid.NewString(), "scope": "profile"})
+	s.Equal(fiber.StatusForbidden, s.send(fiber.MethodGet, "/v1/users", "Bearer "+profileToken))
+
+	scopedToken := s.accessToken(jwt.MapClaims{"client_id": uuid.NewString(), "scope": "profile manageUsers"})
+	s.Equal(fiber.StatusOK, s.send(fiber.MethodGet, "/v1/users", "Bearer "+scopedToken))
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"app/internal/pkg/formatter"
@@ -11,17 +13,38 @@ import (
 
 func LimiterConfig() fiber.Handler {
 	return limiter.New(limiter.Config{
-		Max:        20,
-		Expiration: 15 * time.Minute,
-		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(
-				formatter.NewErrorResponse(
-					formatter.TooManyRequest,
-					"Too many requests, please try again later",
-					c.Get("traceId"),
-				),
-			)
-		},
+		Max:                    20,
+		Expiration:             15 * time.Minute,
+		LimitReached:           limitReached,
 		SkipSuccessfulRequests: true,
 	})
 }
+
+// EmailLimiter limits requests per email address in the JSON body, so one inbox cannot be flooded
+// from many IPs. Requests without an email fall back to the client IP.
+func EmailLimiter(max int, expiration time.Duration) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: expiration,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			var body struct {
+				Email string `json:"email"`
+			}
+			if err := json.Unmarshal(c.Body(), &body); err != nil || body.Email == "" {
+				return c.IP()
+			}
+			return strings.ToLower(strings.TrimSpace(body.Email))
+		},
+		LimitReached: limitReached,
+	})
+}
+
+func limitReached(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusTooManyRequests).JSON(
+		formatter.NewErrorResponse(
+			formatter.TooManyRequest,
+			"Too many requests, please try again later",
+			c.Get("traceId"),
+		),
+	)
+}
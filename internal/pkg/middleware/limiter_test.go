@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailLimiter(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", EmailLimiter(2, time.Minute), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	send := func(body string) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, send(`{"email":"fake@example.com"}`))
+	assert.Equal(t, fiber.StatusOK, send(`{"email":"FAKE@example.com "}`))
+	assert.Equal(t, fiber.StatusTooManyRequests, send(`{"email":"fake@example.com"}`))
+	assert.Equal(t, fiber.StatusOK, send(`{"email":"other@example.com"}`))
+}
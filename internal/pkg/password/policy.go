@@ -0,0 +1,206 @@
+package password
+
+import (
+	"app/config"
+	"app/internal/pkg/validator"
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tommynurwantoro/golog"
+)
+
+const (
+	defaultMinLength = 8
+	// maxLength is the most bytes bcrypt hashes, it refuses longer passwords
+	maxLength = 72
+)
+
+// Rules a password can break, used as keys of the validation errors.
+const (
+	RuleMinLength      = "min_length"
+	RuleMaxLength      = "max_length"
+	RuleUpper          = "upper"
+	RuleLower          = "lower"
+	RuleDigit          = "digit"
+	RuleSpecial        = "special"
+	RuleUserInfo       = "user_info"
+	RuleForbiddenWord  = "forbidden_word"
+	RuleCommonPassword = "common_password"
+)
+
+//go:embed common_passwords.txt
+var defaultCommonPasswords []byte
+
+//go:generate mockgen -source=policy.go -destination=mocks/policy.go -package=mocks
+type Policy interface {
+	// Check returns a validation error with a message per broken rule, or nil. The user info is the
+	// name and email of the user the password is for.
+	Check(password string, userInfo ...string) error
+}
+
+// PolicyImpl applies the password section of the config. The common password list is read once at startup.
+type PolicyImpl struct {
+	Conf *config.Config `inject:"config"`
+
+	commonPasswords map[string]struct{}
+}
+
+func (p *PolicyImpl) Startup() error {
+	var list io.Reader = bytes.NewReader(defaultCommonPasswords)
+
+	if p.Conf.Password.CommonPasswordsFile != "" {
+		file, err := os.Open(p.Conf.Password.CommonPasswordsFile)
+		if err != nil {
+			golog.Error("Failed to open the common password list", err)
+			return err
+		}
+		defer file.Close()
+
+		list = file
+	}
+
+	commonPasswords, err := readCommonPasswords(list)
+	if err != nil {
+		golog.Error("Failed to read the common password list", err)
+		return err
+	}
+	p.commonPasswords = commonPasswords
+
+	return nil
+}
+
+func (p *PolicyImpl) Shutdown() error {
+	return nil
+}
+
+func (p *PolicyImpl) Check(password string, userInfo ...string) error {
+	violations := p.violations(password, userInfo)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	errs := make(map[string]error, len(violations))
+	for rule, message := range violations {
+		errs["password."+rule] = errors.New(message)
+	}
+
+	return validator.NewErrorMap(errs)
+}
+
+func (p *PolicyImpl) violations(password string, userInfo []string) map[string]string {
+	conf := p.Conf.Password
+	violations := make(map[string]string)
+
+	minLength := conf.MinLength
+	if minLength <= 0 {
+		minLength = defaultMinLength
+	}
+	maxLen := conf.MaxLength
+	if maxLen <= 0 || maxLen > maxLength {
+		maxLen = maxLength
+	}
+
+	if utf8.RuneCountInString(password) < minLength {
+		violations[RuleMinLength] = fmt.Sprintf("password must be at least %d characters long", minLength)
+	}
+	if utf8.RuneCountInString(password) > maxLen || len(password) > maxLength {
+		violations[RuleMaxLength] = fmt.Sprintf("password must be at most %d characters long", maxLen)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSpecial = true
+		}
+	}
+
+	if conf.RequireUpper && !hasUpper {
+		violations[RuleUpper] = "password must contain an uppercase letter"
+	}
+	if conf.RequireLower && !hasLower {
+		violations[RuleLower] = "password must contain a lowercase letter"
+	}
+	if conf.RequireDigit && !hasDigit {
+		violations[RuleDigit] = "password must contain a digit"
+	}
+	if conf.RequireSpecial && !hasSpecial {
+		violations[RuleSpecial] = "password must contain a special character"
+	}
+
+	lowered := strings.ToLower(password)
+
+	if conf.ForbidUserInfo && containsAny(lowered, userInfoWords(userInfo)) {
+		violations[RuleUserInfo] = "password must not contain your name or email"
+	}
+	if containsAny(lowered, conf.ForbiddenWords) {
+		violations[RuleForbiddenWord] = "password must not contain a forbidden word"
+	}
+	if _, ok := p.commonPasswords[lowered]; ok {
+		violations[RuleCommonPassword] = "password is too common"
+	}
+
+	return violations
+}
+
+// userInfoWords splits names and emails into the words a password must not contain. Words shorter than
+// three characters are left out, they would refuse too many passwords.
+func userInfoWords(userInfo []string) []string {
+	words := make([]string, 0, len(userInfo))
+	for _, info := range userInfo {
+		info, _, _ = strings.Cut(strings.ToLower(info), "@")
+		for _, word := range strings.FieldsFunc(info, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if utf8.RuneCountInString(word) >= 3 {
+				words = append(words, word)
+			}
+		}
+	}
+
+	return words
+}
+
+func containsAny(lowered string, words []string) bool {
+	for _, word := range words {
+		if word != "" && strings.Contains(lowered, strings.ToLower(word)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readCommonPasswords reads one password per line, skipping blank lines and # comments.
+func readCommonPasswords(r io.Reader) (map[string]struct{}, error) {
+	passwords := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return passwords, nil
+}
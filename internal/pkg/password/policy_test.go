@@ -0,0 +1,115 @@
+package password
+
+import (
+	"app/config"
+	"app/internal/pkg/validator"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type policyTestSuite struct {
+	suite.Suite
+	policy *PolicyImpl
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(policyTestSuite))
+}
+
+func (s *policyTestSuite) SetupTest() {
+	s.policy = &PolicyImpl{Conf: &config.Config{Password: config.PasswordConfig{
+		MinLength:      10,
+		MaxLength:      64,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+		ForbidUserInfo: true,
+		ForbiddenWords: []string{"Acme"},
+	}}}
+	s.Require().NoError(s.policy.Startup())
+}
+
+// Helper returning the broken rules of a password
+func (s *policyTestSuite) brokenRules(password string, userInfo ...string) []string {
+	err := s.policy.Check(password, userInfo...)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *validator.MapValidationError
+	s.Require().True(errors.As(err, &validationErr))
+
+	rules := make([]string, 0, len(validationErr.Errors))
+	for key := range validationErr.Errors {
+		rules = append(rules, strings.TrimPrefix(key, "password."))
+	}
+	return rules
+}
+
+func (s *policyTestSuite) TestCheck_Valid() {
+	s.NoError(s.policy.Check("Tr0ub4dor&3x", "Test User", "test@example.com"))
+}
+
+func (s *policyTestSuite) TestCheck_Lengths() {
+	s.ElementsMatch([]string{RuleMinLength}, s.brokenRules("Sh0rt!pw"))
+	s.ElementsMatch([]string{RuleMaxLength}, s.brokenRules("Aa1!"+strings.Repeat("x", 61)))
+}
+
+func (s *policyTestSuite) TestCheck_MaxLengthCappedByBcrypt() {
+	s.policy.Conf.Password.MaxLength = 200
+
+	s.ElementsMatch([]string{RuleMaxLength}, s.brokenRules("Aa1!"+strings.Repeat("x", 69)))
+}
+
+func (s *policyTestSuite) TestCheck_CharacterClasses() {
+	s.ElementsMatch([]string{RuleUpper, RuleDigit, RuleSpecial}, s.brokenRules("lowercaseonly"))
+	s.ElementsMatch([]string{RuleLower}, s.brokenRules("UPPERCASE1!X"))
+}
+
+func (s *policyTestSuite) TestCheck_ClassesNotRequired() {
+	s.policy.Conf.Password = config.PasswordConfig{}
+
+	s.NoError(s.policy.Check("lowercaseonly"))
+	s.ElementsMatch([]string{RuleMinLength}, s.brokenRules("short"))
+}
+
+func (s *policyTestSuite) TestCheck_UserInfo() {
+	s.ElementsMatch([]string{RuleUserInfo}, s.brokenRules("Jonathan#2026x", "Jonathan Doe", "jd@example.com"))
+	s.ElementsMatch([]string{RuleUserInfo}, s.brokenRules("xx!Jdoe1234Y", "J. Doe", "jdoe@example.com"))
+
+	// Words shorter than three characters and the email domain are allowed
+	s.NoError(s.policy.Check("Example!Jd2026", "Jd", "jd@example.com"))
+}
+
+func (s *policyTestSuite) TestCheck_ForbiddenWord() {
+	s.ElementsMatch([]string{RuleForbiddenWord}, s.brokenRules("myACME!pass9"))
+}
+
+func (s *policyTestSuite) TestCheck_CommonPassword() {
+	s.policy.Conf.Password.MinLength = 8
+
+	s.ElementsMatch([]string{RuleCommonPassword}, s.brokenRules("Password1!"))
+}
+
+func (s *policyTestSuite) TestStartup_CommonPasswordsFile() {
+	path := filepath.Join(s.T().TempDir(), "common.txt")
+	s.Require().NoError(os.WriteFile(path, []byte("# comment\n\nCorrect-Horse-9\n"), 0o600))
+	s.policy.Conf.Password.CommonPasswordsFile = path
+
+	s.Require().NoError(s.policy.Startup())
+
+	s.ElementsMatch([]string{RuleCommonPassword}, s.brokenRules("cORRECT-hORSE-9"))
+	s.NoError(s.policy.Check("Password1!Long"))
+}
+
+func (s *policyTestSuite) TestStartup_MissingFile() {
+	s.policy.Conf.Password.CommonPasswordsFile = filepath.Join(s.T().TempDir(), "missing.txt")
+
+	s.Error(s.policy.Startup())
+}
@@ -0,0 +1,375 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Effect is what an attribute policy does to a request it applies to.
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+	// NotApplicable is the decision when no attribute policy applies, so the rules of Rules decide
+	NotApplicable Effect = "not_applicable"
+)
+
+// Operators a condition compares an attribute with.
+const (
+	OpEquals      = "equals"
+	OpNotEquals   = "not_equals"
+	OpIn          = "in"
+	OpNotIn       = "not_in"
+	OpContains    = "contains"
+	OpNotContains = "not_contains"
+	OpGreaterOrEq = "gte"
+	OpLessOrEq    = "lte"
+)
+
+var operators = []string{OpEquals, OpNotEquals, OpIn, OpNotIn, OpContains, OpNotContains, OpGreaterOrEq, OpLessOrEq}
+
+// Roots of the attribute paths a condition can read.
+const (
+	rootSubject     = "subject"
+	rootResource    = "resource"
+	rootEnvironment = "environment"
+)
+
+// Document is a policy file: attribute policies layered over the role rules of Rules.
+type Document struct {
+	Policies []Statement `yaml:"policies"`
+}
+
+// Statement allows or denies actions when every one of its conditions holds.
+type Statement struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Effect      Effect `yaml:"effect"`
+	// Actions are matched with path.Match, so "user:*" covers every action on users
+	Actions []string `yaml:"actions"`
+	// Resource restricts the statement to a resource type, any type if empty
+	Resource   string      `yaml:"resource"`
+	Conditions []Condition `yaml:"conditions"`
+}
+
+// Condition compares the attribute at a path such as "subject.region" with a literal Value
+// or with the attribute at another path, Ref. A missing attribute fails the condition.
+type Condition struct {
+	Attribute string `yaml:"attribute"`
+	Operator  string `yaml:"operator"`
+	Value     any    `yaml:"value"`
+	Ref       string `yaml:"ref"`
+}
+
+// Input holds the attributes a policy file is evaluated against.
+type Input struct {
+	Action      string         `yaml:"action"`
+	Subject     map[string]any `yaml:"subject"`
+	Resource    map[string]any `yaml:"resource"`
+	Environment map[string]any `yaml:"environment"`
+}
+
+// Decision is the outcome of evaluating a policy file, with the statement that decided it.
+type Decision struct {
+	Effect Effect
+	Policy string
+}
+
+// Request is an action of a subject on a resource, in the environment it happens in.
+type Request struct {
+	Subject     Subject
+	Action      Action
+	Resource    Resource
+	Environment map[string]any
+}
+
+// Input flattens the request into attributes. The id, organization, roles and rights of the subject and the type, id
+// and owner of the resource take precedence over account attributes of the same name.
+func (r Request) Input() Input {
+	subject := make(map[string]any, len(r.Subject.Attributes)+4)
+	for name, value := range r.Subject.Attributes {
+		subject[name] = value
+	}
+	subject["id"] = r.Subject.UserID
+	subject["organization_id"] = r.Subject.OrganizationID
+	subject["roles"] = r.Subject.Roles
+	subject["rights"] = r.Subject.Rights
+
+	resource := make(map[string]any, len(r.Resource.Attributes)+3)
+	for name, value := range r.Resource.Attributes {
+		resource[name] = value
+	}
+	resource["type"] = r.Resource.Type
+	resource["id"] = r.Resource.ID
+	resource["owner_id"] = r.Resource.OwnerID
+
+	return Input{
+		Action:      string(r.Action),
+		Subject:     subject,
+		Resource:    resource,
+		Environment: r.Environment,
+	}
+}
+
+// Environment returns the attributes of the moment a request is made: the time in RFC 3339, the hour
+// of the day and the weekday, all in UTC.
+func Environment(now time.Time) map[string]any {
+	now = now.UTC()
+	return map[string]any{
+		"time":    now.Format(time.RFC3339),
+		"hour":    now.Hour(),
+		"weekday": strings.ToLower(now.Weekday().String()),
+	}
+}
+
+// Load reads and validates a policy file.
+func Load(file string) (*Document, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", file, err)
+	}
+	return doc, nil
+}
+
+// Parse decodes and validates a policy document. Unknown fields are rejected so a typo
+// cannot silently disable a condition.
+func Parse(data []byte) (*Document, error) {
+	doc := &Document{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (d *Document) validate() error {
+	names := make(map[string]struct{}, len(d.Policies))
+
+	for i, statement := range d.Policies {
+		if statement.Name == "" {
+			return fmt.Errorf("policy %d has no name", i+1)
+		}
+		if _, exists := names[statement.Name]; exists {
+			return fmt.Errorf("policy %s is defined twice", statement.Name)
+		}
+		names[statement.Name] = struct{}{}
+
+		if statement.Effect != Allow && statement.Effect != Deny {
+			return fmt.Errorf("policy %s: effect must be allow or deny", statement.Name)
+		}
+		if len(statement.Actions) == 0 {
+			return fmt.Errorf("policy %s has no actions", statement.Name)
+		}
+		for _, action := range statement.Actions {
+			if _, err := path.Match(action, ""); err != nil {
+				return fmt.Errorf("policy %s: invalid action pattern %q", statement.Name, action)
+			}
+		}
+
+		for _, condition := range statement.Conditions {
+			if err := condition.validate(); err != nil {
+				return fmt.Errorf("policy %s: %w", statement.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c Condition) validate() error {
+	if err := validatePath(c.Attribute); err != nil {
+		return err
+	}
+	if !slices.Contains(operators, c.Operator) {
+		return fmt.Errorf("unknown operator %q on %s", c.Operator, c.Attribute)
+	}
+	if (c.Ref == "") == (c.Value == nil) {
+		return fmt.Errorf("condition on %s needs either a value or a ref", c.Attribute)
+	}
+	if c.Ref != "" {
+		return validatePath(c.Ref)
+	}
+	return nil
+}
+
+func validatePath(attribute string) error {
+	root, name, ok := strings.Cut(attribute, ".")
+	if !ok || name == "" || (root != rootSubject && root != rootResource && root != rootEnvironment) {
+		return fmt.Errorf("attribute %q must start with subject., resource. or environment.", attribute)
+	}
+	return nil
+}
+
+// Evaluate applies the statements to the input. A matching deny wins over any allow, and the
+// decision is NotApplicable when no statement matches.
+func (d *Document) Evaluate(input Input) Decision {
+	decision := Decision{Effect: NotApplicable}
+
+	for _, statement := range d.Policies {
+		if !statement.matches(input) {
+			continue
+		}
+		if statement.Effect == Deny {
+			return Decision{Effect: Deny, Policy: statement.Name}
+		}
+		if decision.Effect == NotApplicable {
+			decision = Decision{Effect: Allow, Policy: statement.Name}
+		}
+	}
+
+	return decision
+}
+
+func (s Statement) matches(input Input) bool {
+	if s.Resource != "" && fmt.Sprint(input.Resource["type"]) != s.Resource {
+		return false
+	}
+
+	matched := slices.ContainsFunc(s.Actions, func(pattern string) bool {
+		ok, _ := path.Match(pattern, input.Action)
+		return ok
+	})
+	if !matched {
+		return false
+	}
+
+	for _, condition := range s.Conditions {
+		if !condition.holds(input) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Condition) holds(input Input) bool {
+	attribute, ok := input.lookup(c.Attribute)
+	if !ok {
+		return false
+	}
+
+	value := c.Value
+	if c.Ref != "" {
+		if value, ok = input.lookup(c.Ref); !ok {
+			return false
+		}
+	}
+
+	switch c.Operator {
+	case OpEquals:
+		return equal(attribute, value)
+	case OpNotEquals:
+		return !equal(attribute, value)
+	case OpIn:
+		return containsValue(value, attribute)
+	case OpNotIn:
+		return !containsValue(value, attribute)
+	case OpContains:
+		return containsValue(attribute, value)
+	case OpNotContains:
+		return !containsValue(attribute, value)
+	case OpGreaterOrEq, OpLessOrEq:
+		left, leftOK := number(attribute)
+		right, rightOK := number(value)
+		if !leftOK || !rightOK {
+			return false
+		}
+		if c.Operator == OpGreaterOrEq {
+			return left >= right
+		}
+		return left <= right
+	}
+	return false
+}
+
+func (in Input) lookup(attribute string) (any, bool) {
+	root, name, _ := strings.Cut(attribute, ".")
+
+	var attributes map[string]any
+	switch root {
+	case rootSubject:
+		attributes = in.Subject
+	case rootResource:
+		attributes = in.Resource
+	case rootEnvironment:
+		attributes = in.Environment
+	}
+
+	value, ok := attributes[name]
+	if !ok || value == nil || value == "" {
+		return nil, false
+	}
+	return value, true
+}
+
+// equal compares scalars by their text, so 8 in a policy file equals the hour 8 of the environment.
+func equal(a, b any) bool {
+	if _, isList := list(a); isList {
+		return false
+	}
+	if _, isList := list(b); isList {
+		return false
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// containsValue reports whether the list holds the value.
+func containsValue(values, value any) bool {
+	items, ok := list(values)
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(items, func(item any) bool {
+		return equal(item, value)
+	})
+}
+
+func list(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		return v, true
+	case []string:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
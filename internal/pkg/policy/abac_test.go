@@ -0,0 +1,178 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `
+policies:
+  - name: support-reads-own-region
+    effect: allow
+    actions: ["user:read"]
+    resource: user
+    conditions:
+      - attribute: subject.roles
+        operator: contains
+        value: support
+      - attribute: resource.region
+        operator: equals
+        ref: subject.region
+  - name: admins-cannot-delete-admins
+    effect: deny
+    actions: ["user:delete"]
+    conditions:
+      - attribute: resource.roles
+        operator: contains
+        value: admin
+      - attribute: resource.id
+        operator: not_equals
+        ref: subject.id
+  - name: office-hours
+    effect: deny
+    actions: ["report:*"]
+    conditions:
+      - attribute: environment.hour
+        operator: lte
+        value: 7
+`
+
+func TestDocumentEvaluate(t *testing.T) {
+	doc, err := Parse([]byte(testDocument))
+	require.NoError(t, err)
+
+	support := Subject{UserID: aliceID, Roles: []string{"support"}, Attributes: map[string]string{"region": "eu"}}
+	admin := Subject{UserID: aliceID, Roles: []string{"admin"}, Rights: []string{"manageUsers"}}
+
+	tests := []struct {
+		name    string
+		request Request
+		want    Decision
+	}{
+		{
+			name: "support reads user in own region",
+			request: Request{Subject: support, Action: ReadUser, Resource: Resource{
+				Type: ResourceUser, ID: bobID, Attributes: map[string]any{"region": "eu"},
+			}},
+			want: Decision{Effect: Allow, Policy: "support-reads-own-region"},
+		},
+		{
+			name: "support does not read user in other region",
+			request: Request{Subject: support, Action: ReadUser, Resource: Resource{
+				Type: ResourceUser, ID: bobID, Attributes: map[string]any{"region": "us"},
+			}},
+			want: Decision{Effect: NotApplicable},
+		},
+		{
+			name: "missing attribute fails the condition",
+			request: Request{Subject: support, Action: ReadUser, Resource: Resource{
+				Type: ResourceUser, ID: bobID,
+			}},
+			want: Decision{Effect: NotApplicable},
+		},
+		{
+			name: "admin cannot delete other admin",
+			request: Request{Subject: admin, Action: DeleteUser, Resource: Resource{
+				Type: ResourceUser, ID: bobID, Attributes: map[string]any{"roles": []string{"admin"}},
+			}},
+			want: Decision{Effect: Deny, Policy: "admins-cannot-delete-admins"},
+		},
+		{
+			name: "admin deletes own account",
+			request: Request{Subject: admin, Action: DeleteUser, Resource: Resource{
+				Type: ResourceUser, ID: aliceID, Attributes: map[string]any{"roles": []string{"admin"}},
+			}},
+			want: Decision{Effect: NotApplicable},
+		},
+		{
+			name: "action wildcard and environment",
+			request: Request{Subject: admin, Action: "report:read", Resource: Resource{Type: "report"},
+				Environment: Environment(time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC))},
+			want: Decision{Effect: Deny, Policy: "office-hours"},
+		},
+		{
+			name: "environment outside the condition",
+			request: Request{Subject: admin, Action: "report:read", Resource: Resource{Type: "report"},
+				Environment: Environment(time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC))},
+			want: Decision{Effect: NotApplicable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, doc.Evaluate(tt.request.Input()))
+		})
+	}
+}
+
+func TestDocumentEvaluate_DenyOverridesAllow(t *testing.T) {
+	doc, err := Parse([]byte(`
+policies:
+  - name: everyone-reads
+    effect: allow
+    actions: ["*"]
+  - name: nobody-reads-locked
+    effect: deny
+    actions: ["user:read"]
+    conditions:
+      - attribute: resource.locked
+        operator: in
+        value: ["true", "yes"]
+`))
+	require.NoError(t, err)
+
+	locked := Input{Action: "user:read", Resource: map[string]any{"locked": "true"}}
+	open := Input{Action: "user:read", Resource: map[string]any{"locked": "false"}}
+
+	assert.Equal(t, Decision{Effect: Deny, Policy: "nobody-reads-locked"}, doc.Evaluate(locked))
+	assert.Equal(t, Decision{Effect: Allow, Policy: "everyone-reads"}, doc.Evaluate(open))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{name: "unknown field", document: "policies:\n  - name: a\n    effect: allow\n    action: [\"*\"]\n"},
+		{name: "missing name", document: "policies:\n  - effect: allow\n    actions: [\"*\"]\n"},
+		{name: "duplicate name", document: "policies:\n  - {name: a, effect: allow, actions: [\"*\"]}\n  - {name: a, effect: deny, actions: [\"*\"]}\n"},
+		{name: "unknown effect", document: "policies:\n  - {name: a, effect: permit, actions: [\"*\"]}\n"},
+		{name: "no actions", document: "policies:\n  - {name: a, effect: allow}\n"},
+		{name: "invalid action pattern", document: "policies:\n  - {name: a, effect: allow, actions: [\"user:[\"]}\n"},
+		{name: "unknown operator", document: "policies:\n  - name: a\n    effect: allow\n    actions: [\"*\"]\n    conditions:\n      - {attribute: subject.id, operator: like, value: a}\n"},
+		{name: "unknown root", document: "policies:\n  - name: a\n    effect: allow\n    actions: [\"*\"]\n    conditions:\n      - {attribute: user.id, operator: equals, value: a}\n"},
+		{name: "value and ref", document: "policies:\n  - name: a\n    effect: allow\n    actions: [\"*\"]\n    conditions:\n      - {attribute: subject.id, operator: equals, value: a, ref: resource.id}\n"},
+		{name: "neither value nor ref", document: "policies:\n  - name: a\n    effect: allow\n    actions: [\"*\"]\n    conditions:\n      - {attribute: subject.id, operator: equals}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.document))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policies.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testDocument), 0o600))
+
+	doc, err := Load(file)
+	require.NoError(t, err)
+	assert.Len(t, doc.Policies, 3)
+
+	empty := filepath.Join(t.TempDir(), "empty.yaml")
+	require.NoError(t, os.WriteFile(empty, nil, 0o600))
+
+	doc, err = Load(empty)
+	require.NoError(t, err)
+	assert.Empty(t, doc.Policies)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"app/internal/domain/myerrors"
+	"slices"
+)
+
+// Action is something a subject does to a resource, such as reading a user.
+type Action string
+
+// Subject is the authenticated caller with its roles, the rights of those roles and the attributes of its account.
+// OrganizationID is the organization the caller acts in, empty if none.
+type Subject struct {
+	UserID         string
+	OrganizationID string
+	Roles          []string
+	Rights         []string
+	Attributes     map[string]string
+}
+
+// HasRight reports whether one of the subject's roles grants the right.
+func (s Subject) HasRight(right string) bool {
+	return slices.Contains(s.Rights, right)
+}
+
+// Resource is what an action is performed on. OwnerID is the user the resource belongs to, empty if none.
+// Attributes are what attribute policies may compare, such as the roles and region of a user account.
+type Resource struct {
+	Type       string
+	ID         string
+	OwnerID    string
+	Attributes map[string]any
+}
+
+// Rule decides whether the subject may perform an action on the resource.
+type Rule func(subject Subject, resource Resource) bool
+
+// Policy holds the rule of every action. Actions without a rule are denied.
+type Policy map[Action]Rule
+
+// Authorize returns ErrForbidden unless the rule of the action allows the subject to act on the resource.
+func (p Policy) Authorize(subject Subject, action Action, resource Resource) error {
+	rule, ok := p[action]
+	if !ok || !rule(subject, resource) {
+		return myerrors.ErrForbidden
+	}
+	return nil
+}
+
+// Owner allows subjects acting on a resource they own.
+func Owner() Rule {
+	return func(subject Subject, resource Resource) bool {
+		return subject.UserID != "" && subject.UserID == resource.OwnerID
+	}
+}
+
+// Right allows subjects whose roles grant the right, whoever owns the resource.
+func Right(right string) Rule {
+	return func(subject Subject, _ Resource) bool {
+		return subject.HasRight(right)
+	}
+}
+
+// AnyOf allows the action when one of the rules does.
+func AnyOf(rules ...Rule) Rule {
+	return func(subject Subject, resource Resource) bool {
+		for _, rule := range rules {
+			if rule(subject, resource) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf allows the action only when every rule does.
+func AllOf(rules ...Rule) Rule {
+	return func(subject Subject, resource Resource) bool {
+		for _, rule := range rules {
+			if !rule(subject, resource) {
+				return false
+			}
+		}
+		return len(rules) > 0
+	}
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"testing"
+
+	"app/internal/domain/myerrors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	aliceID = "0192a1b2-0000-7000-8000-000000000001"
+	bobID   = "0192a1b2-0000-7000-8000-000000000002"
+)
+
+func userResource(userID string) Resource {
+	return Resource{Type: ResourceUser, ID: userID, OwnerID: userID}
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	testPolicy := Policy{
+		"report:read": AnyOf(Owner(), Right("getReports")),
+		"report:sign": AllOf(Owner(), Right("signReports")),
+	}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		action   Action
+		resource Resource
+		wantErr  error
+	}{
+		{
+			name:     "owner is allowed by owner rule",
+			subject:  Subject{UserID: aliceID},
+			action:   "report:read",
+			resource: Resource{OwnerID: aliceID},
+		},
+		{
+			name:     "other user without right is denied",
+			subject:  Subject{UserID: bobID},
+			action:   "report:read",
+			resource: Resource{OwnerID: aliceID},
+			wantErr:  myerrors.ErrForbidden,
+		},
+		{
+			name:     "other user with right is allowed",
+			subject:  Subject{UserID: bobID, Rights: []string{"getReports"}},
+			action:   "report:read",
+			resource: Resource{OwnerID: aliceID},
+		},
+		{
+			name:     "all of needs every rule",
+			subject:  Subject{UserID: aliceID},
+			action:   "report:sign",
+			resource: Resource{OwnerID: aliceID},
+			wantErr:  myerrors.ErrForbidden,
+		},
+		{
+			name:     "all of allows when every rule does",
+			subject:  Subject{UserID: aliceID, Rights: []string{"signReports"}},
+			action:   "report:sign",
+			resource: Resource{OwnerID: aliceID},
+		},
+		{
+			name:     "action without rule is denied",
+			subject:  Subject{UserID: aliceID, Rights: []string{"getReports", "signReports"}},
+			action:   "report:delete",
+			resource: Resource{OwnerID: aliceID},
+			wantErr:  myerrors.ErrForbidden,
+		},
+		{
+			name:     "resource without owner is not owned by anonymous subject",
+			subject:  Subject{},
+			action:   "report:read",
+			resource: Resource{},
+			wantErr:  myerrors.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testPolicy.Authorize(tt.subject, tt.action, tt.resource)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestAllOfWithoutRules(t *testing.T) {
+	assert.False(t, AllOf()(Subject{UserID: aliceID}, Resource{OwnerID: aliceID}))
+}
+
+func TestRules(t *testing.T) {
+	admin := Subject{UserID: bobID, Rights: []string{"getUsers", "manageUsers"}}
+	reader := Subject{UserID: bobID, Rights: []string{"getUsers"}}
+	alice := Subject{UserID: aliceID}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		action  Action
+		owner   string
+		allowed bool
+	}{
+		{name: "user reads own account", subject: alice, action: ReadUser, owner: aliceID, allowed: true},
+		{name: "user updates own account", subject: alice, action: UpdateUser, owner: aliceID, allowed: true},
+		{name: "user deletes own account", subject: alice, action: DeleteUser, owner: aliceID, allowed: true},
+		{name: "user cannot read other account", subject: alice, action: ReadUser, owner: bobID},
+		{name: "user cannot update other account", subject: alice, action: UpdateUser, owner: bobID},
+		{name: "reader reads other account", subject: reader, action: ReadUser, owner: aliceID, allowed: true},
+		{name: "reader cannot update other account", subject: reader, action: UpdateUser, owner: aliceID},
+		{name: "reader cannot delete other account", subject: reader, action: DeleteUser, owner: aliceID},
+		{name: "admin updates other account", subject: admin, action: UpdateUser, owner: aliceID, allowed: true},
+		{name: "admin deletes other account", subject: admin, action: DeleteUser, owner: aliceID, allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Rules.Authorize(tt.subject, tt.action, userResource(tt.owner))
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, myerrors.ErrForbidden)
+			}
+		})
+	}
+}
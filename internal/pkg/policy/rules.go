@@ -0,0 +1,18 @@
+package policy
+
+// ResourceUser is a user account; a user owns its own account.
+const ResourceUser = "user"
+
+const (
+	ReadUser   Action = "user:read"
+	UpdateUser Action = "user:update"
+	DeleteUser Action = "user:delete"
+)
+
+// Rules is the policy of the application. Every route acting on a resource of a user declares its action here,
+// so being the owner only grants access where a rule says so.
+var Rules = Policy{
+	ReadUser:   AnyOf(Owner(), Right("getUsers")),
+	UpdateUser: AnyOf(Owner(), Right("manageUsers")),
+	DeleteUser: AnyOf(Owner(), Right("manageUsers")),
+}
@@ -0,0 +1,31 @@
+// Package tenant carries the organization a request acts in. JWTAuth stores it with SetOrganization, and
+// since Fiber's c.Context() reads its locals, repositories find it with OrganizationID on the context
+// handlers already pass down.
+package tenant
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header selects the organization of a request among the organizations of the user.
+const Header = "X-Organization-ID"
+
+type organizationKey struct{}
+
+// SetOrganization makes the organization the tenant of the request.
+func SetOrganization(c *fiber.Ctx, organizationID string) {
+	c.Locals(organizationKey{}, organizationID)
+}
+
+// WithOrganization returns a context acting in the organization, for work outside a request.
+func WithOrganization(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationID)
+}
+
+// OrganizationID returns the organization the context acts in, empty if none.
+func OrganizationID(ctx context.Context) string {
+	organizationID, _ := ctx.Value(organizationKey{}).(string)
+	return organizationID
+}
@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const organizationID = "0192a1b2-0000-7000-8000-000000000001"
+
+func TestWithOrganization(t *testing.T) {
+	assert.Empty(t, OrganizationID(context.Background()))
+	assert.Equal(t, organizationID, OrganizationID(WithOrganization(context.Background(), organizationID)))
+}
+
+func TestSetOrganization_ReachesRequestContext(t *testing.T) {
+	app := fiber.New()
+
+	var got string
+	app.Get("/", func(c *fiber.Ctx) error {
+		SetOrganization(c, organizationID)
+		return c.Next()
+	}, func(c *fiber.Ctx) error {
+		got = OrganizationID(c.Context())
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, organizationID, got)
+}
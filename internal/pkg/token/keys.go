@@ -0,0 +1,310 @@
+package token
+
+import (
+	"app/config"
+	"app/internal/domain/myerrors"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tommynurwantoro/golog"
+)
+
+const defaultHMACKeyID = "default"
+
+//go:generate mockgen -source=keys.go -destination=mocks/keys.go -package=mocks
+type KeyManager interface {
+	Sign(claims jwt.MapClaims) (string, error)
+	Keyfunc(token *jwt.Token) (any, error)
+	Algorithms() []string
+	JWKS() JWKS
+}
+
+// JWK is the public part of a signing key as described in RFC 7517.
+type JWK struct {
+	Kty string `json:"kty" example:"OKP"`
+	Kid string `json:"kid" example:"hWbxA2Ks3Q6lFEwMIfxc2cGJ0m5mYS2vhhxuPgWw8dk"`
+	Use string `json:"use" example:"sig"`
+	Alg string `json:"alg" example:"EdDSA"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty" example:"Ed25519"`
+	X   string `json:"x,omitempty" example:"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type signingKey struct {
+	kid      string
+	method   jwt.SigningMethod
+	private  any
+	public   any
+	notAfter time.Time
+}
+
+// retired reports whether the key is past its not_after date and must no longer verify tokens.
+func (k *signingKey) retired(now time.Time) bool {
+	return !k.notAfter.IsZero() && now.After(k.notAfter)
+}
+
+type KeyManagerImpl struct {
+	Conf *config.Config `inject:"config"`
+
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+func (k *KeyManagerImpl) Startup() error {
+	if err := k.loadKeyRing(); err != nil {
+		golog.Error("Failed to load JWT signing keys", err)
+		return err
+	}
+
+	return nil
+}
+
+func (k *KeyManagerImpl) Shutdown() error {
+	return nil
+}
+
+func (k *KeyManagerImpl) loadKeyRing() error {
+	keyConfs := k.Conf.JWT.Keys
+	if len(keyConfs) == 0 {
+		// Without a key ring the single jwt.algorithm/jwt.secret/jwt.private_key_file key signs everything
+		keyConfs = []config.JWTKey{{
+			ID:             k.Conf.JWT.KeyID,
+			Algorithm:      k.Conf.JWT.Algorithm,
+			PrivateKeyFile: k.Conf.JWT.PrivateKeyFile,
+			Active:         true,
+		}}
+	}
+
+	now := time.Now()
+	k.active = nil
+	k.keys = make(map[string]*signingKey, len(keyConfs))
+
+	for _, keyConf := range keyConfs {
+		key, err := loadSigningKey(keyConf, k.Conf.JWT.Secret)
+		if err != nil {
+			return err
+		}
+
+		if key.retired(now) {
+			if keyConf.Active {
+				return fmt.Errorf("active jwt key %q is past its not_after date", key.kid)
+			}
+			golog.Info(fmt.Sprintf("Skipping retired JWT key %s", key.kid))
+			continue
+		}
+
+		if _, exists := k.keys[key.kid]; exists {
+			return fmt.Errorf("duplicate jwt key id %q", key.kid)
+		}
+		k.keys[key.kid] = key
+
+		if keyConf.Active {
+			if k.active != nil {
+				return fmt.Errorf("jwt keys %q and %q are both active", k.active.kid, key.kid)
+			}
+			k.active = key
+		}
+	}
+
+	if k.active == nil {
+		return errors.New("no active jwt key configured")
+	}
+
+	return nil
+}
+
+// Sign signs claims with the active key and stamps its kid in the header.
+func (k *KeyManagerImpl) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(k.active.method, claims)
+	token.Header["kid"] = k.active.kid
+
+	return token.SignedString(k.active.private)
+}
+
+// Keyfunc resolves the verification key by kid. Tokens without a kid predate it and use the active key.
+func (k *KeyManagerImpl) Keyfunc(token *jwt.Token) (any, error) {
+	key := k.active
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key, ok = k.keys[kid]; !ok {
+			return nil, myerrors.ErrInvalidToken
+		}
+	}
+
+	if key.retired(time.Now()) || token.Method.Alg() != key.method.Alg() {
+		return nil, myerrors.ErrInvalidToken
+	}
+
+	return key.public, nil
+}
+
+func (k *KeyManagerImpl) Algorithms() []string {
+	algorithms := make([]string, 0, len(k.keys))
+	seen := make(map[string]struct{}, len(k.keys))
+	for _, key := range k.keys {
+		if _, ok := seen[key.method.Alg()]; ok {
+			continue
+		}
+		seen[key.method.Alg()] = struct{}{}
+		algorithms = append(algorithms, key.method.Alg())
+	}
+	return algorithms
+}
+
+// JWKS lists the public keys that still verify tokens; HMAC secrets are never published.
+func (k *KeyManagerImpl) JWKS() JWKS {
+	now := time.Now()
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range k.keys {
+		if key.retired(now) {
+			continue
+		}
+		if jwk, ok := publicJWK(key.public); ok {
+			jwk.Kid = key.kid
+			jwk.Use = "sig"
+			jwk.Alg = key.method.Alg()
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+func loadSigningKey(keyConf config.JWTKey, fallbackSecret string) (*signingKey, error) {
+	key := &signingKey{kid: keyConf.ID}
+
+	if keyConf.NotAfter != "" {
+		notAfter, err := time.Parse(time.RFC3339, keyConf.NotAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid not_after for jwt key %q: %w", keyConf.ID, err)
+		}
+		key.notAfter = notAfter
+	}
+
+	algorithm := keyConf.Algorithm
+	if algorithm == "" {
+		algorithm = jwt.SigningMethodHS256.Alg()
+	}
+
+	if algorithm == jwt.SigningMethodHS256.Alg() {
+		secret := fallbackSecret
+		if keyConf.SecretFile != "" {
+			secretBytes, err := os.ReadFile(keyConf.SecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read secret_file of jwt key %q: %w", keyConf.ID, err)
+			}
+			secret = strings.TrimSpace(string(secretBytes))
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("jwt.secret is required for %s", algorithm)
+		}
+		if key.kid == "" {
+			key.kid = defaultHMACKeyID
+		}
+		key.method, key.private, key.public = jwt.SigningMethodHS256, []byte(secret), []byte(secret)
+		return key, nil
+	}
+
+	pemBytes, err := os.ReadFile(keyConf.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private_key_file of jwt key %q: %w", keyConf.ID, err)
+	}
+
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		privateKey, errParse := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if errParse != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", errParse)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodRS256, privateKey, &privateKey.PublicKey
+	case jwt.SigningMethodES256.Alg():
+		privateKey, errParse := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+		if errParse != nil {
+			return nil, fmt.Errorf("failed to parse EC private key: %w", errParse)
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s requires a P-256 key", algorithm)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodES256, privateKey, &privateKey.PublicKey
+	case jwt.SigningMethodEdDSA.Alg():
+		privateKey, errParse := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if errParse != nil {
+			return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", errParse)
+		}
+		edKey, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s requires an Ed25519 key", algorithm)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, edKey, edKey.Public()
+	default:
+		return nil, fmt.Errorf("unsupported jwt.algorithm %q", algorithm)
+	}
+
+	if key.kid == "" {
+		key.kid = thumbprint(key.public)
+	}
+
+	return key, nil
+}
+
+func publicJWK(public any) (JWK, bool) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", N: encode(pub.N.Bytes()), E: encode(big.NewInt(int64(pub.E)).Bytes())}, true
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return JWK{}, false
+		}
+		// Uncompressed point: 0x04 || X || Y
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		return JWK{Kty: "EC", Crv: pub.Curve.Params().Name, X: encode(point[1 : 1+size]), Y: encode(point[1+size:])}, true
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: encode(pub)}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+// thumbprint derives a stable kid from the public key as described in RFC 7638.
+func thumbprint(public any) string {
+	jwk, ok := publicJWK(public)
+	if !ok {
+		return defaultHMACKeyID
+	}
+
+	var members map[string]string
+	switch jwk.Kty {
+	case "RSA":
+		members = map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N}
+	case "EC":
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X, "y": jwk.Y}
+	default:
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	}
+
+	// encoding/json sorts map keys, which gives the canonical member order
+	canonical, _ := json.Marshal(members)
+	sum := sha256.Sum256(canonical)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,184 @@
+package token
+
+import (
+	"app/config"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type keysTestSuite struct {
+	suite.Suite
+}
+
+func TestKeys(t *testing.T) {
+	suite.Run(t, new(keysTestSuite))
+}
+
+// Helper to write a PKCS#8 PEM private key and return its path
+func (s *keysTestSuite) writeKey(key crypto.PrivateKey) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	s.Require().NoError(err)
+
+	path := filepath.Join(s.T().TempDir(), "private.pem")
+	s.Require().NoError(os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	return path
+}
+
+func (s *keysTestSuite) newKeys(jwtConf config.JWTConfig) *KeyManagerImpl {
+	keys := &KeyManagerImpl{Conf: &config.Config{JWT: jwtConf}}
+	s.Require().NoError(keys.Startup())
+	return keys
+}
+
+func (s *keysTestSuite) testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id":    "123e4567-e89b-12d3-a456-426614174000",
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// ==================== Algorithm Tests ====================
+
+func (s *keysTestSuite) TestSignAndVerify_AllAlgorithms() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+
+	cases := map[string]struct {
+		key crypto.PrivateKey
+		kty string
+	}{
+		"RS256": {key: rsaKey, kty: "RSA"},
+		"ES256": {key: ecKey, kty: "EC"},
+		"EdDSA": {key: edKey, kty: "OKP"},
+	}
+
+	for algorithm, tc := range cases {
+		s.Run(algorithm, func() {
+			keys := s.newKeys(config.JWTConfig{Algorithm: algorithm, PrivateKeyFile: s.writeKey(tc.key)})
+
+			tokenStr, err := keys.Sign(s.testClaims())
+			s.Require().NoError(err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+			s.Require().NoError(err)
+			s.Equal(algorithm, parsed.Method.Alg())
+			s.Equal(keys.active.kid, parsed.Header["kid"])
+
+			userID, err := VerifyToken(tokenStr, keys, "access")
+			s.NoError(err)
+			s.Equal("123e4567-e89b-12d3-a456-426614174000", userID)
+
+			jwks := keys.JWKS()
+			s.Require().Len(jwks.Keys, 1)
+			s.Equal(tc.kty, jwks.Keys[0].Kty)
+			s.Equal(algorithm, jwks.Keys[0].Alg)
+			s.Equal("sig", jwks.Keys[0].Use)
+			s.Equal(keys.active.kid, jwks.Keys[0].Kid)
+		})
+	}
+}
+
+func (s *keysTestSuite) TestStartup_ConfiguredKeyID() {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+
+	keys := s.newKeys(config.JWTConfig{Algorithm: "EdDSA", KeyID: "2026-10", PrivateKeyFile: s.writeKey(edKey)})
+
+	s.Equal("2026-10", keys.JWKS().Keys[0].Kid)
+}
+
+func (s *keysTestSuite) TestThumbprint_RFC8037Example() {
+	// Ed25519 thumbprint example from RFC 8037 appendix A.3
+	public, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	s.Require().NoError(err)
+
+	s.Equal("kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", thumbprint(ed25519.PublicKey(public)))
+}
+
+func (s *keysTestSuite) TestJWKS_HMACIsNotPublished() {
+	keys := s.newKeys(config.JWTConfig{Secret: "test-secret"})
+
+	s.Empty(keys.JWKS().Keys)
+	s.Equal([]string{"HS256"}, keys.Algorithms())
+}
+
+// ==================== Rejection Tests ====================
+
+func (s *keysTestSuite) TestVerify_RejectsHMACSignedWithPublicKey() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	keys := s.newKeys(config.JWTConfig{Algorithm: "RS256", PrivateKeyFile: s.writeKey(rsaKey)})
+
+	// Algorithm confusion: HS256 keyed with the public key material
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	s.Require().NoError(err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, s.testClaims())
+	forged.Header["kid"] = keys.active.kid
+	tokenStr, err := forged.SignedString(publicDER)
+	s.Require().NoError(err)
+
+	_, err = VerifyToken(tokenStr, keys, "access")
+	s.Error(err)
+}
+
+func (s *keysTestSuite) TestVerify_RejectsUnknownKeyID() {
+	keys := s.newKeys(config.JWTConfig{Secret: "test-secret"})
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, s.testClaims())
+	forged.Header["kid"] = "unknown"
+	tokenStr, err := forged.SignedString([]byte("test-secret"))
+	s.Require().NoError(err)
+
+	_, err = VerifyToken(tokenStr, keys, "access")
+	s.Error(err)
+}
+
+func (s *keysTestSuite) TestVerify_AcceptsTokenWithoutKeyID() {
+	keys := s.newKeys(config.JWTConfig{Secret: "test-secret"})
+
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, s.testClaims())
+	tokenStr, err := legacy.SignedString([]byte("test-secret"))
+	s.Require().NoError(err)
+
+	_, err = VerifyToken(tokenStr, keys, "access")
+	s.NoError(err)
+}
+
+func (s *keysTestSuite) TestStartup_Errors() {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	s.Require().NoError(err)
+
+	cases := map[string]config.JWTConfig{
+		"missing secret":      {},
+		"unsupported":         {Algorithm: "PS512", PrivateKeyFile: s.writeKey(ecKey)},
+		"missing key file":    {Algorithm: "RS256", PrivateKeyFile: filepath.Join(s.T().TempDir(), "missing.pem")},
+		"wrong key type":      {Algorithm: "RS256", PrivateKeyFile: s.writeKey(ecKey)},
+		"wrong curve for alg": {Algorithm: "ES256", PrivateKeyFile: s.writeKey(ecKey)},
+	}
+
+	for name, jwtConf := range cases {
+		s.Run(name, func() {
+			keys := &KeyManagerImpl{Conf: &config.Config{JWT: jwtConf}}
+			s.Error(keys.Startup())
+		})
+	}
+}
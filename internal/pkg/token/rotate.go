@@ -0,0 +1,128 @@
+package token
+
+import (
+	"app/config"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// GenerateKey writes fresh key material for algorithm into dir and returns its key ring entry.
+func GenerateKey(algorithm, dir string) (config.JWTKey, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return config.JWTKey{}, fmt.Errorf("failed to create key directory: %w", err)
+	}
+
+	if algorithm == jwt.SigningMethodHS256.Alg() {
+		secret := make([]byte, 32)
+		kid := make([]byte, 16)
+		if _, err := rand.Read(secret); err != nil {
+			return config.JWTKey{}, err
+		}
+		if _, err := rand.Read(kid); err != nil {
+			return config.JWTKey{}, err
+		}
+
+		key := config.JWTKey{ID: base64.RawURLEncoding.EncodeToString(kid), Algorithm: algorithm}
+		key.SecretFile = filepath.Join(dir, key.ID+".key")
+		encoded := base64.RawURLEncoding.EncodeToString(secret)
+
+		return key, os.WriteFile(key.SecretFile, []byte(encoded), 0o600)
+	}
+
+	var (
+		privateKey any
+		publicKey  any
+	)
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return config.JWTKey{}, err
+		}
+		privateKey, publicKey = rsaKey, &rsaKey.PublicKey
+	case jwt.SigningMethodES256.Alg():
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return config.JWTKey{}, err
+		}
+		privateKey, publicKey = ecKey, &ecKey.PublicKey
+	case jwt.SigningMethodEdDSA.Alg():
+		edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return config.JWTKey{}, err
+		}
+		privateKey, publicKey = edPrivate, edPublic
+	default:
+		return config.JWTKey{}, fmt.Errorf("unsupported jwt algorithm %q", algorithm)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return config.JWTKey{}, err
+	}
+
+	key := config.JWTKey{ID: thumbprint(publicKey), Algorithm: algorithm}
+	key.PrivateKeyFile = filepath.Join(dir, key.ID+".pem")
+
+	return key, os.WriteFile(key.PrivateKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+}
+
+// RotateKeyRing promotes newKey and retires the currently active key at retireAt, so tokens it already
+// signed stay valid until they expire. A config without a key ring has its single key moved into the ring.
+// Keys already past their not_after date are dropped.
+func RotateKeyRing(conf config.JWTConfig, newKey config.JWTKey, retireAt, now time.Time) ([]config.JWTKey, error) {
+	ring := conf.Keys
+	if len(ring) == 0 {
+		legacy := config.JWTKey{
+			ID:             conf.KeyID,
+			Algorithm:      conf.Algorithm,
+			PrivateKeyFile: conf.PrivateKeyFile,
+			Active:         true,
+		}
+		// Resolve the kid the single key was signing with, so its tokens keep matching
+		key, err := loadSigningKey(legacy, conf.Secret)
+		if err != nil {
+			return nil, err
+		}
+		legacy.ID = key.kid
+		legacy.Algorithm = key.method.Alg()
+		ring = []config.JWTKey{legacy}
+	}
+
+	rotated := make([]config.JWTKey, 0, len(ring)+1)
+	newKey.Active = true
+	newKey.NotAfter = ""
+	rotated = append(rotated, newKey)
+
+	for _, key := range ring {
+		if key.NotAfter != "" {
+			notAfter, err := time.Parse(time.RFC3339, key.NotAfter)
+			if err != nil {
+				return nil, fmt.Errorf("invalid not_after for jwt key %q: %w", key.ID, err)
+			}
+			if now.After(notAfter) {
+				continue
+			}
+		}
+
+		if key.Active {
+			key.Active = false
+			key.NotAfter = retireAt.UTC().Format(time.RFC3339)
+		}
+		rotated = append(rotated, key)
+	}
+
+	return rotated, nil
+}
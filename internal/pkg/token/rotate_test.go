@@ -0,0 +1,163 @@
+package token
+
+import (
+	"app/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type rotateTestSuite struct {
+	suite.Suite
+	keyDir string
+}
+
+func TestRotate(t *testing.T) {
+	suite.Run(t, new(rotateTestSuite))
+}
+
+func (s *rotateTestSuite) SetupTest() {
+	s.keyDir = s.T().TempDir()
+}
+
+func (s *rotateTestSuite) newKeys(jwtConf config.JWTConfig) *KeyManagerImpl {
+	keys := &KeyManagerImpl{Conf: &config.Config{JWT: jwtConf}}
+	s.Require().NoError(keys.Startup())
+	return keys
+}
+
+func (s *rotateTestSuite) signAccessToken(keys KeyManager) string {
+	tokenStr, err := keys.Sign(map[string]any{
+		"user_id":    "123e4567-e89b-12d3-a456-426614174000",
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	})
+	s.Require().NoError(err)
+	return tokenStr
+}
+
+// ==================== GenerateKey Tests ====================
+
+func (s *rotateTestSuite) TestGenerateKey_AllAlgorithms() {
+	for _, algorithm := range []string{"HS256", "RS256", "ES256", "EdDSA"} {
+		s.Run(algorithm, func() {
+			key, err := GenerateKey(algorithm, s.keyDir)
+			s.Require().NoError(err)
+			s.NotEmpty(key.ID)
+
+			key.Active = true
+			keys := s.newKeys(config.JWTConfig{Keys: []config.JWTKey{key}})
+			s.Equal(key.ID, keys.active.kid)
+		})
+	}
+}
+
+func (s *rotateTestSuite) TestGenerateKey_Unsupported() {
+	_, err := GenerateKey("none", s.keyDir)
+	s.Error(err)
+}
+
+// ==================== RotateKeyRing Tests ====================
+
+func (s *rotateTestSuite) TestRotateKeyRing_MovesSingleKeyIntoRing() {
+	now := time.Now()
+	newKey, err := GenerateKey("EdDSA", s.keyDir)
+	s.Require().NoError(err)
+
+	ring, err := RotateKeyRing(config.JWTConfig{Secret: "legacy-secret"}, newKey, now.Add(time.Hour), now)
+	s.Require().NoError(err)
+
+	s.Require().Len(ring, 2)
+	s.Equal(newKey.ID, ring[0].ID)
+	s.True(ring[0].Active)
+	s.Equal(defaultHMACKeyID, ring[1].ID)
+	s.Equal("HS256", ring[1].Algorithm)
+	s.False(ring[1].Active)
+	s.Equal(now.Add(time.Hour).UTC().Format(time.RFC3339), ring[1].NotAfter)
+}
+
+func (s *rotateTestSuite) TestRotateKeyRing_DropsRetiredKeys() {
+	now := time.Now()
+	active, err := GenerateKey("EdDSA", s.keyDir)
+	s.Require().NoError(err)
+	active.Active = true
+	expired, err := GenerateKey("EdDSA", s.keyDir)
+	s.Require().NoError(err)
+	expired.NotAfter = now.Add(-time.Minute).UTC().Format(time.RFC3339)
+	newKey, err := GenerateKey("EdDSA", s.keyDir)
+	s.Require().NoError(err)
+
+	ring, err := RotateKeyRing(
+		config.JWTConfig{Keys: []config.JWTKey{active, expired}}, newKey, now.Add(time.Hour), now,
+	)
+	s.Require().NoError(err)
+
+	s.Require().Len(ring, 2)
+	s.Equal(newKey.ID, ring[0].ID)
+	s.Equal(active.ID, ring[1].ID)
+	s.False(ring[1].Active)
+}
+
+// ==================== Key Ring Tests ====================
+
+func (s *rotateTestSuite) TestKeyRing_RetiredKeyVerifiesUntilNotAfter() {
+	now := time.Now()
+	oldKey, err := GenerateKey("ES256", s.keyDir)
+	s.Require().NoError(err)
+	oldKey.Active = true
+	oldKeys := s.newKeys(config.JWTConfig{Keys: []config.JWTKey{oldKey}})
+	oldToken := s.signAccessToken(oldKeys)
+
+	newKey, err := GenerateKey("EdDSA", s.keyDir)
+	s.Require().NoError(err)
+	ring, err := RotateKeyRing(oldKeys.Conf.JWT, newKey, now.Add(time.Hour), now)
+	s.Require().NoError(err)
+	keys := s.newKeys(config.JWTConfig{Keys: ring})
+
+	_, err = VerifyToken(oldToken, keys, "access")
+	s.NoError(err)
+	_, err = VerifyToken(s.signAccessToken(keys), keys, "access")
+	s.NoError(err)
+	s.Len(keys.JWKS().Keys, 2)
+
+	// Once past not_after the retired key is no longer loaded
+	ring[1].NotAfter = now.Add(-time.Second).UTC().Format(time.RFC3339)
+	keys = s.newKeys(config.JWTConfig{Keys: ring})
+
+	_, err = VerifyToken(oldToken, keys, "access")
+	s.Error(err)
+	s.Len(keys.JWKS().Keys, 1)
+}
+
+func (s *rotateTestSuite) TestKeyRing_InvalidRings() {
+	first, err := GenerateKey("EdDSA", s.keyDir)
+	s.Require().NoError(err)
+	second, err := GenerateKey("EdDSA", s.keyDir)
+	s.Require().NoError(err)
+
+	active := func(key config.JWTKey) config.JWTKey {
+		key.Active = true
+		return key
+	}
+	expiredActive := active(first)
+	expiredActive.NotAfter = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	badDate := first
+	badDate.NotAfter = "tomorrow"
+
+	cases := map[string][]config.JWTKey{
+		"no active key":       {first, second},
+		"two active keys":     {active(first), active(second)},
+		"duplicate key id":    {active(first), first},
+		"expired active key":  {expiredActive},
+		"invalid not_after":   {active(second), badDate},
+		"missing secret file": {{ID: "hs", Algorithm: "HS256", SecretFile: s.keyDir + "/missing.key", Active: true}},
+	}
+
+	for name, ring := range cases {
+		s.Run(name, func() {
+			keys := &KeyManagerImpl{Conf: &config.Config{JWT: config.JWTConfig{Keys: ring}}}
+			s.Error(keys.Startup())
+		})
+	}
+}
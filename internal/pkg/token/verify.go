@@ -6,29 +6,37 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func VerifyToken(tokenStr, secret, tokenType string) (string, error) {
-	token, err := jwt.Parse(tokenStr, func(_ *jwt.Token) (any, error) {
-		return []byte(secret), nil
-	})
+func VerifyToken(tokenStr string, keys KeyManager, tokenType string) (string, error) {
+	claims, err := ParseToken(tokenStr, keys, tokenType)
+	if err != nil {
+		return "", err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", myerrors.ErrInvalidTokenUserID
+	}
 
+	return userID, nil
+}
+
+// ParseToken validates the token signature and type and returns its claims.
+// Only the algorithms of the configured keys are accepted, so a token cannot pick its own verification method.
+func ParseToken(tokenStr string, keys KeyManager, tokenType string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenStr, keys.Keyfunc, jwt.WithValidMethods(keys.Algorithms()))
 	if err != nil || !token.Valid {
-		return "", myerrors.ErrInvalidToken
+		return nil, myerrors.ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", myerrors.ErrInvalidTokenClaims
+		return nil, myerrors.ErrInvalidTokenClaims
 	}
 
 	jwtType, ok := claims["token_type"].(string)
 	if !ok || jwtType != tokenType {
-		return "", myerrors.ErrInvalidTokenType
+		return nil, myerrors.ErrInvalidTokenType
 	}
 
-	userID, ok := claims["user_id"].(string)
-	if !ok {
-		return "", myerrors.ErrInvalidTokenUserID
-	}
-
-	return userID, nil
+	return claims, nil
 }
@@ -1,6 +1,7 @@
 package token
 
 import (
+	"app/config"
 	"app/internal/domain/myerrors"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 type verifyTestSuite struct {
 	suite.Suite
 	testSecret string
+	keys       KeyManager
 }
 
 func TestVerify(t *testing.T) {
@@ -20,6 +22,13 @@ func TestVerify(t *testing.T) {
 
 func (s *verifyTestSuite) SetupTest() {
 	s.testSecret = "test-secret-key-for-unit-testing"
+	s.keys = s.newKeys(s.testSecret)
+}
+
+func (s *verifyTestSuite) newKeys(secret string) KeyManager {
+	keys := &KeyManagerImpl{Conf: &config.Config{JWT: config.JWTConfig{Secret: secret}}}
+	s.Require().NoError(keys.Startup())
+	return keys
 }
 
 // Helper to create a valid JWT token for testing
@@ -42,7 +51,7 @@ func (s *verifyTestSuite) TestVerifyToken_Success() {
 	tokenType := "access"
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(1*time.Hour))
 
-	result, err := VerifyToken(token, s.testSecret, tokenType)
+	result, err := VerifyToken(token, s.keys, tokenType)
 
 	s.NoError(err)
 	s.Equal(userID, result)
@@ -53,7 +62,7 @@ func (s *verifyTestSuite) TestVerifyToken_ResetPasswordType() {
 	tokenType := "resetPassword"
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(15*time.Minute))
 
-	result, err := VerifyToken(token, s.testSecret, tokenType)
+	result, err := VerifyToken(token, s.keys, tokenType)
 
 	s.NoError(err)
 	s.Equal(userID, result)
@@ -64,7 +73,7 @@ func (s *verifyTestSuite) TestVerifyToken_VerifyEmailType() {
 	tokenType := "verifyEmail"
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(24*time.Hour))
 
-	result, err := VerifyToken(token, s.testSecret, tokenType)
+	result, err := VerifyToken(token, s.keys, tokenType)
 
 	s.NoError(err)
 	s.Equal(userID, result)
@@ -75,7 +84,7 @@ func (s *verifyTestSuite) TestVerifyToken_RefreshType() {
 	tokenType := "refresh"
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(7*24*time.Hour))
 
-	result, err := VerifyToken(token, s.testSecret, tokenType)
+	result, err := VerifyToken(token, s.keys, tokenType)
 
 	s.NoError(err)
 	s.Equal(userID, result)
@@ -86,7 +95,7 @@ func (s *verifyTestSuite) TestVerifyToken_RefreshType() {
 func (s *verifyTestSuite) TestVerifyToken_InvalidTokenFormat() {
 	invalidToken := "not-a-valid-jwt-token"
 
-	result, err := VerifyToken(invalidToken, s.testSecret, "access")
+	result, err := VerifyToken(invalidToken, s.keys, "access")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidToken, err)
@@ -94,7 +103,7 @@ func (s *verifyTestSuite) TestVerifyToken_InvalidTokenFormat() {
 }
 
 func (s *verifyTestSuite) TestVerifyToken_EmptyToken() {
-	result, err := VerifyToken("", s.testSecret, "access")
+	result, err := VerifyToken("", s.keys, "access")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidToken, err)
@@ -106,7 +115,7 @@ func (s *verifyTestSuite) TestVerifyToken_ExpiredToken() {
 	tokenType := "access"
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(-1*time.Hour))
 
-	result, err := VerifyToken(token, s.testSecret, tokenType)
+	result, err := VerifyToken(token, s.keys, tokenType)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidToken, err)
@@ -118,7 +127,7 @@ func (s *verifyTestSuite) TestVerifyToken_WrongSecret() {
 	tokenType := "access"
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(1*time.Hour))
 
-	result, err := VerifyToken(token, "wrong-secret", tokenType)
+	result, err := VerifyToken(token, s.newKeys("wrong-secret"), tokenType)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidToken, err)
@@ -131,7 +140,7 @@ func (s *verifyTestSuite) TestVerifyToken_WrongTokenType() {
 	userID := "123e4567-e89b-12d3-a456-426614174000"
 	token := s.createTestToken(userID, "access", s.testSecret, time.Now().Add(1*time.Hour))
 
-	result, err := VerifyToken(token, s.testSecret, "refresh")
+	result, err := VerifyToken(token, s.keys, "refresh")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidTokenType, err)
@@ -142,7 +151,7 @@ func (s *verifyTestSuite) TestVerifyToken_EmptyTokenType() {
 	userID := "123e4567-e89b-12d3-a456-426614174000"
 	token := s.createTestToken(userID, "access", s.testSecret, time.Now().Add(1*time.Hour))
 
-	result, err := VerifyToken(token, s.testSecret, "")
+	result, err := VerifyToken(token, s.keys, "")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidTokenType, err)
@@ -160,7 +169,7 @@ func (s *verifyTestSuite) TestVerifyToken_MissingUserID() {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, _ := token.SignedString([]byte(s.testSecret))
 
-	result, err := VerifyToken(tokenString, s.testSecret, "access")
+	result, err := VerifyToken(tokenString, s.keys, "access")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidTokenUserID, err)
@@ -176,7 +185,7 @@ func (s *verifyTestSuite) TestVerifyToken_MissingTokenType() {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, _ := token.SignedString([]byte(s.testSecret))
 
-	result, err := VerifyToken(tokenString, s.testSecret, "access")
+	result, err := VerifyToken(tokenString, s.keys, "access")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidTokenType, err)
@@ -193,7 +202,7 @@ func (s *verifyTestSuite) TestVerifyToken_UserIDNotString() {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, _ := token.SignedString([]byte(s.testSecret))
 
-	result, err := VerifyToken(tokenString, s.testSecret, "access")
+	result, err := VerifyToken(tokenString, s.keys, "access")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidTokenUserID, err)
@@ -210,7 +219,7 @@ func (s *verifyTestSuite) TestVerifyToken_TokenTypeNotString() {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, _ := token.SignedString([]byte(s.testSecret))
 
-	result, err := VerifyToken(tokenString, s.testSecret, "access")
+	result, err := VerifyToken(tokenString, s.keys, "access")
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidTokenType, err)
@@ -233,7 +242,7 @@ func (s *verifyTestSuite) TestVerifyToken_TokenWithExtraClaims() {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, _ := token.SignedString([]byte(s.testSecret))
 
-	result, err := VerifyToken(tokenString, s.testSecret, tokenType)
+	result, err := VerifyToken(tokenString, s.keys, tokenType)
 
 	s.NoError(err)
 	s.Equal(userID, result)
@@ -245,7 +254,7 @@ func (s *verifyTestSuite) TestVerifyToken_TokenJustExpired() {
 	// Token expired 1 second ago
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(-1*time.Second))
 
-	result, err := VerifyToken(token, s.testSecret, tokenType)
+	result, err := VerifyToken(token, s.keys, tokenType)
 
 	s.Error(err)
 	s.Equal(myerrors.ErrInvalidToken, err)
@@ -258,16 +267,14 @@ func (s *verifyTestSuite) TestVerifyToken_TokenAboutToExpire() {
 	// Token expires in 1 second
 	token := s.createTestToken(userID, tokenType, s.testSecret, time.Now().Add(1*time.Second))
 
-	result, err := VerifyToken(token, s.testSecret, tokenType)
+	result, err := VerifyToken(token, s.keys, tokenType)
 
 	s.NoError(err)
 	s.Equal(userID, result)
 }
 
 func (s *verifyTestSuite) TestVerifyToken_DifferentSigningMethod() {
-	// Create token with HS512 instead of HS256
-	// Note: HS384/HS512 still work with the same secret, they just use different hash sizes
-	// The VerifyToken function doesn't validate the specific algorithm, so this will succeed
+	// Only the configured algorithm is accepted, even when the same secret would verify the signature
 	claims := jwt.MapClaims{
 		"user_id":    "123e4567-e89b-12d3-a456-426614174000",
 		"token_type": "access",
@@ -277,9 +284,37 @@ func (s *verifyTestSuite) TestVerifyToken_DifferentSigningMethod() {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
 	tokenString, _ := token.SignedString([]byte(s.testSecret))
 
-	// HS512 tokens are still valid with the same secret
-	result, err := VerifyToken(tokenString, s.testSecret, "access")
+	result, err := VerifyToken(tokenString, s.keys, "access")
+
+	s.Error(err)
+	s.Equal(myerrors.ErrInvalidToken, err)
+	s.Empty(result)
+}
+
+// ==================== ParseToken Tests ====================
+
+func (s *verifyTestSuite) TestParseToken_ReturnsClaims() {
+	claims := jwt.MapClaims{
+		"user_id":    "123e4567-e89b-12d3-a456-426614174000",
+		"session_id": "0192f8a4-3c1e-7d2a-9b4f-5e6a7b8c9d0e",
+		"token_type": "access",
+		"exp":        time.Now().Add(1 * time.Hour).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, _ := token.SignedString([]byte(s.testSecret))
+
+	result, err := ParseToken(tokenString, s.keys, "access")
 
 	s.NoError(err)
-	s.Equal("123e4567-e89b-12d3-a456-426614174000", result)
+	s.Equal("0192f8a4-3c1e-7d2a-9b4f-5e6a7b8c9d0e", result["session_id"])
+}
+
+func (s *verifyTestSuite) TestParseToken_WrongTokenType() {
+	token := s.createTestToken("123e4567-e89b-12d3-a456-426614174000", "refresh", s.testSecret, time.Now().Add(time.Hour))
+
+	result, err := ParseToken(token, s.keys, "access")
+
+	s.Error(err)
+	s.Equal(myerrors.ErrInvalidTokenType, err)
+	s.Nil(result)
 }
@@ -22,7 +22,6 @@ func NewGoValidator() Validator {
 	trans, _ := uni.GetTranslator("en")
 
 	_ = en_translations.RegisterDefaultTranslations(v, trans)
-	RegisterCustomValidator(v, trans)
 	return &GoValidator{validate: v, uni: trans}
 }
 
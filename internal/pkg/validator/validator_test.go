@@ -53,25 +53,3 @@ func (t *validatorTestSuite) TestValidateError() {
 	err := t.validator.Validate(t.ctx, mockUser)
 	t.Error(err)
 }
-
-func (t *validatorTestSuite) TestValidateStrongPassword() {
-	type Register struct {
-		Password string `validate:"strong-password"`
-	}
-
-	mockRegister := Register{Password: "!@#123Asd"}
-
-	err := t.validator.Validate(t.ctx, mockRegister)
-	t.Nil(err)
-}
-
-func (t *validatorTestSuite) TestValidateStrongPasswordError() {
-	type Register struct {
-		Password string `validate:"strong-password"`
-	}
-
-	mockRegister := Register{Password: "passwordkuat"}
-
-	err := t.validator.Validate(t.ctx, mockRegister)
-	t.Error(err)
-}
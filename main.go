@@ -11,7 +11,7 @@ import "app/cmd"
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Example Value: Bearer eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9...
+// @description Example Value: Bearer eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9... or ApiKey ak_3q2-7wYb1Lk0.Zm9vYmFy...
 func main() {
 	cmd.Execute()
 }